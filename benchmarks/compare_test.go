@@ -0,0 +1,101 @@
+package benchmarks
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mrityunjay-vashisth/go-idforge/pkg/idforge"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/xid"
+)
+
+// collisionBatch is the number of IDs generated per collision measurement
+const collisionBatch = 100000
+
+// library names an ID generator under comparison
+type library struct {
+	name     string
+	generate func() string
+}
+
+// libraries returns fresh generators for every library under comparison
+func libraries() []library {
+	basic := idforge.New()
+	extended := idforge.NewExtendedGenerator()
+	ctx := context.Background()
+
+	return []library{
+		{"idforge", basic.MustGenerate},
+		{"idforge-extended", func() string {
+			id, err := extended.Generate(ctx)
+			if err != nil {
+				panic(err)
+			}
+			return id
+		}},
+		{"google-uuid", func() string { return uuid.New().String() }},
+		{"oklog-ulid", func() string {
+			return ulid.MustNew(ulid.Timestamp(time.Now()), rand.Reader).String()
+		}},
+		{"rs-xid", func() string { return xid.New().String() }},
+	}
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	for _, lib := range libraries() {
+		b.Run(lib.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = lib.generate()
+			}
+		})
+	}
+}
+
+func BenchmarkGenerateParallel(b *testing.B) {
+	for _, lib := range libraries() {
+		b.Run(lib.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_ = lib.generate()
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkCollisions(b *testing.B) {
+	// A short idforge configuration shows how collisions grow as the
+	// keyspace shrinks; the others use their standard formats
+	short := idforge.New(idforge.WithSize(6))
+	libs := append(libraries(), library{"idforge-size6", short.MustGenerate})
+
+	for _, lib := range libs {
+		b.Run(lib.name, func(b *testing.B) {
+			duplicates := 0
+			for i := 0; i < b.N; i++ {
+				seen := make(map[string]struct{}, collisionBatch)
+				for j := 0; j < collisionBatch; j++ {
+					id := lib.generate()
+					if _, dup := seen[id]; dup {
+						duplicates++
+					}
+					seen[id] = struct{}{}
+				}
+			}
+			b.ReportMetric(float64(duplicates)/float64(b.N), "dups/batch")
+		})
+	}
+}
+
+func TestLibrariesProduceDistinctIDs(t *testing.T) {
+	for _, lib := range libraries() {
+		if lib.generate() == lib.generate() {
+			t.Errorf("%s produced the same ID twice in a row", lib.name)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+// Package benchmarks compares idforge with other popular ID libraries.
+//
+// It lives in its own module so the comparison dependencies never leak
+// into the main module. Run it from this directory with:
+//
+//	go test -bench=. -benchmem
+//
+// Throughput and allocations are reported per library; the Collisions
+// benchmarks additionally report observed duplicates per batch.
+package benchmarks
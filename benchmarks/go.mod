@@ -0,0 +1,12 @@
+module github.com/mrityunjay-vashisth/go-idforge/benchmarks
+
+go 1.23.3
+
+replace github.com/mrityunjay-vashisth/go-idforge => ../
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/mrityunjay-vashisth/go-idforge v0.0.0-00010101000000-000000000000
+	github.com/oklog/ulid/v2 v2.1.2
+	github.com/rs/xid v1.6.0
+)
@@ -1,3 +1,5 @@
+//go:build !idforge_lite
+
 package main
 
 import (
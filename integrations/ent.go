@@ -0,0 +1,42 @@
+package integrations
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+)
+
+// IDMixin declares an immutable string "id" field defaulting to a generated
+// ID. Embed it in a schema's Mixin list:
+//
+//	func (User) Mixin() []ent.Mixin {
+//		return []ent.Mixin{integrations.IDMixin{Prefix: "usr", Generate: gen.Generate}}
+//	}
+//
+// ent calls the default function without a way to report errors, so a
+// failing generate function panics inside the create builder.
+type IDMixin struct {
+	mixin.Schema
+
+	Prefix   string                 // Type prefix, empty for bare IDs
+	Generate func() (string, error) // Source of IDs
+}
+
+// Fields implements ent.Mixin
+func (m IDMixin) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			Immutable().
+			Unique().
+			NotEmpty().
+			DefaultFunc(m.newID),
+	}
+}
+
+func (m IDMixin) newID() string {
+	id, err := newID(m.Generate, m.Prefix)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
@@ -0,0 +1,14 @@
+module github.com/mrityunjay-vashisth/go-idforge/integrations
+
+go 1.23.3
+
+require (
+	entgo.io/ent v0.14.1
+	gorm.io/gorm v1.25.12
+)
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
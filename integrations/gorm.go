@@ -0,0 +1,80 @@
+package integrations
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// GORMPlugin fills empty string primary keys before records are created.
+// Models implementing Prefixer get their prefix; others get a bare ID.
+//
+//	db.Use(integrations.NewGORMPlugin(gen.Generate))
+type GORMPlugin struct {
+	generate func() (string, error)
+}
+
+// NewGORMPlugin creates a plugin drawing IDs from generate
+func NewGORMPlugin(generate func() (string, error)) *GORMPlugin {
+	return &GORMPlugin{generate: generate}
+}
+
+// Name identifies the plugin to GORM
+func (p *GORMPlugin) Name() string {
+	return "idforge"
+}
+
+// Initialize registers the ID callback ahead of GORM's own create callback
+func (p *GORMPlugin) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().Before("gorm:create").Register("idforge:assign_id", p.assignIDs)
+}
+
+// assignIDs handles single records as well as batch inserts
+func (p *GORMPlugin) assignIDs(db *gorm.DB) {
+	if db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	if field == nil || field.FieldType.Kind() != reflect.String {
+		return
+	}
+
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			p.assignID(db, field, reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		p.assignID(db, field, rv)
+	}
+}
+
+// assignID sets the primary key of one record unless it is already set
+func (p *GORMPlugin) assignID(db *gorm.DB, field *schema.Field, rv reflect.Value) {
+	ctx := db.Statement.Context
+	if _, zero := field.ValueOf(ctx, rv); !zero {
+		return
+	}
+
+	id, err := newID(p.generate, modelPrefix(rv))
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	if err := field.Set(ctx, rv, id); err != nil {
+		db.AddError(err)
+	}
+}
+
+// modelPrefix returns the prefix of the record in rv, if its type has one
+func modelPrefix(rv reflect.Value) string {
+	if rv.CanAddr() {
+		rv = rv.Addr()
+	}
+	if prefixer, ok := rv.Interface().(Prefixer); ok {
+		return prefixer.IDPrefix()
+	}
+	return ""
+}
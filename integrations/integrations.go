@@ -0,0 +1,25 @@
+// Package integrations populates primary keys with idforge IDs in ORMs.
+//
+// It lives in its own module so the ORM dependencies never leak into the
+// main module. Every helper takes a generate function, such as
+// (*idforge.Generator).Generate or the Get method of an IDPool, and an
+// optional per-model prefix that is joined to the ID with an underscore,
+// giving typed IDs like usr_V1StGXR8Z5jdHi6B.
+package integrations
+
+// Prefixer is implemented by models whose IDs carry a type prefix
+type Prefixer interface {
+	IDPrefix() string
+}
+
+// PrefixSeparator joins a model prefix and the generated ID
+const PrefixSeparator = "_"
+
+// newID generates an ID, prepending prefix when it is not empty
+func newID(generate func() (string, error), prefix string) (string, error) {
+	id, err := generate()
+	if err != nil || prefix == "" {
+		return id, err
+	}
+	return prefix + PrefixSeparator + id, nil
+}
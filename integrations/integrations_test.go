@@ -0,0 +1,43 @@
+package integrations
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewID(t *testing.T) {
+	generate := func() (string, error) { return "abc123", nil }
+
+	if id, _ := newID(generate, ""); id != "abc123" {
+		t.Errorf("Expected abc123, got %s", id)
+	}
+	if id, _ := newID(generate, "usr"); id != "usr_abc123" {
+		t.Errorf("Expected usr_abc123, got %s", id)
+	}
+
+	failure := errors.New("exhausted")
+	if _, err := newID(func() (string, error) { return "", failure }, "usr"); err != failure {
+		t.Errorf("Expected generator error, got %v", err)
+	}
+}
+
+type prefixedModel struct{ ID string }
+
+func (*prefixedModel) IDPrefix() string { return "ord" }
+
+func TestModelPrefix(t *testing.T) {
+	if prefix := modelPrefix(reflect.ValueOf(&prefixedModel{}).Elem()); prefix != "ord" {
+		t.Errorf("Expected ord, got %q", prefix)
+	}
+	if prefix := modelPrefix(reflect.ValueOf(struct{ ID string }{})); prefix != "" {
+		t.Errorf("Expected no prefix, got %q", prefix)
+	}
+}
+
+func TestIDMixinDefault(t *testing.T) {
+	m := IDMixin{Prefix: "usr", Generate: func() (string, error) { return "xyz", nil }}
+	if id := m.newID(); id != "usr_xyz" {
+		t.Errorf("Expected usr_xyz, got %s", id)
+	}
+}
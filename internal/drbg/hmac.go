@@ -0,0 +1,163 @@
+// Package drbg implements the HMAC_DRBG deterministic random bit generator
+// from NIST SP 800-90A using SHA-256.
+package drbg
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"sync"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/secmem"
+)
+
+const (
+	// MaxBytesPerRequest is the SP 800-90A limit of 2^19 bits per request
+	MaxBytesPerRequest = 1 << 16
+
+	// ReseedInterval is the maximum number of requests between reseeds
+	ReseedInterval = 1 << 48
+)
+
+var ErrReseedRequired = errors.New("drbg: reseed required")
+
+// HMACDRBG is an HMAC_DRBG instance. It is safe for concurrent use. The
+// key and V are updated in place, so Lock and Wipe cover every copy.
+type HMACDRBG struct {
+	mu            sync.Mutex
+	key           []byte
+	v             []byte
+	reseedCounter uint64
+	locked        bool
+}
+
+// New instantiates a DRBG from entropy, a nonce and an optional
+// personalization string
+func New(entropy, nonce, personalization []byte) *HMACDRBG {
+	d := &HMACDRBG{
+		key: make([]byte, sha256.Size),
+		v:   make([]byte, sha256.Size),
+	}
+	for i := range d.v {
+		d.v[i] = 0x01
+	}
+
+	d.update(entropy, nonce, personalization)
+	d.reseedCounter = 1
+	return d
+}
+
+// Reseed mixes fresh entropy and optional additional input into the state
+func (d *HMACDRBG) Reseed(entropy, additional []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.update(entropy, additional)
+	d.reseedCounter = 1
+}
+
+// Generate fills out with pseudorandom bytes, mixing in optional
+// additional input
+func (d *HMACDRBG) Generate(out, additional []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(out) > MaxBytesPerRequest {
+		return errors.New("drbg: request too large")
+	}
+	if d.reseedCounter > ReseedInterval {
+		return ErrReseedRequired
+	}
+
+	if len(additional) > 0 {
+		d.update(additional)
+	}
+
+	for n := 0; n < len(out); {
+		mac(d.v, d.key, d.v)
+		n += copy(out[n:], d.v)
+	}
+
+	d.update(additional)
+	d.reseedCounter++
+	return nil
+}
+
+// Read implements io.Reader, splitting large reads into several requests
+func (d *HMACDRBG) Read(p []byte) (int, error) {
+	for n := 0; n < len(p); {
+		end := min(n+MaxBytesPerRequest, len(p))
+		if err := d.Generate(p[n:end], nil); err != nil {
+			return n, err
+		}
+		n = end
+	}
+	return len(p), nil
+}
+
+// Lock keeps the internal state out of swap where the platform supports
+// it, returning secmem.ErrUnsupported elsewhere. Wipe releases the lock.
+func (d *HMACDRBG) Lock() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.locked {
+		return nil
+	}
+	if err := secmem.Lock(d.key); err != nil {
+		return err
+	}
+	if err := secmem.Lock(d.v); err != nil {
+		secmem.Unlock(d.key)
+		return err
+	}
+	d.locked = true
+	return nil
+}
+
+// Wipe overwrites the internal state; the DRBG must not be used afterwards
+func (d *HMACDRBG) Wipe() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.locked {
+		secmem.Unlock(d.key)
+		secmem.Unlock(d.v)
+		d.locked = false
+	}
+	secmem.Wipe(d.key)
+	secmem.Wipe(d.v)
+	d.reseedCounter = ReseedInterval + 1
+}
+
+// update is the HMAC_DRBG_Update function
+func (d *HMACDRBG) update(provided ...[]byte) {
+	empty := true
+	for _, p := range provided {
+		if len(p) > 0 {
+			empty = false
+		}
+	}
+
+	mac(d.key, d.key, append([][]byte{d.v, {0x00}}, provided...)...)
+	mac(d.v, d.key, d.v)
+	if empty {
+		return
+	}
+
+	mac(d.key, d.key, append([][]byte{d.v, {0x01}}, provided...)...)
+	mac(d.v, d.key, d.v)
+}
+
+// mac writes HMAC-SHA256 over the concatenation of data into dst, which may
+// alias key or data, and wipes the intermediate sum
+func mac(dst, key []byte, data ...[]byte) {
+	h := hmac.New(sha256.New, key)
+	for _, b := range data {
+		h.Write(b)
+	}
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	copy(dst, sum[:])
+	secmem.Wipe(sum[:])
+}
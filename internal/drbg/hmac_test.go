@@ -0,0 +1,108 @@
+package drbg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("Invalid hex in test vector: %v", err)
+	}
+	return b
+}
+
+// NIST CAVP HMAC_DRBG, SHA-256, no prediction resistance, no reseed, COUNT = 0
+func TestKnownAnswer(t *testing.T) {
+	entropy := mustHex(t, "ca851911349384bffe89de1cbdc46e6831e44d34a4fb935ee285dd14b71a7488")
+	nonce := mustHex(t, "659ba96c601dc69fc902940805ec0ca8")
+	expected := mustHex(t, "e528e9abf2dece54d47c7e75e5fe302149f817ea9fb4bee6f4199697d04d5b89"+
+		"d54fbb978a15b5c443c9ec21036d2460b6f73ebad0dc2aba6e624abf07745bc1"+
+		"07694bb7547bb0995f70de25d6b29e2d3011bb19d27676c07162c8b5ccde0668"+
+		"961df86803482cb37ed6d5c0bb8d50cf1f50d476aa0458bdaba806f48be9dcb8")
+
+	d := New(entropy, nonce, nil)
+	out := make([]byte, len(expected))
+
+	// The test procedure discards the first output block
+	if err := d.Generate(out, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := d.Generate(out, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(out, expected) {
+		t.Errorf("Known-answer test failed:\n got %x\nwant %x", out, expected)
+	}
+}
+
+func TestDeterministicAndReseed(t *testing.T) {
+	a := New([]byte("entropy-input-entropy-input-1234"), []byte("nonce"), []byte("idforge"))
+	b := New([]byte("entropy-input-entropy-input-1234"), []byte("nonce"), []byte("idforge"))
+
+	outA := make([]byte, 64)
+	outB := make([]byte, 64)
+	a.Read(outA)
+	b.Read(outB)
+	if !bytes.Equal(outA, outB) {
+		t.Errorf("Expected identical seeds to produce identical output")
+	}
+
+	b.Reseed([]byte("fresh-entropy-fresh-entropy-5678"), nil)
+	a.Read(outA)
+	b.Read(outB)
+	if bytes.Equal(outA, outB) {
+		t.Errorf("Expected reseeding to change the output stream")
+	}
+}
+
+func TestReadLarge(t *testing.T) {
+	d := New([]byte("entropy-input-entropy-input-1234"), []byte("nonce"), nil)
+
+	out := make([]byte, 3*MaxBytesPerRequest+17)
+	n, err := d.Read(out)
+	if err != nil || n != len(out) {
+		t.Fatalf("Expected to read %d bytes, got %d (err %v)", len(out), n, err)
+	}
+
+	if err := d.Generate(make([]byte, MaxBytesPerRequest+1), nil); err == nil {
+		t.Errorf("Expected oversized Generate request to fail")
+	}
+}
+
+func TestWipe(t *testing.T) {
+	d := New([]byte("entropy-input-entropy-input-1234"), []byte("nonce"), nil)
+	d.Wipe()
+
+	if !bytes.Equal(d.key, make([]byte, len(d.key))) || !bytes.Equal(d.v, make([]byte, len(d.v))) {
+		t.Errorf("Expected Wipe to zero the internal state")
+	}
+	if _, err := d.Read(make([]byte, 8)); err != ErrReseedRequired {
+		t.Errorf("Expected wiped DRBG to require a reseed, got %v", err)
+	}
+}
+
+func TestLockKeepsStateInPlace(t *testing.T) {
+	d := New([]byte("entropy-input-entropy-input-1234"), []byte("nonce"), nil)
+	if err := d.Lock(); err != nil {
+		t.Skipf("locked memory unavailable: %v", err)
+	}
+	key, v := &d.key[0], &d.v[0]
+
+	d.Reseed([]byte("more-entropy"), nil)
+	if _, err := d.Read(make([]byte, 64)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if &d.key[0] != key || &d.v[0] != v {
+		t.Errorf("Expected the locked state to be updated in place")
+	}
+
+	d.Wipe()
+	if d.locked {
+		t.Errorf("Expected Wipe to release the lock")
+	}
+}
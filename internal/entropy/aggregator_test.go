@@ -0,0 +1,83 @@
+package entropy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// hangingProvider blocks until release is closed, ignoring its context
+type hangingProvider struct {
+	release chan struct{}
+}
+
+func (h hangingProvider) Provide(ctx context.Context) (string, error) {
+	<-h.release
+	return "late", nil
+}
+
+func TestAggregatorRespectsContextDeadline(t *testing.T) {
+	hanging := hangingProvider{release: make(chan struct{})}
+	defer close(hanging.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := NewSecureEntropyAggregator(fixedProvider{"a"}, hanging).Aggregate(ctx)
+	if !errors.Is(err, ErrUnavailable) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected ErrUnavailable wrapping the deadline, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected Aggregate to return at the deadline, took %v", elapsed)
+	}
+}
+
+func TestAggregatorProviderTimeoutWithQuorum(t *testing.T) {
+	hanging := hangingProvider{release: make(chan struct{})}
+	defer close(hanging.release)
+
+	log := NewMixingLog(1)
+	aggregator := NewSecureEntropyAggregator(fixedProvider{"a"}, fixedProvider{"b"}, hanging).
+		WithProviderTimeout(10 * time.Millisecond).
+		WithQuorum(2).
+		WithMixingLog(log)
+
+	entropy, err := aggregator.Aggregate(context.Background())
+	if err != nil {
+		t.Fatalf("Expected the quorum to be met, got %v", err)
+	}
+	if len(entropy) != 64 {
+		t.Errorf("Expected 64 hex characters, got %d", len(entropy))
+	}
+
+	want, _ := NewSecureEntropyAggregator(fixedProvider{"a"}, fixedProvider{"b"}).Aggregate(context.Background())
+	if entropy != want {
+		t.Error("Expected the hung provider to be left out of the hash")
+	}
+	if c := log.Rounds()[0].Contributions[2]; !errors.Is(c.Err, context.DeadlineExceeded) {
+		t.Errorf("Expected the hung provider to be logged as timed out, got %+v", c)
+	}
+}
+
+func TestAggregatorQuorumNotMet(t *testing.T) {
+	aggregator := NewSecureEntropyAggregator(fixedProvider{"a"}, failingProvider{}, failingProvider{}).WithQuorum(2)
+	if _, err := aggregator.Aggregate(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+}
+
+func TestAggregatorHashesInProviderOrder(t *testing.T) {
+	ab, _ := NewSecureEntropyAggregator(fixedProvider{"a"}, fixedProvider{"b"}).Aggregate(context.Background())
+	for i := 0; i < 20; i++ {
+		again, _ := NewSecureEntropyAggregator(fixedProvider{"a"}, fixedProvider{"b"}).Aggregate(context.Background())
+		if again != ab {
+			t.Fatal("Expected the same providers to hash the same way regardless of timing")
+		}
+	}
+	ba, _ := NewSecureEntropyAggregator(fixedProvider{"b"}, fixedProvider{"a"}).Aggregate(context.Background())
+	if ba == ab {
+		t.Error("Expected provider order to affect the hash")
+	}
+}
@@ -0,0 +1,86 @@
+package entropy
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CachedEntropy serves a provider's last output for a TTL so expensive
+// providers, such as network or remote ones, are queried at most once per
+// TTL. Once the output is stale it is still served while a single
+// background query refreshes it; if that query fails the stale output is
+// kept until a later refresh succeeds.
+type CachedEntropy struct {
+	provider EntropyProvider
+	ttl      time.Duration
+	now      func() time.Time
+
+	fetch      sync.Mutex // Serializes synchronous queries
+	mu         sync.Mutex
+	value      string
+	fetchedAt  time.Time
+	cached     bool
+	refreshing bool
+}
+
+// Cached wraps provider so its output is reused for ttl
+func Cached(provider EntropyProvider, ttl time.Duration) *CachedEntropy {
+	return &CachedEntropy{provider: provider, ttl: ttl, now: time.Now}
+}
+
+// Warm queries the provider now, so the first Provide call does not wait
+func (c *CachedEntropy) Warm(ctx context.Context) error {
+	_, err := c.query(ctx)
+	return err
+}
+
+func (c *CachedEntropy) Provide(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	if c.cached {
+		value := c.value
+		if c.now().Sub(c.fetchedAt) >= c.ttl && !c.refreshing {
+			c.refreshing = true
+			go c.refresh(context.WithoutCancel(ctx))
+		}
+		c.mu.Unlock()
+		return value, nil
+	}
+	c.mu.Unlock()
+
+	// Nothing cached yet: wait for the first query, sharing it with
+	// concurrent callers
+	c.fetch.Lock()
+	defer c.fetch.Unlock()
+
+	c.mu.Lock()
+	if c.cached {
+		value := c.value
+		c.mu.Unlock()
+		return value, nil
+	}
+	c.mu.Unlock()
+	return c.query(ctx)
+}
+
+// refresh replaces a stale value in the background
+func (c *CachedEntropy) refresh(ctx context.Context) {
+	c.query(ctx)
+
+	c.mu.Lock()
+	c.refreshing = false
+	c.mu.Unlock()
+}
+
+// query asks the provider and caches a successful answer
+func (c *CachedEntropy) query(ctx context.Context) (string, error) {
+	value, err := c.provider.Provide(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.value, c.fetchedAt, c.cached = value, c.now(), true
+	c.mu.Unlock()
+	return value, nil
+}
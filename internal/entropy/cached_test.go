@@ -0,0 +1,136 @@
+package entropy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// sequenceProvider returns an increasing counter and can be made to fail
+type sequenceProvider struct {
+	calls atomic.Int64
+	fail  atomic.Bool
+}
+
+func (s *sequenceProvider) Provide(ctx context.Context) (string, error) {
+	n := s.calls.Add(1)
+	if s.fail.Load() {
+		return "", errors.New("provider unavailable")
+	}
+	return fmt.Sprint(n), nil
+}
+
+// fakeClock is a manually advanced time source
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// waitForCalls waits until the provider has been queried n times
+func waitForCalls(t *testing.T, p *sequenceProvider, n int64) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for p.calls.Load() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d queries, got %d", n, p.calls.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCachedEntropyReusesValueWithinTTL(t *testing.T) {
+	provider := &sequenceProvider{}
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cached := Cached(provider, time.Minute)
+	cached.now = clock.Now
+
+	for i := 0; i < 5; i++ {
+		value, err := cached.Provide(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if value != "1" {
+			t.Errorf("Expected the cached value 1, got %s", value)
+		}
+		clock.Advance(10 * time.Second)
+	}
+	if n := provider.calls.Load(); n != 1 {
+		t.Errorf("Expected 1 query within the TTL, got %d", n)
+	}
+}
+
+func TestCachedEntropyStaleWhileRevalidate(t *testing.T) {
+	provider := &sequenceProvider{}
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cached := Cached(provider, time.Minute)
+	cached.now = clock.Now
+
+	if err := cached.Warm(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	clock.Advance(2 * time.Minute)
+
+	// The stale value is served at once while a refresh runs
+	if value, _ := cached.Provide(context.Background()); value != "1" {
+		t.Errorf("Expected the stale value 1, got %s", value)
+	}
+	waitForCalls(t, provider, 2)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		value, _ := cached.Provide(context.Background())
+		if value == "2" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the refreshed value 2, got %s", value)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCachedEntropyKeepsStaleValueOnFailure(t *testing.T) {
+	provider := &sequenceProvider{}
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cached := Cached(provider, time.Minute)
+	cached.now = clock.Now
+
+	cached.Warm(context.Background())
+	provider.fail.Store(true)
+	clock.Advance(2 * time.Minute)
+
+	cached.Provide(context.Background())
+	waitForCalls(t, provider, 2)
+	if value, err := cached.Provide(context.Background()); err != nil || value != "1" {
+		t.Errorf("Expected the stale value after a failed refresh, got %q, %v", value, err)
+	}
+}
+
+func TestCachedEntropyFirstQueryFails(t *testing.T) {
+	provider := &sequenceProvider{}
+	provider.fail.Store(true)
+	cached := Cached(provider, time.Minute)
+
+	if _, err := cached.Provide(context.Background()); err == nil {
+		t.Error("Expected the provider's error without a cached value")
+	}
+	if err := cached.Warm(context.Background()); err == nil {
+		t.Error("Expected Warm to report the provider's error")
+	}
+}
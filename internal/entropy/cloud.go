@@ -0,0 +1,204 @@
+//go:build !idforge_lite
+
+package entropy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// cloudMetadataEndpoint is where EC2, GCE and Azure all serve instance
+	// metadata
+	cloudMetadataEndpoint = "http://169.254.169.254"
+
+	// DefaultCloudMetadataTimeout bounds each metadata query
+	DefaultCloudMetadataTimeout = 2 * time.Second
+
+	// cloudDocumentLimit caps how much of a response is read
+	cloudDocumentLimit = 64 << 10
+)
+
+// Cloud names a cloud whose instance metadata service is queried
+type Cloud int
+
+const (
+	CloudAuto Cloud = iota // Query every cloud and use the one that answers
+	CloudEC2
+	CloudGCE
+	CloudAzure
+)
+
+func (c Cloud) String() string {
+	switch c {
+	case CloudAuto:
+		return "auto"
+	case CloudEC2:
+		return "ec2"
+	case CloudGCE:
+		return "gce"
+	case CloudAzure:
+		return "azure"
+	default:
+		return "unknown"
+	}
+}
+
+// CloudIdentity is an instance's identity as its metadata service reports
+// it
+type CloudIdentity struct {
+	Cloud      Cloud
+	InstanceID string
+	Document   []byte // Identity document the instance ID was read from
+}
+
+// CloudMetadataEntropy fingerprints the cloud instance a generator runs on
+// from its instance identity document: the EC2 identity document (through
+// IMDSv2), the GCE instance ID or the Azure compute metadata. Like
+// EnvironmentEntropy it tells apart instances cloned from one image, and
+// the document is salted and hashed so no identifier leaves the provider;
+// set Salt to keep it stable, or leave it nil for a random per-process
+// salt. Off the cloud, or when the service does not answer within Timeout,
+// Provide returns ErrUnavailable. The provider is not part of any default
+// set and must be configured explicitly; wrap it in Cached to avoid a
+// metadata query per aggregation.
+type CloudMetadataEntropy struct {
+	Cloud    Cloud
+	Salt     []byte
+	Timeout  time.Duration // Per query, 0 uses DefaultCloudMetadataTimeout
+	Endpoint string        // Metadata service base URL, empty for the cloud's own
+	Client   *http.Client  // nil uses a client that bypasses proxies
+}
+
+func (c *CloudMetadataEntropy) Provide(ctx context.Context) (string, error) {
+	identity, err := c.Identity(ctx)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	hash.Write(saltOrDefault(c.Salt))
+	hash.Write([]byte(identity.Cloud.String()))
+	hash.Write([]byte{0})
+	hash.Write(identity.Document)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Identity queries the metadata service for the instance's identity. With
+// CloudAuto, all clouds are queried at once and the first to answer wins.
+func (c *CloudMetadataEntropy) Identity(ctx context.Context) (CloudIdentity, error) {
+	if c.Cloud != CloudAuto {
+		return c.identity(ctx, c.Cloud)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	clouds := []Cloud{CloudEC2, CloudGCE, CloudAzure}
+	type answer struct {
+		identity CloudIdentity
+		err      error
+	}
+	answers := make(chan answer, len(clouds))
+	for _, cloud := range clouds {
+		go func() {
+			identity, err := c.identity(ctx, cloud)
+			answers <- answer{identity, err}
+		}()
+	}
+	var errs []error
+	for range clouds {
+		a := <-answers
+		if a.err == nil {
+			return a.identity, nil
+		}
+		errs = append(errs, a.err)
+	}
+	return CloudIdentity{}, errors.Join(errs...)
+}
+
+// identity queries one cloud's metadata service
+func (c *CloudMetadataEntropy) identity(ctx context.Context, cloud Cloud) (CloudIdentity, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCloudMetadataTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	identity := CloudIdentity{Cloud: cloud}
+	var err error
+	switch cloud {
+	case CloudEC2:
+		var token []byte
+		token, err = c.query(ctx, http.MethodPut, "/latest/api/token", "X-aws-ec2-metadata-token-ttl-seconds", "60")
+		if err == nil {
+			identity.Document, err = c.query(ctx, http.MethodGet, "/latest/dynamic/instance-identity/document", "X-aws-ec2-metadata-token", string(token))
+		}
+		if err == nil {
+			var doc struct {
+				InstanceID string `json:"instanceId"`
+			}
+			err = json.Unmarshal(identity.Document, &doc)
+			identity.InstanceID = doc.InstanceID
+		}
+	case CloudGCE:
+		identity.Document, err = c.query(ctx, http.MethodGet, "/computeMetadata/v1/instance/id", "Metadata-Flavor", "Google")
+		identity.InstanceID = strings.TrimSpace(string(identity.Document))
+	case CloudAzure:
+		identity.Document, err = c.query(ctx, http.MethodGet, "/metadata/instance/compute?api-version=2021-02-01", "Metadata", "true")
+		if err == nil {
+			var doc struct {
+				VMID string `json:"vmId"`
+			}
+			err = json.Unmarshal(identity.Document, &doc)
+			identity.InstanceID = doc.VMID
+		}
+	default:
+		return CloudIdentity{}, fmt.Errorf("%w: unknown cloud %d", ErrUnavailable, cloud)
+	}
+	if err == nil && identity.InstanceID == "" {
+		err = errors.New("no instance ID")
+	}
+	if err != nil {
+		return CloudIdentity{}, fmt.Errorf("%w: %s metadata: %w", ErrUnavailable, cloud, err)
+	}
+	return identity, nil
+}
+
+// query sends one metadata request with header set and returns the body
+func (c *CloudMetadataEntropy) query(ctx context.Context, method, path, header, value string) ([]byte, error) {
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = cloudMetadataEndpoint
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(endpoint, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(header, value)
+
+	client := c.Client
+	if client == nil {
+		client = metadataClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, cloudDocumentLimit))
+}
+
+// metadataClient talks to the link-local metadata service directly, since
+// proxies cannot reach it
+var metadataClient = &http.Client{Transport: &http.Transport{Proxy: nil}}
@@ -0,0 +1,124 @@
+//go:build !idforge_lite
+
+package entropy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeMetadata serves the metadata endpoints of one cloud
+func fakeMetadata(t *testing.T, cloud Cloud) *httptest.Server {
+	mux := http.NewServeMux()
+	switch cloud {
+	case CloudEC2:
+		mux.HandleFunc("PUT /latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-aws-ec2-metadata-token-ttl-seconds") == "" {
+				http.Error(w, "missing TTL", http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte("token-123"))
+		})
+		mux.HandleFunc("GET /latest/dynamic/instance-identity/document", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-aws-ec2-metadata-token") != "token-123" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"instanceId":"i-0abc","region":"eu-west-1"}`))
+		})
+	case CloudGCE:
+		mux.HandleFunc("GET /computeMetadata/v1/instance/id", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Metadata-Flavor") != "Google" {
+				http.Error(w, "missing header", http.StatusForbidden)
+				return
+			}
+			w.Write([]byte("4520031799277581759"))
+		})
+	case CloudAzure:
+		mux.HandleFunc("GET /metadata/instance/compute", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Metadata") != "true" || r.URL.Query().Get("api-version") == "" {
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"vmId":"02aab8a4-74ef-476e-8182-f6d2ba4166a6"}`))
+		})
+	}
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCloudMetadataIdentity(t *testing.T) {
+	for _, tc := range []struct {
+		cloud      Cloud
+		instanceID string
+	}{
+		{CloudEC2, "i-0abc"},
+		{CloudGCE, "4520031799277581759"},
+		{CloudAzure, "02aab8a4-74ef-476e-8182-f6d2ba4166a6"},
+	} {
+		server := fakeMetadata(t, tc.cloud)
+		for _, cloud := range []Cloud{tc.cloud, CloudAuto} {
+			provider := &CloudMetadataEntropy{Cloud: cloud, Endpoint: server.URL}
+			identity, err := provider.Identity(context.Background())
+			if err != nil {
+				t.Fatalf("Unexpected error for %s: %v", cloud, err)
+			}
+			if identity.Cloud != tc.cloud || identity.InstanceID != tc.instanceID {
+				t.Errorf("Expected %s instance %s, got %s instance %s", tc.cloud, tc.instanceID, identity.Cloud, identity.InstanceID)
+			}
+		}
+	}
+}
+
+func TestCloudMetadataEntropy(t *testing.T) {
+	server := fakeMetadata(t, CloudGCE)
+	provider := &CloudMetadataEntropy{Salt: []byte("salt"), Endpoint: server.URL}
+	a, err := provider.Provide(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	b, _ := provider.Provide(context.Background())
+	if len(a) != 64 || a != b {
+		t.Errorf("Expected a stable 64-character hash, got %s and %s", a, b)
+	}
+	other, _ := (&CloudMetadataEntropy{Salt: []byte("other"), Endpoint: server.URL}).Provide(context.Background())
+	if other == a {
+		t.Error("Expected the salt to change the output")
+	}
+}
+
+func TestCloudMetadataUnavailable(t *testing.T) {
+	server := fakeMetadata(t, CloudGCE)
+	provider := &CloudMetadataEntropy{Cloud: CloudEC2, Endpoint: server.URL}
+	if _, err := provider.Provide(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable from the wrong cloud, got %v", err)
+	}
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+	provider = &CloudMetadataEntropy{Endpoint: slow.URL, Timeout: 20 * time.Millisecond}
+	start := time.Now()
+	_, err := provider.Provide(context.Background())
+	if !errors.Is(err, ErrUnavailable) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected ErrUnavailable wrapping the deadline, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the timeout to cut the query short, took %v", elapsed)
+	}
+}
+
+func TestCloudString(t *testing.T) {
+	if CloudEC2.String() != "ec2" || Cloud(99).String() != "unknown" {
+		t.Errorf("Unexpected cloud names %s and %s", CloudEC2, Cloud(99))
+	}
+}
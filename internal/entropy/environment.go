@@ -0,0 +1,83 @@
+package entropy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"slices"
+)
+
+// containerIDLength is the length of the hex container IDs that Docker,
+// containerd and CRI-O write into cgroup paths
+const containerIDLength = 64
+
+// EnvironmentEntropy fingerprints the environment a generator runs in: the
+// hostname, the kernel boot ID, the container ID and a hash of the
+// environment variables. These differ between VMs cloned from one image
+// even when their clocks agree. The fingerprint is salted and hashed so no
+// identifier leaves the provider; set Salt to keep it stable across
+// restarts, or leave it nil for a random per-process salt. Identifiers the
+// host does not expose are skipped.
+type EnvironmentEntropy struct {
+	Salt []byte
+}
+
+func (e *EnvironmentEntropy) Provide(ctx context.Context) (string, error) {
+	hash := sha256.New()
+	hash.Write(saltOrDefault(e.Salt))
+
+	hostname, _ := os.Hostname()
+	bootID, _ := os.ReadFile("/proc/sys/kernel/random/boot_id")
+	for _, part := range [][]byte{[]byte(hostname), bootID, containerID(), environmentHash()} {
+		hash.Write(part)
+		hash.Write([]byte{0}) // Keep parts apart
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// containerID returns the ID of the container the process runs in, or nil
+// outside a container
+func containerID() []byte {
+	for _, path := range []string{"/proc/self/cgroup", "/proc/self/mountinfo"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if id := findContainerID(data); id != nil {
+			return id
+		}
+	}
+	return nil
+}
+
+// findContainerID returns the first run of containerIDLength lowercase hex
+// digits in data, or nil. It is scanned by hand to keep regexp out of lite
+// builds.
+func findContainerID(data []byte) []byte {
+	run := 0
+	for i, c := range data {
+		if '0' <= c && c <= '9' || 'a' <= c && c <= 'f' {
+			run++
+			if run == containerIDLength {
+				return data[i+1-containerIDLength : i+1]
+			}
+		} else {
+			run = 0
+		}
+	}
+	return nil
+}
+
+// environmentHash hashes the environment variables in sorted order
+func environmentHash() []byte {
+	env := os.Environ()
+	slices.Sort(env)
+
+	hash := sha256.New()
+	for _, kv := range env {
+		hash.Write([]byte(kv))
+		hash.Write([]byte{0})
+	}
+	return hash.Sum(nil)
+}
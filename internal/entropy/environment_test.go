@@ -0,0 +1,69 @@
+package entropy
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEnvironmentEntropyStableWithSalt(t *testing.T) {
+	provider := &EnvironmentEntropy{Salt: []byte("fleet-salt")}
+
+	first, err := provider.Provide(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, err := provider.Provide(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first != second {
+		t.Errorf("Expected a stable fingerprint with a fixed salt, got %s and %s", first, second)
+	}
+	if len(first) != 64 {
+		t.Errorf("Expected 64 hex characters, got %d", len(first))
+	}
+
+	other, _ := (&EnvironmentEntropy{Salt: []byte("other-salt")}).Provide(context.Background())
+	if other == first {
+		t.Error("Expected different salts to give different fingerprints")
+	}
+}
+
+func TestEnvironmentEntropyHidesIdentifiers(t *testing.T) {
+	entropy, err := (&EnvironmentEntropy{}).Provide(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if hostname, _ := os.Hostname(); hostname != "" && strings.Contains(entropy, hostname) {
+		t.Errorf("Expected the hostname to be hashed, got %s", entropy)
+	}
+}
+
+func TestEnvironmentEntropyChangesWithEnvironment(t *testing.T) {
+	provider := &EnvironmentEntropy{Salt: []byte("salt")}
+	before, _ := provider.Provide(context.Background())
+
+	t.Setenv("IDFORGE_ENVIRONMENT_TEST", "1")
+	after, _ := provider.Provide(context.Background())
+	if before == after {
+		t.Error("Expected the fingerprint to change with the environment")
+	}
+}
+
+func TestFindContainerID(t *testing.T) {
+	id := strings.Repeat("ab12", 16)
+	line := "0::/system.slice/docker-" + id + ".scope\n"
+	if got := string(findContainerID([]byte(line))); got != id {
+		t.Errorf("Expected %s, got %s", id, got)
+	}
+
+	short := "0::/system.slice/docker-" + id[:63] + ".scope\n"
+	if got := findContainerID([]byte(short + short)); got != nil {
+		t.Errorf("Expected no container ID in runs shorter than 64, got %s", got)
+	}
+	if got := findContainerID([]byte(strings.ToUpper(line))); got != nil {
+		t.Errorf("Expected uppercase hex to be ignored, got %s", got)
+	}
+}
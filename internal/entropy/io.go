@@ -0,0 +1,41 @@
+package entropy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+// IOEntropy samples process IO counters and disk statistics together with
+// the time it takes to read them, which jitters with disk and scheduler
+// activity. The statistics come from the operating system where it
+// exposes them (/proc on Linux); elsewhere Provide returns ErrUnavailable.
+// Raw statistics are hashed, never returned.
+type IOEntropy struct{}
+
+func (e *IOEntropy) Provide(ctx context.Context) (string, error) {
+	hash := sha256.New()
+	found := false
+	for _, read := range ioSources {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		start := time.Now()
+		stats, err := read()
+		latency := time.Since(start)
+		if err != nil {
+			continue
+		}
+		found = true
+		hash.Write(stats)
+		hash.Write(binary.LittleEndian.AppendUint64(nil, uint64(latency)))
+	}
+
+	if !found {
+		return "", ErrUnavailable
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
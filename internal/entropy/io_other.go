@@ -0,0 +1,7 @@
+//go:build !linux
+
+package entropy
+
+// ioSources is empty where the operating system does not expose IO
+// statistics as files
+var ioSources []func() ([]byte, error)
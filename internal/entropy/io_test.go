@@ -0,0 +1,45 @@
+package entropy
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestIOEntropy(t *testing.T) {
+	entropy, err := (&IOEntropy{}).Provide(context.Background())
+	if runtime.GOOS != "linux" {
+		if !errors.Is(err, ErrUnavailable) {
+			t.Errorf("Expected ErrUnavailable on %s, got %v", runtime.GOOS, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Skipf("IO statistics not readable here: %v", err)
+	}
+	if len(entropy) != 64 {
+		t.Errorf("Expected 64 hex characters, got %d", len(entropy))
+	}
+}
+
+func TestIOEntropyWithoutSources(t *testing.T) {
+	saved := ioSources
+	defer func() { ioSources = saved }()
+	ioSources = []func() ([]byte, error){
+		func() ([]byte, error) { return nil, errors.New("not readable") },
+	}
+
+	if _, err := (&IOEntropy{}).Provide(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+}
+
+func TestIOEntropyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := (&IOEntropy{}).Provide(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
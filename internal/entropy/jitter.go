@@ -0,0 +1,63 @@
+package entropy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// defaultJitterSamples is the number of timer deltas collected per call
+const defaultJitterSamples = 1024
+
+// JitterEntropy collects CPU timing jitter in the spirit of haveged and
+// jitterentropy: it times a short memory-bound loop many times and hashes
+// the variation between the timings, which comes from caches, pipelines
+// and interrupts. It needs no devices or network, so it suits air-gapped
+// machines, but it is slower than the other providers.
+type JitterEntropy struct {
+	Samples int // Timer deltas per call, 0 uses 1024
+}
+
+func (j *JitterEntropy) Provide(ctx context.Context) (string, error) {
+	samples := j.Samples
+	if samples <= 0 {
+		samples = defaultJitterSamples
+	}
+
+	var (
+		buf   [4096]byte
+		pos   int
+		prev  int64
+		stuck int
+	)
+	hash := sha256.New()
+	for i := 0; i < samples; i++ {
+		if i%64 == 0 && ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		// Walk the buffer with a stride that depends on earlier timings
+		start := time.Now()
+		for k := 0; k < 64; k++ {
+			pos = (pos + int(buf[pos]) + 67) % len(buf)
+			buf[pos] += byte(k + i)
+		}
+		delta := int64(time.Since(start))
+
+		// A delta equal to the previous one carries no new information
+		if delta == prev {
+			stuck++
+		}
+		hash.Write(binary.LittleEndian.AppendUint64(nil, uint64(delta-prev)))
+		prev = delta
+	}
+
+	// Like jitterentropy's stuck test, refuse timers too coarse to jitter
+	if stuck > samples*9/10 {
+		return "", fmt.Errorf("%w: timer too coarse for jitter entropy", ErrUnavailable)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
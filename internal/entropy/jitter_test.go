@@ -0,0 +1,34 @@
+package entropy
+
+import (
+	"context"
+	"testing"
+)
+
+func TestJitterEntropy(t *testing.T) {
+	provider := &JitterEntropy{}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 10; i++ {
+		entropy, err := provider.Provide(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error from jitter entropy: %v", err)
+		}
+		if len(entropy) != 64 {
+			t.Errorf("Expected 64 hex characters, got %d", len(entropy))
+		}
+		if seen[entropy] {
+			t.Errorf("Jitter entropy repeated a value: %s", entropy)
+		}
+		seen[entropy] = true
+	}
+}
+
+func TestJitterEntropyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := (&JitterEntropy{}).Provide(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package entropy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Contribution records what one provider added to an aggregation round
+type Contribution struct {
+	Provider string // Provider type, such as *entropy.UUIDEntropy
+	Digest   string // Hex SHA-256 of the provider's output, empty unless it succeeded
+	Err      error  // Why the provider contributed nothing
+	Skipped  bool   // The provider was not queried, e.g. behind an open circuit breaker
+}
+
+// MixingRound records every provider's contribution to one aggregation
+type MixingRound struct {
+	Time          time.Time
+	Contributions []Contribution
+}
+
+// MixingLog keeps the most recent aggregation rounds in a ring buffer, so
+// security reviews can confirm that every configured source contributes.
+// Outputs are only ever stored as digests.
+type MixingLog struct {
+	mu     sync.Mutex
+	rounds []MixingRound
+	next   int
+	full   bool
+}
+
+// NewMixingLog creates a log holding up to capacity rounds
+func NewMixingLog(capacity int) *MixingLog {
+	return &MixingLog{rounds: make([]MixingRound, max(capacity, 1))}
+}
+
+// Cap returns the number of rounds the log holds
+func (l *MixingLog) Cap() int {
+	return len(l.rounds)
+}
+
+// Record adds a round, overwriting the oldest once the log is full
+func (l *MixingLog) Record(round MixingRound) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rounds[l.next] = round
+	l.next = (l.next + 1) % len(l.rounds)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Rounds returns the recorded rounds, oldest first
+func (l *MixingLog) Rounds() []MixingRound {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.full {
+		return append([]MixingRound(nil), l.rounds[:l.next]...)
+	}
+	return append(append([]MixingRound(nil), l.rounds[l.next:]...), l.rounds[:l.next]...)
+}
+
+// Contributed describes a provider's output without revealing it
+func Contributed(provider EntropyProvider, value string, err error) Contribution {
+	c := Contribution{Provider: fmt.Sprintf("%T", provider), Err: err}
+	if err == nil {
+		sum := sha256.Sum256([]byte(value))
+		c.Digest = hex.EncodeToString(sum[:])
+	}
+	return c
+}
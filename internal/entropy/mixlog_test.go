@@ -0,0 +1,72 @@
+package entropy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fixedProvider always returns the same value
+type fixedProvider struct {
+	value string
+}
+
+func (f fixedProvider) Provide(ctx context.Context) (string, error) {
+	return f.value, nil
+}
+
+// failingProvider always returns an error
+type failingProvider struct{}
+
+func (failingProvider) Provide(ctx context.Context) (string, error) {
+	return "", errors.New("provider unavailable")
+}
+
+func TestMixingLogRingBuffer(t *testing.T) {
+	log := NewMixingLog(3)
+	if rounds := log.Rounds(); len(rounds) != 0 {
+		t.Errorf("Expected an empty log, got %d rounds", len(rounds))
+	}
+
+	base := time.Unix(0, 0)
+	for i := 0; i < 5; i++ {
+		log.Record(MixingRound{Time: base.Add(time.Duration(i) * time.Second)})
+	}
+	rounds := log.Rounds()
+	if len(rounds) != 3 {
+		t.Fatalf("Expected 3 rounds, got %d", len(rounds))
+	}
+	for i, round := range rounds {
+		if want := base.Add(time.Duration(i+2) * time.Second); !round.Time.Equal(want) {
+			t.Errorf("Round %d: expected %v, got %v", i, want, round.Time)
+		}
+	}
+}
+
+func TestAggregatorRecordsContributions(t *testing.T) {
+	log := NewMixingLog(4)
+	aggregator := NewSecureEntropyAggregator(fixedProvider{"secret"}, failingProvider{}).WithMixingLog(log)
+	if _, err := aggregator.Aggregate(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("Expected ErrUnavailable, got %v", err)
+	}
+
+	rounds := log.Rounds()
+	if len(rounds) != 1 {
+		t.Fatalf("Expected 1 round, got %d", len(rounds))
+	}
+	ok, failed := rounds[0].Contributions[0], rounds[0].Contributions[1]
+
+	sum := sha256.Sum256([]byte("secret"))
+	if ok.Digest != hex.EncodeToString(sum[:]) || ok.Err != nil {
+		t.Errorf("Expected the digest of the output, got %+v", ok)
+	}
+	if ok.Provider != "entropy.fixedProvider" {
+		t.Errorf("Expected provider entropy.fixedProvider, got %s", ok.Provider)
+	}
+	if failed.Err == nil || failed.Digest != "" {
+		t.Errorf("Expected the failure without a digest, got %+v", failed)
+	}
+}
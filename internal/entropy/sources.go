@@ -4,18 +4,18 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
-	"encoding/binary"
+	"errors"
 	"fmt"
-	"math/big"
-	"net"
 	"runtime"
-	"strings"
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/mrityunjay-vashisth/go-idforge/internal/uuid"
 )
 
+// ErrUnavailable is returned when entropy could not be collected
+var ErrUnavailable = errors.New("entropy unavailable")
+
 // EntropyProvider defines an interface for generating entropy
 type EntropyProvider interface {
 	Provide(ctx context.Context) (string, error)
@@ -41,11 +41,12 @@ type RandomBytesEntropy struct {
 }
 
 func (r *RandomBytesEntropy) Provide(ctx context.Context) (string, error) {
-	if r.length == 0 {
-		r.length = 16 // Default length
+	length := r.length
+	if length == 0 {
+		length = 16 // Default length; r is shared between goroutines, so leave it unchanged
 	}
 
-	b := make([]byte, r.length)
+	b := make([]byte, length)
 	_, err := rand.Read(b)
 	if err != nil {
 		return "", err
@@ -71,149 +72,149 @@ func (s *SystemEntropy) Provide(ctx context.Context) (string, error) {
 	), nil
 }
 
-// NetworkEntropy generates entropy from network interfaces
-type NetworkEntropy struct{}
-
-func (n *NetworkEntropy) Provide(ctx context.Context) (string, error) {
-	// Get network interfaces
-	interfaces, err := net.Interfaces()
-	if err != nil {
-		return "", err
-	}
-
-	// Collect MAC addresses from non-loopback, up interfaces
-	var macAddresses []string
-	for _, iface := range interfaces {
-		// Check if interface is up and not a loopback
-		if iface.Flags&net.FlagUp != 0 && iface.Flags&net.FlagLoopback == 0 {
-			// Only add if HardwareAddr is not empty
-			if len(iface.HardwareAddr) > 0 {
-				macAddresses = append(macAddresses, iface.HardwareAddr.String())
-			}
-		}
-	}
-
-	// If no MAC addresses found, return empty string
-	if len(macAddresses) == 0 {
-		return "", nil
-	}
-
-	// Join MAC addresses
-	return strings.Join(macAddresses, ","), nil
-}
-
-// EnhancedEntropyProvider adds more sophisticated entropy generation
-type EnhancedEntropyProvider struct {
-	mu        sync.Mutex
-	lastValue *big.Int
-}
-
-func (e *EnhancedEntropyProvider) Provide(ctx context.Context) (string, error) {
-	e.mu.Lock()
-	defer e.mu.Unlock()
-
-	// Collect multiple entropy sources
-	sources := [][]byte{
-		// Timestamp with nanosecond precision
-		binary.BigEndian.AppendUint64(nil, uint64(time.Now().UnixNano())),
-
-		// UUID as bytes
-		[]byte(uuid.New().String()),
-
-		// Memory statistics
-		func() []byte {
-			var memStats runtime.MemStats
-			runtime.ReadMemStats(&memStats)
-			return binary.BigEndian.AppendUint64(nil, memStats.Alloc)
-		}(),
-
-		// Goroutine ID (somewhat unique)
-		[]byte(fmt.Sprintf("%d", runtime.NumGoroutine())),
-	}
-
-	// Combine sources using SHA-256
-	hash := sha256.New()
-	for _, source := range sources {
-		hash.Write(source)
-	}
-
-	// If a previous value exists, incorporate it for additional randomness
-	if e.lastValue != nil {
-		hash.Write(e.lastValue.Bytes())
-	}
-
-	// Generate a new big integer from the hash
-	hashBytes := hash.Sum(nil)
-	newValue := new(big.Int).SetBytes(hashBytes)
-
-	// Store the last generated value
-	e.lastValue = newValue
-
-	return newValue.String(), nil
-}
-
 // SecureEntropyAggregator combines multiple entropy sources with additional security
 type SecureEntropyAggregator struct {
 	providers []EntropyProvider
+	log       *MixingLog
+	timeout   time.Duration // Per-provider deadline, 0 relies on the context alone
+	quorum    int           // Providers that must succeed, 0 requires all
 }
 
 func NewSecureEntropyAggregator(providers ...EntropyProvider) *SecureEntropyAggregator {
 	// Add default enhanced entropy if no providers specified
 	if len(providers) == 0 {
-		providers = []EntropyProvider{
-			&EnhancedEntropyProvider{},
-			&SystemEntropy{},
-			&UUIDEntropy{},
-		}
+		providers = defaultAggregatorProviders()
 	}
 	return &SecureEntropyAggregator{providers: providers}
 }
 
+// WithMixingLog records each provider's contribution to every round in log
+func (s *SecureEntropyAggregator) WithMixingLog(log *MixingLog) *SecureEntropyAggregator {
+	s.log = log
+	return s
+}
+
+// WithProviderTimeout gives each provider at most timeout per round; a
+// provider that overruns it counts as failed
+func (s *SecureEntropyAggregator) WithProviderTimeout(timeout time.Duration) *SecureEntropyAggregator {
+	s.timeout = timeout
+	return s
+}
+
+// WithQuorum lets a round succeed once n providers have contributed, so
+// failing or hanging providers are left out instead of failing the round.
+// By default every provider must succeed.
+func (s *SecureEntropyAggregator) WithQuorum(n int) *SecureEntropyAggregator {
+	s.quorum = n
+	return s
+}
+
+// Aggregate queries all providers in parallel and hashes their output in
+// provider order. It never waits past the context deadline or the
+// per-provider timeout, even for providers that ignore their context, and
+// fails with ErrUnavailable if fewer providers than the quorum succeed.
 func (s *SecureEntropyAggregator) Aggregate(ctx context.Context) (string, error) {
-	var entropyParts []string
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	var errs []error
+	type result struct {
+		value string
+		err   error
+	}
+	results := make([]result, len(s.providers))
 
-	for _, provider := range s.providers {
+	var wg sync.WaitGroup
+	round := MixingRound{Time: time.Now(), Contributions: make([]Contribution, len(s.providers))}
+	for i, provider := range s.providers {
 		wg.Add(1)
-		go func(p EntropyProvider) {
+		go func(i int, p EntropyProvider) {
 			defer wg.Done()
-			entropy, err := p.Provide(ctx)
-			if err != nil {
-				mu.Lock()
-				errs = append(errs, err)
-				mu.Unlock()
-				return
-			}
-			mu.Lock()
-			entropyParts = append(entropyParts, entropy)
-			mu.Unlock()
-		}(provider)
+			value, err := s.provide(ctx, p)
+			results[i] = result{value, err}
+			round.Contributions[i] = Contributed(p, value, err)
+		}(i, provider)
 	}
-
 	wg.Wait()
 
-	if len(errs) > 0 {
-		return "", fmt.Errorf("entropy collection errors: %v", errs)
+	if s.log != nil {
+		s.log.Record(round)
 	}
 
-	// Hash the combined entropy for additional security
+	var errs []error
 	hash := sha256.New()
-	for _, part := range entropyParts {
-		hash.Write([]byte(part))
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+			continue
+		}
+		// Hash the combined entropy for additional security
+		hash.Write([]byte(r.value))
 	}
 
+	quorum := s.quorum
+	if quorum <= 0 || quorum > len(s.providers) {
+		quorum = len(s.providers)
+	}
+	if len(s.providers)-len(errs) < quorum {
+		return "", fmt.Errorf("%w: %w", ErrUnavailable, errors.Join(errs...))
+	}
 	return fmt.Sprintf("%x", hash.Sum(nil)), nil
 }
 
+// provide queries p under the per-provider timeout, returning as soon as
+// the deadline passes even if p keeps running
+func (s *SecureEntropyAggregator) provide(ctx context.Context, p EntropyProvider) (string, error) {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		value string
+		err   error
+	}
+	// Buffered so an abandoned provider can still deliver and exit
+	done := make(chan result, 1)
+	go func() {
+		value, err := p.Provide(ctx)
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("%T: %w", p, ctx.Err())
+	}
+}
+
+var (
+	processSaltOnce sync.Once
+	processSalt     []byte
+)
+
+// saltOrDefault returns salt, or a random salt shared by the process when
+// salt is nil
+func saltOrDefault(salt []byte) []byte {
+	if salt != nil {
+		return salt
+	}
+	processSaltOnce.Do(func() {
+		processSalt = make([]byte, 32)
+		rand.Read(processSalt)
+	})
+	return processSalt
+}
+
 // DefaultEntropyProviders returns a set of standard entropy sources
 func DefaultEntropyProviders() []EntropyProvider {
+	return defaultProviders()
+}
+
+// PrivacySafeEntropyProviders returns sources that reveal nothing about the
+// host: no hardware addresses or system statistics
+func PrivacySafeEntropyProviders() []EntropyProvider {
 	return []EntropyProvider{
 		&TimestampEntropy{},
 		&UUIDEntropy{},
 		&RandomBytesEntropy{length: 16},
-		&SystemEntropy{},
-		&EnhancedEntropyProvider{},
 	}
 }
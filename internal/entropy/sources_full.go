@@ -0,0 +1,128 @@
+//go:build !idforge_lite
+
+package entropy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/uuid"
+)
+
+// NetworkEntropy generates entropy from network interfaces. MAC addresses
+// are hashed with a salt so they never leave the provider; the zero value
+// uses a random per-process salt. The provider is not part of any default
+// set and must be configured explicitly.
+type NetworkEntropy struct {
+	Salt []byte
+}
+
+func (n *NetworkEntropy) Provide(ctx context.Context) (string, error) {
+	// Get network interfaces
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return "", err
+	}
+
+	// Collect MAC addresses from non-loopback, up interfaces
+	var macAddresses []string
+	for _, iface := range interfaces {
+		// Check if interface is up and not a loopback
+		if iface.Flags&net.FlagUp != 0 && iface.Flags&net.FlagLoopback == 0 {
+			// Only add if HardwareAddr is not empty
+			if len(iface.HardwareAddr) > 0 {
+				macAddresses = append(macAddresses, iface.HardwareAddr.String())
+			}
+		}
+	}
+
+	// If no MAC addresses found, return empty string
+	if len(macAddresses) == 0 {
+		return "", nil
+	}
+
+	hash := sha256.New()
+	hash.Write(saltOrDefault(n.Salt))
+	hash.Write([]byte(strings.Join(macAddresses, ",")))
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// EnhancedEntropyProvider adds more sophisticated entropy generation
+type EnhancedEntropyProvider struct {
+	mu        sync.Mutex
+	lastValue *big.Int
+}
+
+func (e *EnhancedEntropyProvider) Provide(ctx context.Context) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Collect multiple entropy sources
+	sources := [][]byte{
+		// Timestamp with nanosecond precision
+		binary.BigEndian.AppendUint64(nil, uint64(time.Now().UnixNano())),
+
+		// UUID as bytes
+		[]byte(uuid.New().String()),
+
+		// Memory statistics
+		func() []byte {
+			var memStats runtime.MemStats
+			runtime.ReadMemStats(&memStats)
+			return binary.BigEndian.AppendUint64(nil, memStats.Alloc)
+		}(),
+
+		// Goroutine ID (somewhat unique)
+		[]byte(fmt.Sprintf("%d", runtime.NumGoroutine())),
+	}
+
+	// Combine sources using SHA-256
+	hash := sha256.New()
+	for _, source := range sources {
+		hash.Write(source)
+	}
+
+	// If a previous value exists, incorporate it for additional randomness
+	if e.lastValue != nil {
+		hash.Write(e.lastValue.Bytes())
+	}
+
+	// Generate a new big integer from the hash
+	hashBytes := hash.Sum(nil)
+	newValue := new(big.Int).SetBytes(hashBytes)
+
+	// Store the last generated value
+	e.lastValue = newValue
+
+	return newValue.String(), nil
+}
+
+// defaultProviders lists the sources used when none are configured
+func defaultProviders() []EntropyProvider {
+	return []EntropyProvider{
+		&TimestampEntropy{},
+		&UUIDEntropy{},
+		&RandomBytesEntropy{length: 16},
+		&SystemEntropy{},
+		&EnhancedEntropyProvider{},
+	}
+}
+
+// defaultAggregatorProviders lists the sources SecureEntropyAggregator
+// falls back to
+func defaultAggregatorProviders() []EntropyProvider {
+	return []EntropyProvider{
+		&EnhancedEntropyProvider{},
+		&SystemEntropy{},
+		&UUIDEntropy{},
+	}
+}
@@ -0,0 +1,25 @@
+//go:build idforge_lite
+
+package entropy
+
+// The lite build leaves out the network, cloud metadata and enhanced
+// providers so the package does not use math/big or net itself.
+
+// defaultProviders lists the sources used when none are configured
+func defaultProviders() []EntropyProvider {
+	return []EntropyProvider{
+		&TimestampEntropy{},
+		&UUIDEntropy{},
+		&RandomBytesEntropy{length: 16},
+		&SystemEntropy{},
+	}
+}
+
+// defaultAggregatorProviders lists the sources SecureEntropyAggregator
+// falls back to
+func defaultAggregatorProviders() []EntropyProvider {
+	return []EntropyProvider{
+		&SystemEntropy{},
+		&UUIDEntropy{},
+	}
+}
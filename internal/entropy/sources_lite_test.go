@@ -0,0 +1,31 @@
+//go:build idforge_lite
+
+package entropy
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLiteDefaultEntropyProviders(t *testing.T) {
+	ctx := context.Background()
+	for i, provider := range DefaultEntropyProviders() {
+		entropy, err := provider.Provide(ctx)
+		if err != nil {
+			t.Errorf("Provider %d failed to generate entropy: %v", i, err)
+		}
+		if entropy == "" {
+			t.Errorf("Provider %d generated empty entropy", i)
+		}
+	}
+}
+
+func TestLiteSecureEntropyAggregator(t *testing.T) {
+	entropy, err := NewSecureEntropyAggregator().Aggregate(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error from SecureEntropyAggregator: %v", err)
+	}
+	if len(entropy) != 64 {
+		t.Errorf("Unexpected entropy length. Expected 64, got %d", len(entropy))
+	}
+}
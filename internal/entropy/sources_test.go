@@ -1,11 +1,15 @@
+//go:build !idforge_lite
+
 package entropy
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -71,6 +75,20 @@ func TestRandomBytesEntropy(t *testing.T) {
 		return len(s) == 32
 	})
 
+	// The default must not be written back, as providers are shared
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defaultProvider.Provide(context.Background())
+		}()
+	}
+	wg.Wait()
+	if defaultProvider.length != 0 {
+		t.Errorf("Expected Provide to leave the length unset, got %d", defaultProvider.length)
+	}
+
 	// Test custom length
 	customLengths := []int{8, 16, 32, 64}
 	for _, length := range customLengths {
@@ -116,22 +134,26 @@ func TestNetworkEntropy(t *testing.T) {
 			return true
 		}
 
-		// Split MAC addresses
-		macAddresses := strings.Split(s, ",")
-
-		for _, macStr := range macAddresses {
-			macStr = strings.TrimSpace(macStr)
+		// Only a salted SHA-256 digest may be emitted, never raw MACs
+		return regexp.MustCompile(`^[0-9a-f]{64}$`).MatchString(s)
+	})
+}
 
-			// Validate MAC address format
-			_, err := net.ParseMAC(macStr)
-			if err != nil {
-				t.Logf("Invalid MAC address: %s, Error: %v", macStr, err)
-				return false
-			}
-		}
+func TestNetworkEntropySalt(t *testing.T) {
+	ctx := context.Background()
+	a, _ := (&NetworkEntropy{Salt: []byte("a")}).Provide(ctx)
+	if a == "" {
+		t.Skip("No active non-loopback interfaces")
+	}
 
-		return true
-	})
+	again, _ := (&NetworkEntropy{Salt: []byte("a")}).Provide(ctx)
+	b, _ := (&NetworkEntropy{Salt: []byte("b")}).Provide(ctx)
+	if a != again {
+		t.Errorf("Expected the same salt to give the same digest, got %s and %s", a, again)
+	}
+	if a == b {
+		t.Error("Expected different salts to give different digests")
+	}
 }
 
 func TestEnhancedEntropyProvider(t *testing.T) {
@@ -254,3 +276,12 @@ func TestContextTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregatorErrorsWrapUnavailable(t *testing.T) {
+	aggregator := NewSecureEntropyAggregator(&UUIDEntropy{}, failingProvider{}, failingProvider{})
+
+	_, err := aggregator.Aggregate(context.Background())
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package secmem
+
+func lock(b []byte) error {
+	return ErrUnsupported
+}
+
+func unlock(b []byte) error {
+	return ErrUnsupported
+}
@@ -0,0 +1,13 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package secmem
+
+import "syscall"
+
+func lock(b []byte) error {
+	return syscall.Mlock(b)
+}
+
+func unlock(b []byte) error {
+	return syscall.Munlock(b)
+}
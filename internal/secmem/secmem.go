@@ -0,0 +1,36 @@
+// Package secmem zeroizes secrets and keeps them out of swap where the
+// platform allows it.
+package secmem
+
+import (
+	"errors"
+	"runtime"
+)
+
+// ErrUnsupported is returned by Lock on platforms without mlock
+var ErrUnsupported = errors.New("secmem: locked memory is not supported on this platform")
+
+// Wipe overwrites b with zeros. The write is kept even though b is not read
+// again, so the compiler cannot drop it.
+func Wipe(b []byte) {
+	clear(b)
+	runtime.KeepAlive(b)
+}
+
+// Lock pins the pages holding b in RAM so they are never written to swap.
+// Pass the same slice to Unlock before dropping it.
+func Lock(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return lock(b)
+}
+
+// Unlock wipes b and releases a lock taken by Lock
+func Unlock(b []byte) error {
+	Wipe(b)
+	if len(b) == 0 {
+		return nil
+	}
+	return unlock(b)
+}
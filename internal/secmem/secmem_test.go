@@ -0,0 +1,43 @@
+package secmem
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWipe(t *testing.T) {
+	b := []byte("secret key material")
+	Wipe(b)
+	if !bytes.Equal(b, make([]byte, len(b))) {
+		t.Errorf("Expected zeroed bytes, got %v", b)
+	}
+}
+
+func TestLockUnlock(t *testing.T) {
+	b := []byte("secret key material")
+	err := Lock(b)
+	if errors.Is(err, ErrUnsupported) {
+		t.Skip("mlock not supported on this platform")
+	}
+	if err != nil {
+		// Unprivileged sandboxes may have no locked memory allowance
+		t.Skipf("mlock unavailable: %v", err)
+	}
+
+	if err := Unlock(b); err != nil {
+		t.Errorf("Expected Unlock to succeed, got %v", err)
+	}
+	if !bytes.Equal(b, make([]byte, len(b))) {
+		t.Errorf("Expected Unlock to wipe the buffer, got %v", b)
+	}
+}
+
+func TestLockEmpty(t *testing.T) {
+	if err := Lock(nil); err != nil {
+		t.Errorf("Expected locking nothing to succeed, got %v", err)
+	}
+	if err := Unlock(nil); err != nil {
+		t.Errorf("Expected unlocking nothing to succeed, got %v", err)
+	}
+}
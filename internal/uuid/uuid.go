@@ -0,0 +1,104 @@
+// Package uuid implements RFC 9562 version 4 and version 7 UUIDs so the
+// module does not need a third-party UUID dependency.
+package uuid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// UUID is a 128-bit universally unique identifier
+type UUID [16]byte
+
+var ErrInvalidFormat = errors.New("invalid UUID format")
+
+// New returns a random version 4 UUID, panicking if randomness is unavailable
+func New() UUID {
+	u, err := NewV4()
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// NewV4 returns a random version 4 UUID
+func NewV4() (UUID, error) {
+	var u UUID
+	if _, err := rand.Read(u[:]); err != nil {
+		return UUID{}, err
+	}
+	u.setVersion(4)
+	return u, nil
+}
+
+// NewV7 returns a time-ordered version 7 UUID for the current time. Use
+// NewV7At to supply the time, e.g. from a test clock.
+func NewV7() (UUID, error) {
+	return NewV7At(time.Now())
+}
+
+// NewV7At returns a version 7 UUID carrying the given time
+func NewV7At(t time.Time) (UUID, error) {
+	var u UUID
+	if _, err := rand.Read(u[6:]); err != nil {
+		return UUID{}, err
+	}
+
+	// The first 48 bits hold the Unix time in milliseconds
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(t.UnixMilli()))
+	copy(u[:6], ms[2:])
+
+	u.setVersion(7)
+	return u, nil
+}
+
+// Parse decodes the canonical 8-4-4-4-12 hexadecimal form
+func Parse(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, ErrInvalidFormat
+	}
+
+	src := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+	if _, err := hex.Decode(u[:], []byte(src)); err != nil {
+		return UUID{}, ErrInvalidFormat
+	}
+	return u, nil
+}
+
+// Version returns the UUID version number
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// Time returns the timestamp embedded in a version 7 UUID
+func (u UUID) Time() time.Time {
+	var ms [8]byte
+	copy(ms[2:], u[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(ms[:])))
+}
+
+// String returns the canonical lowercase 8-4-4-4-12 form
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// setVersion stamps the version and the RFC 9562 variant bits
+func (u *UUID) setVersion(version byte) {
+	u[6] = (u[6] & 0x0f) | version<<4
+	u[8] = (u[8] & 0x3f) | 0x80
+}
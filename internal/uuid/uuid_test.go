@@ -0,0 +1,77 @@
+package uuid
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+var canonical = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewV4(t *testing.T) {
+	seen := make(map[UUID]bool)
+	for i := 0; i < 100; i++ {
+		u, err := NewV4()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if u.Version() != 4 {
+			t.Errorf("Expected version 4, got %d", u.Version())
+		}
+		if !canonical.MatchString(u.String()) {
+			t.Errorf("UUID %s is not in canonical form", u)
+		}
+		if seen[u] {
+			t.Errorf("Duplicate UUID generated: %s", u)
+		}
+		seen[u] = true
+	}
+}
+
+func TestNewV7(t *testing.T) {
+	at := time.UnixMilli(1700000000123)
+	u, err := NewV7At(at)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if u.Version() != 7 {
+		t.Errorf("Expected version 7, got %d", u.Version())
+	}
+	if !u.Time().Equal(at) {
+		t.Errorf("Expected embedded time %v, got %v", at, u.Time())
+	}
+	if !canonical.MatchString(u.String()) {
+		t.Errorf("UUID %s is not in canonical form", u)
+	}
+
+	// Later timestamps must sort after earlier ones
+	later, _ := NewV7At(at.Add(time.Millisecond))
+	if later.String() <= u.String() {
+		t.Errorf("Expected %s to sort after %s", later, u)
+	}
+}
+
+func TestParse(t *testing.T) {
+	u := New()
+
+	parsed, err := Parse(u.String())
+	if err != nil {
+		t.Fatalf("Unexpected error parsing %s: %v", u, err)
+	}
+	if parsed != u {
+		t.Errorf("Round trip mismatch: %s != %s", parsed, u)
+	}
+
+	invalid := []string{
+		"",
+		"not-a-uuid",
+		"123e4567e89b12d3a456426614174000",
+		"123e4567-e89b-12d3-a456-42661417400g",
+	}
+	for _, s := range invalid {
+		if _, err := Parse(s); err != ErrInvalidFormat {
+			t.Errorf("Expected ErrInvalidFormat for %q, got %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package idforge
+
+import (
+	"context"
+	"io"
+)
+
+// StringFunc adapts the generator to the func() string factories that ORMs
+// and other libraries accept for default values. The factory panics if
+// generation fails, like MustGenerate.
+func (g *Generator) StringFunc() func() string {
+	return g.MustGenerate
+}
+
+// StringFunc adapts the generator to the func() string factories that ORMs
+// and other libraries accept for default values. The factory panics if
+// generation fails.
+func (g *ExtendedGenerator) StringFunc() func() string {
+	return func() string {
+		id, err := g.Generate(context.Background())
+		if err != nil {
+			panic(err)
+		}
+		return id
+	}
+}
+
+// Reader returns the generator's random source as an io.Reader, for
+// libraries that accept one, such as UUID or ULID packages. With WithDRBG
+// the bytes come from the seeded DRBG, reseeded on the configured interval,
+// and with WithRNG from the injected RNG; otherwise they come from
+// crypto/rand.
+func (g *ExtendedGenerator) Reader() io.Reader {
+	return generatorReader{g}
+}
+
+// generatorReader reads from a generator's random source under its lock
+type generatorReader struct {
+	g *ExtendedGenerator
+}
+
+func (r generatorReader) Read(p []byte) (int, error) {
+	r.g.mu.Lock()
+	defer r.g.mu.Unlock()
+
+	if r.g.config.DRBG {
+		if err := r.g.ensureDRBG(context.Background()); err != nil {
+			return 0, err
+		}
+	}
+	return io.ReadFull(r.g.randomSource(), p)
+}
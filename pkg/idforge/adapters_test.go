@@ -0,0 +1,46 @@
+package idforge
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestStringFunc(t *testing.T) {
+	basic := New(WithSize(10)).StringFunc()
+	if id := basic(); len(id) != 10 {
+		t.Errorf("Expected 10 characters, got %q", id)
+	}
+
+	extended := NewExtendedGenerator().StringFunc()
+	a, b := extended(), extended()
+	if len(a) != DefaultSize || a == b {
+		t.Errorf("Expected distinct IDs of length %d, got %q and %q", DefaultSize, a, b)
+	}
+}
+
+func TestStringFuncPanicsOnError(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for an invalid configuration")
+		}
+	}()
+	gen := NewExtendedGenerator(func(c *GeneratorConfig) { c.Size = 0 })
+	gen.StringFunc()()
+}
+
+func TestReader(t *testing.T) {
+	for _, gen := range []*ExtendedGenerator{
+		NewExtendedGenerator(),
+		NewExtendedGenerator(WithDRBG(time.Hour)),
+	} {
+		a, b := make([]byte, 32), make([]byte, 32)
+		if n, err := gen.Reader().Read(a); err != nil || n != 32 {
+			t.Fatalf("Expected 32 bytes, got %d, %v", n, err)
+		}
+		gen.Reader().Read(b)
+		if bytes.Equal(a, b) {
+			t.Error("Expected successive reads to differ")
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package idforge
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// symbols is an alphabet prepared for indexing. ASCII alphabets are indexed
+// by byte; alphabets with multi-byte characters are indexed by rune.
+type symbols struct {
+	ascii   string
+	runes   []rune
+	weights *aliasTable // Non-uniform character distribution, nil when uniform
+}
+
+// newSymbols prepares alphabet, which must be valid UTF-8
+func newSymbols(alphabet string) symbols {
+	for i := 0; i < len(alphabet); i++ {
+		if alphabet[i] >= utf8.RuneSelf {
+			return symbols{runes: []rune(alphabet)}
+		}
+	}
+	return symbols{ascii: alphabet}
+}
+
+// weightedSymbols prepares the alphabet of c, including its weights
+func weightedSymbols(c GeneratorConfig) symbols {
+	s := newSymbols(c.Alphabet)
+	if c.AlphabetWeights != nil {
+		s.weights = newAliasTable(c.characterProbabilities())
+	}
+	return s
+}
+
+// len returns the number of characters
+func (s symbols) len() int {
+	if s.runes != nil {
+		return len(s.runes)
+	}
+	return len(s.ascii)
+}
+
+// maxBytes returns the longest encoding of a single character
+func (s symbols) maxBytes() int {
+	if s.runes != nil {
+		return utf8.UTFMax
+	}
+	return 1
+}
+
+// append appends the i-th character to dst
+func (s symbols) append(dst []byte, i int) []byte {
+	if s.runes != nil {
+		return utf8.AppendRune(dst, s.runes[i])
+	}
+	return append(dst, s.ascii[i])
+}
+
+// at returns the i-th character
+func (s symbols) at(i int) rune {
+	if s.runes != nil {
+		return s.runes[i]
+	}
+	return rune(s.ascii[i])
+}
+
+// index returns the position of char, or -1 when it is not in the alphabet
+func (s symbols) index(char rune) int {
+	if s.runes == nil {
+		if char >= utf8.RuneSelf {
+			return -1
+		}
+		return strings.IndexByte(s.ascii, byte(char))
+	}
+	for i, r := range s.runes {
+		if r == char {
+			return i
+		}
+	}
+	return -1
+}
+
+// alphabetLen returns the number of characters in alphabet
+func alphabetLen(alphabet string) int {
+	return utf8.RuneCountInString(alphabet)
+}
+
+// validAlphabet reports whether alphabet is valid UTF-8 with at least two
+// characters
+func validAlphabet(alphabet string) bool {
+	return utf8.ValidString(alphabet) && alphabetLen(alphabet) >= 2
+}
+
+// splitChars splits s after its first n characters, reporting false when s
+// is shorter or not valid UTF-8
+func splitChars(s string, n int) (head, tail string, ok bool) {
+	offset := 0
+	for i := 0; i < n; i++ {
+		if offset >= len(s) {
+			return "", "", false
+		}
+		r, size := utf8.DecodeRuneInString(s[offset:])
+		if r == utf8.RuneError && size <= 1 {
+			return "", "", false
+		}
+		offset += size
+	}
+	return s[:offset], s[offset:], true
+}
@@ -0,0 +1,94 @@
+package idforge
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const (
+	emojiAlphabet    = "🍎🍌🍒🍇🍉🍑🍍🥝"
+	cyrillicAlphabet = "абвгдежзийклмнопрстуфхцчшщэюя"
+)
+
+func TestGeneratorRuneAlphabets(t *testing.T) {
+	for _, alphabet := range []string{emojiAlphabet, cyrillicAlphabet} {
+		gen := New(WithAlphabet(alphabet), WithSize(16))
+
+		id, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !utf8.ValidString(id) {
+			t.Errorf("Expected valid UTF-8, got %q", id)
+		}
+		if n := utf8.RuneCountInString(id); n != 16 {
+			t.Errorf("Expected 16 characters, got %d in %q", n, id)
+		}
+		for _, char := range id {
+			if !strings.ContainsRune(alphabet, char) {
+				t.Errorf("Unexpected character %q in %q", char, id)
+			}
+		}
+		if !gen.Validate(id) {
+			t.Errorf("Expected %q to validate", id)
+		}
+	}
+}
+
+func TestExtendedGeneratorRuneAlphabet(t *testing.T) {
+	gen := NewExtendedGenerator(WithCustomAlphabet(emojiAlphabet), func(c *GeneratorConfig) { c.Size = 10 })
+
+	id, err := gen.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := utf8.RuneCountInString(id); n != 10 {
+		t.Errorf("Expected 10 characters, got %d in %q", n, id)
+	}
+	if !IsValidID(id, emojiAlphabet, 10) {
+		t.Errorf("Expected %q to validate", id)
+	}
+}
+
+func TestRuneAlphabetRejectsPartialCharacters(t *testing.T) {
+	gen := New(WithAlphabet(cyrillicAlphabet), WithSize(4))
+
+	id, _ := gen.Generate()
+	if gen.Validate(id[:len(id)-1]) {
+		t.Errorf("Expected truncated %q to be rejected", id[:len(id)-1])
+	}
+	if gen.Validate(strings.Repeat("a", len(id))) {
+		t.Error("Expected ASCII ID with the same byte length to be rejected")
+	}
+}
+
+func TestInvalidAlphabets(t *testing.T) {
+	for _, alphabet := range []string{"", "я", "a\xff"} {
+		if validAlphabet(alphabet) {
+			t.Errorf("Expected %q to be invalid", alphabet)
+		}
+		if gen := New(WithAlphabet(alphabet)); gen.alphabet != DefaultAlphabet {
+			t.Errorf("Expected %q to be ignored, got alphabet %q", alphabet, gen.alphabet)
+		}
+	}
+
+	cfg := GeneratorConfig{Alphabet: "a\xffb", Size: DefaultSize}
+	if err := cfg.validate(); err != ErrInvalidAlphabet {
+		t.Errorf("Expected ErrInvalidAlphabet, got %v", err)
+	}
+}
+
+func TestSplitChars(t *testing.T) {
+	head, tail, ok := splitChars("жжa-b", 2)
+	if !ok || head != "жж" || tail != "a-b" {
+		t.Errorf("Expected (жж, a-b, true), got (%q, %q, %v)", head, tail, ok)
+	}
+	if _, _, ok := splitChars("ж", 2); ok {
+		t.Error("Expected short input to fail")
+	}
+	if _, _, ok := splitChars("\xffab", 1); ok {
+		t.Error("Expected invalid UTF-8 to fail")
+	}
+}
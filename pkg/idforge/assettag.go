@@ -0,0 +1,187 @@
+package idforge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	// AssetTagLetters leaves out I, O and Q, which are easily mistaken for
+	// 1, 0 and O on plates and labels
+	AssetTagLetters = "ABCDEFGHJKLMNPRSTUVWXYZ"
+	assetTagDigits  = "0123456789"
+
+	// assetTagAttempts bounds how many candidates Issue tries
+	assetTagAttempts = 100
+)
+
+var ErrInvalidTagFormat = fmt.Errorf("%w: asset tag format needs at least one 'A' or '9'", ErrInvalidConfig)
+
+// AssetTagOption configures an AssetTagGenerator
+type AssetTagOption func(*AssetTagGenerator)
+
+// WithExcludedCombinations rejects tags containing any of words, ignoring
+// case, such as offensive or reserved letter combinations
+func WithExcludedCombinations(words ...string) AssetTagOption {
+	return func(g *AssetTagGenerator) {
+		for _, word := range words {
+			if word != "" {
+				g.excluded = append(g.excluded, strings.ToUpper(word))
+			}
+		}
+	}
+}
+
+// WithMaxRun rejects tags with n or more characters in a row that repeat or
+// count up or down, such as AAA, 1234 or CBA
+func WithMaxRun(n int) AssetTagOption {
+	return func(g *AssetTagGenerator) {
+		if n > 1 {
+			g.maxRun = n
+		}
+	}
+}
+
+// WithTagStore records issued tags in store instead of process memory, so
+// several instances never issue the same tag
+func WithTagStore(store UniquenessStore) AssetTagOption {
+	return func(g *AssetTagGenerator) {
+		if store != nil {
+			g.store = store
+		}
+	}
+}
+
+// AssetTagGenerator creates fixed-format tags for fleets and equipment
+type AssetTagGenerator struct {
+	layout   *LayoutGenerator
+	excluded []string
+	maxRun   int
+	store    UniquenessStore
+}
+
+// NewAssetTagGenerator creates a generator for format, in which 'A' stands
+// for a letter of AssetTagLetters, '9' for a digit, a backslash makes the
+// next character literal and any other character stands for itself:
+// "AAA-9999" gives tags like KTR-4821 and "FLEET-\A99" gives FLEET-A07.
+func NewAssetTagGenerator(format string, opts ...AssetTagOption) (*AssetTagGenerator, error) {
+	gen, ok := compileFormat(format, map[byte]string{'A': AssetTagLetters, '9': assetTagDigits})
+	if !ok {
+		return nil, ErrInvalidTagFormat
+	}
+
+	g := &AssetTagGenerator{layout: gen, store: NewMemoryUniquenessStore()}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g, nil
+}
+
+// Generate creates a tag that passes the exclusions, without checking
+// whether it was issued before
+func (g *AssetTagGenerator) Generate() (string, error) {
+	for attempt := 0; attempt < assetTagAttempts; attempt++ {
+		tag, err := g.layout.Generate()
+		if err != nil {
+			return "", err
+		}
+		if !g.excludedTag(tag) {
+			return tag, nil
+		}
+	}
+	return "", fmt.Errorf("%w: exclusions rejected %d candidates", ErrGenerationTimeout, assetTagAttempts)
+}
+
+// Issue creates a tag and records it as issued, retrying when a candidate
+// was issued before
+func (g *AssetTagGenerator) Issue(ctx context.Context) (string, error) {
+	for attempt := 0; attempt < assetTagAttempts; attempt++ {
+		tag, err := g.Generate()
+		if err != nil {
+			return "", err
+		}
+		reserved, err := g.store.Reserve(ctx, tag, 0)
+		if err != nil {
+			return "", fmt.Errorf("tag store: %w", err)
+		}
+		if reserved {
+			return tag, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %w after %d attempts", ErrGenerationTimeout, ErrCollision, assetTagAttempts)
+}
+
+// Validate checks that tag matches the format and passes the exclusions
+func (g *AssetTagGenerator) Validate(tag string) bool {
+	return g.layout.Validate(tag) && !g.excludedTag(tag)
+}
+
+// excludedTag reports whether tag contains an excluded combination or an
+// overly long run
+func (g *AssetTagGenerator) excludedTag(tag string) bool {
+	upper := strings.ToUpper(tag)
+	for _, word := range g.excluded {
+		if strings.Contains(upper, word) {
+			return true
+		}
+	}
+	return g.maxRun > 0 && longestRun(tag) >= g.maxRun
+}
+
+// compileFormat builds a layout from format, in which each placeholder
+// character stands for a random character of its alphabet, a backslash
+// makes the next character literal and any other character stands for
+// itself. It reports false for a trailing backslash or a format without
+// placeholders.
+func compileFormat(format string, placeholders map[byte]string) (*LayoutGenerator, bool) {
+	layout := NewLayout()
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			layout.Literal(literal.String())
+			literal.Reset()
+		}
+	}
+
+	for rest := format; rest != ""; {
+		if alphabet, ok := placeholders[rest[0]]; ok {
+			flush()
+			run := len(rest) - len(strings.TrimLeft(rest, rest[:1]))
+			layout.Random(alphabet, run)
+			rest = rest[run:]
+			continue
+		}
+		if rest[0] == '\\' {
+			if len(rest) < 2 {
+				return nil, false
+			}
+			rest = rest[1:]
+		}
+		literal.WriteByte(rest[0])
+		rest = rest[1:]
+	}
+	flush()
+
+	gen, err := layout.Build()
+	return gen, err == nil
+}
+
+// longestRun returns the length of the longest stretch of characters that
+// repeat, ascend or descend by one
+func longestRun(s string) int {
+	longest := min(len(s), 1)
+	same, up, down := 1, 1, 1
+	for i := 1; i < len(s); i++ {
+		same, up, down = extendRun(same, s[i] == s[i-1]), extendRun(up, s[i] == s[i-1]+1), extendRun(down, s[i] == s[i-1]-1)
+		longest = max(longest, same, up, down)
+	}
+	return longest
+}
+
+func extendRun(n int, continues bool) int {
+	if continues {
+		return n + 1
+	}
+	return 1
+}
@@ -0,0 +1,105 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAssetTagFormat(t *testing.T) {
+	gen, err := NewAssetTagGenerator("AAA-9999")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		tag, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(tag) != 8 || tag[3] != '-' {
+			t.Fatalf("Expected AAA-9999 format, got %s", tag)
+		}
+		if strings.Trim(tag[:3], AssetTagLetters) != "" || strings.Trim(tag[4:], "0123456789") != "" {
+			t.Errorf("Unexpected characters in %s", tag)
+		}
+		if !gen.Validate(tag) {
+			t.Errorf("Expected %s to validate", tag)
+		}
+	}
+	if gen.Validate("AB1-2345") || gen.Validate("ABC-234") {
+		t.Error("Expected malformed tags to be rejected")
+	}
+}
+
+func TestAssetTagExclusions(t *testing.T) {
+	gen, err := NewAssetTagGenerator("AA9", WithExcludedCombinations("ab", "Z"), WithMaxRun(2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, tag := range []string{"AB1", "ZC1", "BC1", "CC1", "CB1"} {
+		if gen.Validate(tag) {
+			t.Errorf("Expected %s to be excluded", tag)
+		}
+	}
+	if !gen.Validate("BD1") {
+		t.Error("Expected BD1 to be allowed")
+	}
+	for i := 0; i < 200; i++ {
+		tag, _ := gen.Generate()
+		if strings.Contains(tag, "AB") || strings.Contains(tag, "Z") || longestRun(tag) >= 2 {
+			t.Fatalf("Generated excluded tag %s", tag)
+		}
+	}
+}
+
+func TestAssetTagEscapes(t *testing.T) {
+	gen, err := NewAssetTagGenerator("FLEET-\\A99")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tag, _ := gen.Generate()
+	if !strings.HasPrefix(tag, "FLEET-A") || len(tag) != 9 {
+		t.Errorf("Expected FLEET-A followed by two digits, got %s", tag)
+	}
+}
+
+func TestLongestRun(t *testing.T) {
+	cases := map[string]int{"": 0, "Q": 1, "A7K": 1, "AAB": 2, "X1234": 4, "DCBA9": 4, "AB-CD": 2}
+	for s, want := range cases {
+		if got := longestRun(s); got != want {
+			t.Errorf("longestRun(%q) = %d, expected %d", s, got, want)
+		}
+	}
+}
+
+func TestAssetTagIssue(t *testing.T) {
+	// Only 10 tags exist, so the eleventh issue must fail
+	gen, err := NewAssetTagGenerator("T-9")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	seen := make(map[string]bool)
+	for i := 0; i < 10; i++ {
+		tag, err := gen.Issue(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error issuing tag %d: %v", i, err)
+		}
+		if seen[tag] {
+			t.Fatalf("Tag %s issued twice", tag)
+		}
+		seen[tag] = true
+	}
+	if _, err := gen.Issue(ctx); !errors.Is(err, ErrCollision) {
+		t.Errorf("Expected ErrCollision once all tags are issued, got %v", err)
+	}
+}
+
+func TestAssetTagInvalidFormat(t *testing.T) {
+	for _, format := range []string{"", "T\\AG-", "99\\"} {
+		if _, err := NewAssetTagGenerator(format); err != ErrInvalidTagFormat {
+			t.Errorf("Format %q: expected ErrInvalidTagFormat, got %v", format, err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package idforge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditEvent describes a single issued identifier
+type AuditEvent struct {
+	ID        string            `json:"id"`
+	Timestamp time.Time         `json:"timestamp"`
+	Generator string            `json:"generator,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// Auditor receives an event for every successfully generated ID.
+// Returning an error fails the generation so no unaudited ID is handed out.
+type Auditor interface {
+	Audit(ctx context.Context, event AuditEvent) error
+}
+
+// WithAuditor registers an auditor for generated IDs
+func WithAuditor(auditor Auditor) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.Auditor = auditor
+	}
+}
+
+// WithName sets the generator name reported in audit events
+func WithName(name string) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.Name = name
+	}
+}
+
+type auditMetadataKey struct{}
+
+// WithAuditMetadata attaches caller metadata to IDs generated with ctx.
+// Metadata from enclosing contexts is preserved unless overridden.
+func WithAuditMetadata(ctx context.Context, metadata map[string]string) context.Context {
+	merged := make(map[string]string)
+	for k, v := range auditMetadata(ctx) {
+		merged[k] = v
+	}
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, auditMetadataKey{}, merged)
+}
+
+// auditMetadata returns the metadata attached to ctx, if any
+func auditMetadata(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(auditMetadataKey{}).(map[string]string)
+	return metadata
+}
+
+// audit builds the event for id and hands it to the auditor
+func audit(ctx context.Context, auditor Auditor, name, id string) error {
+	event := AuditEvent{
+		ID:        id,
+		Timestamp: time.Now().UTC(),
+		Generator: name,
+		Metadata:  auditMetadata(ctx),
+	}
+	if err := auditor.Audit(ctx, event); err != nil {
+		return fmt.Errorf("audit failed: %w", err)
+	}
+	return nil
+}
+
+// JSONLinesAuditor writes each event as a JSON object on its own line
+type JSONLinesAuditor struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewJSONLinesAuditor creates an auditor writing to w
+func NewJSONLinesAuditor(w io.Writer) *JSONLinesAuditor {
+	return &JSONLinesAuditor{w: w}
+}
+
+// OpenJSONLinesAuditor creates an auditor appending to the file at path
+func OpenJSONLinesAuditor(path string) (*JSONLinesAuditor, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLinesAuditor{w: f, closer: f}, nil
+}
+
+func (a *JSONLinesAuditor) Audit(ctx context.Context, event AuditEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, err = a.w.Write(line)
+	return err
+}
+
+// Close closes the underlying file when the auditor owns it
+func (a *JSONLinesAuditor) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// ChannelAuditor delivers events to a channel.
+// Delivery blocks until the event is received or ctx is done.
+type ChannelAuditor struct {
+	events chan<- AuditEvent
+}
+
+// NewChannelAuditor creates an auditor sending events to ch
+func NewChannelAuditor(ch chan<- AuditEvent) *ChannelAuditor {
+	return &ChannelAuditor{events: ch}
+}
+
+func (a *ChannelAuditor) Audit(ctx context.Context, event AuditEvent) error {
+	select {
+	case a.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
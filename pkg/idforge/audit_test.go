@@ -0,0 +1,110 @@
+package idforge
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type failingAuditor struct{}
+
+func (failingAuditor) Audit(ctx context.Context, event AuditEvent) error {
+	return errors.New("sink unavailable")
+}
+
+func TestJSONLinesAuditor(t *testing.T) {
+	var buf bytes.Buffer
+	gen := NewExtendedGenerator(
+		WithName("orders"),
+		WithAuditor(NewJSONLinesAuditor(&buf)),
+	)
+
+	ctx := WithAuditMetadata(context.Background(), map[string]string{"service": "checkout"})
+	ids := make([]string, 3)
+	for i := range ids {
+		id, err := gen.Generate(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error generating ID: %v", err)
+		}
+		ids[i] = id
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	for i := 0; scanner.Scan(); i++ {
+		var event AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		if event.ID != ids[i] {
+			t.Errorf("Expected audited ID %s, got %s", ids[i], event.ID)
+		}
+		if event.Generator != "orders" {
+			t.Errorf("Expected generator name 'orders', got %s", event.Generator)
+		}
+		if event.Metadata["service"] != "checkout" {
+			t.Errorf("Expected metadata to be recorded, got %v", event.Metadata)
+		}
+		if event.Timestamp.IsZero() {
+			t.Errorf("Expected event timestamp to be set")
+		}
+	}
+}
+
+func TestOpenJSONLinesAuditor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditor, err := OpenJSONLinesAuditor(path)
+	if err != nil {
+		t.Fatalf("Unexpected error opening audit file: %v", err)
+	}
+
+	gen := NewExtendedGenerator(WithAuditor(auditor))
+	id, err := gen.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error generating ID: %v", err)
+	}
+	if err := auditor.Close(); err != nil {
+		t.Fatalf("Unexpected error closing auditor: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading audit file: %v", err)
+	}
+	if !bytes.Contains(data, []byte(id)) {
+		t.Errorf("Audit file does not contain generated ID %s", id)
+	}
+}
+
+func TestChannelAuditor(t *testing.T) {
+	events := make(chan AuditEvent, 1)
+	gen := NewExtendedGenerator(WithAuditor(NewChannelAuditor(events)))
+
+	id, err := gen.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error generating ID: %v", err)
+	}
+
+	select {
+	case event := <-events:
+		if event.ID != id {
+			t.Errorf("Expected audited ID %s, got %s", id, event.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected an audit event on the channel")
+	}
+}
+
+func TestAuditorFailureFailsGeneration(t *testing.T) {
+	gen := NewExtendedGenerator(WithAuditor(failingAuditor{}))
+
+	id, err := gen.Generate(context.Background())
+	if err == nil {
+		t.Fatalf("Expected audit failure to fail generation, got ID %s", id)
+	}
+}
@@ -0,0 +1,130 @@
+package idforge
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+)
+
+// BarcodeSymbology selects the barcode IDs must print in
+type BarcodeSymbology int
+
+const (
+	// Code39 covers uppercase letters, digits and -.$/+% and uses the
+	// standard modulo 43 check character
+	Code39 BarcodeSymbology = iota
+	// Code128 prints any ASCII; IDs use letters and digits and carry a
+	// Luhn mod N check character from the same alphabet. The symbology's
+	// own check value is added by the barcode encoder.
+	Code128
+)
+
+const (
+	// Code39Alphabet is the Code 39 character set without space and the
+	// '*' start/stop character
+	Code39Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ-.$/+%"
+
+	// Code128Alphabet is a subset of Code 128 set B that survives URLs,
+	// file names and spreadsheets unchanged
+	Code128Alphabet = DefaultAlphabet
+
+	// code39Values lists Code 39 characters in check value order
+	code39Values = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ-. $/+%"
+)
+
+var ErrInvalidSymbology = errors.New("unknown barcode symbology")
+
+// BarcodeGenerator creates IDs that print as barcodes without
+// post-processing. The last character of each ID is a check character.
+type BarcodeGenerator struct {
+	symbology BarcodeSymbology
+	alphabet  string
+	size      int
+}
+
+// NewBarcodeGenerator creates a generator of size-character IDs, check
+// character included
+func NewBarcodeGenerator(symbology BarcodeSymbology, size int) (*BarcodeGenerator, error) {
+	alphabet, ok := barcodeAlphabet(symbology)
+	if !ok {
+		return nil, ErrInvalidSymbology
+	}
+	if size < 2 {
+		return nil, ErrInvalidSize
+	}
+	return &BarcodeGenerator{symbology: symbology, alphabet: alphabet, size: size}, nil
+}
+
+// Generate creates an ID ending in its check character. Code 39 bodies
+// whose check character would be a space are drawn again, so no ID ends
+// in one.
+func (b *BarcodeGenerator) Generate() (string, error) {
+	for {
+		body, err := buildID(rand.Reader, newSymbols(b.alphabet), b.size-1, nil, nil)
+		if err != nil {
+			return "", err
+		}
+		if check := barcodeCheck(body, b.symbology); check != ' ' {
+			return body + string(check), nil
+		}
+	}
+}
+
+// Validate checks the length, character set and check character of id
+func (b *BarcodeGenerator) Validate(id string) bool {
+	return len(id) == b.size && ValidateBarcode(id, b.symbology)
+}
+
+// ValidateBarcode checks that id uses only characters of the symbology's
+// alphabet and ends in a correct check character other than a space
+func ValidateBarcode(id string, symbology BarcodeSymbology) bool {
+	alphabet, ok := barcodeAlphabet(symbology)
+	if !ok || len(id) < 2 {
+		return false
+	}
+	body := id[:len(id)-1]
+	for i := 0; i < len(body); i++ {
+		if strings.IndexByte(alphabet, body[i]) < 0 {
+			return false
+		}
+	}
+	check := barcodeCheck(body, symbology)
+	return check != ' ' && id[len(id)-1] == check
+}
+
+func barcodeAlphabet(symbology BarcodeSymbology) (string, bool) {
+	switch symbology {
+	case Code39:
+		return Code39Alphabet, true
+	case Code128:
+		return Code128Alphabet, true
+	default:
+		return "", false
+	}
+}
+
+// barcodeCheck computes the check character of body, which must use the
+// symbology's alphabet
+func barcodeCheck(body string, symbology BarcodeSymbology) byte {
+	if symbology == Code39 {
+		sum := 0
+		for i := 0; i < len(body); i++ {
+			sum += strings.IndexByte(code39Values, body[i])
+		}
+		return code39Values[sum%len(code39Values)]
+	}
+	return Code128Alphabet[luhnModN(body, Code128Alphabet)]
+}
+
+// luhnModN returns the index of the Luhn mod N check character of body,
+// which catches every single-character error and most transpositions
+func luhnModN(body, alphabet string) int {
+	n := len(alphabet)
+	factor, sum := 2, 0
+	for i := len(body) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(alphabet, body[i])
+		sum += addend/n + addend%n
+		factor = 3 - factor
+	}
+	return (n - sum%n) % n
+}
@@ -0,0 +1,81 @@
+package idforge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCode39CheckCharacter(t *testing.T) {
+	// Worked example from the Code 39 specification: CODE39 -> W
+	if !ValidateBarcode("CODE39W", Code39) {
+		t.Error("Expected CODE39W to validate")
+	}
+	if ValidateBarcode("CODE39X", Code39) {
+		t.Error("Expected CODE39X to fail the check")
+	}
+	if ValidateBarcode("code39W", Code39) {
+		t.Error("Expected lowercase characters to be rejected")
+	}
+}
+
+func TestBarcodeGenerator(t *testing.T) {
+	for _, symbology := range []BarcodeSymbology{Code39, Code128} {
+		gen, err := NewBarcodeGenerator(symbology, 12)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for i := 0; i < 50; i++ {
+			id, err := gen.Generate()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !gen.Validate(id) {
+				t.Errorf("Expected %s to validate", id)
+			}
+		}
+	}
+}
+
+func TestCode39NoTrailingSpace(t *testing.T) {
+	// A and S have check values 10 and 28, summing to the space's 38
+	if ValidateBarcode("AS ", Code39) {
+		t.Error("Expected an ID ending in a space to be rejected")
+	}
+
+	gen, _ := NewBarcodeGenerator(Code39, 3)
+	for i := 0; i < 2000; i++ {
+		id, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.HasSuffix(id, " ") {
+			t.Fatalf("Expected no ID to end in a space, got %q", id)
+		}
+	}
+}
+
+func TestCode128DetectsSubstitution(t *testing.T) {
+	gen, _ := NewBarcodeGenerator(Code128, 10)
+	id, _ := gen.Generate()
+
+	for i := 0; i < len(id)-1; i++ {
+		for j := 0; j < len(Code128Alphabet); j++ {
+			if Code128Alphabet[j] == id[i] {
+				continue
+			}
+			typo := id[:i] + string(Code128Alphabet[j]) + id[i+1:]
+			if ValidateBarcode(typo, Code128) {
+				t.Fatalf("Expected typo %s of %s to be rejected", typo, id)
+			}
+		}
+	}
+}
+
+func TestBarcodeGeneratorInvalid(t *testing.T) {
+	if _, err := NewBarcodeGenerator(BarcodeSymbology(9), 10); err != ErrInvalidSymbology {
+		t.Errorf("Expected ErrInvalidSymbology, got %v", err)
+	}
+	if _, err := NewBarcodeGenerator(Code39, 1); err != ErrInvalidSize {
+		t.Errorf("Expected ErrInvalidSize, got %v", err)
+	}
+}
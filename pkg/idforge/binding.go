@@ -0,0 +1,16 @@
+package idforge
+
+import "strconv"
+
+// Binding is data identifying the client a signed token was issued to.
+// Build one with BindCertificate, BindSubnet or BindValue; lite builds
+// only have BindValue.
+type Binding []byte
+
+// BindValue binds to any other client attribute, such as a device ID or a
+// session key thumbprint. kind names the attribute, so equal values of
+// different kinds never match. kind is length-prefixed, so no split of
+// the same text into kind and value matches another.
+func BindValue(kind, value string) Binding {
+	return Binding(strconv.Itoa(len(kind)) + ":" + kind + ":" + value)
+}
@@ -0,0 +1,30 @@
+//go:build !idforge_lite
+
+package idforge
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"net/netip"
+)
+
+// BindCertificate binds to the SHA-256 hash of a client certificate, as in
+// certificate-bound access tokens (RFC 8705)
+func BindCertificate(cert *x509.Certificate) Binding {
+	sum := sha256.Sum256(cert.Raw)
+	return append(Binding("cert:"), sum[:]...)
+}
+
+// BindSubnet binds to the network of addr, its first bits bits, so the
+// token keeps working when the client's address changes within it, e.g.
+// 24 for an IPv4 /24 or 64 for an IPv6 /64. IPv4-mapped IPv6 addresses
+// bind like their IPv4 form. An invalid prefix length binds to the whole
+// address.
+func BindSubnet(addr netip.Addr, bits int) Binding {
+	addr = addr.Unmap()
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	return Binding("net:" + prefix.String())
+}
@@ -0,0 +1,93 @@
+//go:build !idforge_lite
+
+package idforge
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestSignBound(t *testing.T) {
+	signer := NewSigner(newStaticKeys("k1", map[string]string{"k1": "secret"}))
+	ctx := context.Background()
+	id := New().MustGenerate()
+	client := BindSubnet(netip.MustParseAddr("203.0.113.17"), 24)
+
+	token, err := signer.SignBound(ctx, id, client, BindValue("device", "d-42"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got, err := signer.VerifyBound(ctx, token, BindSubnet(netip.MustParseAddr("203.0.113.200"), 24), BindValue("device", "d-42"))
+	if err != nil {
+		t.Fatalf("Expected the same subnet and device to verify, got %v", err)
+	}
+	if got != id {
+		t.Errorf("Expected %s, got %s", id, got)
+	}
+
+	for name, bindings := range map[string][]Binding{
+		"other subnet": {BindSubnet(netip.MustParseAddr("198.51.100.17"), 24), BindValue("device", "d-42")},
+		"other device": {client, BindValue("device", "d-43")},
+		"swapped":      {BindValue("device", "d-42"), client},
+		"missing":      {client},
+		"unbound":      nil,
+	} {
+		if _, err := signer.VerifyBound(ctx, token, bindings...); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("Expected ErrInvalidSignature for %s, got %v", name, err)
+		}
+	}
+
+	// Unbound tokens do not verify with a binding either
+	plain, _ := signer.Sign(ctx, id)
+	if _, err := signer.VerifyBound(ctx, plain, client); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected an unbound token to fail a bound check, got %v", err)
+	}
+}
+
+func TestBindingBoundaries(t *testing.T) {
+	signer := NewSigner(newStaticKeys("k1", map[string]string{"k1": "secret"}))
+	ctx := context.Background()
+
+	token, _ := signer.SignBound(ctx, "id", Binding("ab"), Binding("c"))
+	if _, err := signer.VerifyBound(ctx, token, Binding("a"), Binding("bc")); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected shifted binding boundaries to fail, got %v", err)
+	}
+}
+
+func TestBindValueKinds(t *testing.T) {
+	if a, b := BindValue("a:b", "c"), BindValue("a", "b:c"); string(a) == string(b) {
+		t.Errorf("Expected kinds containing ':' to stay distinct, got %q for both", a)
+	}
+	if a, b := BindValue("device", "d-42"), BindValue("device", "d-42"); string(a) != string(b) {
+		t.Errorf("Expected equal bindings, got %q and %q", a, b)
+	}
+}
+
+func TestBindSubnet(t *testing.T) {
+	mapped := BindSubnet(netip.MustParseAddr("::ffff:203.0.113.17"), 24)
+	plain := BindSubnet(netip.MustParseAddr("203.0.113.99"), 24)
+	if string(mapped) != string(plain) || string(plain) != "net:203.0.113.0/24" {
+		t.Errorf("Expected both to bind to net:203.0.113.0/24, got %s and %s", mapped, plain)
+	}
+	if b := BindSubnet(netip.MustParseAddr("2001:db8::1"), 64); string(b) != "net:2001:db8::/64" {
+		t.Errorf("Expected net:2001:db8::/64, got %s", b)
+	}
+	if b := BindSubnet(netip.MustParseAddr("10.0.0.1"), 99); string(b) != "net:10.0.0.1/32" {
+		t.Errorf("Expected an invalid length to bind the whole address, got %s", b)
+	}
+}
+
+func TestBindCertificate(t *testing.T) {
+	a := BindCertificate(&x509.Certificate{Raw: []byte("cert-a")})
+	b := BindCertificate(&x509.Certificate{Raw: []byte("cert-b")})
+	if string(a) == string(b) {
+		t.Error("Expected different certificates to bind differently")
+	}
+	if len(a) != len("cert:")+32 {
+		t.Errorf("Expected a SHA-256 hash, got %d bytes", len(a))
+	}
+}
@@ -0,0 +1,207 @@
+package idforge
+
+// Levenshtein returns the number of single-character insertions, deletions
+// and substitutions needed to turn a into b, counting characters rather
+// than bytes
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// WithMinEditDistance rejects candidates fewer than distance edits away
+// from any remembered ID, treating them as collisions, so hand-typed codes
+// stay distinguishable. Each check searches a BK-tree of the remembered
+// IDs, which is slower than the exact check and may need a larger ID size
+// to avoid exhausting the attempts.
+func WithMinEditDistance(distance int) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		if distance > 0 {
+			c.MinEditDistance = distance
+		}
+	}
+}
+
+// NearDuplicate is a pair of IDs within a small edit distance
+type NearDuplicate struct {
+	A, B     string
+	Distance int
+}
+
+// NearDuplicates returns every pair of ids at most maxDistance edits apart,
+// for auditing human-entered codes such as coupons or invites that could
+// be mistaken for one another. Exact repeats are reported with distance 0.
+func NearDuplicates(ids []string, maxDistance int) []NearDuplicate {
+	var pairs []NearDuplicate
+	tree := NewBKTree()
+	for _, id := range ids {
+		for _, match := range tree.Search(id, maxDistance) {
+			pairs = append(pairs, NearDuplicate{A: match, B: id, Distance: Levenshtein(match, id)})
+		}
+		tree.Add(id)
+	}
+	return pairs
+}
+
+// BKTree indexes strings by Levenshtein distance, so finding everything
+// within a small distance of a query visits only a fraction of the entries.
+// It is not safe for concurrent use.
+type BKTree struct {
+	root *bkNode
+	live int
+	dead int // Removed entries still present as tombstones
+}
+
+type bkNode struct {
+	id       string
+	removed  bool
+	children map[int]*bkNode
+}
+
+// NewBKTree creates an empty tree
+func NewBKTree() *BKTree {
+	return &BKTree{}
+}
+
+// Len returns the number of strings in the tree
+func (t *BKTree) Len() int {
+	return t.live
+}
+
+// Add inserts id unless it is already present
+func (t *BKTree) Add(id string) {
+	if t.root == nil {
+		t.root = &bkNode{id: id}
+		t.live++
+		return
+	}
+
+	node := t.root
+	for {
+		d := Levenshtein(node.id, id)
+		if d == 0 {
+			if node.removed {
+				node.removed = false
+				t.dead--
+				t.live++
+			}
+			return
+		}
+		child, ok := node.children[d]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[int]*bkNode)
+			}
+			node.children[d] = &bkNode{id: id}
+			t.live++
+			return
+		}
+		node = child
+	}
+}
+
+// Remove deletes id. Removed entries stay in place as tombstones until they
+// outnumber the live ones, when the tree is rebuilt.
+func (t *BKTree) Remove(id string) {
+	node := t.root
+	for node != nil {
+		d := Levenshtein(node.id, id)
+		if d == 0 {
+			if !node.removed {
+				node.removed = true
+				t.live--
+				t.dead++
+			}
+			break
+		}
+		node = node.children[d]
+	}
+
+	if t.dead > t.live {
+		t.rebuild()
+	}
+}
+
+// Search returns the strings at most maxDistance edits from id
+func (t *BKTree) Search(id string, maxDistance int) []string {
+	var matches []string
+	t.walk(id, maxDistance, func(match string) bool {
+		matches = append(matches, match)
+		return true
+	})
+	return matches
+}
+
+// Has reports whether any string is at most maxDistance edits from id
+func (t *BKTree) Has(id string, maxDistance int) bool {
+	found := false
+	t.walk(id, maxDistance, func(string) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// walk calls visit for each match until it returns false. By the triangle
+// inequality only children whose edge lies within maxDistance of the
+// query's distance to their parent can hold matches.
+func (t *BKTree) walk(id string, maxDistance int, visit func(string) bool) {
+	if t.root == nil {
+		return
+	}
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		d := Levenshtein(node.id, id)
+		if d <= maxDistance && !node.removed && !visit(node.id) {
+			return
+		}
+		for edge, child := range node.children {
+			if edge >= d-maxDistance && edge <= d+maxDistance {
+				stack = append(stack, child)
+			}
+		}
+	}
+}
+
+// rebuild drops tombstones by reinserting the live entries
+func (t *BKTree) rebuild() {
+	var ids []string
+	stack := []*bkNode{}
+	if t.root != nil {
+		stack = append(stack, t.root)
+	}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !node.removed {
+			ids = append(ids, node.id)
+		}
+		for _, child := range node.children {
+			stack = append(stack, child)
+		}
+	}
+
+	*t = BKTree{}
+	for _, id := range ids {
+		t.Add(id)
+	}
+}
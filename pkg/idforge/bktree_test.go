@@ -0,0 +1,134 @@
+package idforge
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"ABCD12", "ABDC12", 2},
+		{"жук", "жуки", 1},
+	}
+	for _, c := range cases {
+		if got := Levenshtein(c.a, c.b); got != c.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, expected %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestBKTreeSearch(t *testing.T) {
+	tree := NewBKTree()
+	words := []string{"book", "books", "cake", "boo", "cape", "cart", "boon", "cook"}
+	for _, w := range words {
+		tree.Add(w)
+	}
+	tree.Add("book")
+	if tree.Len() != len(words) {
+		t.Errorf("Expected %d entries, got %d", len(words), tree.Len())
+	}
+
+	got := tree.Search("bok", 1)
+	sort.Strings(got)
+	want := []string{"boo", "book"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// Compare against a linear scan
+	for _, query := range []string{"cak", "bookz", "xyz", "coke"} {
+		for distance := 0; distance <= 2; distance++ {
+			expected := 0
+			for _, w := range words {
+				if Levenshtein(w, query) <= distance {
+					expected++
+				}
+			}
+			if n := len(tree.Search(query, distance)); n != expected {
+				t.Errorf("Search(%q, %d) found %d, expected %d", query, distance, n, expected)
+			}
+		}
+	}
+}
+
+func TestBKTreeRemove(t *testing.T) {
+	tree := NewBKTree()
+	for _, w := range []string{"aaaa", "aaab", "aabb", "abbb", "bbbb"} {
+		tree.Add(w)
+	}
+
+	tree.Remove("aaab")
+	tree.Remove("missing")
+	if tree.Has("aaab", 0) || tree.Len() != 4 {
+		t.Errorf("Expected aaab to be removed, %d entries left", tree.Len())
+	}
+
+	// Removing most entries triggers a rebuild that must keep the rest
+	tree.Remove("aaaa")
+	tree.Remove("aabb")
+	if tree.Len() != 2 || !tree.Has("abbb", 0) || !tree.Has("bbbb", 0) {
+		t.Errorf("Expected abbb and bbbb to remain, got %v", tree.Search("bbbb", 4))
+	}
+
+	tree.Add("aaab")
+	if !tree.Has("aaab", 0) {
+		t.Error("Expected aaab to be added back")
+	}
+}
+
+func TestNearDuplicates(t *testing.T) {
+	pairs := NearDuplicates([]string{"SAVE20", "SAVE2O", "FREESHIP", "SAVE20"}, 1)
+	if len(pairs) != 3 {
+		t.Fatalf("Expected 3 pairs, got %v", pairs)
+	}
+	if pairs[0] != (NearDuplicate{A: "SAVE20", B: "SAVE2O", Distance: 1}) {
+		t.Errorf("Unexpected first pair %+v", pairs[0])
+	}
+}
+
+func TestWithMinEditDistance(t *testing.T) {
+	gen := NewExtendedGenerator(
+		WithCustomAlphabet("0123456789"),
+		WithMinEditDistance(3),
+		func(c *GeneratorConfig) { c.Size = 6 },
+	)
+
+	var ids []string
+	for i := 0; i < 200; i++ {
+		id, err := gen.Generate(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if pairs := NearDuplicates(ids, 2); len(pairs) != 0 {
+		t.Errorf("Expected every pair at least 3 edits apart, got %v", pairs[0])
+	}
+	if gen.issued.near.Len() != len(ids) {
+		t.Errorf("Expected %d indexed IDs, got %d", len(ids), gen.issued.near.Len())
+	}
+}
+
+func TestIssuedSetNearTracksEviction(t *testing.T) {
+	s := newIssuedSet(2, 0)
+	s.trackNear(true)
+	now := time.Now()
+	s.add("aaaa", now)
+	s.add("bbbb", now)
+	s.add("cccc", now)
+
+	if s.nearby("aaab", 1, now) {
+		t.Error("Expected evicted aaaa to be forgotten")
+	}
+	if !s.nearby("bbbc", 1, now) {
+		t.Error("Expected bbbb to be found")
+	}
+}
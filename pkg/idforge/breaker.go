@@ -0,0 +1,100 @@
+package idforge
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrProviderTimeout = errors.New("entropy provider timed out")
+
+// BreakerState describes whether an entropy provider is being queried
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // Provider is queried normally
+	BreakerOpen                         // Provider is skipped until the cooldown elapses
+	BreakerHalfOpen                     // One trial query decides whether to close again
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// WithProviderTimeout bounds how long each entropy provider may take
+func WithProviderTimeout(timeout time.Duration) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		if timeout > 0 {
+			c.ProviderTimeout = timeout
+		}
+	}
+}
+
+// WithCircuitBreaker skips a provider for cooldown after threshold
+// consecutive failures. Failures are still returned to the caller until
+// the breaker opens.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		if threshold > 0 && cooldown > 0 {
+			c.BreakerThreshold = threshold
+			c.BreakerCooldown = cooldown
+		}
+	}
+}
+
+// breaker tracks consecutive failures of one provider
+type breaker struct {
+	state               BreakerState
+	consecutiveFailures int
+	failures            uint64
+	openedAt            time.Time
+}
+
+// allow reports whether the provider should be queried now
+func (b *breaker) allow(now time.Time, cooldown time.Duration) bool {
+	if b.state != BreakerOpen {
+		return true
+	}
+	if now.Sub(b.openedAt) >= cooldown {
+		b.state = BreakerHalfOpen
+		return true
+	}
+	return false
+}
+
+// success closes the breaker
+func (b *breaker) success() {
+	b.state = BreakerClosed
+	b.consecutiveFailures = 0
+}
+
+// failure records a failed query and opens the breaker when warranted
+func (b *breaker) failure(now time.Time, threshold int) {
+	b.consecutiveFailures++
+	b.failures++
+
+	if threshold <= 0 {
+		return
+	}
+	if b.state == BreakerHalfOpen || b.consecutiveFailures >= threshold {
+		b.state = BreakerOpen
+		b.openedAt = now
+	}
+}
+
+// currentState reports the state as a caller would observe it now,
+// treating open breakers whose cooldown elapsed as ready for a trial
+func (b *breaker) currentState(now time.Time, cooldown time.Duration) BreakerState {
+	if b.state == BreakerOpen && now.Sub(b.openedAt) >= cooldown {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
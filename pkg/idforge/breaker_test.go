@@ -0,0 +1,108 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+// flakyEntropy fails while failing is set and counts its invocations
+type flakyEntropy struct {
+	failing atomic.Bool
+	calls   atomic.Int32
+}
+
+func (f *flakyEntropy) Provide(ctx context.Context) (string, error) {
+	f.calls.Add(1)
+	if f.failing.Load() {
+		return "", errors.New("provider unavailable")
+	}
+	return "flaky", nil
+}
+
+func TestProviderTimeout(t *testing.T) {
+	gen := NewExtendedGenerator(
+		WithEntropyProviders([]entropy.EntropyProvider{&slowEntropy{delay: time.Second}}),
+		WithProviderTimeout(20*time.Millisecond),
+	)
+
+	start := time.Now()
+	_, err := gen.Generate(context.Background())
+
+	if !errors.Is(err, ErrProviderTimeout) {
+		t.Errorf("Expected ErrProviderTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("Provider timeout took %v to trigger", elapsed)
+	}
+}
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	flaky := &flakyEntropy{}
+	flaky.failing.Store(true)
+
+	gen := NewExtendedGenerator(
+		WithEntropyProviders([]entropy.EntropyProvider{&entropy.TimestampEntropy{}, flaky}),
+		WithCircuitBreaker(2, 50*time.Millisecond),
+	)
+	ctx := context.Background()
+
+	// Failures are reported until the breaker opens
+	for i := 0; i < 2; i++ {
+		if _, err := gen.Generate(ctx); err == nil {
+			t.Fatalf("Expected failure %d to be returned", i+1)
+		}
+	}
+
+	stats := gen.Stats()
+	if stats.Providers[1].State != BreakerOpen {
+		t.Fatalf("Expected breaker to be open, got %v", stats.Providers[1].State)
+	}
+	if stats.Providers[1].Failures != 2 {
+		t.Errorf("Expected 2 recorded failures, got %d", stats.Providers[1].Failures)
+	}
+	if stats.Providers[0].State != BreakerClosed {
+		t.Errorf("Expected healthy provider to stay closed, got %v", stats.Providers[0].State)
+	}
+
+	// While open the provider is skipped entirely
+	calls := flaky.calls.Load()
+	if _, err := gen.Generate(ctx); err != nil {
+		t.Fatalf("Expected generation to skip the open provider, got %v", err)
+	}
+	if flaky.calls.Load() != calls {
+		t.Errorf("Expected open breaker to skip the provider")
+	}
+
+	// After the cooldown a successful trial closes the breaker again
+	flaky.failing.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	if gen.Stats().Providers[1].State != BreakerHalfOpen {
+		t.Errorf("Expected breaker to report half-open after the cooldown")
+	}
+	if _, err := gen.Generate(ctx); err != nil {
+		t.Fatalf("Unexpected error after recovery: %v", err)
+	}
+
+	stats = gen.Stats()
+	if stats.Providers[1].State != BreakerClosed || stats.Providers[1].ConsecutiveFailures != 0 {
+		t.Errorf("Expected breaker to close after a successful trial, got %+v", stats.Providers[1])
+	}
+}
+
+func TestBreakerStateString(t *testing.T) {
+	states := map[BreakerState]string{
+		BreakerClosed:   "closed",
+		BreakerOpen:     "open",
+		BreakerHalfOpen: "half-open",
+	}
+	for state, want := range states {
+		if state.String() != want {
+			t.Errorf("Expected %q, got %q", want, state.String())
+		}
+	}
+}
@@ -0,0 +1,400 @@
+package idforge
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// bulkStripes is the number of independently locked shards in the
+	// batch dedupe set
+	bulkStripes = 64
+
+	// progressBatch is how many IDs a worker draws between progress updates
+	progressBatch = 256
+)
+
+// BulkOption configures GenerateBulk
+type BulkOption func(*bulkConfig)
+
+type bulkConfig struct {
+	preserveOrder    bool
+	disk             bool
+	diskDir          string
+	progress         ProgressFunc
+	progressInterval time.Duration
+}
+
+// WithPreserveOrder issues the IDs, recording and auditing them, in the
+// order GenerateBulk returns them. Without it each worker's share is
+// issued as soon as it is ready, which overlaps issuing with generation.
+func WithPreserveOrder() BulkOption {
+	return func(c *bulkConfig) {
+		c.preserveOrder = true
+	}
+}
+
+// WithProgress calls fn every interval, one second if interval is not
+// positive, with the number of IDs generated so far, and once more when
+// GenerateBulk finishes
+func WithProgress(fn ProgressFunc, interval time.Duration) BulkOption {
+	return func(c *bulkConfig) {
+		c.progress = fn
+		c.progressInterval = interval
+	}
+}
+
+// GenerateBulk generates n unique IDs on workers goroutines, or GOMAXPROCS
+// when workers is not positive. Each worker mixes one round of provider
+// entropy into all of its IDs instead of querying the providers per ID,
+// and candidates are deduplicated within the batch through a lock-striped
+// set, or on disk with WithDiskDedupe, before being checked against issued
+// IDs and the uniqueness store.
+// The batch is bounded by ctx rather than MaxGenerationTime. The rate limit
+// and Auditor apply to every ID; hooks do not run. On error, IDs issued
+// before the failure stay recorded as issued.
+func (g *ExtendedGenerator) GenerateBulk(ctx context.Context, n, workers int, opts ...BulkOption) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, n)
+
+	var bc bulkConfig
+	for _, opt := range opts {
+		opt(&bc)
+	}
+
+	var seen dedupeSet = newStripedSet(n)
+	if bc.disk {
+		set, err := newDiskSet(bc.diskDir, n)
+		if err != nil {
+			return nil, err
+		}
+		seen = set
+	}
+	defer seen.close()
+
+	start := time.Now()
+	g.mu.Lock()
+	if err := g.config.validate(); err != nil {
+		g.mu.Unlock()
+		return nil, err
+	}
+	if g.config.DRBG {
+		if err := g.ensureDRBG(ctx); err != nil {
+			g.mu.Unlock()
+			return nil, err
+		}
+	}
+	if g.symbols == nil {
+		s := weightedSymbols(g.config)
+		g.symbols = &s
+	}
+	job := &bulkJob{
+		g:        g,
+		cfg:      g.config,
+		alphabet: *g.symbols,
+		limiter:  g.limiter,
+		source:   g.bulkSource(),
+		seen:     seen,
+	}
+	g.mu.Unlock()
+
+	charge, err := job.cfg.Quota.charge(ctx, n)
+	if err != nil {
+		g.stats.failures.Add(1)
+		return nil, err
+	}
+
+	job.progress = startProgress(bc.progress, n, bc.progressInterval)
+	defer job.progress.finish()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg     sync.WaitGroup
+		issue  sync.Mutex // Serializes issuing shares
+		issued = make([]string, 0, n)
+		shares = make([][]string, workers)
+		errs   = make([]error, workers)
+	)
+	for w := 0; w < workers; w++ {
+		count := (w+1)*n/workers - w*n/workers
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			share, err := job.draw(ctx, count)
+			if err == nil && !bc.preserveOrder {
+				issue.Lock()
+				issued, err = job.issue(ctx, share, issued)
+				issue.Unlock()
+			}
+			if err != nil {
+				errs[w] = err
+				cancel()
+				return
+			}
+			shares[w] = share
+		}(w)
+	}
+	wg.Wait()
+
+	err = firstBulkError(errs)
+	if err == nil && bc.preserveOrder {
+		for _, share := range shares {
+			if issued, err = job.issue(ctx, share, issued); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		charge.refund(ctx, n-len(issued))
+		g.stats.failures.Add(1)
+		return nil, err
+	}
+	g.stats.recordBulk(len(issued), time.Since(start))
+	job.cfg.Issuance.Record(job.cfg.IssuanceNamespace, len(issued))
+	return issued, nil
+}
+
+// bulkSource returns a random source safe for concurrent use by workers;
+// the caller must hold g.mu
+func (g *ExtendedGenerator) bulkSource() io.Reader {
+	if g.drbg != nil {
+		return g.drbg
+	}
+	if g.config.RNG != nil {
+		return &lockedReader{r: g.config.RNG}
+	}
+	return rand.Reader
+}
+
+// firstBulkError picks the error that stopped a batch, preferring the
+// cause over the cancellations it triggered in other workers
+func firstBulkError(errs []error) error {
+	var first error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, context.Canceled) {
+			return err
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// bulkJob is the state shared by the workers of one GenerateBulk call
+type bulkJob struct {
+	g        *ExtendedGenerator
+	cfg      GeneratorConfig
+	alphabet symbols
+	limiter  *rateLimiter
+	source   io.Reader
+	seen     dedupeSet
+	progress *progressTracker
+}
+
+// draw generates count IDs that are unique within the batch
+func (j *bulkJob) draw(ctx context.Context, count int) ([]string, error) {
+	seed, err := j.seed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAttempts := calculateMaxAttempts(j.cfg.effectiveAlphabetSize(), j.cfg.Size, j.cfg.UniquenessPressure)
+	ids := make([]string, 0, count)
+	rejected, attempts := ErrCollision, 0
+	for len(ids) < count {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %w", ErrGenerationTimeout, ctx.Err())
+		}
+		if attempts == maxAttempts {
+			return nil, fmt.Errorf("%w: %w after %d attempts", ErrGenerationTimeout, rejected, maxAttempts)
+		}
+		attempts++
+
+		id, err := buildID(j.source, j.alphabet, j.cfg.Size, seed, nil)
+		if err != nil {
+			return nil, err
+		}
+		if j.cfg.FormatVersion != 0 {
+			id = string(j.cfg.FormatVersion) + id
+		}
+		if j.cfg.WordFilter != nil && j.cfg.WordFilter.Contains(id) {
+			rejected = ErrForbiddenWord
+			continue
+		}
+		added, err := j.seen.add(id)
+		if err != nil {
+			return nil, err
+		}
+		if !added {
+			rejected = ErrCollision
+			j.g.stats.collisions.Add(1)
+			if j.cfg.OnCollision != nil {
+				j.cfg.OnCollision(id, attempts)
+			}
+			if j.cfg.CollisionStrategy == CollisionFail {
+				return nil, ErrCollision
+			}
+			continue
+		}
+
+		if err := j.throttle(ctx); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+		attempts = 0
+		if len(ids)%progressBatch == 0 {
+			j.progress.add(progressBatch)
+		}
+	}
+	j.progress.add(len(ids) % progressBatch)
+	return ids, nil
+}
+
+// seed collects one round of provider entropy for a worker, unless the
+// configuration draws from a DRBG, an injected RNG or FIPS mode
+func (j *bulkJob) seed(ctx context.Context) ([]byte, error) {
+	if j.cfg.DRBG || j.cfg.RNG != nil || j.cfg.FIPSMode {
+		return nil, nil
+	}
+	j.g.mu.Lock()
+	parts, _, err := j.g.collectEntropy(ctx)
+	j.g.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strings.Join(parts, "")), nil
+}
+
+// throttle applies the rate limit captured when the batch started
+func (j *bulkJob) throttle(ctx context.Context) error {
+	if j.limiter == nil {
+		return nil
+	}
+	if j.cfg.RateLimitWait {
+		return j.limiter.wait(ctx)
+	}
+	if !j.limiter.allow() {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// issue records share as issued and appends it to out. IDs that collide
+// with previously issued ones are replaced by the single-ID path.
+func (j *bulkJob) issue(ctx context.Context, share, out []string) ([]string, error) {
+	g := j.g
+	var replace []int
+	now := j.cfg.now()
+
+	g.mu.Lock()
+	for i, id := range share {
+		ok, err := g.admit(ctx, id, now)
+		if err != nil {
+			g.mu.Unlock()
+			return out, err
+		}
+		if !ok {
+			replace = append(replace, i)
+		}
+	}
+	g.mu.Unlock()
+
+	for _, i := range replace {
+		g.stats.collisions.Add(1)
+		if j.cfg.OnCollision != nil {
+			j.cfg.OnCollision(share[i], 1)
+		}
+		if j.cfg.CollisionStrategy == CollisionFail {
+			return out, ErrCollision
+		}
+		for {
+			result, _, err := g.generate(ctx)
+			if err != nil {
+				return out, err
+			}
+			added, err := j.seen.add(result.ID)
+			if err != nil {
+				return out, err
+			}
+			if added {
+				share[i] = result.ID
+				break
+			}
+		}
+	}
+
+	if j.cfg.Auditor != nil {
+		for _, id := range share {
+			if err := audit(ctx, j.cfg.Auditor, j.cfg.Name, id); err != nil {
+				return out, err
+			}
+		}
+	}
+	return append(out, share...), nil
+}
+
+// lockedReader serializes reads from a reader that is not safe for
+// concurrent use
+type lockedReader struct {
+	mu sync.Mutex
+	r  io.Reader
+}
+
+func (l *lockedReader) Read(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Read(p)
+}
+
+// stripedSet is a set of IDs split into independently locked stripes, so
+// workers adding different IDs rarely wait for each other
+type stripedSet struct {
+	seed    maphash.Seed
+	stripes [bulkStripes]struct {
+		mu  sync.Mutex
+		ids map[string]struct{}
+	}
+}
+
+func newStripedSet(capacity int) *stripedSet {
+	s := &stripedSet{seed: maphash.MakeSeed()}
+	for i := range s.stripes {
+		s.stripes[i].ids = make(map[string]struct{}, capacity/bulkStripes+1)
+	}
+	return s
+}
+
+func (s *stripedSet) add(id string) (bool, error) {
+	stripe := &s.stripes[maphash.String(s.seed, id)%bulkStripes]
+	stripe.mu.Lock()
+	defer stripe.mu.Unlock()
+
+	if _, ok := stripe.ids[id]; ok {
+		return false, nil
+	}
+	stripe.ids[id] = struct{}{}
+	return true, nil
+}
+
+func (s *stripedSet) close() error {
+	return nil
+}
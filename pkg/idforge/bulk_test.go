@@ -0,0 +1,145 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingAuditor remembers audited IDs in order
+type recordingAuditor struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (a *recordingAuditor) Audit(ctx context.Context, event AuditEvent) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.ids = append(a.ids, event.ID)
+	return nil
+}
+
+func TestGenerateBulk(t *testing.T) {
+	gen := NewExtendedGenerator()
+	ids, err := gen.GenerateBulk(context.Background(), 10000, 8)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ids) != 10000 {
+		t.Fatalf("Expected 10000 IDs, got %d", len(ids))
+	}
+
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("Duplicate ID %s", id)
+		}
+		seen[id] = true
+		if err := CheckID(id, DefaultAlphabet, DefaultSize); err != nil {
+			t.Fatalf("Expected a valid ID, got %v", err)
+		}
+	}
+	if stats := gen.Stats(); stats.Generated != 10000 {
+		t.Errorf("Expected 10000 generated IDs in stats, got %d", stats.Generated)
+	}
+}
+
+func TestGenerateBulkPreserveOrder(t *testing.T) {
+	auditor := &recordingAuditor{}
+	gen := NewExtendedGenerator(WithAuditor(auditor), WithFormatVersion('v'))
+
+	ids, err := gen.GenerateBulk(context.Background(), 500, 4, WithPreserveOrder())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(auditor.ids, ids) {
+		t.Error("Expected IDs to be audited in the returned order")
+	}
+	for _, id := range ids {
+		if id[0] != 'v' {
+			t.Fatalf("Expected the format marker on %q", id)
+		}
+	}
+}
+
+func TestGenerateBulkAvoidsIssuedIDs(t *testing.T) {
+	gen := NewExtendedGenerator(WithCustomAlphabet("0123456789"), func(c *GeneratorConfig) {
+		c.Size = 2
+	})
+	now := time.Now()
+	for i := 0; i < 50; i++ {
+		gen.issued.add(fmt.Sprintf("%02d", i), now)
+	}
+
+	ids, err := gen.GenerateBulk(context.Background(), 20, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if id < "50" || seen[id] {
+			t.Errorf("Expected a fresh ID, got %s", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestGenerateBulkKeyspaceExhausted(t *testing.T) {
+	gen := NewExtendedGenerator(WithCustomAlphabet("ab"), func(c *GeneratorConfig) {
+		c.Size = 2
+	})
+	_, err := gen.GenerateBulk(context.Background(), 5, 1)
+	if !errors.Is(err, ErrCollision) || !errors.Is(err, ErrGenerationTimeout) {
+		t.Errorf("Expected ErrCollision wrapped in ErrGenerationTimeout, got %v", err)
+	}
+	if stats := gen.Stats(); stats.Failures != 1 {
+		t.Errorf("Expected 1 failure in stats, got %d", stats.Failures)
+	}
+}
+
+func TestGenerateBulkCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewExtendedGenerator().GenerateBulk(ctx, 1000, 4)
+	if !errors.Is(err, ErrGenerationTimeout) {
+		t.Errorf("Expected ErrGenerationTimeout, got %v", err)
+	}
+}
+
+func TestGenerateBulkSerializesInjectedRNG(t *testing.T) {
+	// A ChaCha8 stream is not safe for concurrent use
+	gen := NewExtendedGenerator(WithRNG(NewRNG(rand.NewChaCha8([32]byte{}))))
+
+	ids, err := gen.GenerateBulk(context.Background(), 200, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ids) != 200 {
+		t.Errorf("Expected 200 IDs, got %d", len(ids))
+	}
+}
+
+func TestGenerateBulkEmpty(t *testing.T) {
+	ids, err := NewExtendedGenerator().GenerateBulk(context.Background(), 0, 4)
+	if err != nil || ids != nil {
+		t.Errorf("Expected no IDs and no error, got %v, %v", ids, err)
+	}
+}
+
+func BenchmarkGenerateBulk(b *testing.B) {
+	gen := NewExtendedGenerator()
+	ctx := context.Background()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := gen.GenerateBulk(ctx, 100000, 0); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
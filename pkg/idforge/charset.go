@@ -0,0 +1,87 @@
+package idforge
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// charset is an alphabet compiled for membership tests: a bitmap for ASCII
+// characters and a set for the rest, so checking a character takes
+// constant time however large the alphabet is
+type charset struct {
+	ascii [2]uint64
+	other map[rune]struct{}
+}
+
+// newCharset compiles alphabet, which must be valid UTF-8
+func newCharset(alphabet string) *charset {
+	c := &charset{}
+	for _, char := range alphabet {
+		if char < utf8.RuneSelf {
+			c.ascii[char>>6] |= 1 << (char & 63)
+			continue
+		}
+		if c.other == nil {
+			c.other = make(map[rune]struct{})
+		}
+		c.other[char] = struct{}{}
+	}
+	return c
+}
+
+// contains reports whether char is in the alphabet
+func (c *charset) contains(char rune) bool {
+	if char < utf8.RuneSelf {
+		return c.ascii[char>>6]&(1<<(char&63)) != 0
+	}
+	_, ok := c.other[char]
+	return ok
+}
+
+// check validates id against the alphabet and size in a single pass,
+// reporting the same violations as CheckID. Valid IDs do not allocate.
+func (c *charset) check(id string, size int) error {
+	length, invalid, first := 0, 0, -1
+	validUTF8 := true
+	for i := 0; i < len(id); length++ {
+		char, n := rune(id[i]), 1
+		if char >= utf8.RuneSelf {
+			char, n = utf8.DecodeRuneInString(id[i:])
+			if char == utf8.RuneError && n == 1 {
+				validUTF8 = false
+			}
+		}
+		if !c.contains(char) {
+			if first < 0 {
+				first = i
+			}
+			invalid++
+		}
+		i += n
+	}
+	if length == size && invalid == 0 && validUTF8 {
+		return nil
+	}
+
+	var violations []Violation
+	if length != size {
+		violations = append(violations, Violation{
+			Rule:    RuleLength,
+			Message: fmt.Sprintf("length is %d, expected %d", length, size),
+			Args:    []any{length, size},
+		})
+	}
+	if !validUTF8 {
+		violations = append(violations, Violation{
+			Rule:    RuleEncoding,
+			Message: "not valid UTF-8",
+		})
+	} else if invalid > 0 {
+		violations = append(violations, Violation{
+			Rule:    RuleAlphabet,
+			Message: fmt.Sprintf("%d characters outside the alphabet, first at byte %d", invalid, first),
+			Args:    []any{invalid, first},
+		})
+	}
+	return &ValidationError{ID: id, violations: violations}
+}
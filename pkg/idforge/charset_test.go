@@ -0,0 +1,103 @@
+package idforge
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCharsetContains(t *testing.T) {
+	c := newCharset("a?Zé日")
+	for _, char := range "a?Zé日" {
+		if !c.contains(char) {
+			t.Errorf("Expected %q to be in the charset", char)
+		}
+	}
+	for _, char := range "bA\x00\x7fe月" {
+		if c.contains(char) {
+			t.Errorf("Expected %q not to be in the charset", char)
+		}
+	}
+}
+
+func TestCharsetCheck(t *testing.T) {
+	c := newCharset("abcé")
+
+	tests := []struct {
+		id   string
+		want []Violation
+	}{
+		{"abé", nil},
+		{"ab", []Violation{{Rule: RuleLength, Message: "length is 2, expected 3", Args: []any{2, 3}}}},
+		{"aéx", []Violation{{Rule: RuleAlphabet, Message: "1 characters outside the alphabet, first at byte 3", Args: []any{1, 3}}}},
+		{"a\xffb", []Violation{{Rule: RuleEncoding, Message: "not valid UTF-8"}}},
+		{"a\xff\xffb", []Violation{
+			{Rule: RuleLength, Message: "length is 4, expected 3", Args: []any{4, 3}},
+			{Rule: RuleEncoding, Message: "not valid UTF-8"},
+		}},
+	}
+
+	for _, tt := range tests {
+		err := c.check(tt.id, 3)
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("Expected %q to pass, got %v", tt.id, err)
+			}
+			continue
+		}
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("Expected a *ValidationError for %q, got %v", tt.id, err)
+		}
+		if got := verr.Violations(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Expected violations %+v for %q, got %+v", tt.want, tt.id, got)
+		}
+	}
+}
+
+func TestCharsetCheckReplacementCharacter(t *testing.T) {
+	c := newCharset("ab\uFFFD")
+	if err := c.check("a\uFFFD", 2); err != nil {
+		t.Errorf("Expected an encoded U+FFFD to pass, got %v", err)
+	}
+	if err := c.check("a\xff", 2); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected invalid UTF-8 to fail even though U+FFFD is in the alphabet, got %v", err)
+	}
+}
+
+func TestGeneratorValidateDoesNotAllocate(t *testing.T) {
+	gen := New()
+	id := gen.MustGenerate()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if !gen.Validate(id) {
+			t.Fatalf("Expected %s to be valid", id)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Expected validation not to allocate, got %v allocations", allocs)
+	}
+}
+
+func BenchmarkGeneratorValidate(b *testing.B) {
+	gen := New()
+	id := gen.MustGenerate()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if !gen.Validate(id) {
+			b.Fatalf("Expected %s to be valid", id)
+		}
+	}
+}
+
+func BenchmarkCheckID(b *testing.B) {
+	id := New().MustGenerate()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := CheckID(id, DefaultAlphabet, DefaultSize); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package idforge
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells time-based generators the current time. Tests pass a
+// FakeClock to freeze or step time and check sort order and rollover.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock reads the system clock, as time-based generators do by
+// default
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FakeClock is a Clock that only moves when told to. It is safe for
+// concurrent use.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock creates a clock frozen at t
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: t}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to t, which may lie in the past
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Advance moves the clock by d, backwards when d is negative
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// MemoryOption configures an in-memory store
+type MemoryOption func(*memoryOptions)
+
+type memoryOptions struct {
+	clock Clock
+}
+
+// WithMemoryClock makes an in-memory store expire entries by clock instead
+// of the system clock, typically the clock of the component using it
+func WithMemoryClock(clock Clock) MemoryOption {
+	return func(o *memoryOptions) {
+		if clock != nil {
+			o.clock = clock
+		}
+	}
+}
+
+// memoryClock returns the clock selected by opts
+func memoryClock(opts []MemoryOption) Clock {
+	o := memoryOptions{clock: SystemClock}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o.clock
+}
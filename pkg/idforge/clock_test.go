@@ -0,0 +1,216 @@
+package idforge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	if !clock.Now().Equal(start) {
+		t.Errorf("Expected %v, got %v", start, clock.Now())
+	}
+	clock.Advance(time.Minute)
+	if want := start.Add(time.Minute); !clock.Now().Equal(want) {
+		t.Errorf("Expected %v, got %v", want, clock.Now())
+	}
+	clock.Set(start.Add(-time.Hour))
+	if want := start.Add(-time.Hour); !clock.Now().Equal(want) {
+		t.Errorf("Expected %v, got %v", want, clock.Now())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clock.Advance(time.Second)
+			clock.Now()
+		}()
+	}
+	wg.Wait()
+	if want := start.Add(-time.Hour + 4*time.Second); !clock.Now().Equal(want) {
+		t.Errorf("Expected %v, got %v", want, clock.Now())
+	}
+
+	if d := time.Since(SystemClock.Now()); d < 0 || d > time.Minute {
+		t.Errorf("Expected SystemClock to read the system time, off by %v", d)
+	}
+}
+
+func TestLayoutClockSortsAndRollsOver(t *testing.T) {
+	clock := NewFakeClock(time.Unix(1700000009, 0))
+	gen, err := NewLayout().
+		Timestamp("0123456789", 1, time.Second).
+		Random("0123456789ABCDEFGHJKMNPQRSTVWXYZ", 8).
+		Build(WithMonotonic(), WithLayoutClock(clock))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Frozen within one tick, monotonic IDs still sort in order
+	prev, _ := gen.Generate()
+	for i := 0; i < 100; i++ {
+		id, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if id <= prev || id[0] != '9' {
+			t.Fatalf("Expected %s to follow %s in tick 9", id, prev)
+		}
+		prev = id
+	}
+
+	// One second on, the single-digit timestamp wraps to 0
+	clock.Advance(time.Second)
+	id, _ := gen.Generate()
+	if id[0] != '0' {
+		t.Errorf("Expected the timestamp to roll over to 0, got %s", id)
+	}
+	parsed, err := gen.Parse(id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed.Time.Unix()%10 != 0 {
+		t.Errorf("Expected a time on a 10-second boundary, got %v", parsed.Time)
+	}
+}
+
+func TestObjectIDClock(t *testing.T) {
+	clock := NewFakeClock(time.Unix(1700000000, 0))
+	gen, err := NewObjectIDGenerator(WithObjectIDClock(clock))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	a := gen.NewObjectID()
+	clock.Advance(time.Hour)
+	b := gen.NewObjectID()
+	if !a.Timestamp().Equal(time.Unix(1700000000, 0)) || b.Timestamp().Sub(a.Timestamp()) != time.Hour {
+		t.Errorf("Expected timestamps an hour apart, got %v and %v", a.Timestamp(), b.Timestamp())
+	}
+	if b.Hex() <= a.Hex() {
+		t.Errorf("Expected %s to sort after %s", b.Hex(), a.Hex())
+	}
+}
+
+func TestExtendedGeneratorClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	gen := NewExtendedGenerator(WithClock(clock), WithDRBG(time.Minute), func(c *GeneratorConfig) {
+		c.UniqueIDRetention = time.Hour
+	})
+	ctx := context.Background()
+
+	result, err := gen.GenerateWithInfo(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.GeneratedAt.Equal(start) {
+		t.Errorf("Expected GeneratedAt %v, got %v", start, result.GeneratedAt)
+	}
+	if !gen.seededAt.Equal(start) {
+		t.Errorf("Expected the DRBG seeded at %v, got %v", start, gen.seededAt)
+	}
+
+	// Past the reseed interval and the retention window
+	clock.Advance(2 * time.Hour)
+	if _, err := gen.Generate(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := start.Add(2 * time.Hour); !gen.seededAt.Equal(want) {
+		t.Errorf("Expected a reseed at %v, got %v", want, gen.seededAt)
+	}
+	var state bytes.Buffer
+	if err := gen.ExportState(&state); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bytes.Contains(state.Bytes(), []byte(result.ID)) {
+		t.Errorf("Expected %s to be forgotten after the retention window", result.ID)
+	}
+}
+
+func TestOneTimeTokenClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	svc := NewOneTimeTokenService("reset", nil, time.Hour, WithOneTimeTokenClock(clock))
+	ctx := context.Background()
+
+	token, err := svc.Issue(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	clock.Advance(59 * time.Minute)
+	if result, _ := svc.Peek(ctx, token); result.Status != TokenValid {
+		t.Errorf("Expected TokenValid before expiry, got %v", result.Status)
+	}
+	clock.Advance(time.Minute)
+	if result, _ := svc.Verify(ctx, token); result.Status != TokenExpired {
+		t.Errorf("Expected TokenExpired at expiry, got %v", result.Status)
+	}
+}
+
+func TestQuotaClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC))
+	m := NewQuotaManager(NewMemoryQuotaCounter(WithMemoryClock(clock)), WithQuotaClock(clock))
+	m.SetDefaultQuota(Quota{Period: QuotaDaily, Limit: 1})
+	ctx := context.Background()
+
+	if err := m.Consume(ctx, "acme", 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := m.Consume(ctx, "acme", 1); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected ErrQuotaExceeded within the day, got %v", err)
+	}
+	usage, _ := m.Usage(ctx, "acme")
+	if want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC); len(usage) != 1 || !usage[0].ResetsAt.Equal(want) {
+		t.Errorf("Expected the quota to reset at %v, got %+v", want, usage)
+	}
+
+	clock.Advance(14 * time.Hour)
+	if err := m.Consume(ctx, "acme", 1); err != nil {
+		t.Errorf("Expected a fresh quota the next day, got %v", err)
+	}
+}
+
+func TestSequencerClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	lock := NewMemoryLeaderLock(WithMemoryClock(clock))
+	store := NewMemorySequenceStore()
+	a := NewSequencer("a", lock, store, WithSequencerLease(time.Minute), WithSequencerClock(clock))
+	b := NewSequencer("b", lock, store, WithSequencerLease(time.Minute), WithSequencerClock(clock))
+	ctx := context.Background()
+
+	if _, err := a.AllocateBlock(ctx, 10); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := b.AllocateBlock(ctx, 10); !errors.Is(err, ErrNotLeader) {
+		t.Errorf("Expected ErrNotLeader while a holds the lease, got %v", err)
+	}
+
+	clock.Advance(time.Minute)
+	if a.Leader() {
+		t.Error("Expected a's lease to lapse")
+	}
+	if _, err := b.AllocateBlock(ctx, 10); err != nil {
+		t.Errorf("Expected b to take over after the lease lapsed, got %v", err)
+	}
+}
+
+func TestIssuanceClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	c := NewIssuanceCollector(time.Hour, 24*time.Hour, WithIssuanceClock(clock))
+
+	c.Record("orders", 2)
+	clock.Advance(time.Minute)
+	c.Record("orders", 3)
+
+	series := c.Series("orders", PerMinute, start, start.Add(time.Minute))
+	if len(series) != 2 || series[0].Count != 2 || series[1].Count != 3 {
+		t.Errorf("Expected counts 2 and 3 in consecutive minutes, got %+v", series)
+	}
+}
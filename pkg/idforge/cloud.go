@@ -0,0 +1,29 @@
+//go:build !idforge_lite
+
+package idforge
+
+import (
+	"context"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+// CloudNodeID derives a worker ID in [0, 2^bits) for snowflake-style
+// generators from the instance ID that source's metadata service reports;
+// a nil source queries every cloud with the default timeout. The ID stays
+// the same for the instance's lifetime, but, being a hash, two instances
+// may share one, so keep bits well above log2 of the fleet size. Off the
+// cloud it returns entropy.ErrUnavailable.
+func CloudNodeID(ctx context.Context, source *entropy.CloudMetadataEntropy, bits int) (int64, error) {
+	if err := checkNodeIDBits(bits); err != nil {
+		return 0, err
+	}
+	if source == nil {
+		source = &entropy.CloudMetadataEntropy{}
+	}
+	identity, err := source.Identity(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hashedNodeID(identity.Cloud.String()+"/"+identity.InstanceID, bits)
+}
@@ -0,0 +1,49 @@
+//go:build !idforge_lite
+
+package idforge
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+func TestCloudNodeID(t *testing.T) {
+	instanceID := "4520031799277581759"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/computeMetadata/v1/instance/id" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(instanceID))
+	}))
+	defer server.Close()
+
+	source := &entropy.CloudMetadataEntropy{Endpoint: server.URL}
+	a, err := CloudNodeID(context.Background(), source, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	b, _ := CloudNodeID(context.Background(), source, 10)
+	if a != b || a < 0 || a >= 1024 {
+		t.Errorf("Expected a stable node ID below 1024, got %d and %d", a, b)
+	}
+
+	wide, _ := CloudNodeID(context.Background(), source, 32)
+	instanceID = "7290531799277581234"
+	if other, _ := CloudNodeID(context.Background(), source, 32); other == wide {
+		t.Error("Expected another instance to get another node ID")
+	}
+
+	if _, err := CloudNodeID(context.Background(), source, 64); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for 64 bits, got %v", err)
+	}
+	broken := &entropy.CloudMetadataEntropy{Cloud: entropy.CloudEC2, Endpoint: server.URL}
+	if _, err := CloudNodeID(context.Background(), broken, 10); !errors.Is(err, entropy.ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package idforge
+
+import "errors"
+
+// Code is a stable, machine-readable identifier for an error, for API
+// layers that map errors to responses. Codes never change meaning once
+// released; new errors get new codes.
+type Code string
+
+// Validation codes, for IDs that are malformed, revoked or unverifiable
+const (
+	CodeInvalidID           Code = "IDF-VAL-001" // Several rules broken at once
+	CodeInvalidLength       Code = "IDF-VAL-002"
+	CodeInvalidEncoding     Code = "IDF-VAL-003"
+	CodeInvalidCharacter    Code = "IDF-VAL-004"
+	CodeRevoked             Code = "IDF-VAL-005"
+	CodeInvalidSignature    Code = "IDF-VAL-006"
+	CodeUnrecognizedFormat  Code = "IDF-VAL-007"
+	CodeUnknownFormat       Code = "IDF-VAL-008"
+	CodeLayoutMismatch      Code = "IDF-VAL-009"
+	CodeInvalidComposite    Code = "IDF-VAL-010"
+	CodeInvalidPartitioned  Code = "IDF-VAL-011"
+	CodeInvalidObjectID     Code = "IDF-VAL-012"
+	CodeInvalidEncodedID    Code = "IDF-VAL-013"
+	CodeInvalidMessage      Code = "IDF-VAL-014"
+	CodeInvalidShard        Code = "IDF-VAL-015"
+	CodeNotQRAlphanumeric   Code = "IDF-VAL-016"
+	CodeForbiddenWord       Code = "IDF-VAL-017"
+	CodeReplayed            Code = "IDF-VAL-018"
+	CodeCSRFTokenExpired    Code = "IDF-VAL-019" // Registered by csrf.go, which lite builds leave out
+	CodeInvalidCSRFToken    Code = "IDF-VAL-020" // Registered by csrf.go
+	CodeInvalidHardwareAddr Code = "IDF-VAL-021" // Registered by eui64.go, which lite builds leave out
+	CodeConflictingMapping  Code = "IDF-VAL-022"
+)
+
+// Generation codes, for failures while issuing IDs
+const (
+	CodeEntropyUnavailable Code = "IDF-GEN-001"
+	CodeProviderTimeout    Code = "IDF-GEN-002"
+	CodeGenerationTimeout  Code = "IDF-GEN-003"
+	CodeCollision          Code = "IDF-GEN-004"
+	CodeRateLimited        Code = "IDF-GEN-005"
+	CodeMonotonicOverflow  Code = "IDF-GEN-006"
+	CodeClockBeforeEpoch   Code = "IDF-GEN-007"
+	CodePoolClosed         Code = "IDF-GEN-008"
+	CodeLeaseUnsupported   Code = "IDF-GEN-009"
+	CodeLeaseExpired       Code = "IDF-GEN-010"
+	CodeSelfTestFailed     Code = "IDF-GEN-011"
+	CodeNoScheme           Code = "IDF-GEN-012"
+	CodeUnknownTenant      Code = "IDF-GEN-013"
+	CodeKeyNotFound        Code = "IDF-GEN-014"
+	CodeInvalidState       Code = "IDF-GEN-015"
+	CodeFilterExhausted    Code = "IDF-GEN-016" // Every candidate contained a forbidden word
+	CodeNoUniquenessStore  Code = "IDF-GEN-017"
+	CodeQuotaExceeded      Code = "IDF-GEN-018"
+	CodeNotLeader          Code = "IDF-GEN-019"
+	CodeSequenceRegressed  Code = "IDF-GEN-020"
+	CodeDedupeFull         Code = "IDF-GEN-021"
+	CodeCorruptDeviceID    Code = "IDF-GEN-022"
+	CodeNotInKubernetes    Code = "IDF-GEN-023"
+	CodeNodeIDRange        Code = "IDF-GEN-024"
+)
+
+// Configuration codes, for options that cannot be used as given
+const (
+	CodeInvalidConfig         Code = "IDF-CFG-001" // No more specific code applies
+	CodeInvalidAlphabet       Code = "IDF-CFG-002"
+	CodeInvalidSize           Code = "IDF-CFG-003"
+	CodeInvalidWeights        Code = "IDF-CFG-004"
+	CodeFIPSIncompatible      Code = "IDF-CFG-005"
+	CodeConflictingRNG        Code = "IDF-CFG-006"
+	CodeInvalidLayout         Code = "IDF-CFG-007"
+	CodeInvalidFormatVersion  Code = "IDF-CFG-008"
+	CodeDuplicateScheme       Code = "IDF-CFG-009"
+	CodeInvalidTagFormat      Code = "IDF-CFG-010"
+	CodeInvalidGS1Prefix      Code = "IDF-CFG-011"
+	CodeInvalidSymbology      Code = "IDF-CFG-012"
+	CodeInvalidTenant         Code = "IDF-CFG-013"
+	CodeInvalidReseedInterval Code = "IDF-CFG-014"
+	CodeInvalidExport         Code = "IDF-CFG-015"
+	CodeInvalidCouponPattern  Code = "IDF-CFG-016"
+)
+
+// CodeUnknown is reported for errors that do not come from this package
+const CodeUnknown Code = "IDF-UNK-001"
+
+// errorCodes maps sentinel errors to codes. More specific errors come
+// before the errors they wrap, so the first match wins.
+var errorCodes = []struct {
+	err  error
+	code Code
+}{
+	{ErrInvalidSignature, CodeInvalidSignature},
+	{ErrUnrecognizedFormat, CodeUnrecognizedFormat},
+	{ErrUnknownFormat, CodeUnknownFormat},
+	{ErrLayoutMismatch, CodeLayoutMismatch},
+	{ErrInvalidCompositeID, CodeInvalidComposite},
+	{ErrInvalidPartitionedID, CodeInvalidPartitioned},
+	{ErrInvalidObjectID, CodeInvalidObjectID},
+	{ErrInvalidEncoding, CodeInvalidEncodedID},
+	{ErrInvalidMessage, CodeInvalidMessage},
+	{ErrInvalidShard, CodeInvalidShard},
+	{ErrNotQRAlphanumeric, CodeNotQRAlphanumeric},
+	{ErrConflictingMapping, CodeConflictingMapping},
+	{ErrValidation, CodeInvalidID},
+
+	{ErrProviderTimeout, CodeProviderTimeout},
+	{ErrEntropyUnavailable, CodeEntropyUnavailable},
+	{ErrForbiddenWord, CodeFilterExhausted},
+	{ErrCollision, CodeCollision},
+	{ErrGenerationTimeout, CodeGenerationTimeout},
+	{ErrRateLimited, CodeRateLimited},
+	{ErrMonotonicOverflow, CodeMonotonicOverflow},
+	{ErrClockBeforeEpoch, CodeClockBeforeEpoch},
+	{ErrPoolClosed, CodePoolClosed},
+	{ErrLeaseUnsupported, CodeLeaseUnsupported},
+	{ErrLeaseExpired, CodeLeaseExpired},
+	{ErrSelfTestFailed, CodeSelfTestFailed},
+	{ErrNoScheme, CodeNoScheme},
+	{ErrUnknownTenant, CodeUnknownTenant},
+	{ErrKeyNotFound, CodeKeyNotFound},
+	{ErrInvalidState, CodeInvalidState},
+	{ErrNoUniquenessStore, CodeNoUniquenessStore},
+	{ErrQuotaExceeded, CodeQuotaExceeded},
+	{ErrNotLeader, CodeNotLeader},
+	{ErrSequenceRegressed, CodeSequenceRegressed},
+	{ErrDedupeFull, CodeDedupeFull},
+	{ErrCorruptDeviceID, CodeCorruptDeviceID},
+	{ErrNotInKubernetes, CodeNotInKubernetes},
+	{ErrNodeIDRange, CodeNodeIDRange},
+
+	{ErrInvalidAlphabet, CodeInvalidAlphabet},
+	{ErrInvalidSize, CodeInvalidSize},
+	{ErrInvalidWeights, CodeInvalidWeights},
+	{ErrFIPSIncompatible, CodeFIPSIncompatible},
+	{ErrConflictingRNG, CodeConflictingRNG},
+	{ErrInvalidLayout, CodeInvalidLayout},
+	{ErrInvalidFormatVersion, CodeInvalidFormatVersion},
+	{ErrDuplicateScheme, CodeDuplicateScheme},
+	{ErrInvalidTagFormat, CodeInvalidTagFormat},
+	{ErrInvalidGS1Prefix, CodeInvalidGS1Prefix},
+	{ErrInvalidSymbology, CodeInvalidSymbology},
+	{ErrInvalidTenant, CodeInvalidTenant},
+	{ErrInvalidReseedInterval, CodeInvalidReseedInterval},
+	{ErrInvalidExport, CodeInvalidExport},
+	{ErrInvalidCouponPattern, CodeInvalidCouponPattern},
+	{ErrInvalidConfig, CodeInvalidConfig},
+}
+
+// registerCode adds the code of an error declared in a file that lite
+// builds leave out. Registered errors are matched last, so they must not
+// wrap an error already in the table.
+func registerCode(err error, code Code) {
+	errorCodes = append(errorCodes, struct {
+		err  error
+		code Code
+	}{err, code})
+}
+
+// ruleCodes maps validation rules to codes
+var ruleCodes = map[string]Code{
+	RuleLength:    CodeInvalidLength,
+	RuleEncoding:  CodeInvalidEncoding,
+	RuleAlphabet:  CodeInvalidCharacter,
+	RuleRevoked:   CodeRevoked,
+	RuleForbidden: CodeForbiddenWord,
+	RuleReplayed:  CodeReplayed,
+}
+
+// ErrorCode returns the code for err, or an empty code for nil. A
+// *ValidationError breaking a single rule gets that rule's code; one
+// breaking several gets CodeInvalidID. Errors this package does not define
+// are reported as CodeUnknown.
+func ErrorCode(err error) Code {
+	if err == nil {
+		return ""
+	}
+
+	var verr *ValidationError
+	if errors.As(err, &verr) && len(verr.violations) == 1 {
+		return verr.violations[0].Code()
+	}
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return CodeUnknown
+}
+
+// Code returns the code for the violated rule, or CodeInvalidID for rules
+// without their own code
+func (v Violation) Code() Code {
+	if code, ok := ruleCodes[v.Rule]; ok {
+		return code
+	}
+	return CodeInvalidID
+}
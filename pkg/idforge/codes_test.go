@@ -0,0 +1,83 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code Code
+	}{
+		{nil, ""},
+		{errors.New("other"), CodeUnknown},
+		{CheckID("ab", "abc", 3), CodeInvalidLength},
+		{CheckID("abx", "abc", 3), CodeInvalidCharacter},
+		{CheckID("xyzw", "abc", 3), CodeInvalidID},
+		{fmt.Errorf("lookup: %w", ErrInvalidSignature), CodeInvalidSignature},
+		{ErrInvalidAlphabet, CodeInvalidAlphabet},
+		{fmt.Errorf("%w: custom", ErrInvalidConfig), CodeInvalidConfig},
+		{fmt.Errorf("%w: provider: %w", ErrEntropyUnavailable, ErrProviderTimeout), CodeProviderTimeout},
+		{fmt.Errorf("%w: %w after 3 attempts", ErrGenerationTimeout, ErrCollision), CodeCollision},
+		{ErrGenerationTimeout, CodeGenerationTimeout},
+		{ErrDedupeFull, CodeDedupeFull},
+		{fmt.Errorf("%w: checksum mismatch", ErrCorruptDeviceID), CodeCorruptDeviceID},
+		{fmt.Errorf("%w: pod name unknown", ErrNotInKubernetes), CodeNotInKubernetes},
+		{ErrNodeIDRange, CodeNodeIDRange},
+		{fmt.Errorf("tmp_a: %w", ErrConflictingMapping), CodeConflictingMapping},
+		{ErrInvalidReseedInterval, CodeInvalidReseedInterval},
+		{fmt.Errorf("%w: unknown format 9", ErrInvalidExport), CodeInvalidExport},
+		{ErrInvalidCouponPattern, CodeInvalidCouponPattern},
+	}
+
+	for _, tt := range tests {
+		if got := ErrorCode(tt.err); got != tt.code {
+			t.Errorf("Expected code %q for %v, got %q", tt.code, tt.err, got)
+		}
+	}
+}
+
+func TestErrorCodeFromGenerators(t *testing.T) {
+	gen := NewExtendedGenerator(WithRateLimit(1, 1))
+	ctx := context.Background()
+	if _, err := gen.Generate(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := gen.Generate(ctx); ErrorCode(err) != CodeRateLimited {
+		t.Errorf("Expected %s, got %s (%v)", CodeRateLimited, ErrorCode(err), err)
+	}
+
+	gen = NewExtendedGenerator(WithRNG(CryptoRNG), WithDRBG(time.Hour))
+	if _, err := gen.Generate(ctx); ErrorCode(err) != CodeConflictingRNG {
+		t.Errorf("Expected %s, got %s (%v)", CodeConflictingRNG, ErrorCode(err), err)
+	}
+}
+
+func TestErrorCodesAreUnique(t *testing.T) {
+	seen := map[Code]error{CodeUnknown: nil}
+	for _, c := range errorCodes {
+		if prev, ok := seen[c.code]; ok {
+			t.Errorf("Code %s is used by both %v and %v", c.code, prev, c.err)
+		}
+		seen[c.code] = c.err
+	}
+	for rule, code := range ruleCodes {
+		if _, ok := seen[code]; ok {
+			t.Errorf("Code %s of rule %s is also used by an error", code, rule)
+		}
+		seen[code] = nil
+	}
+}
+
+func TestViolationCode(t *testing.T) {
+	if code := (Violation{Rule: RuleRevoked}).Code(); code != CodeRevoked {
+		t.Errorf("Expected %s, got %s", CodeRevoked, code)
+	}
+	if code := (Violation{Rule: "custom"}).Code(); code != CodeInvalidID {
+		t.Errorf("Expected %s for an unknown rule, got %s", CodeInvalidID, code)
+	}
+}
@@ -0,0 +1,37 @@
+package idforge
+
+// CollisionStrategy decides what ExtendedGenerator does when a candidate
+// repeats an already issued ID
+type CollisionStrategy int
+
+const (
+	// CollisionRetry draws a new candidate of the same size until the
+	// attempt limit derived from UniquenessPressure is reached
+	CollisionRetry CollisionStrategy = iota
+	// CollisionGrowSize retries with a candidate one character longer after
+	// each collision, trading ID length for latency. Grown IDs no longer
+	// match the configured size.
+	CollisionGrowSize
+	// CollisionFail returns ErrCollision on the first collision
+	CollisionFail
+)
+
+func (s CollisionStrategy) String() string {
+	switch s {
+	case CollisionRetry:
+		return "retry"
+	case CollisionGrowSize:
+		return "grow-size"
+	case CollisionFail:
+		return "fail"
+	default:
+		return "unknown"
+	}
+}
+
+// WithCollisionStrategy selects how collisions with issued IDs are handled
+func WithCollisionStrategy(strategy CollisionStrategy) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.CollisionStrategy = strategy
+	}
+}
@@ -0,0 +1,53 @@
+package idforge
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// exhaustedGenerator returns a generator whose two-character binary
+// keyspace has been fully issued
+func exhaustedGenerator(strategy CollisionStrategy) *ExtendedGenerator {
+	gen := NewExtendedGenerator(
+		WithCustomAlphabet("01"),
+		func(cfg *GeneratorConfig) {
+			cfg.Size = 2
+		},
+		WithCollisionStrategy(strategy),
+	)
+	for _, id := range []string{"00", "01", "10", "11"} {
+		gen.issued.add(id, time.Now())
+	}
+	return gen
+}
+
+func TestCollisionFail(t *testing.T) {
+	gen := exhaustedGenerator(CollisionFail)
+
+	if _, err := gen.Generate(context.Background()); err != ErrCollision {
+		t.Errorf("Expected ErrCollision, got %v", err)
+	}
+	if stats := gen.Stats(); stats.Collisions != 1 || stats.Retries != 0 {
+		t.Errorf("Expected a single collision without retries, got %d and %d",
+			stats.Collisions, stats.Retries)
+	}
+}
+
+func TestCollisionGrowSize(t *testing.T) {
+	gen := exhaustedGenerator(CollisionGrowSize)
+
+	id, err := gen.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(id) != 3 {
+		t.Errorf("Expected the ID to grow to 3 characters, got %s", id)
+	}
+}
+
+func TestCollisionStrategyString(t *testing.T) {
+	if CollisionGrowSize.String() != "grow-size" {
+		t.Errorf("Expected grow-size, got %s", CollisionGrowSize)
+	}
+}
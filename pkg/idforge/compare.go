@@ -0,0 +1,125 @@
+package idforge
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// CharClass is a set of character classes found in an ID
+type CharClass uint8
+
+const (
+	ClassDigit CharClass = 1 << iota
+	ClassLower
+	ClassUpper
+	ClassSymbol   // Other printable ASCII, such as '-' or '_'
+	ClassNonASCII // Anything outside printable ASCII
+)
+
+var charClassNames = []string{"digit", "lower", "upper", "symbol", "non-ascii"}
+
+func (c CharClass) String() string {
+	var names []string
+	for i, name := range charClassNames {
+		if c&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// classify returns the classes of the characters in id
+func classify(id string) CharClass {
+	var c CharClass
+	for _, r := range id {
+		switch {
+		case '0' <= r && r <= '9':
+			c |= ClassDigit
+		case 'a' <= r && r <= 'z':
+			c |= ClassLower
+		case 'A' <= r && r <= 'Z':
+			c |= ClassUpper
+		case ' ' <= r && r <= '~':
+			c |= ClassSymbol
+		default:
+			c |= ClassNonASCII
+		}
+	}
+	return c
+}
+
+// ComparisonReport describes how two IDs differ without containing either
+// of them, so it can be logged or pasted into a support ticket
+type ComparisonReport struct {
+	// Equal reports whether the IDs are identical
+	Equal bool
+
+	// FingerprintA and FingerprintB are truncated SHA-256 hashes of the
+	// IDs, enough to match a report against an ID someone already holds
+	FingerprintA string
+	FingerprintB string
+
+	// LengthDelta is the length of b minus the length of a, in characters
+	LengthDelta int
+
+	// SharedPrefix is the number of leading characters the IDs share
+	SharedPrefix int
+
+	// Hamming counts the positions whose characters differ, with each
+	// character past the end of the shorter ID counting as a difference
+	Hamming int
+
+	// ClassesA and ClassesB are the character classes in each ID, and
+	// OnlyInA and OnlyInB the classes found in one ID but not the other
+	ClassesA CharClass
+	ClassesB CharClass
+	OnlyInA  CharClass
+	OnlyInB  CharClass
+}
+
+// Compare reports how a and b differ, for debugging mismatches such as an
+// ID that was truncated, re-cased or retyped somewhere along the way
+func Compare(a, b string) ComparisonReport {
+	report := ComparisonReport{
+		Equal:        a == b,
+		FingerprintA: fingerprint(a),
+		FingerprintB: fingerprint(b),
+		LengthDelta:  utf8.RuneCountInString(b) - utf8.RuneCountInString(a),
+		ClassesA:     classify(a),
+		ClassesB:     classify(b),
+	}
+	report.OnlyInA = report.ClassesA &^ report.ClassesB
+	report.OnlyInB = report.ClassesB &^ report.ClassesA
+
+	ra, rb := []rune(a), []rune(b)
+	shorter := min(len(ra), len(rb))
+	prefix := true
+	for i := 0; i < shorter; i++ {
+		if ra[i] != rb[i] {
+			prefix = false
+			report.Hamming++
+		} else if prefix {
+			report.SharedPrefix++
+		}
+	}
+	report.Hamming += max(len(ra), len(rb)) - shorter
+	return report
+}
+
+// fingerprint returns the first 8 bytes of the SHA-256 of id in hex
+func fingerprint(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:8])
+}
+
+// String formats the report as key=value pairs for logs
+func (r ComparisonReport) String() string {
+	return fmt.Sprintf("a=%s b=%s equal=%t length_delta=%d shared_prefix=%d hamming=%d only_in_a=%s only_in_b=%s",
+		r.FingerprintA, r.FingerprintB, r.Equal, r.LengthDelta, r.SharedPrefix, r.Hamming, r.OnlyInA, r.OnlyInB)
+}
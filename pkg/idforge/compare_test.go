@@ -0,0 +1,71 @@
+package idforge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompareEqual(t *testing.T) {
+	r := Compare("abc123", "abc123")
+	if !r.Equal {
+		t.Error("Expected identical IDs to compare equal")
+	}
+	if r.FingerprintA != r.FingerprintB {
+		t.Errorf("Expected matching fingerprints, got %s and %s", r.FingerprintA, r.FingerprintB)
+	}
+	if r.Hamming != 0 || r.LengthDelta != 0 || r.SharedPrefix != 6 {
+		t.Errorf("Expected no differences, got %+v", r)
+	}
+}
+
+func TestCompareDifferences(t *testing.T) {
+	r := Compare("abcd1234", "abcD12")
+	if r.Equal {
+		t.Error("Expected different IDs not to compare equal")
+	}
+	if r.LengthDelta != -2 {
+		t.Errorf("Expected length delta -2, got %d", r.LengthDelta)
+	}
+	if r.SharedPrefix != 3 {
+		t.Errorf("Expected shared prefix 3, got %d", r.SharedPrefix)
+	}
+	if r.Hamming != 3 {
+		t.Errorf("Expected Hamming distance 3, got %d", r.Hamming)
+	}
+	if r.OnlyInA != 0 {
+		t.Errorf("Expected no classes only in a, got %s", r.OnlyInA)
+	}
+	if r.OnlyInB != ClassUpper {
+		t.Errorf("Expected upper only in b, got %s", r.OnlyInB)
+	}
+}
+
+func TestCompareCountsCharacters(t *testing.T) {
+	r := Compare("日本語", "日本")
+	if r.LengthDelta != -1 || r.SharedPrefix != 2 || r.Hamming != 1 {
+		t.Errorf("Expected character-based counts, got %+v", r)
+	}
+	if r.ClassesA != ClassNonASCII {
+		t.Errorf("Expected non-ascii class, got %s", r.ClassesA)
+	}
+}
+
+func TestComparisonReportHidesIDs(t *testing.T) {
+	a, b := "secretValueA1", "secretValueB2"
+	s := Compare(a, b).String()
+	if strings.Contains(s, a) || strings.Contains(s, b) || strings.Contains(s, "secret") {
+		t.Errorf("Expected report to omit the IDs, got %s", s)
+	}
+	if !strings.Contains(s, "shared_prefix=11") {
+		t.Errorf("Expected shared prefix in report, got %s", s)
+	}
+}
+
+func TestCharClassString(t *testing.T) {
+	if s := (ClassDigit | ClassUpper).String(); s != "digit|upper" {
+		t.Errorf("Expected digit|upper, got %s", s)
+	}
+	if s := CharClass(0).String(); s != "none" {
+		t.Errorf("Expected none, got %s", s)
+	}
+}
@@ -0,0 +1,75 @@
+package idforge
+
+import (
+	"errors"
+	"strings"
+)
+
+// CompositeDelimiter separates the parts of a composite ID
+const CompositeDelimiter = "::"
+
+var ErrInvalidCompositeID = errors.New("invalid composite ID")
+
+// Segment is one part of a composite ID, such as a tenant or entity ID
+type Segment string
+
+// compositeEscaper percent-encodes the characters that could make a part
+// look like a delimiter or an escape
+var compositeEscaper = strings.NewReplacer("%", "%25", ":", "%3A")
+
+// BuildCompositeID joins parts with CompositeDelimiter, as in
+// tenant::entity::random. Any ':' or '%' inside a part is percent-encoded,
+// so SplitCompositeID always recovers the original parts.
+func BuildCompositeID(parts ...Segment) string {
+	var b strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			b.WriteString(CompositeDelimiter)
+		}
+		compositeEscaper.WriteString(&b, string(part))
+	}
+	return b.String()
+}
+
+// SplitCompositeID reverses BuildCompositeID
+func SplitCompositeID(id string) ([]Segment, error) {
+	fields := strings.Split(id, CompositeDelimiter)
+	parts := make([]Segment, len(fields))
+	for i, field := range fields {
+		part, err := unescapeComposite(field)
+		if err != nil {
+			return nil, err
+		}
+		parts[i] = Segment(part)
+	}
+	return parts, nil
+}
+
+// unescapeComposite decodes the escapes written by compositeEscaper,
+// rejecting anything it would not have produced
+func unescapeComposite(field string) (string, error) {
+	if strings.IndexByte(field, ':') >= 0 {
+		return "", ErrInvalidCompositeID
+	}
+	if strings.IndexByte(field, '%') < 0 {
+		return field, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] != '%' {
+			b.WriteByte(field[i])
+			continue
+		}
+		switch field[i+1 : min(i+3, len(field))] {
+		case "25":
+			b.WriteByte('%')
+		case "3A":
+			b.WriteByte(':')
+		default:
+			return "", ErrInvalidCompositeID
+		}
+		i += 2
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,46 @@
+package idforge
+
+import (
+	"testing"
+)
+
+func TestCompositeIDRoundTrip(t *testing.T) {
+	cases := [][]Segment{
+		{"acme", "user", "V1StGXR8Z5jdHi6B"},
+		{"a:b", "c::d", "100%", ""},
+		{":", "%3A", "::"},
+		{""},
+	}
+	for _, parts := range cases {
+		id := BuildCompositeID(parts...)
+		got, err := SplitCompositeID(id)
+		if err != nil {
+			t.Fatalf("Unexpected error splitting %q: %v", id, err)
+		}
+		if len(got) != len(parts) {
+			t.Fatalf("Expected %d parts from %q, got %q", len(parts), id, got)
+		}
+		for i := range parts {
+			if got[i] != parts[i] {
+				t.Errorf("Part %d of %q: expected %q, got %q", i, id, parts[i], got[i])
+			}
+		}
+	}
+}
+
+func TestBuildCompositeID(t *testing.T) {
+	if id := BuildCompositeID("acme", "order", "42"); id != "acme::order::42" {
+		t.Errorf("Expected acme::order::42, got %s", id)
+	}
+	if id := BuildCompositeID("a:b", "50%"); id != "a%3Ab::50%25" {
+		t.Errorf("Expected a%%3Ab::50%%25, got %s", id)
+	}
+}
+
+func TestSplitCompositeIDInvalid(t *testing.T) {
+	for _, id := range []string{"a:::b", "a::b:c", "100%", "%2", "%zz::a"} {
+		if _, err := SplitCompositeID(id); err != ErrInvalidCompositeID {
+			t.Errorf("SplitCompositeID(%q): expected ErrInvalidCompositeID, got %v", id, err)
+		}
+	}
+}
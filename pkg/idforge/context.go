@@ -0,0 +1,32 @@
+package idforge
+
+import "context"
+
+// idContextKey is the context key under which IDs are stored
+type idContextKey struct{}
+
+// NewContext returns a copy of ctx carrying id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idContextKey{}, id)
+}
+
+// FromContext returns the ID stored in ctx by NewContext or
+// RequestIDMiddleware
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(idContextKey{}).(string)
+	return id, ok
+}
+
+// EnsureID returns ctx and its ID, calling generate and storing the result
+// when ctx does not carry one yet
+func EnsureID(ctx context.Context, generate func() (string, error)) (context.Context, string, error) {
+	if id, ok := FromContext(ctx); ok {
+		return ctx, id, nil
+	}
+
+	id, err := generate()
+	if err != nil {
+		return ctx, "", err
+	}
+	return NewContext(ctx, id), id, nil
+}
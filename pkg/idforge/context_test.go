@@ -0,0 +1,48 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNewContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("Expected no ID in an empty context")
+	}
+
+	ctx := NewContext(context.Background(), "abc")
+	id, ok := FromContext(ctx)
+	if !ok || id != "abc" {
+		t.Errorf("Expected abc, got %q (found %v)", id, ok)
+	}
+}
+
+func TestEnsureID(t *testing.T) {
+	gen := New()
+
+	ctx, id, err := EnsureID(context.Background(), gen.Generate)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !gen.Validate(id) {
+		t.Errorf("Expected a generated ID, got %q", id)
+	}
+	if stored, _ := FromContext(ctx); stored != id {
+		t.Errorf("Expected %q to be stored in the context, got %q", id, stored)
+	}
+
+	// An existing ID is kept without calling the generator
+	_, again, err := EnsureID(ctx, func() (string, error) {
+		t.Error("Generator should not be called when an ID is present")
+		return "", nil
+	})
+	if err != nil || again != id {
+		t.Errorf("Expected existing ID %q, got %q (err %v)", id, again, err)
+	}
+
+	genErr := errors.New("no entropy")
+	if _, _, err := EnsureID(context.Background(), func() (string, error) { return "", genErr }); err != genErr {
+		t.Errorf("Expected generator error, got %v", err)
+	}
+}
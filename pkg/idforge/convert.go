@@ -0,0 +1,224 @@
+package idforge
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidEncoding = errors.New("invalid encoded ID")
+
+// Encoding writes the bytes behind an ID as text and reads them back
+type Encoding interface {
+	Encode(value []byte) (string, error)
+	Decode(id string) ([]byte, error)
+}
+
+const (
+	base58Alphabet    = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+var (
+	// HexEncoding writes two lowercase hex digits per byte
+	HexEncoding Encoding = hexEncoding{}
+
+	// Base58Encoding uses the Bitcoin alphabet
+	Base58Encoding = NewBaseEncoding(base58Alphabet)
+
+	// UUIDEncoding writes 16 bytes in the 8-4-4-4-12 hex form
+	UUIDEncoding Encoding = uuidEncoding{}
+
+	// ULIDEncoding writes 16 bytes as 26 Crockford base32 characters. ULIDs
+	// and UUIDv7 both start with a 48-bit millisecond timestamp, so
+	// converting one into the other keeps its time and sort order.
+	ULIDEncoding Encoding = ulidEncoding{}
+)
+
+// Convert re-encodes id from one encoding into another, for migrating
+// stored references between ID schemes. Converting back returns the
+// original ID as long as both encodings are lossless for its bytes.
+func Convert(id string, from, to Encoding) (string, error) {
+	value, err := from.Decode(id)
+	if err != nil {
+		return "", err
+	}
+	return to.Encode(value)
+}
+
+type hexEncoding struct{}
+
+func (hexEncoding) Encode(value []byte) (string, error) {
+	return hex.EncodeToString(value), nil
+}
+
+func (hexEncoding) Decode(id string) ([]byte, error) {
+	value, err := hex.DecodeString(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+	}
+	return value, nil
+}
+
+type uuidEncoding struct{}
+
+func (uuidEncoding) Encode(value []byte) (string, error) {
+	if len(value) != 16 {
+		return "", fmt.Errorf("%w: UUID needs 16 bytes, got %d", ErrInvalidEncoding, len(value))
+	}
+	s := hex.EncodeToString(value)
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:], nil
+}
+
+func (uuidEncoding) Decode(id string) ([]byte, error) {
+	if len(id) != 36 || id[8] != '-' || id[13] != '-' || id[18] != '-' || id[23] != '-' {
+		return nil, fmt.Errorf("%w: not a UUID", ErrInvalidEncoding)
+	}
+	value, err := hex.DecodeString(id[:8] + id[9:13] + id[14:18] + id[19:23] + id[24:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+	}
+	return value, nil
+}
+
+type ulidEncoding struct{}
+
+func (ulidEncoding) Encode(value []byte) (string, error) {
+	if len(value) != 16 {
+		return "", fmt.Errorf("%w: ULID needs 16 bytes, got %d", ErrInvalidEncoding, len(value))
+	}
+
+	// 130 bits of output: two leading zero bits, then the 128 value bits
+	id := make([]byte, 26)
+	var acc uint32
+	bits := 2
+	j := 0
+	for _, b := range value {
+		acc = acc<<8 | uint32(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			id[j] = crockfordAlphabet[acc>>bits&31]
+			j++
+		}
+	}
+	return string(id), nil
+}
+
+func (ulidEncoding) Decode(id string) ([]byte, error) {
+	if len(id) != 26 {
+		return nil, fmt.Errorf("%w: ULID must be 26 characters", ErrInvalidEncoding)
+	}
+
+	value := make([]byte, 0, 16)
+	var acc uint32
+	bits := -2 // The first two bits are padding
+	for i := 0; i < len(id); i++ {
+		digit := strings.IndexByte(crockfordAlphabet, upper(id[i]))
+		if digit < 0 || (i == 0 && digit > 7) {
+			return nil, fmt.Errorf("%w: not a ULID", ErrInvalidEncoding)
+		}
+		acc = acc<<5 | uint32(digit)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			value = append(value, byte(acc>>bits))
+		}
+	}
+	return value, nil
+}
+
+// upper maps an ASCII lowercase letter to uppercase
+func upper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// BaseEncoding treats text in an alphabet as a big-endian number, with one
+// leading first-alphabet character per leading zero byte as in Base58
+type BaseEncoding struct {
+	alphabet symbols
+}
+
+// NewBaseEncoding creates an encoding over alphabet, such as DefaultAlphabet
+// for IDs created by the generators. It panics if alphabet is not valid
+// UTF-8 with at least two characters.
+func NewBaseEncoding(alphabet string) *BaseEncoding {
+	if !validAlphabet(alphabet) {
+		panic(ErrInvalidAlphabet)
+	}
+	return &BaseEncoding{alphabet: newSymbols(alphabet)}
+}
+
+// Encode writes value in the encoding's alphabet
+func (e *BaseEncoding) Encode(value []byte) (string, error) {
+	n := e.alphabet.len()
+	zeros := 0
+	for zeros < len(value) && value[zeros] == 0 {
+		zeros++
+	}
+
+	// Repeatedly divide the big-endian number by n, collecting remainders
+	num := append([]byte(nil), value[zeros:]...)
+	var digits []int
+	for len(num) > 0 {
+		rem := 0
+		quotient := num[:0]
+		for _, b := range num {
+			acc := rem<<8 | int(b)
+			if q := acc / n; q > 0 || len(quotient) > 0 {
+				quotient = append(quotient, byte(q))
+			}
+			rem = acc % n
+		}
+		digits = append(digits, rem)
+		num = quotient
+	}
+
+	id := make([]byte, 0, (zeros+len(digits))*e.alphabet.maxBytes())
+	for i := 0; i < zeros; i++ {
+		id = e.alphabet.append(id, 0)
+	}
+	for i := len(digits) - 1; i >= 0; i-- {
+		id = e.alphabet.append(id, digits[i])
+	}
+	return string(id), nil
+}
+
+// Decode reads id back into the bytes it encodes
+func (e *BaseEncoding) Decode(id string) ([]byte, error) {
+	n := e.alphabet.len()
+	var value []byte // Little-endian while accumulating
+	zeros := 0
+	leading := true
+	for _, char := range id {
+		digit := e.alphabet.index(char)
+		if digit < 0 {
+			return nil, fmt.Errorf("%w: %q is outside the alphabet", ErrInvalidEncoding, char)
+		}
+		if leading && digit == 0 {
+			zeros++
+			continue
+		}
+		leading = false
+
+		carry := digit
+		for i := range value {
+			carry += int(value[i]) * n
+			value[i] = byte(carry)
+			carry >>= 8
+		}
+		for ; carry > 0; carry >>= 8 {
+			value = append(value, byte(carry))
+		}
+	}
+
+	out := make([]byte, zeros, zeros+len(value))
+	for i := len(value) - 1; i >= 0; i-- {
+		out = append(out, value[i])
+	}
+	return out, nil
+}
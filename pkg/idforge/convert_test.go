@@ -0,0 +1,109 @@
+package idforge
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestConvertHexBase58(t *testing.T) {
+	// Known vector from the Bitcoin Base58 test suite
+	b58, err := Convert("00000000000000000000000000000000", HexEncoding, Base58Encoding)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if b58 != "1111111111111111" {
+		t.Errorf("Expected sixteen 1s, got %s", b58)
+	}
+
+	b58, _ = Convert("61", HexEncoding, Base58Encoding)
+	if b58 != "2g" {
+		t.Errorf("Expected 2g, got %s", b58)
+	}
+
+	back, err := Convert("0000287fb4cd", HexEncoding, Base58Encoding)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if back != "11233QC4" {
+		t.Errorf("Expected 11233QC4, got %s", back)
+	}
+	if hex, _ := Convert(back, Base58Encoding, HexEncoding); hex != "0000287fb4cd" {
+		t.Errorf("Expected round trip to 0000287fb4cd, got %s", hex)
+	}
+}
+
+func TestConvertUUIDULIDPreservesTimestamp(t *testing.T) {
+	// UUIDv7 for 2023-11-14T22:13:20Z
+	uuid := "018bcfe5-6800-7abc-8def-0123456789ab"
+
+	ulid, err := Convert(uuid, UUIDEncoding, ULIDEncoding)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ulid) != 26 {
+		t.Fatalf("Expected 26 characters, got %s", ulid)
+	}
+	// The first ten characters encode the 48-bit millisecond timestamp
+	if ulid[:10] != "01HF7YAT00" {
+		t.Errorf("Expected timestamp prefix 01HF7YAT00, got %s", ulid[:10])
+	}
+
+	back, err := Convert(ulid, ULIDEncoding, UUIDEncoding)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if back != uuid {
+		t.Errorf("Expected round trip to %s, got %s", uuid, back)
+	}
+}
+
+func TestConvertGeneratedIDs(t *testing.T) {
+	base62 := NewBaseEncoding(DefaultAlphabet)
+	for i := 0; i < 50; i++ {
+		id := Generate()
+		hex, err := Convert(id, base62, HexEncoding)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		back, err := Convert(hex, HexEncoding, base62)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if back != id {
+			t.Errorf("Expected round trip to %s, got %s via %s", id, back, hex)
+		}
+	}
+}
+
+func TestBaseEncodingRuneAlphabet(t *testing.T) {
+	enc := NewBaseEncoding(emojiAlphabet)
+	value := []byte{0, 1, 2, 250}
+
+	id, _ := enc.Encode(value)
+	got, err := enc.Decode(id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, value) {
+		t.Errorf("Expected %v, got %v", value, got)
+	}
+}
+
+func TestConvertInvalid(t *testing.T) {
+	cases := []struct {
+		id       string
+		from, to Encoding
+	}{
+		{"zz", HexEncoding, Base58Encoding},
+		{"0OIl", Base58Encoding, HexEncoding},
+		{"not-a-uuid", UUIDEncoding, ULIDEncoding},
+		{"8ZZZZZZZZZZZZZZZZZZZZZZZZZ", ULIDEncoding, UUIDEncoding},
+		{"0011", HexEncoding, ULIDEncoding},
+	}
+	for _, c := range cases {
+		if _, err := Convert(c.id, c.from, c.to); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("Convert(%q): expected ErrInvalidEncoding, got %v", c.id, err)
+		}
+	}
+}
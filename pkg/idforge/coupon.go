@@ -0,0 +1,70 @@
+package idforge
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// CouponLetters leaves out I and O, which customers confuse with 1 and 0
+	CouponLetters = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	couponDigits  = "0123456789"
+)
+
+var ErrInvalidCouponPattern = fmt.Errorf("%w: coupon pattern needs at least one '#', '@' or '*'", ErrInvalidConfig)
+
+// CouponPattern generates and validates coupon codes of one format
+type CouponPattern struct {
+	pattern string
+	layout  *LayoutGenerator
+}
+
+// CompileCoupon compiles pattern, in which '#' stands for a digit, '@' for
+// a letter of CouponLetters, '*' for either, a backslash makes the next
+// character literal and any other character stands for itself:
+// "SUMMER-####-@@@@" gives codes like SUMMER-4821-KTRW and "\#@@##" gives
+// #XK42.
+func CompileCoupon(pattern string) (*CouponPattern, error) {
+	layout, ok := compileFormat(pattern, map[byte]string{
+		'#': couponDigits,
+		'@': CouponLetters,
+		'*': couponDigits + CouponLetters,
+	})
+	if !ok {
+		return nil, ErrInvalidCouponPattern
+	}
+	return &CouponPattern{pattern: pattern, layout: layout}, nil
+}
+
+// MustCompileCoupon is like CompileCoupon but panics on an invalid pattern,
+// for patterns fixed at compile time
+func MustCompileCoupon(pattern string) *CouponPattern {
+	p, err := CompileCoupon(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Generate creates a code in the pattern, without checking whether it was
+// issued before
+func (p *CouponPattern) Generate() (string, error) {
+	return p.layout.Generate()
+}
+
+// Validate checks that code follows the pattern. Codes typed in lowercase
+// are accepted too.
+func (p *CouponPattern) Validate(code string) bool {
+	return p.layout.Validate(code) || p.layout.Validate(strings.ToUpper(code))
+}
+
+// Shape describes the pattern's codes, including how much randomness they
+// carry for judging whether a campaign's codes can be guessed
+func (p *CouponPattern) Shape() IDShape {
+	return p.layout.Shape()
+}
+
+// String returns the pattern the codes follow
+func (p *CouponPattern) String() string {
+	return p.pattern
+}
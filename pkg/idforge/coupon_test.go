@@ -0,0 +1,70 @@
+package idforge
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCouponPattern(t *testing.T) {
+	p, err := CompileCoupon("SUMMER-####-@@@@")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		code, err := p.Generate()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(code) != 16 || !strings.HasPrefix(code, "SUMMER-") || code[11] != '-' {
+			t.Fatalf("Expected SUMMER-####-@@@@, got %s", code)
+		}
+		if strings.Trim(code[7:11], couponDigits) != "" || strings.Trim(code[12:], CouponLetters) != "" {
+			t.Fatalf("Expected digits then letters, got %s", code)
+		}
+		if !p.Validate(code) || !p.Validate(strings.ToLower(code)) {
+			t.Errorf("Expected %s to validate in either case", code)
+		}
+	}
+
+	for _, code := range []string{"SUMMER-1234-ABC", "SUMMER-12A4-ABCD", "WINTER-1234-ABCD", "SUMMER-1234-ABIO"} {
+		if p.Validate(code) {
+			t.Errorf("Expected %s to be rejected", code)
+		}
+	}
+	if !p.Validate("SUMMER-1234-ABCD") {
+		t.Error("Expected SUMMER-1234-ABCD to validate")
+	}
+	if p.String() != "SUMMER-####-@@@@" {
+		t.Errorf("Expected the source pattern, got %s", p.String())
+	}
+}
+
+func TestCouponPatternEscapesAndWildcards(t *testing.T) {
+	p := MustCompileCoupon(`\#***`)
+	code, _ := p.Generate()
+	if len(code) != 4 || code[0] != '#' {
+		t.Errorf("Expected a literal # and three characters, got %s", code)
+	}
+	if !p.Validate("#A1B") || p.Validate("#a-b") {
+		t.Error("Expected * to accept letters and digits only")
+	}
+	if bits := p.Shape().EntropyBits; bits < 15 || bits > 16 {
+		t.Errorf("Expected about 15.3 bits, got %f", bits)
+	}
+}
+
+func TestCompileCouponErrors(t *testing.T) {
+	for _, pattern := range []string{"", "SUMMER", `\#\@`, `SUMMER-##\`} {
+		if _, err := CompileCoupon(pattern); !errors.Is(err, ErrInvalidCouponPattern) {
+			t.Errorf("Expected ErrInvalidCouponPattern for %q, got %v", pattern, err)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustCompileCoupon to panic")
+		}
+	}()
+	MustCompileCoupon("SUMMER")
+}
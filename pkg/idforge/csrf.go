@@ -0,0 +1,173 @@
+//go:build !idforge_lite
+
+package idforge
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrInvalidCSRFToken = errors.New("invalid CSRF token")
+	ErrCSRFTokenExpired = fmt.Errorf("%w: token expired", ErrInvalidCSRFToken)
+)
+
+func init() {
+	// The more specific error first, as it wraps the other
+	registerCode(ErrCSRFTokenExpired, CodeCSRFTokenExpired)
+	registerCode(ErrInvalidCSRFToken, CodeInvalidCSRFToken)
+}
+
+const (
+	DefaultCSRFHeader = "X-CSRF-Token" // Header read by VerifyRequest
+	DefaultCSRFField  = "csrf_token"   // Form field read by VerifyRequest
+	DefaultCSRFCookie = "csrf_token"   // Cookie set by SetCookie
+	DefaultCSRFTTL    = 12 * time.Hour
+)
+
+// csrfNonceBytes is the random part of a CSRF token
+const csrfNonceBytes = 16
+
+// CSRF issues and checks anti-forgery tokens bound to a session. A token
+// is a signed, expiring random value whose signature covers the session
+// ID, so it verifies without server-side state and is useless for any
+// other session. Tokens have the Signer form <keyID>.<payload>.<signature>,
+// so they survive key rotation like signed IDs.
+type CSRF struct {
+	signer        *Signer
+	ttl           time.Duration
+	requireCookie bool
+	clock         Clock
+}
+
+// CSRFOption configures a CSRF token source
+type CSRFOption func(*CSRF)
+
+// WithCSRFCookieRequired makes VerifyRequest require the DefaultCSRFCookie
+// cookie on every request, not only those without a session. Use it when
+// all tokens are issued with SetCookie.
+func WithCSRFCookieRequired() CSRFOption {
+	return func(c *CSRF) {
+		c.requireCookie = true
+	}
+}
+
+// WithCSRFClock reads token expiry times from clock instead of the system
+// clock
+func WithCSRFClock(clock Clock) CSRFOption {
+	return func(c *CSRF) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// NewCSRF creates a CSRF token source signing with keys. Tokens expire
+// after ttl, or DefaultCSRFTTL when it is not positive.
+func NewCSRF(keys KeyProvider, ttl time.Duration, opts ...CSRFOption) *CSRF {
+	if ttl <= 0 {
+		ttl = DefaultCSRFTTL
+	}
+	c := &CSRF{signer: NewSigner(keys), ttl: ttl, clock: SystemClock}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Generate returns a fresh token for sessionID. An empty sessionID is
+// allowed for forms shown before a session exists, such as a login form,
+// where the double-submit cookie carries the protection: issue such tokens
+// with SetCookie, since VerifyRequest rejects them without the cookie.
+func (c *CSRF) Generate(ctx context.Context, sessionID string) (string, error) {
+	payload := make([]byte, csrfNonceBytes, csrfNonceBytes+8)
+	if _, err := rand.Read(payload); err != nil {
+		return "", err
+	}
+	payload = binary.BigEndian.AppendUint64(payload, uint64(c.clock.Now().Add(c.ttl).Unix()))
+	return c.signer.SignBound(ctx, base64.RawURLEncoding.EncodeToString(payload), csrfBinding(sessionID))
+}
+
+// Verify checks that token was issued for sessionID and has not expired,
+// comparing signatures in constant time. It fails with ErrInvalidCSRFToken,
+// or ErrCSRFTokenExpired which wraps it; key provider errors are returned
+// as is.
+func (c *CSRF) Verify(ctx context.Context, sessionID, token string) error {
+	encoded, err := c.signer.VerifyBound(ctx, token, csrfBinding(sessionID))
+	if errors.Is(err, ErrInvalidSignature) {
+		return ErrInvalidCSRFToken
+	}
+	if err != nil {
+		return err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(payload) != csrfNonceBytes+8 {
+		return ErrInvalidCSRFToken
+	}
+	expiry := time.Unix(int64(binary.BigEndian.Uint64(payload[csrfNonceBytes:])), 0)
+	if !c.clock.Now().Before(expiry) {
+		return ErrCSRFTokenExpired
+	}
+	return nil
+}
+
+// SetCookie issues a token for sessionID and stores it in the
+// DefaultCSRFCookie cookie for the double-submit pattern, returning it for
+// embedding in forms. The cookie is readable by scripts, which echo it in
+// the DefaultCSRFHeader header; SameSite and Secure keep it first-party.
+func (c *CSRF) SetCookie(w http.ResponseWriter, sessionID string) (string, error) {
+	token, err := c.Generate(context.Background(), sessionID)
+	if err != nil {
+		return "", err
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     DefaultCSRFCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(c.ttl / time.Second),
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return token, nil
+}
+
+// VerifyRequest checks the token a request submits in the DefaultCSRFHeader
+// header or, failing that, the DefaultCSRFField form field. When the
+// request carries the DefaultCSRFCookie cookie, the submitted token must
+// also equal it (double submit). The cookie is mandatory when sessionID is
+// empty, as anyone can obtain a session-less token, and on every request
+// with WithCSRFCookieRequired. Safe methods such as GET are not exempt;
+// callers decide which requests to check.
+func (c *CSRF) VerifyRequest(r *http.Request, sessionID string) error {
+	submitted := r.Header.Get(DefaultCSRFHeader)
+	if submitted == "" {
+		submitted = r.PostFormValue(DefaultCSRFField)
+	}
+	if submitted == "" {
+		return fmt.Errorf("%w: no token submitted", ErrInvalidCSRFToken)
+	}
+
+	cookie, err := r.Cookie(DefaultCSRFCookie)
+	switch {
+	case err == nil:
+		if subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(submitted)) != 1 {
+			return fmt.Errorf("%w: token does not match cookie", ErrInvalidCSRFToken)
+		}
+	case sessionID == "" || c.requireCookie:
+		return fmt.Errorf("%w: no cookie", ErrInvalidCSRFToken)
+	}
+	return c.Verify(r.Context(), sessionID, submitted)
+}
+
+// csrfBinding binds a token to its session
+func csrfBinding(sessionID string) Binding {
+	return BindValue("csrf-session", sessionID)
+}
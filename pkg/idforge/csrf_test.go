@@ -0,0 +1,150 @@
+//go:build !idforge_lite
+
+package idforge
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCSRF(ttl time.Duration, opts ...CSRFOption) *CSRF {
+	return NewCSRF(newStaticKeys("k1", map[string]string{"k1": "secret"}), ttl, opts...)
+}
+
+func TestCSRFGenerateVerify(t *testing.T) {
+	csrf := newTestCSRF(time.Hour)
+	ctx := context.Background()
+
+	token, err := csrf.Generate(ctx, "session-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := csrf.Verify(ctx, "session-1", token); err != nil {
+		t.Errorf("Expected the token to verify, got %v", err)
+	}
+	if err := csrf.Verify(ctx, "session-2", token); !errors.Is(err, ErrInvalidCSRFToken) {
+		t.Errorf("Expected ErrInvalidCSRFToken for another session, got %v", err)
+	}
+	if err := csrf.Verify(ctx, "session-1", token+"x"); !errors.Is(err, ErrInvalidCSRFToken) {
+		t.Errorf("Expected ErrInvalidCSRFToken for a tampered token, got %v", err)
+	}
+
+	other, _ := csrf.Generate(ctx, "session-1")
+	if other == token {
+		t.Error("Expected every token to be different")
+	}
+}
+
+func TestCSRFExpiry(t *testing.T) {
+	csrf := newTestCSRF(time.Second)
+	ctx := context.Background()
+
+	// Sign a payload whose expiry has passed
+	payload := make([]byte, csrfNonceBytes+8)
+	expired, _ := csrf.signer.SignBound(ctx, base64.RawURLEncoding.EncodeToString(payload), csrfBinding("s"))
+	err := csrf.Verify(ctx, "s", expired)
+	if !errors.Is(err, ErrCSRFTokenExpired) || !errors.Is(err, ErrInvalidCSRFToken) {
+		t.Errorf("Expected ErrCSRFTokenExpired, got %v", err)
+	}
+	if code := ErrorCode(err); code != CodeCSRFTokenExpired {
+		t.Errorf("Expected code %s, got %s", CodeCSRFTokenExpired, code)
+	}
+
+	// A token from a clock-driven source expires once the clock passes ttl
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	timed := newTestCSRF(time.Minute, WithCSRFClock(clock))
+	token, _ := timed.Generate(ctx, "s")
+	if err := timed.Verify(ctx, "s", token); err != nil {
+		t.Errorf("Expected a fresh token to verify, got %v", err)
+	}
+	clock.Advance(time.Minute)
+	if err := timed.Verify(ctx, "s", token); !errors.Is(err, ErrCSRFTokenExpired) {
+		t.Errorf("Expected ErrCSRFTokenExpired after ttl, got %v", err)
+	}
+
+	// A validly signed payload of the wrong shape
+	short, _ := csrf.signer.SignBound(ctx, "abc", csrfBinding("s"))
+	if err := csrf.Verify(ctx, "s", short); !errors.Is(err, ErrInvalidCSRFToken) {
+		t.Errorf("Expected ErrInvalidCSRFToken for a malformed payload, got %v", err)
+	}
+}
+
+func TestCSRFDoubleSubmit(t *testing.T) {
+	csrf := newTestCSRF(time.Hour)
+	rec := httptest.NewRecorder()
+	token, err := csrf.SetCookie(rec, "session-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cookie := rec.Result().Cookies()[0]
+	if cookie.Name != DefaultCSRFCookie || cookie.Value != token || !cookie.Secure || cookie.SameSite != http.SameSiteStrictMode {
+		t.Errorf("Expected a secure strict cookie with the token, got %+v", cookie)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.AddCookie(cookie)
+	req.Header.Set(DefaultCSRFHeader, token)
+	if err := csrf.VerifyRequest(req, "session-1"); err != nil {
+		t.Errorf("Expected the header token to verify, got %v", err)
+	}
+
+	other, _ := csrf.Generate(context.Background(), "session-1")
+	req.Header.Set(DefaultCSRFHeader, other)
+	if err := csrf.VerifyRequest(req, "session-1"); !errors.Is(err, ErrInvalidCSRFToken) {
+		t.Errorf("Expected a token differing from the cookie to fail, got %v", err)
+	}
+
+	form := url.Values{DefaultCSRFField: {token}}
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := csrf.VerifyRequest(req, "session-1"); err != nil {
+		t.Errorf("Expected the form token to verify, got %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	if err := csrf.VerifyRequest(req, "session-1"); !errors.Is(err, ErrInvalidCSRFToken) {
+		t.Errorf("Expected a request without a token to fail, got %v", err)
+	}
+}
+
+func TestCSRFCookieRequired(t *testing.T) {
+	// An attacker can obtain a session-less token on their own visit
+	forged, err := newTestCSRF(time.Hour).Generate(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	form := url.Values{DefaultCSRFField: {forged}}
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := newTestCSRF(time.Hour).VerifyRequest(req, ""); !errors.Is(err, ErrInvalidCSRFToken) {
+		t.Errorf("Expected a session-less token without the cookie to fail, got %v", err)
+	}
+
+	csrf := newTestCSRF(time.Hour)
+	rec := httptest.NewRecorder()
+	token, err := csrf.SetCookie(rec, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	req = httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.AddCookie(rec.Result().Cookies()[0])
+	req.Header.Set(DefaultCSRFHeader, token)
+	if err := csrf.VerifyRequest(req, ""); err != nil {
+		t.Errorf("Expected a session-less token with the cookie to verify, got %v", err)
+	}
+
+	strict := NewCSRF(newStaticKeys("k1", map[string]string{"k1": "secret"}), time.Hour, WithCSRFCookieRequired())
+	token, _ = strict.Generate(context.Background(), "session-1")
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(DefaultCSRFHeader, token)
+	if err := strict.VerifyRequest(req, "session-1"); !errors.Is(err, ErrInvalidCSRFToken) {
+		t.Errorf("Expected WithCSRFCookieRequired to reject a request without the cookie, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package idforge
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// SQLDialect selects the database flavour of generated DDL
+type SQLDialect int
+
+const (
+	Postgres SQLDialect = iota
+	MySQL
+)
+
+func (d SQLDialect) String() string {
+	switch d {
+	case Postgres:
+		return "postgres"
+	case MySQL:
+		return "mysql"
+	default:
+		return "unknown"
+	}
+}
+
+// ColumnDefinition returns the recommended definition of an ID column for
+// the IDs gen produces, with a CHECK constraint enforcing its pattern, such
+// as "id" CHAR(21) NOT NULL CHECK ("id" ~ '^[0-9A-Za-z]{21}$'). MySQL
+// columns use a binary collation, so IDs differing only in case stay
+// distinct.
+func ColumnDefinition(column string, gen Shaped, dialect SQLDialect) string {
+	shape := gen.Shape()
+	return fmt.Sprintf("%s %s NOT NULL CHECK (%s)",
+		quoteIdentifier(column, dialect), columnType(shape, dialect), checkExpression(column, shape, dialect))
+}
+
+// CheckConstraint returns the CHECK expression alone, for adding it to an
+// existing column with ALTER TABLE
+func CheckConstraint(column string, gen Shaped, dialect SQLDialect) string {
+	return checkExpression(column, gen.Shape(), dialect)
+}
+
+// columnType picks CHAR for fixed-length IDs and VARCHAR otherwise
+func columnType(shape IDShape, dialect SQLDialect) string {
+	var typ string
+	switch {
+	case shape.MaxLength == shape.MinLength:
+		typ = fmt.Sprintf("CHAR(%d)", shape.MaxLength)
+	case shape.MaxLength > 0:
+		typ = fmt.Sprintf("VARCHAR(%d)", shape.MaxLength)
+	case dialect == MySQL:
+		typ = "VARCHAR(255)" // Still short enough to index
+	default:
+		typ = "TEXT"
+	}
+
+	if dialect == MySQL {
+		if isASCII(shape.Pattern) {
+			typ += " CHARACTER SET ascii COLLATE ascii_bin"
+		} else {
+			typ += " CHARACTER SET utf8mb4 COLLATE utf8mb4_bin"
+		}
+	}
+	return typ
+}
+
+// checkExpression matches column against the shape's pattern, case
+// sensitively
+func checkExpression(column string, shape IDShape, dialect SQLDialect) string {
+	column = quoteIdentifier(column, dialect)
+	if dialect == MySQL {
+		return fmt.Sprintf("REGEXP_LIKE(%s, %s, 'c')", column, quoteString(shape.Pattern, dialect))
+	}
+	return fmt.Sprintf("%s ~ %s", column, quoteString(shape.Pattern, dialect))
+}
+
+// quoteIdentifier quotes a table or column name
+func quoteIdentifier(name string, dialect SQLDialect) string {
+	if dialect == MySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteString writes s as a string literal. MySQL treats backslashes in
+// literals as escapes by default, so they are doubled.
+func quoteString(s string, dialect SQLDialect) string {
+	if dialect == MySQL {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// isASCII reports whether s contains only ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
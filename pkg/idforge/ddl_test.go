@@ -0,0 +1,47 @@
+package idforge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColumnDefinitionPostgres(t *testing.T) {
+	got := ColumnDefinition("id", New(), Postgres)
+	want := `"id" CHAR(21) NOT NULL CHECK ("id" ~ '^[0-9A-Za-z]{21}$')`
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestColumnDefinitionMySQL(t *testing.T) {
+	got := ColumnDefinition("id", New(), MySQL)
+	want := "`id` CHAR(21) CHARACTER SET ascii COLLATE ascii_bin NOT NULL CHECK (REGEXP_LIKE(`id`, '^[0-9A-Za-z]{21}$', 'c'))"
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	multiByte := ColumnDefinition("id", New(WithAlphabet(cyrillicAlphabet)), MySQL)
+	if !strings.Contains(multiByte, "utf8mb4_bin") {
+		t.Errorf("Expected a utf8mb4 column for a Cyrillic alphabet, got %s", multiByte)
+	}
+}
+
+func TestColumnDefinitionVariableLength(t *testing.T) {
+	gen := NewExtendedGenerator(WithCollisionStrategy(CollisionGrowSize))
+	if got := ColumnDefinition("id", gen, Postgres); !strings.Contains(got, `"id" TEXT NOT NULL`) {
+		t.Errorf("Expected a TEXT column, got %s", got)
+	}
+	if got := ColumnDefinition("id", gen, MySQL); !strings.Contains(got, "VARCHAR(255)") {
+		t.Errorf("Expected a VARCHAR(255) column, got %s", got)
+	}
+}
+
+func TestCheckConstraintEscaping(t *testing.T) {
+	gen := New(WithAlphabet(`ab'\`), WithSize(4))
+	if got, want := CheckConstraint(`we"ird`, gen, Postgres), `"we""ird" ~ '^[''\\ab]{4}$'`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if got, want := CheckConstraint("id", gen, MySQL), "REGEXP_LIKE(`id`, '^[''\\\\\\\\ab]{4}$', 'c')"; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,146 @@
+package idforge
+
+import (
+	"container/list"
+	"time"
+)
+
+// WithUniqueIDRetention limits how long issued IDs are remembered for
+// duplicate detection, in addition to the MaxUniqueIDs bound
+func WithUniqueIDRetention(retention time.Duration) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		if retention > 0 {
+			c.UniqueIDRetention = retention
+		}
+	}
+}
+
+// issuedEntry records when an ID was handed out
+type issuedEntry struct {
+	id       string
+	issuedAt time.Time
+}
+
+// issuedSet remembers recently issued IDs. Once it holds capacity IDs, or
+// an ID is older than retention, the oldest IDs are forgotten one at a
+// time, so the guarantee degrades gradually instead of being dropped all at
+// once. A zero retention keeps IDs until capacity forces them out.
+type issuedSet struct {
+	capacity  int
+	retention time.Duration
+	order     *list.List // Oldest entry at the front
+	index     map[string]*list.Element
+	near      *BKTree // Indexes the same IDs by edit distance, nil unless needed
+}
+
+func newIssuedSet(capacity int, retention time.Duration) *issuedSet {
+	return &issuedSet{
+		capacity:  capacity,
+		retention: retention,
+		order:     list.New(),
+		index:     make(map[string]*list.Element),
+	}
+}
+
+// contains reports whether id was issued within the retention window
+func (s *issuedSet) contains(id string, now time.Time) bool {
+	s.expire(now)
+	_, ok := s.index[id]
+	return ok
+}
+
+// trackNear maintains an edit-distance index of the set when enabled
+func (s *issuedSet) trackNear(enabled bool) {
+	switch {
+	case !enabled:
+		s.near = nil
+	case s.near == nil:
+		s.near = NewBKTree()
+		for e := s.order.Front(); e != nil; e = e.Next() {
+			s.near.Add(e.Value.(issuedEntry).id)
+		}
+	}
+}
+
+// nearby reports whether an ID within maxDistance edits of id was issued
+// within the retention window
+func (s *issuedSet) nearby(id string, maxDistance int, now time.Time) bool {
+	s.expire(now)
+	return s.near != nil && s.near.Has(id, maxDistance)
+}
+
+// add records id as issued at now, evicting the oldest IDs beyond capacity
+func (s *issuedSet) add(id string, now time.Time) {
+	if _, ok := s.index[id]; ok {
+		return
+	}
+	s.index[id] = s.order.PushBack(issuedEntry{id: id, issuedAt: now})
+	if s.near != nil {
+		s.near.Add(id)
+	}
+	s.evict()
+}
+
+// addAt records id as issued at issuedAt, which may predate IDs already in
+// the set, keeping the set ordered by issue time
+func (s *issuedSet) addAt(id string, issuedAt time.Time) {
+	if _, ok := s.index[id]; ok {
+		return
+	}
+
+	entry := issuedEntry{id: id, issuedAt: issuedAt}
+	mark := s.order.Back()
+	for mark != nil && mark.Value.(issuedEntry).issuedAt.After(issuedAt) {
+		mark = mark.Prev()
+	}
+	if mark == nil {
+		s.index[id] = s.order.PushFront(entry)
+	} else {
+		s.index[id] = s.order.InsertAfter(entry, mark)
+	}
+	if s.near != nil {
+		s.near.Add(id)
+	}
+	s.evict()
+}
+
+// resize applies new limits, keeping as many recent IDs as they allow
+func (s *issuedSet) resize(capacity int, retention time.Duration, now time.Time) {
+	s.capacity = capacity
+	s.retention = retention
+	s.evict()
+	s.expire(now)
+}
+
+func (s *issuedSet) len() int {
+	return s.order.Len()
+}
+
+// evict drops the oldest IDs until the set fits its capacity
+func (s *issuedSet) evict() {
+	for s.order.Len() > max(s.capacity, 0) {
+		s.remove(s.order.Front())
+	}
+}
+
+// expire drops IDs issued longer than retention ago
+func (s *issuedSet) expire(now time.Time) {
+	if s.retention <= 0 {
+		return
+	}
+	for front := s.order.Front(); front != nil; front = s.order.Front() {
+		if now.Sub(front.Value.(issuedEntry).issuedAt) < s.retention {
+			return
+		}
+		s.remove(front)
+	}
+}
+
+func (s *issuedSet) remove(e *list.Element) {
+	id := e.Value.(issuedEntry).id
+	delete(s.index, id)
+	s.order.Remove(e)
+	if s.near != nil {
+		s.near.Remove(id)
+	}
+}
@@ -0,0 +1,75 @@
+package idforge
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestIssuedSetEvictsOldestFirst(t *testing.T) {
+	set := newIssuedSet(3, 0)
+	now := time.Now()
+
+	for i := 0; i < 5; i++ {
+		set.add(strconv.Itoa(i), now)
+	}
+
+	if set.len() != 3 {
+		t.Errorf("Expected 3 remembered IDs, got %d", set.len())
+	}
+	for i, want := range []bool{false, false, true, true, true} {
+		if got := set.contains(strconv.Itoa(i), now); got != want {
+			t.Errorf("ID %d: expected contains=%v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestIssuedSetRetention(t *testing.T) {
+	set := newIssuedSet(10, time.Minute)
+	start := time.Now()
+
+	set.add("old", start)
+	set.add("new", start.Add(45*time.Second))
+
+	later := start.Add(90 * time.Second)
+	if set.contains("old", later) {
+		t.Error("Expected IDs beyond the retention window to be forgotten")
+	}
+	if !set.contains("new", later) {
+		t.Error("Expected IDs within the retention window to be remembered")
+	}
+}
+
+func TestIssuedSetResize(t *testing.T) {
+	set := newIssuedSet(5, 0)
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		set.add(strconv.Itoa(i), now)
+	}
+
+	set.resize(2, 0, now)
+	if set.len() != 2 || !set.contains("4", now) || set.contains("2", now) {
+		t.Errorf("Expected only the two most recent IDs after shrinking, got %d", set.len())
+	}
+}
+
+func TestExtendedGeneratorKeepsRecentIDsAtCapacity(t *testing.T) {
+	gen := NewExtendedGenerator(func(cfg *GeneratorConfig) {
+		cfg.MaxUniqueIDs = 10
+	})
+	ctx := context.Background()
+
+	var last string
+	for i := 0; i < 25; i++ {
+		last, _ = gen.Generate(ctx)
+	}
+
+	// Reaching the limit no longer wipes every remembered ID
+	if gen.issued.len() != 10 {
+		t.Errorf("Expected 10 remembered IDs, got %d", gen.issued.len())
+	}
+	if !gen.issued.contains(last, time.Now()) {
+		t.Error("Expected the most recent ID to still be remembered")
+	}
+}
@@ -0,0 +1,158 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var ErrCorruptDeviceID = errors.New("device ID file is corrupt")
+
+// DeviceIDOption configures a DeviceIDProvisioner
+type DeviceIDOption func(*DeviceIDProvisioner)
+
+// WithDeviceIDGenerator replaces the basic Generator that creates the ID
+func WithDeviceIDGenerator(generate func() (string, error)) DeviceIDOption {
+	return func(p *DeviceIDProvisioner) {
+		if generate != nil {
+			p.generate = generate
+		}
+	}
+}
+
+// DeviceIDProvisioner gives a device one stable identifier: the first call
+// to ID creates it and persists it at a path, and every later call, in this
+// process or after a reboot, returns the stored value
+type DeviceIDProvisioner struct {
+	path     string
+	generate func() (string, error)
+
+	mu sync.Mutex
+	id string // Cached once read or written
+}
+
+// NewDeviceIDProvisioner creates a provisioner storing the ID at path. The
+// directory must exist; a lock file is kept next to path.
+func NewDeviceIDProvisioner(path string, opts ...DeviceIDOption) *DeviceIDProvisioner {
+	p := &DeviceIDProvisioner{
+		path:     path,
+		generate: New().Generate,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Path returns where the ID is stored
+func (p *DeviceIDProvisioner) Path() string {
+	return p.path
+}
+
+// ID returns the device's identifier, creating and persisting it on first
+// use. Provisioning holds a file lock, so concurrent processes agree on one
+// ID; the file is replaced atomically, so a power loss leaves either no ID
+// or a complete one. A stored ID failing its checksum is reported as
+// ErrCorruptDeviceID rather than replaced, since the device would silently
+// change identity.
+func (p *DeviceIDProvisioner) ID(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.id != "" {
+		return p.id, nil
+	}
+
+	unlock, err := lockFile(ctx, p.path+".lock")
+	if err != nil {
+		return "", fmt.Errorf("device ID lock: %w", err)
+	}
+	defer unlock()
+
+	id, err := readDeviceID(p.path)
+	if errors.Is(err, os.ErrNotExist) {
+		id, err = p.provision()
+	}
+	if err != nil {
+		return "", err
+	}
+	p.id = id
+	return id, nil
+}
+
+// provision creates an ID and writes it to p.path; the caller must hold
+// the file lock
+func (p *DeviceIDProvisioner) provision() (string, error) {
+	id, err := p.generate()
+	if err != nil {
+		return "", err
+	}
+	if id == "" || strings.ContainsAny(id, "\r\n") {
+		return "", fmt.Errorf("%w: device ID must be a single non-empty line", ErrInvalidConfig)
+	}
+	if err := writeFileAtomic(p.path, formatDeviceID(id)); err != nil {
+		return "", fmt.Errorf("device ID write: %w", err)
+	}
+	return id, nil
+}
+
+// formatDeviceID stores id on the first line and its CRC-32 in hex on the
+// second
+func formatDeviceID(id string) []byte {
+	return fmt.Appendf(nil, "%s\n%08x\n", id, crc32.ChecksumIEEE([]byte(id)))
+}
+
+// readDeviceID reads and verifies the ID stored at path
+func readDeviceID(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	id, sum, ok := strings.Cut(strings.TrimSuffix(string(data), "\n"), "\n")
+	if !ok || id == "" {
+		return "", ErrCorruptDeviceID
+	}
+	want, err := strconv.ParseUint(sum, 16, 32)
+	if err != nil || len(sum) != 8 || uint32(want) != crc32.ChecksumIEEE([]byte(id)) {
+		return "", ErrCorruptDeviceID
+	}
+	return id, nil
+}
+
+// writeFileAtomic replaces path with data through a synced temporary file
+// in the same directory
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Persist the rename itself; not every platform can sync a directory
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
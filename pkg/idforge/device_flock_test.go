@@ -0,0 +1,26 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package idforge
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDeviceIDProvisionerLockTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "device-id")
+	unlock, err := lockFile(context.Background(), path+".lock")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := NewDeviceIDProvisioner(path).ID(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the lock wait to time out, got %v", err)
+	}
+}
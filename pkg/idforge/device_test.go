@@ -0,0 +1,115 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestDeviceIDProvisioner(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "device-id")
+	calls := 0
+	generate := func() (string, error) {
+		calls++
+		return New().Generate()
+	}
+
+	p := NewDeviceIDProvisioner(path, WithDeviceIDGenerator(generate))
+	id, err := p.ID(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	again, _ := p.ID(context.Background())
+	if again != id {
+		t.Errorf("Expected the same ID, got %s and %s", id, again)
+	}
+
+	// A fresh provisioner, as after a reboot, reads the stored ID
+	restarted := NewDeviceIDProvisioner(path, WithDeviceIDGenerator(generate))
+	stored, err := restarted.ID(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stored != id {
+		t.Errorf("Expected stored ID %s, got %s", id, stored)
+	}
+	if calls != 1 {
+		t.Errorf("Expected one generated ID, got %d", calls)
+	}
+	if restarted.Path() != path {
+		t.Errorf("Expected path %s, got %s", path, restarted.Path())
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".device-id.*"))
+	if len(matches) != 0 {
+		t.Errorf("Expected no temporary files left, got %v", matches)
+	}
+}
+
+func TestDeviceIDProvisionerConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "device-id")
+	ids := make([]string, 8)
+	var wg sync.WaitGroup
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Separate provisioners only share the file lock
+			id, err := NewDeviceIDProvisioner(path).ID(context.Background())
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			ids[i] = id
+		}(i)
+	}
+	wg.Wait()
+	for _, id := range ids[1:] {
+		if id != ids[0] {
+			t.Fatalf("Expected all provisioners to agree, got %v", ids)
+		}
+	}
+}
+
+func TestDeviceIDProvisionerCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "device-id")
+	id, _ := NewDeviceIDProvisioner(path).ID(context.Background())
+	checksum := string(formatDeviceID(id)[len(id):]) // "\n%08x\n"
+
+	for _, content := range []string{
+		"!" + id[1:] + checksum,
+		id + "\n",
+		"\n00000000\n",
+		id + "\nnot-hex!\n",
+	} {
+		os.WriteFile(path, []byte(content), 0o644)
+		if _, err := NewDeviceIDProvisioner(path).ID(context.Background()); !errors.Is(err, ErrCorruptDeviceID) {
+			t.Errorf("Expected ErrCorruptDeviceID for %q, got %v", content, err)
+		}
+	}
+}
+
+func TestDeviceIDProvisionerErrors(t *testing.T) {
+	dir := t.TempDir()
+	failing := errors.New("no entropy")
+	p := NewDeviceIDProvisioner(filepath.Join(dir, "a"), WithDeviceIDGenerator(func() (string, error) {
+		return "", failing
+	}))
+	if _, err := p.ID(context.Background()); !errors.Is(err, failing) {
+		t.Errorf("Expected the generator error, got %v", err)
+	}
+
+	p = NewDeviceIDProvisioner(filepath.Join(dir, "b"), WithDeviceIDGenerator(func() (string, error) {
+		return "two\nlines", nil
+	}))
+	if _, err := p.ID(context.Background()); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for a multi-line ID, got %v", err)
+	}
+
+	p = NewDeviceIDProvisioner(filepath.Join(dir, "missing", "c"))
+	if _, err := p.ID(context.Background()); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
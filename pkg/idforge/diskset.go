@@ -0,0 +1,146 @@
+package idforge
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/maphash"
+	"math/bits"
+	"os"
+	"sync"
+)
+
+var ErrDedupeFull = errors.New("bulk dedupe store is full")
+
+// diskSetSlot is the size of a fingerprint slot
+const diskSetSlot = 16
+
+// WithDiskDedupe deduplicates the batch in a temporary file in dir instead
+// of memory, for jobs of hundreds of millions of IDs. IDs are stored as
+// 128-bit fingerprints in a memory-mapped hash table where the platform
+// supports it, so the operating system pages the table to disk as needed.
+// A fingerprint clash only causes a redraw. The file is removed when
+// GenerateBulk returns.
+func WithDiskDedupe(dir string) BulkOption {
+	return func(c *bulkConfig) {
+		c.diskDir = dir
+		c.disk = true
+	}
+}
+
+// dedupeSet records the IDs of one batch
+type dedupeSet interface {
+	// add inserts id and reports whether it was new
+	add(id string) (bool, error)
+	close() error
+}
+
+// diskSet is an open-addressing hash set of ID fingerprints in a file. The
+// table is split into independently locked partitions, each probed
+// linearly, so workers rarely wait for each other.
+type diskSet struct {
+	file  *os.File
+	table slotTable
+	seeds [2]maphash.Seed
+	parts [bulkStripes]struct {
+		mu   sync.Mutex
+		used int
+	}
+	partSlots int // Slots per partition, a power of two
+}
+
+// slotTable reads and writes the fingerprint slots of the backing file
+type slotTable interface {
+	get(i int, dst []byte) error
+	set(i int, src []byte) error
+	close() error
+}
+
+// newDiskSet creates a set sized for capacity IDs at half load
+func newDiskSet(dir string, capacity int) (*diskSet, error) {
+	perPart := max(2*(capacity/bulkStripes+1), 1024)
+	partSlots := 1 << bits.Len(uint(perPart-1))
+
+	file, err := os.CreateTemp(dir, "idforge-dedupe-*")
+	if err != nil {
+		return nil, err
+	}
+	size := int64(bulkStripes) * int64(partSlots) * diskSetSlot
+	table, err := mapSlots(file, size)
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	return &diskSet{
+		file:      file,
+		table:     table,
+		seeds:     [2]maphash.Seed{maphash.MakeSeed(), maphash.MakeSeed()},
+		partSlots: partSlots,
+	}, nil
+}
+
+func (s *diskSet) add(id string) (bool, error) {
+	var fp [diskSetSlot]byte
+	h := maphash.String(s.seeds[0], id)
+	binary.LittleEndian.PutUint64(fp[:8], h)
+	binary.LittleEndian.PutUint64(fp[8:], maphash.String(s.seeds[1], id))
+	if fp == ([diskSetSlot]byte{}) {
+		fp[0] = 1 // The zero fingerprint marks empty slots
+	}
+
+	p := int(h % bulkStripes)
+	part := &s.parts[p]
+	part.mu.Lock()
+	defer part.mu.Unlock()
+
+	if part.used >= s.partSlots*3/4 {
+		return false, ErrDedupeFull
+	}
+	var slot [diskSetSlot]byte
+	mask := s.partSlots - 1
+	for i := int(h>>32) & mask; ; i = (i + 1) & mask {
+		index := p*s.partSlots + i
+		if err := s.table.get(index, slot[:]); err != nil {
+			return false, err
+		}
+		if slot == fp {
+			return false, nil
+		}
+		if slot == ([diskSetSlot]byte{}) {
+			part.used++
+			return true, s.table.set(index, fp[:])
+		}
+	}
+}
+
+// close releases the table and removes the backing file
+func (s *diskSet) close() error {
+	err := s.table.close()
+	if cerr := s.file.Close(); err == nil {
+		err = cerr
+	}
+	if rerr := os.Remove(s.file.Name()); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// fileSlots accesses slots with positioned reads and writes, for platforms
+// without mmap
+type fileSlots struct {
+	file *os.File
+}
+
+func (f fileSlots) get(i int, dst []byte) error {
+	_, err := f.file.ReadAt(dst, int64(i)*diskSetSlot)
+	return err
+}
+
+func (f fileSlots) set(i int, src []byte) error {
+	_, err := f.file.WriteAt(src, int64(i)*diskSetSlot)
+	return err
+}
+
+func (f fileSlots) close() error {
+	return nil
+}
@@ -0,0 +1,39 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package idforge
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapSlots accesses slots through a shared memory mapping of the file
+type mmapSlots struct {
+	data []byte
+}
+
+// mapSlots grows file to size and maps it into memory
+func mapSlots(file *os.File, size int64) (slotTable, error) {
+	if err := file.Truncate(size); err != nil {
+		return nil, err
+	}
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	return &mmapSlots{data: data}, nil
+}
+
+func (m *mmapSlots) get(i int, dst []byte) error {
+	copy(dst, m.data[i*diskSetSlot:])
+	return nil
+}
+
+func (m *mmapSlots) set(i int, src []byte) error {
+	copy(m.data[i*diskSetSlot:], src)
+	return nil
+}
+
+func (m *mmapSlots) close() error {
+	return syscall.Munmap(m.data)
+}
@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package idforge
+
+import "os"
+
+// mapSlots grows file to size and accesses it with positioned reads and
+// writes, since this platform has no mmap
+func mapSlots(file *os.File, size int64) (slotTable, error) {
+	if err := file.Truncate(size); err != nil {
+		return nil, err
+	}
+	return fileSlots{file: file}, nil
+}
@@ -0,0 +1,94 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestDiskSet(t *testing.T) {
+	dir := t.TempDir()
+	set, err := newDiskSet(dir, 10000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i := 0; i < 10000; i++ {
+		added, err := set.add(fmt.Sprintf("id-%d", i))
+		if err != nil || !added {
+			t.Fatalf("Expected id-%d to be new, got %v (err %v)", i, added, err)
+		}
+	}
+	for _, id := range []string{"id-0", "id-4242", "id-9999"} {
+		if added, err := set.add(id); err != nil || added {
+			t.Errorf("Expected %s to be a duplicate, got %v (err %v)", id, added, err)
+		}
+	}
+
+	if err := set.close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected the backing file to be removed, found %d entries", len(entries))
+	}
+}
+
+func TestDiskSetFull(t *testing.T) {
+	set, err := newDiskSet(t.TempDir(), 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer set.close()
+
+	for i := range set.parts {
+		set.parts[i].used = set.partSlots
+	}
+	if _, err := set.add("id"); !errors.Is(err, ErrDedupeFull) {
+		t.Errorf("Expected ErrDedupeFull, got %v", err)
+	}
+}
+
+func TestFileSlots(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "slots")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := file.Truncate(4 * diskSetSlot); err != nil {
+		t.Fatal(err)
+	}
+
+	slots := fileSlots{file: file}
+	want := []byte("0123456789abcdef")
+	if err := slots.set(2, want); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got := make([]byte, diskSetSlot)
+	if err := slots.get(2, got); err != nil || string(got) != string(want) {
+		t.Errorf("Expected %q, got %q (err %v)", want, got, err)
+	}
+}
+
+func TestGenerateBulkWithDiskDedupe(t *testing.T) {
+	dir := t.TempDir()
+	ids, err := NewExtendedGenerator().GenerateBulk(context.Background(), 5000, 4, WithDiskDedupe(dir))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("Duplicate ID %s", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != 5000 {
+		t.Errorf("Expected 5000 unique IDs, got %d", len(seen))
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected the dedupe file to be removed, found %d entries", len(entries))
+	}
+}
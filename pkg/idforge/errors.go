@@ -0,0 +1,73 @@
+package idforge
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+// Error kinds callers can branch on with errors.Is. More specific errors,
+// such as ErrInvalidAlphabet or ErrProviderTimeout, wrap one of these.
+var (
+	ErrInvalidConfig      = errors.New("invalid configuration")
+	ErrEntropyUnavailable = entropy.ErrUnavailable
+	ErrCollision          = errors.New("ID collides with an issued ID")
+	ErrValidation         = errors.New("ID failed validation")
+)
+
+// Rules reported in validation violations
+const (
+	RuleLength   = "length"
+	RuleEncoding = "encoding"
+	RuleAlphabet = "alphabet"
+	RuleRevoked  = "revoked"
+)
+
+// Violation describes one rule an ID breaks
+type Violation struct {
+	Rule    string
+	Message string // English description
+	Args    []any  // Values formatted into Message, in order, for translations
+}
+
+// ValidationError lists every rule an ID breaks. It matches ErrValidation
+// with errors.Is and can be retrieved with errors.As.
+type ValidationError struct {
+	ID         string
+	violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.violations))
+	for i, v := range e.violations {
+		messages[i] = v.Message
+	}
+	return ErrValidation.Error() + ": " + strings.Join(messages, "; ")
+}
+
+// Is reports whether target is ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Violations returns the rules the ID breaks
+func (e *ValidationError) Violations() []Violation {
+	return append([]Violation(nil), e.violations...)
+}
+
+// addViolation appends v to err, a *ValidationError for id or nil
+func addViolation(err error, id string, v Violation) error {
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		verr.violations = append(verr.violations, v)
+		return verr
+	}
+	return &ValidationError{ID: id, violations: []Violation{v}}
+}
+
+// CheckID is like IsValidID but explains why an ID is rejected with a
+// *ValidationError
+func CheckID(id string, alphabet string, size int) error {
+	return newCharset(alphabet).check(id, size)
+}
@@ -0,0 +1,103 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+func TestConfigErrorsWrapInvalidConfig(t *testing.T) {
+	for _, err := range []error{ErrInvalidAlphabet, ErrInvalidSize, ErrFIPSIncompatible} {
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("Expected %v to wrap ErrInvalidConfig", err)
+		}
+	}
+}
+
+func TestEntropyUnavailable(t *testing.T) {
+	basic := New()
+	basic.entropy = []entropy.EntropyProvider{failingEntropy{}}
+	if _, err := basic.Generate(); !errors.Is(err, ErrEntropyUnavailable) {
+		t.Errorf("Expected ErrEntropyUnavailable from Generator, got %v", err)
+	}
+
+	extended := NewExtendedGenerator(
+		WithEntropyProviders([]entropy.EntropyProvider{failingEntropy{}}),
+	)
+	if _, err := extended.Generate(context.Background()); !errors.Is(err, ErrEntropyUnavailable) {
+		t.Errorf("Expected ErrEntropyUnavailable from ExtendedGenerator, got %v", err)
+	}
+}
+
+func TestCollisionExhaustion(t *testing.T) {
+	gen := NewExtendedGenerator(
+		WithCustomAlphabet("01"),
+		func(cfg *GeneratorConfig) {
+			cfg.Size = 1
+			cfg.UniquenessPressure = 1
+		},
+	)
+	gen.issued.add("0", time.Now())
+	gen.issued.add("1", time.Now())
+
+	_, err := gen.Generate(context.Background())
+	if !errors.Is(err, ErrCollision) || !errors.Is(err, ErrGenerationTimeout) {
+		t.Errorf("Expected ErrCollision wrapped in ErrGenerationTimeout, got %v", err)
+	}
+}
+
+func TestCheckID(t *testing.T) {
+	if err := CheckID("abc", "abc", 3); err != nil {
+		t.Errorf("Expected valid ID, got %v", err)
+	}
+
+	tests := []struct {
+		id    string
+		rules []string
+	}{
+		{"ab", []string{RuleLength}},
+		{"abx", []string{RuleAlphabet}},
+		{"xyzw", []string{RuleLength, RuleAlphabet}},
+		{"a\xffb", []string{RuleEncoding}},
+	}
+
+	for _, tt := range tests {
+		err := CheckID(tt.id, "abc", 3)
+		if !errors.Is(err, ErrValidation) {
+			t.Fatalf("Expected ErrValidation for %q, got %v", tt.id, err)
+		}
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("Expected *ValidationError for %q", tt.id)
+		}
+		violations := verr.Violations()
+		if len(violations) != len(tt.rules) {
+			t.Fatalf("Expected %d violations for %q, got %v", len(tt.rules), tt.id, violations)
+		}
+		for i, rule := range tt.rules {
+			if violations[i].Rule != rule {
+				t.Errorf("Expected rule %s for %q, got %s", rule, tt.id, violations[i].Rule)
+			}
+		}
+	}
+}
+
+func TestGeneratorCheckRevoked(t *testing.T) {
+	list := NewRevocationList(nil)
+	gen := New(WithRevocationCheck(list))
+	id := gen.MustGenerate()
+
+	if err := gen.Check(id); err != nil {
+		t.Errorf("Expected %s to pass, got %v", id, err)
+	}
+
+	list.Add(context.Background(), id)
+	var verr *ValidationError
+	if err := gen.Check(id); !errors.As(err, &verr) || verr.Violations()[0].Rule != RuleRevoked {
+		t.Errorf("Expected a revoked violation, got %v", err)
+	}
+}
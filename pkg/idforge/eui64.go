@@ -0,0 +1,172 @@
+//go:build !idforge_lite
+
+package idforge
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+var ErrInvalidHardwareAddr = errors.New("hardware address must be an EUI-48 or EUI-64")
+
+func init() {
+	registerCode(ErrInvalidHardwareAddr, CodeInvalidHardwareAddr)
+}
+
+// InterfaceID is the 64-bit interface identifier that ends an IPv6 address
+type InterfaceID [8]byte
+
+// ModifiedEUI64 derives the interface identifier of an EUI-48 or EUI-64
+// hardware address as in RFC 4291: an EUI-48 gets FFFE inserted in the
+// middle, and the universal/local bit is inverted
+func ModifiedEUI64(mac net.HardwareAddr) (InterfaceID, error) {
+	var id InterfaceID
+	switch len(mac) {
+	case 6:
+		copy(id[:3], mac[:3])
+		id[3], id[4] = 0xff, 0xfe
+		copy(id[5:], mac[3:])
+	case 8:
+		copy(id[:], mac)
+	default:
+		return InterfaceID{}, ErrInvalidHardwareAddr
+	}
+	id[0] ^= 0x02
+	return id, nil
+}
+
+// ParseInterfaceID reads an identifier in the form String writes, with
+// leading zeros in each group optional
+func ParseInterfaceID(s string) (InterfaceID, error) {
+	addr, err := netip.ParseAddr("::" + s)
+	groups := strings.Split(s, ":")
+	if err != nil || len(groups) != 4 || !addr.Is6() {
+		return InterfaceID{}, fmt.Errorf("%w: not an interface identifier", ErrInvalidEncoding)
+	}
+	b := addr.As16()
+	return InterfaceID(b[8:]), nil
+}
+
+// String writes the identifier as four groups of hex digits, as in the
+// last half of an IPv6 address: 021b:21ff:fe3c:4d5e
+func (id InterfaceID) String() string {
+	return fmt.Sprintf("%04x:%04x:%04x:%04x",
+		binary.BigEndian.Uint16(id[0:]), binary.BigEndian.Uint16(id[2:]),
+		binary.BigEndian.Uint16(id[4:]), binary.BigEndian.Uint16(id[6:]))
+}
+
+// EUI64 writes the identifier in IEEE form: 02-1B-21-FF-FE-3C-4D-5E
+func (id InterfaceID) EUI64() string {
+	return strings.ToUpper(strings.ReplaceAll(net.HardwareAddr(id[:]).String(), ":", "-"))
+}
+
+// Addr combines the identifier with an IPv6 prefix of at most 64 bits
+func (id InterfaceID) Addr(prefix netip.Prefix) (netip.Addr, error) {
+	if !prefix.Addr().Is6() || prefix.Bits() < 0 || prefix.Bits() > 64 {
+		return netip.Addr{}, fmt.Errorf("%w: interface identifiers need an IPv6 prefix of up to 64 bits", ErrInvalidConfig)
+	}
+	b := prefix.Masked().Addr().As16()
+	copy(b[8:], id[:])
+	return netip.AddrFrom16(b), nil
+}
+
+// reserved reports whether id is one of the identifiers reserved by
+// RFC 5453, which must not be assigned to interfaces
+func (id InterfaceID) reserved() bool {
+	v := binary.BigEndian.Uint64(id[:])
+	return v == 0 || // Subnet-router anycast
+		(v >= 0x02005efffe000000 && v <= 0x02005efffeffffff) || // Proxy Mobile IPv6 and reserved
+		(v >= 0xfdffffffffffff80 && v <= 0xfdffffffffffffff) // Subnet anycast
+}
+
+// InterfaceIDOption configures an InterfaceIDGenerator
+type InterfaceIDOption func(*InterfaceIDGenerator)
+
+// WithStablePrivacy derives identifiers by hashing the hardware address
+// with secret and the network prefix, as in RFC 7217, instead of embedding
+// the address. Identifiers stay stable within a network but differ between
+// networks, so they can neither be traced to the hardware nor followed
+// across networks. secret must stay the same for stable identifiers.
+func WithStablePrivacy(secret []byte) InterfaceIDOption {
+	return func(g *InterfaceIDGenerator) {
+		g.secret = append([]byte(nil), secret...)
+	}
+}
+
+// InterfaceIDGenerator derives stable interface identifiers from hardware
+// addresses, as modified EUI-64 by default
+type InterfaceIDGenerator struct {
+	secret []byte // Enables stable privacy when not nil
+}
+
+// NewInterfaceIDGenerator creates a generator for interface identifiers
+func NewInterfaceIDGenerator(opts ...InterfaceIDOption) *InterfaceIDGenerator {
+	g := &InterfaceIDGenerator{}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Derive returns the identifier for mac on the network prefix, which only
+// stable privacy identifiers depend on
+func (g *InterfaceIDGenerator) Derive(prefix netip.Prefix, mac net.HardwareAddr) (InterfaceID, error) {
+	if g.secret == nil {
+		return ModifiedEUI64(mac)
+	}
+	if len(mac) != 6 && len(mac) != 8 {
+		return InterfaceID{}, ErrInvalidHardwareAddr
+	}
+
+	// F(Prefix, Net_Iface, Network_ID, DAD_Counter, secret_key), with the
+	// counter raised past reserved identifiers
+	network := prefix.Masked().String()
+	for counter := uint8(0); ; counter++ {
+		h := hmac.New(sha256.New, g.secret)
+		h.Write([]byte(network))
+		h.Write([]byte{0})
+		h.Write(mac)
+		h.Write([]byte{counter})
+		var id InterfaceID
+		copy(id[:], h.Sum(nil))
+		id[0] &^= 0x02 // Not derived from a universal address
+		if !id.reserved() {
+			return id, nil
+		}
+	}
+}
+
+// HardwareInterfaceID is the identifier derived for one network interface
+type HardwareInterfaceID struct {
+	Name         string
+	HardwareAddr net.HardwareAddr
+	ID           InterfaceID
+}
+
+// Interfaces derives identifiers for the interfaces that are up, not
+// loopback and have an EUI-48 or EUI-64 hardware address, the same ones
+// the network entropy provider reads
+func (g *InterfaceIDGenerator) Interfaces(prefix netip.Prefix) ([]HardwareInterfaceID, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var ids []HardwareInterfaceID
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		id, err := g.Derive(prefix, iface.HardwareAddr)
+		if err != nil {
+			continue // No usable hardware address
+		}
+		ids = append(ids, HardwareInterfaceID{Name: iface.Name, HardwareAddr: iface.HardwareAddr, ID: id})
+	}
+	return ids, nil
+}
@@ -0,0 +1,130 @@
+//go:build !idforge_lite
+
+package idforge
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+)
+
+func TestModifiedEUI64(t *testing.T) {
+	mac, _ := net.ParseMAC("00:1b:21:3c:4d:5e")
+	id, err := ModifiedEUI64(mac)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id.String() != "021b:21ff:fe3c:4d5e" {
+		t.Errorf("Expected 021b:21ff:fe3c:4d5e, got %s", id)
+	}
+	if id.EUI64() != "02-1B-21-FF-FE-3C-4D-5E" {
+		t.Errorf("Expected 02-1B-21-FF-FE-3C-4D-5E, got %s", id.EUI64())
+	}
+
+	eui64, _ := net.ParseMAC("02:00:00:ff:fe:00:00:01")
+	if id, _ := ModifiedEUI64(eui64); id.String() != "0000:00ff:fe00:0001" {
+		t.Errorf("Expected an EUI-64 to only flip the U/L bit, got %s", id)
+	}
+
+	if _, err := ModifiedEUI64(net.HardwareAddr{1, 2, 3}); !errors.Is(err, ErrInvalidHardwareAddr) {
+		t.Errorf("Expected ErrInvalidHardwareAddr, got %v", err)
+	}
+}
+
+func TestInterfaceIDAddr(t *testing.T) {
+	mac, _ := net.ParseMAC("00:1b:21:3c:4d:5e")
+	id, _ := ModifiedEUI64(mac)
+	addr, err := id.Addr(netip.MustParsePrefix("2001:db8:1:2::ffff/64"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if addr.String() != "2001:db8:1:2:21b:21ff:fe3c:4d5e" {
+		t.Errorf("Expected 2001:db8:1:2:21b:21ff:fe3c:4d5e, got %s", addr)
+	}
+
+	for _, prefix := range []string{"2001:db8::/80", "192.0.2.0/24"} {
+		if _, err := id.Addr(netip.MustParsePrefix(prefix)); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("Expected ErrInvalidConfig for %s, got %v", prefix, err)
+		}
+	}
+}
+
+func TestParseInterfaceID(t *testing.T) {
+	id, err := ParseInterfaceID("21b:21ff:fe3c:4d5e")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id.String() != "021b:21ff:fe3c:4d5e" {
+		t.Errorf("Expected 021b:21ff:fe3c:4d5e, got %s", id)
+	}
+	for _, s := range []string{"", "1:2:3", "1:2:3:4:5", "1::2:3", "12345:0:0:0", "g:0:0:0"} {
+		if _, err := ParseInterfaceID(s); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("Expected ErrInvalidEncoding for %q, got %v", s, err)
+		}
+	}
+}
+
+func TestStablePrivacyInterfaceID(t *testing.T) {
+	mac, _ := net.ParseMAC("00:1b:21:3c:4d:5e")
+	home := netip.MustParsePrefix("2001:db8:1::/64")
+	office := netip.MustParsePrefix("2001:db8:2::/64")
+
+	g := NewInterfaceIDGenerator(WithStablePrivacy([]byte("device secret")))
+	a, err := g.Derive(home, mac)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	b, _ := g.Derive(netip.MustParsePrefix("2001:db8:1::1234/64"), mac)
+	if a != b {
+		t.Errorf("Expected a stable identifier within a network, got %s and %s", a, b)
+	}
+	if c, _ := g.Derive(office, mac); c == a {
+		t.Error("Expected a different identifier on another network")
+	}
+	if other, _ := NewInterfaceIDGenerator(WithStablePrivacy([]byte("other"))).Derive(home, mac); other == a {
+		t.Error("Expected a different identifier with another secret")
+	}
+	if eui, _ := ModifiedEUI64(mac); a == eui {
+		t.Error("Expected the hardware address to stay hidden")
+	}
+	if a[0]&0x02 != 0 {
+		t.Error("Expected the universal/local bit to be cleared")
+	}
+
+	if _, err := g.Derive(home, net.HardwareAddr{1}); !errors.Is(err, ErrInvalidHardwareAddr) {
+		t.Errorf("Expected ErrInvalidHardwareAddr, got %v", err)
+	} else if code := ErrorCode(err); code != CodeInvalidHardwareAddr {
+		t.Errorf("Expected %s, got %s", CodeInvalidHardwareAddr, code)
+	}
+	if id, _ := NewInterfaceIDGenerator().Derive(home, mac); id.String() != "021b:21ff:fe3c:4d5e" {
+		t.Errorf("Expected modified EUI-64 by default, got %s", id)
+	}
+}
+
+func TestInterfaceIDReserved(t *testing.T) {
+	for _, s := range []string{"0:0:0:0", "0200:5eff:fe00:0000", "0200:5eff:fe00:5213", "0200:5eff:fe00:5214", "0200:5eff:feff:ffff", "fdff:ffff:ffff:ff80", "fdff:ffff:ffff:ffff"} {
+		id, _ := ParseInterfaceID(s)
+		if !id.reserved() {
+			t.Errorf("Expected %s to be reserved", s)
+		}
+	}
+	for _, s := range []string{"0200:5eff:fd00:0000", "0200:5eff:ff00:0000", "fdff:ffff:ffff:ff7f", "fe00:0000:0000:0001", "ffff:ffff:ffff:ffff", "021b:21ff:fe3c:4d5e"} {
+		id, _ := ParseInterfaceID(s)
+		if id.reserved() {
+			t.Errorf("Expected %s not to be reserved", s)
+		}
+	}
+}
+
+func TestInterfaceIDGeneratorInterfaces(t *testing.T) {
+	ids, err := NewInterfaceIDGenerator().Interfaces(netip.MustParsePrefix("fe80::/64"))
+	if err != nil {
+		t.Skipf("Interfaces unavailable: %v", err)
+	}
+	for _, entry := range ids {
+		if want, _ := ModifiedEUI64(entry.HardwareAddr); entry.ID != want {
+			t.Errorf("Expected %s for %s, got %s", want, entry.Name, entry.ID)
+		}
+	}
+}
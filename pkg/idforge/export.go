@@ -0,0 +1,115 @@
+package idforge
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidExport is returned by ExportBatch for an unknown format or a
+// metadata column named "id"
+var ErrInvalidExport = fmt.Errorf("%w: invalid export", ErrInvalidConfig)
+
+// ExportFormat selects the file format written by ExportBatch
+type ExportFormat int
+
+const (
+	ExportCSV   ExportFormat = iota // Header row, then one row per ID
+	ExportJSONL                     // One JSON object per line
+	ExportText                      // One bare ID per line
+)
+
+func (f ExportFormat) String() string {
+	switch f {
+	case ExportCSV:
+		return "csv"
+	case ExportJSONL:
+		return "jsonl"
+	case ExportText:
+		return "text"
+	default:
+		return "unknown"
+	}
+}
+
+// ExportBatch writes ids to w for handing a batch to another system. Each
+// meta entry becomes a column repeated on every row, after the "id" column
+// and in key order, such as the campaign or batch a set of codes belongs
+// to. Metadata describes the whole batch; IDs with different values go in
+// separate batches. Plain text holds only the IDs.
+func ExportBatch(w io.Writer, ids []string, format ExportFormat, meta map[string]string) error {
+	if _, ok := meta["id"]; ok {
+		return fmt.Errorf("%w: metadata column %q clashes with the ID column", ErrInvalidExport, "id")
+	}
+	keys := make([]string, 0, len(meta))
+	for key := range meta {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	switch format {
+	case ExportCSV:
+		return exportCSV(w, ids, keys, meta)
+	case ExportJSONL:
+		return exportJSONL(w, ids, keys, meta)
+	case ExportText:
+		return exportText(w, ids)
+	default:
+		return fmt.Errorf("%w: unknown format %d", ErrInvalidExport, format)
+	}
+}
+
+func exportCSV(w io.Writer, ids, keys []string, meta map[string]string) error {
+	cw := csv.NewWriter(w)
+	row := append([]string{"id"}, keys...)
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+	for i, key := range keys {
+		row[i+1] = meta[key]
+	}
+	for _, id := range ids {
+		row[0] = id
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func exportJSONL(w io.Writer, ids, keys []string, meta map[string]string) error {
+	// The metadata is the same on every line, so encode it once
+	var suffix strings.Builder
+	for _, key := range keys {
+		k, _ := json.Marshal(key)
+		v, _ := json.Marshal(meta[key])
+		suffix.WriteByte(',')
+		suffix.Write(k)
+		suffix.WriteByte(':')
+		suffix.Write(v)
+	}
+	suffix.WriteString("}\n")
+
+	bw := bufio.NewWriter(w)
+	for _, id := range ids {
+		encoded, _ := json.Marshal(id)
+		bw.WriteString(`{"id":`)
+		bw.Write(encoded)
+		bw.WriteString(suffix.String())
+	}
+	return bw.Flush()
+}
+
+func exportText(w io.Writer, ids []string) error {
+	bw := bufio.NewWriter(w)
+	for _, id := range ids {
+		bw.WriteString(id)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
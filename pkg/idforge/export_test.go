@@ -0,0 +1,97 @@
+package idforge
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExportBatchCSV(t *testing.T) {
+	var buf bytes.Buffer
+	ids := []string{"abc", "d,ef"}
+	meta := map[string]string{"campaign": "spring", "batch": "7"}
+	if err := ExportBatch(&buf, ids, ExportCSV, meta); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Unexpected error reading CSV: %v", err)
+	}
+	expected := [][]string{
+		{"id", "batch", "campaign"},
+		{"abc", "7", "spring"},
+		{"d,ef", "7", "spring"},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected %v, got %v", expected, rows)
+	}
+}
+
+func TestExportBatchJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	ids := []string{"abc", `q"uote`}
+	if err := ExportBatch(&buf, ids, ExportJSONL, map[string]string{"batch": "7"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	for i := 0; scanner.Scan(); i++ {
+		var row map[string]string
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatalf("Line %d is not JSON: %v", i, err)
+		}
+		expected := map[string]string{"id": ids[i], "batch": "7"}
+		if !reflect.DeepEqual(row, expected) {
+			t.Errorf("Expected %v, got %v", expected, row)
+		}
+	}
+
+	buf.Reset()
+	if err := ExportBatch(&buf, []string{"abc"}, ExportJSONL, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.String() != "{\"id\":\"abc\"}\n" {
+		t.Errorf("Expected a bare ID object, got %q", buf.String())
+	}
+}
+
+func TestExportBatchText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ExportBatch(&buf, []string{"abc", "def"}, ExportText, map[string]string{"batch": "7"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.String() != "abc\ndef\n" {
+		t.Errorf("Expected one ID per line, got %q", buf.String())
+	}
+}
+
+func TestExportBatchErrors(t *testing.T) {
+	var buf bytes.Buffer
+	err := ExportBatch(&buf, []string{"abc"}, ExportFormat(42), nil)
+	if !errors.Is(err, ErrInvalidExport) || !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidExport for an unknown format, got %v", err)
+	}
+
+	err = ExportBatch(&buf, []string{"abc"}, ExportCSV, map[string]string{"id": "x"})
+	if !errors.Is(err, ErrInvalidExport) {
+		t.Errorf("Expected ErrInvalidExport for an id column, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing written on error, got %q", buf.String())
+	}
+}
+
+func TestExportFormatString(t *testing.T) {
+	for format, expected := range map[ExportFormat]string{
+		ExportCSV: "csv", ExportJSONL: "jsonl", ExportText: "text", ExportFormat(9): "unknown",
+	} {
+		if got := format.String(); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
@@ -4,19 +4,23 @@ import (
 	"context"
 	"crypto/rand"
 	"errors"
+	"fmt"
+	"io"
 	"math"
-	"math/big"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/mrityunjay-vashisth/go-idforge/internal/drbg"
 	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+	"github.com/mrityunjay-vashisth/go-idforge/internal/secmem"
 )
 
 var (
-	ErrInvalidAlphabet   = errors.New("alphabet must contain at least 2 unique characters")
-	ErrInvalidSize       = errors.New("size must be positive")
-	ErrGenerationTimeout = errors.New("ID generation timed out")
+	ErrInvalidAlphabet       = fmt.Errorf("%w: alphabet must contain at least 2 unique characters", ErrInvalidConfig)
+	ErrInvalidSize           = fmt.Errorf("%w: size must be positive", ErrInvalidConfig)
+	ErrInvalidReseedInterval = fmt.Errorf("%w: DRBG reseed interval must be positive", ErrInvalidConfig)
+	ErrGenerationTimeout     = errors.New("ID generation timed out")
 )
 
 // GeneratorConfig provides advanced configuration options
@@ -26,15 +30,72 @@ type GeneratorConfig struct {
 	Entropy            []entropy.EntropyProvider
 	MaxGenerationTime  time.Duration
 	UniquenessPressure float64
-	MaxUniqueIDs       int // New option to limit unique ID tracking
+	MaxUniqueIDs       int           // Most recent IDs remembered for duplicate detection
+	UniqueIDRetention  time.Duration // How long IDs are remembered, 0 keeps them until evicted
+	RateLimit          float64       // Maximum IDs per second, 0 disables throttling
+	RateBurst          int           // Number of IDs that may be issued in a burst
+	RateLimitWait      bool          // Block instead of failing when throttled
+	Name               string        // Identifies the generator in audit events
+	Auditor            Auditor       // Notified of every successfully generated ID
+	ProviderTimeout    time.Duration // Per-provider deadline, 0 disables it
+	BreakerThreshold   int           // Consecutive failures before a provider is skipped
+	BreakerCooldown    time.Duration // How long an open breaker skips its provider
+	EntropyConcurrency int           // Providers queried at once, 0 queries all together
+	DRBG               bool          // Draw randomness from a seeded DRBG instead of per-ID entropy
+	DRBGReseedInterval time.Duration // How often the DRBG is reseeded from the providers
+	RNG                RNG           // Random source replacing crypto/rand, nil uses CryptoRNG
+	FIPSMode           bool          // Restrict generation to FIPS-approved components
+	LockedMemory       bool          // Keep the DRBG state in locked memory where supported
+	CollisionStrategy  CollisionStrategy
+	UniquenessStore    UniquenessStore  // Reserves IDs across instances, nil disables it
+	UniquenessTTL      time.Duration    // How long IDs stay reserved in UniquenessStore
+	AlphabetWeights    map[rune]float64 // Relative character frequencies, nil samples uniformly
+	MinEditDistance    int              // Minimum edits between new and remembered IDs, 2 or more enables the check
+	FormatVersion      rune             // Marker prepended to every ID, 0 disables it
+	MixingLogSize      int              // Entropy rounds kept for MixingLog, 0 disables it
+	WordFilter         *Wordlist        // Forbidden words redrawn during generation, nil disables it
+	Issuance           *IssuanceCollector
+	IssuanceNamespace  string        // Namespace counted in Issuance
+	Quota              *QuotaManager // Issuance caps per caller, nil disables them
+	Clock              Clock         // Time source for retention, breakers and reseeding, nil uses SystemClock
+
+	// OnCollision is called with each candidate that repeats an issued ID
+	// and the 1-based attempt number
+	OnCollision func(id string, attempt int)
+
+	// Hooks intercept every Generate call, outermost first
+	Hooks []Hook
+}
+
+// now reads the configured clock
+func (c GeneratorConfig) now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
+	}
+	return c.Clock.Now()
 }
 
+// Defaults for the settings a zero GeneratorConfig cannot generate without
+const (
+	defaultMaxGenerationTime  = 5 * time.Second
+	defaultUniquenessPressure = 0.99 // 99% uniqueness guarantee
+)
+
 // ExtendedGenerator provides more advanced ID generation capabilities
 type ExtendedGenerator struct {
-	mu        sync.Mutex
-	config    GeneratorConfig
-	generated map[string]bool
-	idCounter int
+	mu       sync.Mutex
+	config   GeneratorConfig
+	issued   *issuedSet
+	limiter  *rateLimiter
+	breakers []*breaker
+	drbg     *drbg.HMACDRBG
+	seededAt time.Time
+	stats    statsCounters
+	symbols  *symbols // Prepared alphabet, rebuilt after UpdateConfig
+	mixing   *entropy.MixingLog
+
+	stop chan struct{} // Closed to end background reseeding
+	wg   sync.WaitGroup
 }
 
 // NewExtendedGenerator creates a new generator with comprehensive configuration
@@ -44,9 +105,10 @@ func NewExtendedGenerator(opts ...func(*GeneratorConfig)) *ExtendedGenerator {
 		Alphabet:           DefaultAlphabet,
 		Size:               DefaultSize,
 		Entropy:            entropy.DefaultEntropyProviders(),
-		MaxGenerationTime:  5 * time.Second,
-		UniquenessPressure: 0.99,  // 99% uniqueness guarantee
+		MaxGenerationTime:  defaultMaxGenerationTime,
+		UniquenessPressure: defaultUniquenessPressure,
 		MaxUniqueIDs:       10000, // Limit unique ID tracking
+		EntropyConcurrency: 4,
 	}
 
 	// Apply custom options
@@ -54,123 +116,506 @@ func NewExtendedGenerator(opts ...func(*GeneratorConfig)) *ExtendedGenerator {
 		opt(&config)
 	}
 
-	return &ExtendedGenerator{
-		config:    config,
-		generated: make(map[string]bool),
-		idCounter: 0,
+	g := &ExtendedGenerator{
+		config:  config,
+		issued:  newIssuedSet(config.MaxUniqueIDs, config.UniqueIDRetention),
+		limiter: newRateLimiter(config.RateLimit, config.RateBurst),
+	}
+	g.issued.trackNear(config.MinEditDistance > 1)
+	g.resizeMixingLog()
+
+	// Seed eagerly; a failure here is retried on the first Generate call
+	if config.DRBG {
+		g.ensureDRBG(context.Background())
 	}
+	return g
 }
 
-// Generate creates a unique identifier with advanced features
+// Generate creates a unique identifier with advanced features.
+// An ID is never repeated while it is among the last MaxUniqueIDs issued
+// and, when UniqueIDRetention is set, was issued within the retention
+// window; older IDs are forgotten oldest first.
 func (g *ExtendedGenerator) Generate(ctx context.Context) (string, error) {
+	result, err := g.GenerateWithInfo(ctx)
+	return result.ID, err
+}
+
+// GenerateWithInfo is like Generate but also describes how the ID was made,
+// for audit pipelines that persist this metadata with each issued ID
+func (g *ExtendedGenerator) GenerateWithInfo(ctx context.Context) (GenResult, error) {
+	g.mu.Lock()
+	hooks := g.config.Hooks
+	issuance, namespace := g.config.Issuance, g.config.IssuanceNamespace
+	g.mu.Unlock()
+
+	start := time.Now()
+	result, err := runHooks(ctx, hooks, g.generateAudited)
+	g.stats.record(start, err)
+	if err == nil {
+		issuance.Record(namespace, 1)
+	}
+	return result, err
+}
+
+// generateAudited throttles, generates and audits a single ID
+func (g *ExtendedGenerator) generateAudited(ctx context.Context) (GenResult, error) {
+	// Throttle before taking the lock so waiting callers don't block others
+	if err := g.throttle(ctx); err != nil {
+		return GenResult{}, err
+	}
+
+	g.mu.Lock()
+	quota := g.config.Quota
+	g.mu.Unlock()
+	charge, err := quota.charge(ctx, 1)
+	if err != nil {
+		return GenResult{}, err
+	}
+
+	result, cfg, err := g.generate(ctx)
+	if err != nil {
+		charge.refund(ctx, 1)
+		return GenResult{}, err
+	}
+
+	if cfg.Auditor != nil {
+		if err := audit(ctx, cfg.Auditor, cfg.Name, result.ID); err != nil {
+			charge.refund(ctx, 1)
+			return GenResult{}, err
+		}
+	}
+	return result, nil
+}
+
+// generate produces a unique candidate under the generator lock and returns
+// the configuration that was in effect for it
+func (g *ExtendedGenerator) generate(ctx context.Context) (GenResult, GeneratorConfig, error) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
 	// Validate configuration
-	if len(g.config.Alphabet) < 2 {
-		return "", ErrInvalidAlphabet
+	if err := g.config.validate(); err != nil {
+		return GenResult{}, g.config, err
+	}
+
+	result := GenResult{
+		Generator:     g.config.Name,
+		FormatVersion: g.config.FormatVersion,
+		RandomSource:  RandomSourceCrypto,
 	}
-	if g.config.Size <= 0 {
-		return "", ErrInvalidSize
+	if g.config.DRBG {
+		result.RandomSource = RandomSourceDRBG
+	} else if g.config.RNG != nil {
+		result.RandomSource = RandomSourceCustom
 	}
 
 	// Prepare context with timeout
 	timeoutCtx, cancel := context.WithTimeout(ctx, g.config.MaxGenerationTime)
 	defer cancel()
 
-	// Efficient entropy collection with context check
-	entropyParts, err := g.collectEntropy(timeoutCtx)
-	if err != nil {
-		return "", err
+	// With a DRBG the providers are only queried when (re)seeding;
+	// otherwise entropy is collected for every ID unless FIPS mode
+	// forbids mixing it in or an injected RNG replaces it
+	var seedBytes []byte
+	if g.config.DRBG {
+		if err := g.ensureDRBG(timeoutCtx); err != nil {
+			return GenResult{}, g.config, err
+		}
+	} else if !g.config.FIPSMode && g.config.RNG == nil {
+		// Efficient entropy collection with context check
+		entropyParts, sources, err := g.collectEntropy(timeoutCtx)
+		if err != nil {
+			return GenResult{}, g.config, err
+		}
+		result.EntropySources = sources
+
+		// Seed random generation with entropy
+		combinedEntropy := strings.Join(entropyParts, "")
+		seedBytes = []byte(combinedEntropy)
+		defer secmem.Wipe(seedBytes)
 	}
 
 	// Dynamic max attempts calculation
-	alphabetLen := len(g.config.Alphabet)
-	maxAttempts := calculateMaxAttempts(alphabetLen, g.config.Size, g.config.UniquenessPressure)
+	maxAttempts := calculateMaxAttempts(g.config.effectiveAlphabetSize(), g.config.Size, g.config.UniquenessPressure)
 
-	// Seed random generation with entropy
-	combinedEntropy := strings.Join(entropyParts, "")
-	seedBytes := []byte(combinedEntropy)
+	size := g.config.Size
+	rejected := ErrCollision
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		// Generate candidate ID with optimized randomness; cancellation is
+		// checked between batches of characters
+		candidateID, err := g.generateCandidateID(timeoutCtx, seedBytes, size)
+		if err != nil {
+			return GenResult{}, g.config, err
+		}
+		if g.config.FormatVersion != 0 {
+			candidateID = string(g.config.FormatVersion) + candidateID
+		}
+		if g.config.WordFilter != nil && g.config.WordFilter.Contains(candidateID) {
+			rejected = ErrForbiddenWord
+			continue
+		}
+		rejected = ErrCollision
 
-	// More efficient unique ID tracking
-	if g.idCounter >= g.config.MaxUniqueIDs {
-		g.generated = make(map[string]bool)
-		g.idCounter = 0
-	}
+		// Check for uniqueness, locally and then across instances
+		now := g.config.now()
+		unique, err := g.admit(timeoutCtx, candidateID, now)
+		if err != nil {
+			return GenResult{}, g.config, err
+		}
+		if unique {
+			result.ID, result.GeneratedAt, result.Attempts = candidateID, now, attempt+1
+			return result, g.config, nil
+		}
 
-	for attempt := 0; attempt < maxAttempts; attempt++ {
-		// Less frequent context checks
-		if attempt%10 == 0 {
-			select {
-			case <-timeoutCtx.Done():
-				return "", ErrGenerationTimeout
-			default:
-			}
+		g.stats.collisions.Add(1)
+		if g.config.OnCollision != nil {
+			g.config.OnCollision(candidateID, attempt+1)
+		}
+
+		switch g.config.CollisionStrategy {
+		case CollisionFail:
+			return GenResult{}, g.config, ErrCollision
+		case CollisionGrowSize:
+			size++
+		}
+		if attempt+1 < maxAttempts {
+			g.stats.retries.Add(1)
 		}
+	}
 
-		// Generate candidate ID with optimized randomness
-		candidateID := g.generateCandidateID(seedBytes)
+	return GenResult{}, g.config, fmt.Errorf("%w: %w after %d attempts", ErrGenerationTimeout, rejected, maxAttempts)
+}
 
-		// Check for uniqueness
-		if !g.generated[candidateID] {
-			g.generated[candidateID] = true
-			g.idCounter++
-			return candidateID, nil
+// admit records id as issued unless it repeats or comes too close to a
+// remembered ID, or the uniqueness store has it reserved elsewhere; the
+// caller must hold g.mu
+func (g *ExtendedGenerator) admit(ctx context.Context, id string, now time.Time) (bool, error) {
+	unique := !g.issued.contains(id, now)
+	if unique && g.config.MinEditDistance > 1 {
+		unique = !g.issued.nearby(id, g.config.MinEditDistance-1, now)
+	}
+	if unique && g.config.UniquenessStore != nil {
+		var err error
+		unique, err = g.config.UniquenessStore.Reserve(ctx, id, g.config.UniquenessTTL)
+		if err != nil {
+			return false, fmt.Errorf("uniqueness store: %w", err)
 		}
 	}
+	if unique {
+		g.issued.add(id, now)
+	}
+	return unique, nil
+}
+
+// UpdateConfig atomically replaces the generator's configuration.
+// In-flight Generate calls complete with the previous configuration;
+// already issued IDs are kept as far as the new MaxUniqueIDs and
+// UniqueIDRetention allow. A zero MaxGenerationTime or UniquenessPressure
+// takes the NewExtendedGenerator default.
+func (g *ExtendedGenerator) UpdateConfig(cfg GeneratorConfig) error {
+	if cfg.MaxGenerationTime <= 0 {
+		cfg.MaxGenerationTime = defaultMaxGenerationTime
+	}
+	if cfg.UniquenessPressure <= 0 {
+		cfg.UniquenessPressure = defaultUniquenessPressure
+	}
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if cfg.RateLimit != g.config.RateLimit || cfg.RateBurst != g.config.RateBurst {
+		g.limiter = newRateLimiter(cfg.RateLimit, cfg.RateBurst)
+	}
+	g.config = cfg
+	g.issued.resize(cfg.MaxUniqueIDs, cfg.UniqueIDRetention, cfg.now())
+	g.issued.trackNear(cfg.MinEditDistance > 1)
+	g.resizeMixingLog()
+	g.breakers = nil
+	g.wipeDRBG()
+	g.symbols = nil
+	return nil
+}
+
+// Config returns a snapshot of the generator's current configuration
+func (g *ExtendedGenerator) Config() GeneratorConfig {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	cfg := g.config
+	cfg.Entropy = append([]entropy.EntropyProvider(nil), g.config.Entropy...)
+	return cfg
+}
+
+// throttle applies the configured rate limit
+func (g *ExtendedGenerator) throttle(ctx context.Context) error {
+	g.mu.Lock()
+	limiter, wait := g.limiter, g.config.RateLimitWait
+	g.mu.Unlock()
+
+	if limiter == nil {
+		return nil
+	}
+	if wait {
+		return limiter.wait(ctx)
+	}
+	if !limiter.allow() {
+		return ErrRateLimited
+	}
+	return nil
+}
 
-	return "", ErrGenerationTimeout
+// validate checks that the configuration can produce IDs
+func (c GeneratorConfig) validate() error {
+	if !validAlphabet(c.Alphabet) {
+		return ErrInvalidAlphabet
+	}
+	if c.Size <= 0 {
+		return ErrInvalidSize
+	}
+	if c.DRBG && c.DRBGReseedInterval <= 0 {
+		return ErrInvalidReseedInterval
+	}
+	if err := c.validateWeights(); err != nil {
+		return err
+	}
+	if err := c.validateFormatVersion(); err != nil {
+		return err
+	}
+	if err := c.validateRNG(); err != nil {
+		return err
+	}
+	return c.validateFIPS()
 }
 
-// collectEntropy efficiently gathers entropy with context management
-func (g *ExtendedGenerator) collectEntropy(ctx context.Context) ([]string, error) {
-	entropyParts := make([]string, 0, len(g.config.Entropy))
+// collectEntropy queries providers in parallel, bounded by
+// EntropyConcurrency, and combines their output in configuration order
+// so the result does not depend on which provider answers first. It also
+// returns the names of the providers that contributed.
+func (g *ExtendedGenerator) collectEntropy(ctx context.Context) ([]string, []string, error) {
+	type result struct {
+		value   string
+		err     error
+		queried bool
+	}
+
+	g.ensureBreakers()
+	results := make([]result, len(g.config.Entropy))
+
+	limit := g.config.EntropyConcurrency
+	if limit <= 0 {
+		limit = len(g.config.Entropy)
+	}
+	sem := make(chan struct{}, max(limit, 1))
+
+	var wg sync.WaitGroup
+	now := g.config.now()
+	for i, provider := range g.config.Entropy {
+		if !g.breakers[i].allow(now, g.config.BreakerCooldown) {
+			continue
+		}
 
-	for _, provider := range g.config.Entropy {
-		// Occasional context check to reduce overhead
-		select {
-		case <-ctx.Done():
-			return nil, ErrGenerationTimeout
-		default:
-			entropyStr, err := provider.Provide(ctx)
-			if err != nil {
-				return nil, err
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, provider entropy.EntropyProvider) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			value, err := g.provideWithTimeout(ctx, provider)
+			results[i] = result{value: value, err: err, queried: true}
+		}(i, provider)
+	}
+	wg.Wait()
+
+	if g.mixing != nil {
+		round := entropy.MixingRound{Time: now, Contributions: make([]entropy.Contribution, len(results))}
+		for i, r := range results {
+			if r.queried {
+				round.Contributions[i] = entropy.Contributed(g.config.Entropy[i], r.value, r.err)
+			} else {
+				round.Contributions[i] = entropy.Contribution{Provider: providerName(g.config.Entropy[i]), Skipped: true}
+			}
+		}
+		g.mixing.Record(round)
+	}
+
+	entropyParts := make([]string, 0, len(results))
+	sources := make([]string, 0, len(results))
+	var firstErr error
+	failed := -1
+	for i, r := range results {
+		if !r.queried {
+			continue
+		}
+		if r.err != nil {
+			if ctx.Err() == nil {
+				// Only the provider failed, not the overall generation
+				g.breakers[i].failure(g.config.now(), g.config.BreakerThreshold)
+			}
+			if firstErr == nil {
+				firstErr, failed = r.err, i
 			}
-			entropyParts = append(entropyParts, entropyStr)
+			continue
 		}
+		g.breakers[i].success()
+		entropyParts = append(entropyParts, r.value)
+		sources = append(sources, providerName(g.config.Entropy[i]))
 	}
 
-	return entropyParts, nil
+	if firstErr == ErrGenerationTimeout {
+		return nil, nil, firstErr
+	}
+	if firstErr != nil {
+		return nil, nil, fmt.Errorf("%w: %s: %w", ErrEntropyUnavailable, providerName(g.config.Entropy[failed]), firstErr)
+	}
+	return entropyParts, sources, nil
 }
 
-// generateCandidateID creates an ID with enhanced randomness
-func (g *ExtendedGenerator) generateCandidateID(seedBytes []byte) string {
-	id := make([]byte, g.config.Size)
-	alphabetLen := big.NewInt(int64(len(g.config.Alphabet)))
+// provideWithTimeout queries a provider under the configured per-provider deadline
+func (g *ExtendedGenerator) provideWithTimeout(ctx context.Context, provider entropy.EntropyProvider) (string, error) {
+	if g.config.ProviderTimeout <= 0 {
+		return provide(ctx, provider)
+	}
+
+	providerCtx, cancel := context.WithTimeout(ctx, g.config.ProviderTimeout)
+	defer cancel()
 
-	for i := 0; i < g.config.Size; i++ {
-		// Use crypto/rand for secure randomness
-		num, _ := rand.Int(rand.Reader, alphabetLen)
+	value, err := provide(providerCtx, provider)
+	if err == ErrGenerationTimeout && ctx.Err() == nil {
+		return "", ErrProviderTimeout
+	}
+	return value, err
+}
 
-		// Incorporate entropy-based randomness
-		if len(seedBytes) > 0 {
-			num = new(big.Int).Add(
-				num,
-				big.NewInt(int64(seedBytes[i%len(seedBytes)])),
-			)
-			num = new(big.Int).Mod(num, alphabetLen)
+// ensureBreakers allocates one breaker per configured provider
+func (g *ExtendedGenerator) ensureBreakers() {
+	if len(g.breakers) == len(g.config.Entropy) {
+		return
+	}
+	g.breakers = make([]*breaker, len(g.config.Entropy))
+	for i := range g.breakers {
+		g.breakers[i] = &breaker{}
+	}
+}
+
+// provide queries a provider but stops waiting as soon as ctx is done,
+// so providers that ignore their context cannot overrun the deadline
+func provide(ctx context.Context, provider entropy.EntropyProvider) (string, error) {
+	if ctx.Err() != nil {
+		return "", ErrGenerationTimeout
+	}
+
+	type result struct {
+		value string
+		err   error
+	}
+	// Buffered so an abandoned provider can still deliver and exit
+	done := make(chan result, 1)
+	go func() {
+		value, err := provider.Provide(ctx)
+		done <- result{value, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", ErrGenerationTimeout
+	case r := <-done:
+		return r.value, r.err
+	}
+}
+
+// generateCandidateID creates an ID of size characters with enhanced randomness
+func (g *ExtendedGenerator) generateCandidateID(ctx context.Context, seedBytes []byte, size int) (string, error) {
+	// Incorporate entropy-based randomness
+	if g.config.FIPSMode {
+		seedBytes = nil
+	}
+
+	// Use crypto/rand, the seeded DRBG or the injected RNG for secure
+	// randomness; cancellation is checked between batches of characters
+	if g.symbols == nil {
+		s := weightedSymbols(g.config)
+		g.symbols = &s
+	}
+	return buildID(g.randomSource(), *g.symbols, size, seedBytes, func() error {
+		if ctx.Err() != nil {
+			return ErrGenerationTimeout
+		}
+		return nil
+	})
+}
+
+// randomSource returns the reader candidate characters are drawn from
+func (g *ExtendedGenerator) randomSource() io.Reader {
+	if g.drbg != nil {
+		return g.drbg
+	}
+	if g.config.RNG != nil {
+		return g.config.RNG
+	}
+	return rand.Reader
+}
+
+// ensureDRBG instantiates the DRBG on first use and reseeds it once the
+// reseed interval has elapsed
+func (g *ExtendedGenerator) ensureDRBG(ctx context.Context) error {
+	if g.config.DRBGReseedInterval <= 0 {
+		return ErrInvalidReseedInterval
+	}
+	if g.drbg != nil && g.config.now().Sub(g.seededAt) < g.config.DRBGReseedInterval {
+		return nil
+	}
+
+	seed, err := drbgSeed(ctx, g.config, g.mixing)
+	if err != nil {
+		// A seeded DRBG stays secure without a reseed, so keep using it
+		if g.drbg != nil {
+			return nil
 		}
+		return err
+	}
+	defer secmem.Wipe(seed)
 
-		id[i] = g.config.Alphabet[num.Int64()]
+	if g.drbg == nil {
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			return err
+		}
+		g.drbg = drbg.New(seed, nonce, []byte("go-idforge"))
+		if err := g.lockDRBG(); err != nil {
+			return err
+		}
+	} else {
+		g.drbg.Reseed(seed, nil)
+	}
+	g.seededAt = g.config.now()
+	return nil
+}
+
+// drbgSeed combines crypto/rand output with the aggregated, hashed output
+// of the configured providers, each bounded by ProviderTimeout
+func drbgSeed(ctx context.Context, cfg GeneratorConfig, log *entropy.MixingLog) ([]byte, error) {
+	material, err := entropy.NewSecureEntropyAggregator(cfg.Entropy...).
+		WithProviderTimeout(cfg.ProviderTimeout).
+		WithMixingLog(log).
+		Aggregate(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return string(id)
+	seed := make([]byte, 32, 32+len(material))
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	return append(seed, material...), nil
 }
 
 // Utility function to calculate max attempts dynamically
-func calculateMaxAttempts(alphabetLen, size int, uniquenessPressure float64) int {
+func calculateMaxAttempts(alphabetSize float64, size int, uniquenessPressure float64) int {
 	maxAttempts := int(math.Min(
-		math.Pow(float64(alphabetLen), float64(size))*uniquenessPressure,
+		math.Pow(alphabetSize, float64(size))*uniquenessPressure,
 		1000, // Prevent excessive iterations
 	))
 	return maxAttempts
@@ -178,8 +623,13 @@ func calculateMaxAttempts(alphabetLen, size int, uniquenessPressure float64) int
 
 // GetUniquenessProbability calculates the probability of generating a unique ID
 func (g *ExtendedGenerator) GetUniquenessProbability(numIDs int) float64 {
-	alphabetSize := len(g.config.Alphabet)
-	possibleCombinations := math.Pow(float64(alphabetSize), float64(g.config.Size))
+	g.mu.Lock()
+	cfg := g.config
+	g.mu.Unlock()
+
+	// Weighted alphabets collide as often as a smaller uniform one
+	alphabetSize := cfg.effectiveAlphabetSize()
+	possibleCombinations := math.Pow(alphabetSize, float64(cfg.Size))
 
 	// Probability of at least one collision
 	probabilityOfCollision := 1 - math.Exp(
@@ -2,8 +2,10 @@ package idforge
 
 import (
 	"context"
+	"errors"
 	"math"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -171,3 +173,309 @@ func BenchmarkExtendedGenerator(b *testing.B) {
 		}
 	}
 }
+
+func TestExtendedGeneratorUpdateConfig(t *testing.T) {
+	gen := NewExtendedGenerator()
+	ctx := context.Background()
+
+	// Keep generating while the configuration is swapped underneath
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			if _, err := gen.Generate(ctx); err != nil {
+				t.Errorf("Unexpected error during concurrent generation: %v", err)
+				return
+			}
+		}
+	}()
+
+	cfg := gen.Config()
+	cfg.Alphabet = "0123456789"
+	cfg.Size = 8
+	if err := gen.UpdateConfig(cfg); err != nil {
+		t.Fatalf("Unexpected error updating config: %v", err)
+	}
+	<-done
+
+	id, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error generating ID: %v", err)
+	}
+	if !IsValidID(id, "0123456789", 8) {
+		t.Errorf("ID does not reflect updated configuration: %s", id)
+	}
+}
+
+func TestExtendedGeneratorUpdateConfigPartial(t *testing.T) {
+	gen := NewExtendedGenerator()
+	if err := gen.UpdateConfig(GeneratorConfig{Alphabet: "abcdef", Size: 8}); err != nil {
+		t.Fatalf("Unexpected error updating config: %v", err)
+	}
+
+	id, err := gen.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Expected generation after a partial update to succeed, got %v", err)
+	}
+	if !IsValidID(id, "abcdef", 8) {
+		t.Errorf("ID does not reflect updated configuration: %s", id)
+	}
+	if cfg := gen.Config(); cfg.MaxGenerationTime != defaultMaxGenerationTime || cfg.UniquenessPressure != defaultUniquenessPressure {
+		t.Errorf("Expected defaults for unset fields, got %v and %v", cfg.MaxGenerationTime, cfg.UniquenessPressure)
+	}
+}
+
+func TestExtendedGeneratorUpdateConfigInvalid(t *testing.T) {
+	gen := NewExtendedGenerator()
+
+	cfg := gen.Config()
+	cfg.Alphabet = "a"
+	if err := gen.UpdateConfig(cfg); err != ErrInvalidAlphabet {
+		t.Errorf("Expected ErrInvalidAlphabet, got %v", err)
+	}
+
+	cfg = gen.Config()
+	cfg.Size = 0
+	if err := gen.UpdateConfig(cfg); err != ErrInvalidSize {
+		t.Errorf("Expected ErrInvalidSize, got %v", err)
+	}
+
+	// The original configuration must remain in effect
+	if gen.Config().Alphabet != DefaultAlphabet || gen.Config().Size != DefaultSize {
+		t.Errorf("Invalid update modified the configuration")
+	}
+}
+
+// slowEntropy ignores its context and takes a long time to respond
+type slowEntropy struct {
+	delay time.Duration
+}
+
+func (s *slowEntropy) Provide(ctx context.Context) (string, error) {
+	time.Sleep(s.delay)
+	return "slow", nil
+}
+
+func TestExtendedGeneratorCancellationDuringEntropy(t *testing.T) {
+	gen := NewExtendedGenerator(
+		WithEntropyProviders([]entropy.EntropyProvider{&slowEntropy{delay: 2 * time.Second}}),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := gen.Generate(ctx)
+	elapsed := time.Since(start)
+
+	if err != ErrGenerationTimeout {
+		t.Errorf("Expected ErrGenerationTimeout, got %v", err)
+	}
+	if elapsed > 250*time.Millisecond {
+		t.Errorf("Cancellation took %v, expected it within 250ms of the deadline", elapsed)
+	}
+}
+
+func TestExtendedGeneratorCancellationDuringCharacters(t *testing.T) {
+	gen := NewExtendedGenerator(
+		WithEntropyProviders([]entropy.EntropyProvider{&entropy.TimestampEntropy{}}),
+		func(cfg *GeneratorConfig) {
+			cfg.Size = 5_000_000
+		},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := gen.Generate(ctx)
+	elapsed := time.Since(start)
+
+	if err != ErrGenerationTimeout {
+		t.Errorf("Expected ErrGenerationTimeout, got %v", err)
+	}
+	if elapsed > 200*time.Millisecond {
+		t.Errorf("Cancellation took %v, expected it within 200ms of the deadline", elapsed)
+	}
+}
+
+func TestExtendedGeneratorAlreadyCancelled(t *testing.T) {
+	gen := NewExtendedGenerator()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := gen.Generate(ctx); err != ErrGenerationTimeout {
+		t.Errorf("Expected ErrGenerationTimeout for a cancelled context, got %v", err)
+	}
+}
+
+// labelledEntropy returns a fixed label after a delay
+type labelledEntropy struct {
+	label string
+	delay time.Duration
+}
+
+func (l *labelledEntropy) Provide(ctx context.Context) (string, error) {
+	time.Sleep(l.delay)
+	return l.label, nil
+}
+
+func TestCollectEntropyParallel(t *testing.T) {
+	providers := []entropy.EntropyProvider{
+		&labelledEntropy{label: "a", delay: 80 * time.Millisecond},
+		&labelledEntropy{label: "b", delay: 10 * time.Millisecond},
+		&labelledEntropy{label: "c", delay: 40 * time.Millisecond},
+		&labelledEntropy{label: "d", delay: 80 * time.Millisecond},
+	}
+	gen := NewExtendedGenerator(WithEntropyProviders(providers), WithEntropyConcurrency(4))
+
+	start := time.Now()
+	parts, _, err := gen.collectEntropy(context.Background())
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("Unexpected error collecting entropy: %v", err)
+	}
+
+	// Results follow configuration order regardless of completion order
+	if strings.Join(parts, "") != "abcd" {
+		t.Errorf("Expected entropy in provider order 'abcd', got %v", parts)
+	}
+
+	// Serial collection would take 210ms
+	if elapsed > 150*time.Millisecond {
+		t.Errorf("Expected parallel collection, took %v", elapsed)
+	}
+}
+
+func TestCollectEntropyBoundedConcurrency(t *testing.T) {
+	providers := make([]entropy.EntropyProvider, 4)
+	for i := range providers {
+		providers[i] = &labelledEntropy{label: "x", delay: 30 * time.Millisecond}
+	}
+	gen := NewExtendedGenerator(WithEntropyProviders(providers), WithEntropyConcurrency(2))
+
+	start := time.Now()
+	if _, _, err := gen.collectEntropy(context.Background()); err != nil {
+		t.Fatalf("Unexpected error collecting entropy: %v", err)
+	}
+
+	// Two at a time means at least two rounds of 30ms
+	if elapsed := time.Since(start); elapsed < 55*time.Millisecond {
+		t.Errorf("Expected concurrency to be bounded at 2, took only %v", elapsed)
+	}
+}
+
+// countingEntropy counts how often it is queried
+type countingEntropy struct {
+	calls atomic.Int32
+}
+
+func (c *countingEntropy) Provide(ctx context.Context) (string, error) {
+	c.calls.Add(1)
+	return "counted", nil
+}
+
+func TestExtendedGeneratorDRBG(t *testing.T) {
+	counter := &countingEntropy{}
+	gen := NewExtendedGenerator(
+		WithEntropyProviders([]entropy.EntropyProvider{counter}),
+		WithDRBG(time.Hour),
+	)
+	ctx := context.Background()
+
+	seeded := counter.calls.Load()
+	if seeded != 1 {
+		t.Errorf("Expected the DRBG to be seeded once at construction, got %d queries", seeded)
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id, err := gen.Generate(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error generating ID: %v", err)
+		}
+		if !IsValidID(id, DefaultAlphabet, DefaultSize) {
+			t.Errorf("Invalid ID generated from DRBG: %s", id)
+		}
+		if seen[id] {
+			t.Errorf("Duplicate ID generated from DRBG: %s", id)
+		}
+		seen[id] = true
+	}
+
+	if counter.calls.Load() != seeded {
+		t.Errorf("Expected providers not to be queried per ID, got %d queries", counter.calls.Load())
+	}
+}
+
+func TestExtendedGeneratorDRBGReseed(t *testing.T) {
+	counter := &countingEntropy{}
+	gen := NewExtendedGenerator(
+		WithEntropyProviders([]entropy.EntropyProvider{counter}),
+		WithDRBG(10*time.Millisecond),
+	)
+
+	time.Sleep(20 * time.Millisecond)
+	if _, err := gen.Generate(context.Background()); err != nil {
+		t.Fatalf("Unexpected error generating ID: %v", err)
+	}
+
+	if counter.calls.Load() != 2 {
+		t.Errorf("Expected a reseed after the interval elapsed, got %d queries", counter.calls.Load())
+	}
+}
+
+func TestExtendedGeneratorDRBGSeedingTimeout(t *testing.T) {
+	gen := NewExtendedGenerator(
+		WithEntropyProviders([]entropy.EntropyProvider{hangingEntropy{}}),
+		WithDRBG(time.Hour),
+		WithProviderTimeout(10*time.Millisecond),
+	)
+
+	start := time.Now()
+	_, err := gen.Generate(context.Background())
+	if !errors.Is(err, entropy.ErrUnavailable) {
+		t.Errorf("Expected seeding to fail with ErrUnavailable, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected seeding to stop at the provider timeout, took %v", elapsed)
+	}
+}
+
+func TestExtendedGeneratorCollisionHook(t *testing.T) {
+	var collided []string
+	var attempts []int
+	gen := NewExtendedGenerator(
+		WithCustomAlphabet("01"),
+		func(cfg *GeneratorConfig) {
+			cfg.Size = 2
+		},
+		WithCollisionHook(func(id string, attempt int) {
+			collided = append(collided, id)
+			attempts = append(attempts, attempt)
+		}),
+	)
+	for _, id := range []string{"00", "01", "10", "11"} {
+		gen.issued.add(id, time.Now())
+	}
+
+	gen.Generate(context.Background())
+
+	// Four combinations at 0.99 uniqueness pressure allow three attempts
+	if len(collided) != 3 {
+		t.Fatalf("Expected 3 collisions to be reported, got %d", len(collided))
+	}
+	for i, attempt := range attempts {
+		if attempt != i+1 {
+			t.Errorf("Expected attempt %d, got %d", i+1, attempt)
+		}
+		if !gen.issued.contains(collided[i], time.Now()) {
+			t.Errorf("Reported ID %s was not previously issued", collided[i])
+		}
+	}
+	if stats := gen.Stats(); stats.Collisions != 3 {
+		t.Errorf("Expected Stats to count 3 collisions, got %d", stats.Collisions)
+	}
+}
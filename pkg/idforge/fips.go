@@ -0,0 +1,37 @@
+package idforge
+
+import (
+	"fmt"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+var ErrFIPSIncompatible = fmt.Errorf("%w: option is not permitted in FIPS mode", ErrInvalidConfig)
+
+// WithFIPSMode restricts generation to FIPS-approved components: characters
+// are drawn from crypto/rand, or from the SP 800-90A HMAC-DRBG when WithDRBG
+// is also set, and the only entropy provider is crypto/rand itself.
+// Provider output is no longer mixed into IDs. Configuring other entropy
+// providers after this option makes Generate fail with ErrFIPSIncompatible.
+//
+// Approved operation also requires building against a validated
+// cryptographic module, such as Go's FIPS 140-3 module.
+func WithFIPSMode() func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.FIPSMode = true
+		c.Entropy = []entropy.EntropyProvider{&entropy.RandomBytesEntropy{}}
+	}
+}
+
+// validateFIPS rejects settings that would use non-approved components
+func (c GeneratorConfig) validateFIPS() error {
+	if !c.FIPSMode {
+		return nil
+	}
+	for _, provider := range c.Entropy {
+		if _, ok := provider.(*entropy.RandomBytesEntropy); !ok {
+			return ErrFIPSIncompatible
+		}
+	}
+	return nil
+}
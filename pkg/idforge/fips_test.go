@@ -0,0 +1,51 @@
+package idforge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+func TestFIPSModeGenerates(t *testing.T) {
+	for _, opts := range [][]func(*GeneratorConfig){
+		{WithFIPSMode()},
+		{WithFIPSMode(), WithDRBG(time.Minute)},
+	} {
+		gen := NewExtendedGenerator(opts...)
+
+		cfg := gen.Config()
+		if len(cfg.Entropy) != 1 {
+			t.Fatalf("Expected a single approved provider, got %d", len(cfg.Entropy))
+		}
+		if _, ok := cfg.Entropy[0].(*entropy.RandomBytesEntropy); !ok {
+			t.Errorf("Expected crypto/rand provider, got %T", cfg.Entropy[0])
+		}
+
+		id, err := gen.Generate(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !IsValidID(id, DefaultAlphabet, DefaultSize) {
+			t.Errorf("Invalid ID generated in FIPS mode: %s", id)
+		}
+	}
+}
+
+func TestFIPSModeRejectsProviders(t *testing.T) {
+	gen := NewExtendedGenerator(
+		WithFIPSMode(),
+		WithEntropyProviders([]entropy.EntropyProvider{&entropy.TimestampEntropy{}}),
+	)
+	if _, err := gen.Generate(context.Background()); err != ErrFIPSIncompatible {
+		t.Errorf("Expected ErrFIPSIncompatible, got %v", err)
+	}
+
+	approved := NewExtendedGenerator(WithFIPSMode())
+	cfg := approved.Config()
+	cfg.Entropy = append(cfg.Entropy, &entropy.SystemEntropy{})
+	if err := approved.UpdateConfig(cfg); err != ErrFIPSIncompatible {
+		t.Errorf("Expected UpdateConfig to reject non-approved providers, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package idforge
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnrecognizedFormat = errors.New("ID matches no known format")
+
+// Format is a well-known ID format recognized by ValidateAny
+type Format int
+
+const (
+	FormatUnknown  Format = iota
+	FormatUUID            // 8-4-4-4-12 hex, any version
+	FormatULID            // 26 Crockford base32 characters
+	FormatKSUID           // 27 base62 characters
+	FormatObjectID        // 24 hex characters
+	FormatNanoID          // 21 characters of the URL-safe nanoid alphabet
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatUUID:
+		return "uuid"
+	case FormatULID:
+		return "ulid"
+	case FormatKSUID:
+		return "ksuid"
+	case FormatObjectID:
+		return "objectid"
+	case FormatNanoID:
+		return "nanoid"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	nanoIDAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789_-"
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	// maxKSUID encodes 2^160-1, the largest 20-byte KSUID
+	maxKSUID = "aWgEPTl1tmebfsQzFP4bxwgy80V"
+)
+
+// ValidateAny recognizes id as one of the common formats by its shape and,
+// where the format has one, its value range. The formats have different
+// lengths, so at most one can match.
+func ValidateAny(id string) (Format, error) {
+	switch len(id) {
+	case 36:
+		if _, err := UUIDEncoding.Decode(id); err == nil {
+			return FormatUUID, nil
+		}
+	case 26:
+		if _, err := ULIDEncoding.Decode(id); err == nil {
+			return FormatULID, nil
+		}
+	case 27:
+		// base62Alphabet is in ASCII order, so comparing strings compares values
+		if onlyChars(id, base62Alphabet) && id <= maxKSUID {
+			return FormatKSUID, nil
+		}
+	case 24:
+		if _, err := ParseObjectID(id); err == nil {
+			return FormatObjectID, nil
+		}
+	case 21:
+		if onlyChars(id, nanoIDAlphabet) {
+			return FormatNanoID, nil
+		}
+	}
+	return FormatUnknown, ErrUnrecognizedFormat
+}
+
+// Normalize returns id in the canonical case of the format: lowercase for
+// UUIDs and ObjectIDs, uppercase for ULIDs. Other formats are case
+// sensitive and returned unchanged.
+func (f Format) Normalize(id string) string {
+	switch f {
+	case FormatUUID, FormatObjectID:
+		return strings.ToLower(id)
+	case FormatULID:
+		return strings.ToUpper(id)
+	default:
+		return id
+	}
+}
+
+// onlyChars reports whether every byte of s is in the ASCII alphabet
+func onlyChars(s, alphabet string) bool {
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(alphabet, s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
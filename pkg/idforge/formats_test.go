@@ -0,0 +1,74 @@
+package idforge
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateAny(t *testing.T) {
+	cases := map[string]Format{
+		"f47ac10b-58cc-4372-a567-0e02b2c3d479": FormatUUID,
+		"F47AC10B-58CC-4372-A567-0E02B2C3D479": FormatUUID,
+		"01ARZ3NDEKTSV4RRFFQ69G5FAV":           FormatULID,
+		"01arz3ndektsv4rrffq69g5fav":           FormatULID,
+		"0ujtsYcgvSTl8PAuAdqWYSMnLOv":          FormatKSUID,
+		maxKSUID:                               FormatKSUID,
+		"507f1f77bcf86cd799439011":             FormatObjectID,
+		"V1StGXR8_Z5jdHi6B-myT":                FormatNanoID,
+	}
+	for id, want := range cases {
+		got, err := ValidateAny(id)
+		if err != nil {
+			t.Errorf("ValidateAny(%q): unexpected error: %v", id, err)
+		}
+		if got != want {
+			t.Errorf("ValidateAny(%q): expected %s, got %s", id, want, got)
+		}
+	}
+}
+
+func TestValidateAnyGenerated(t *testing.T) {
+	if f, err := ValidateAny(Generate()); err != nil || f != FormatNanoID {
+		t.Errorf("Expected a default ID to be recognized as nanoid, got %s, %v", f, err)
+	}
+	gen, err := NewObjectIDGenerator()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	id, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f, err := ValidateAny(id); err != nil || f != FormatObjectID {
+		t.Errorf("Expected an ObjectID to be recognized, got %s, %v", f, err)
+	}
+}
+
+func TestValidateAnyRejects(t *testing.T) {
+	for _, id := range []string{
+		"",
+		"f47ac10b-58cc-4372-a567-0e02b2c3d47g", // Not hex
+		"f47ac10b058cc-4372-a567-0e02b2c3d479", // Misplaced hyphen
+		"81ARZ3NDEKTSV4RRFFQ69G5FAV",           // ULID overflows 128 bits
+		"01ARZ3NDEKTSV4RRFFQ69G5FAU",           // U is not Crockford base32
+		"aWgEPTl1tmebfsQzFP4bxwgy80W",          // KSUID overflows 160 bits
+		"507f1f77bcf86cd79943901z",
+		"V1StGXR8_Z5jdHi6B-my!",
+	} {
+		if f, err := ValidateAny(id); !errors.Is(err, ErrUnrecognizedFormat) || f != FormatUnknown {
+			t.Errorf("ValidateAny(%q): expected ErrUnrecognizedFormat, got %s, %v", id, f, err)
+		}
+	}
+}
+
+func TestFormatNormalize(t *testing.T) {
+	if id := FormatUUID.Normalize("F47AC10B-58CC-4372-A567-0E02B2C3D479"); id != "f47ac10b-58cc-4372-a567-0e02b2c3d479" {
+		t.Errorf("Expected lowercase UUID, got %s", id)
+	}
+	if id := FormatULID.Normalize("01arz3ndektsv4rrffq69g5fav"); id != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Errorf("Expected uppercase ULID, got %s", id)
+	}
+	if id := FormatNanoID.Normalize("V1StGXR8_Z5jdHi6B-myT"); id != "V1StGXR8_Z5jdHi6B-myT" {
+		t.Errorf("Expected nanoid unchanged, got %s", id)
+	}
+}
@@ -0,0 +1,151 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+	"unicode/utf8"
+)
+
+// Fuzz targets run their seed corpus as part of `go test`;
+// use `go test -fuzz=FuzzValidate ./pkg/idforge` to explore further.
+
+func FuzzValidate(f *testing.F) {
+	f.Add("", 0, "")
+	f.Add(DefaultAlphabet, DefaultSize, "V1StGXR8_Z5jdHi6B-myT")
+	f.Add("ab", 3, "aba")
+	f.Add("αβγ", 2, "αβ")
+	f.Add("0123456789", 4, "12\xff4")
+
+	f.Fuzz(func(t *testing.T, alphabet string, size int, id string) {
+		gen := New(WithAlphabet(alphabet), WithSize(size))
+
+		if !gen.Validate(id) {
+			return
+		}
+
+		// Anything accepted must honour the generator's size and alphabet
+		if length := utf8.RuneCountInString(id); length != gen.size {
+			t.Errorf("Validate accepted %q with length %d, want %d", id, length, gen.size)
+		}
+		if !utf8.ValidString(id) {
+			t.Errorf("Validate accepted invalid UTF-8 %q", id)
+		}
+		for _, char := range id {
+			if !strings.ContainsRune(gen.alphabet, char) {
+				t.Errorf("Validate accepted %q containing %q outside the alphabet", id, char)
+			}
+		}
+	})
+}
+
+func FuzzIsValidID(f *testing.F) {
+	f.Add("", "", 0)
+	f.Add("ABC123", "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789", 6)
+	f.Add("🙂🙂", "🙂", 8)
+	f.Add("abc", "", 3)
+
+	f.Fuzz(func(t *testing.T, id, alphabet string, size int) {
+		valid := IsValidID(id, alphabet, size)
+
+		// IsValidID must agree with a generator configured the same way
+		if validAlphabet(alphabet) && size > 0 {
+			gen := New(WithAlphabet(alphabet), WithSize(size))
+			if gen.Validate(id) != valid {
+				t.Errorf("IsValidID(%q, %q, %d) = %v disagrees with Generator.Validate",
+					id, alphabet, size, valid)
+			}
+		}
+
+		if valid && alphabet == "" && id != "" {
+			t.Errorf("IsValidID accepted %q against an empty alphabet", id)
+		}
+	})
+}
+
+func FuzzSanitize(f *testing.F) {
+	f.Add(" ＡＢｃ-ｏl9 ", 0)
+	f.Add("\ufeffabc\u200b\x00", 3)
+	f.Add("\xff\xfe", 1)
+
+	f.Fuzz(func(t *testing.T, id string, maxLength int) {
+		opts := SanitizeOptions{
+			FoldWidth:  true,
+			Case:       UpperCase,
+			Lookalikes: DefaultLookalikes,
+			MaxLength:  maxLength % 64,
+		}
+		clean := Sanitize(id, opts)
+
+		if !utf8.ValidString(clean) {
+			t.Errorf("Sanitize(%q) produced invalid UTF-8 %q", id, clean)
+		}
+		if opts.MaxLength > 0 && utf8.RuneCountInString(clean) > opts.MaxLength {
+			t.Errorf("Sanitize(%q) = %q exceeds %d characters", id, clean, opts.MaxLength)
+		}
+		// Sanitizing is idempotent
+		if again := Sanitize(clean, opts); again != clean {
+			t.Errorf("Sanitize(%q) = %q, but sanitizing again gives %q", id, clean, again)
+		}
+	})
+}
+
+// generatorParams produces random ASCII generator configurations for property tests
+type generatorParams struct {
+	Alphabet string
+	Size     int
+}
+
+func (generatorParams) Generate(r *rand.Rand, _ int) reflect.Value {
+	// Shuffle the default alphabet and keep a random prefix of at least two characters
+	chars := []byte(DefaultAlphabet)
+	r.Shuffle(len(chars), func(i, j int) { chars[i], chars[j] = chars[j], chars[i] })
+
+	return reflect.ValueOf(generatorParams{
+		Alphabet: string(chars[:2+r.Intn(len(chars)-1)]),
+		Size:     1 + r.Intn(64),
+	})
+}
+
+func TestPropertyGeneratedIDsValidate(t *testing.T) {
+	property := func(p generatorParams) bool {
+		gen := New(WithAlphabet(p.Alphabet), WithSize(p.Size))
+
+		id, err := gen.Generate()
+		if err != nil {
+			return false
+		}
+		return len(id) == p.Size && gen.Validate(id) && IsValidID(id, p.Alphabet, p.Size)
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertyExtendedGeneratedIDsValidate(t *testing.T) {
+	ctx := context.Background()
+	property := func(p generatorParams) bool {
+		gen := NewExtendedGenerator(
+			WithCustomAlphabet(p.Alphabet),
+			func(cfg *GeneratorConfig) {
+				cfg.Size = p.Size
+			},
+		)
+
+		id, err := gen.Generate(ctx)
+		if err != nil {
+			// Tiny keyspaces may legitimately run out of unique IDs
+			return errors.Is(err, ErrCollision)
+		}
+		return IsValidID(id, p.Alphabet, p.Size)
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
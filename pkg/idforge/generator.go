@@ -3,9 +3,10 @@ package idforge
 import (
 	"context"
 	"crypto/rand"
-	"math/big"
+	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
 )
@@ -20,6 +21,14 @@ type Generator struct {
 	alphabet string
 	size     int
 	entropy  []entropy.EntropyProvider
+
+	revocations *RevocationList
+	replays     *ReplayGuard
+	forbidden   *Wordlist
+	charset     *charset // The alphabet compiled for validation
+
+	stats          statsCounters
+	providerErrors []uint64
 }
 
 func New(opts ...Option) *Generator {
@@ -32,6 +41,7 @@ func New(opts ...Option) *Generator {
 	for _, opt := range opts {
 		opt(g)
 	}
+	g.charset = newCharset(g.alphabet)
 	return g
 }
 
@@ -40,45 +50,48 @@ func (g *Generator) Generate() (string, error) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	start := time.Now()
+	id, err := g.generate()
+	g.stats.record(start, err)
+	return id, err
+}
+
+// generate builds an ID; the caller must hold g.mu
+func (g *Generator) generate() (string, error) {
 	// Collect entropy from providers
 	var entropyParts []string
 	ctx := context.Background()
-	for _, provider := range g.entropy {
+	for i, provider := range g.entropy {
 		entropyStr, err := provider.Provide(ctx)
 		if err != nil {
-			return "", err
+			g.recordProviderError(i)
+			return "", fmt.Errorf("%w: %s: %w", ErrEntropyUnavailable, providerName(provider), err)
 		}
 		entropyParts = append(entropyParts, entropyStr)
 	}
 
-	// Generate the ID using collected entropy
-	id := make([]byte, g.size)
-	alphabetLen := big.NewInt(int64(len(g.alphabet)))
-
 	// Use entropy as additional randomness source
 	combinedEntropy := strings.Join(entropyParts, "")
 	seedBytes := []byte(combinedEntropy)
 
-	for i := 0; i < g.size; i++ {
-		// Use cryptographically secure random number generation
-		num, err := rand.Int(rand.Reader, alphabetLen)
-		if err != nil {
-			return "", err
-		}
-
-		// Add some entropy-based randomness
-		if len(seedBytes) > 0 {
-			num = new(big.Int).Add(
-				num,
-				big.NewInt(int64(seedBytes[i%len(seedBytes)])),
-			)
-			num = new(big.Int).Mod(num, alphabetLen)
+	// Use cryptographically secure random number generation, shifted by
+	// the entropy bytes, redrawing IDs that contain a forbidden word
+	alphabet := newSymbols(g.alphabet)
+	for attempt := 0; attempt < maxFilterAttempts; attempt++ {
+		id, err := buildID(rand.Reader, alphabet, g.size, seedBytes, nil)
+		if err != nil || g.forbidden == nil || !g.forbidden.Contains(id) {
+			return id, err
 		}
-
-		id[i] = g.alphabet[num.Int64()]
 	}
+	return "", fmt.Errorf("%w after %d attempts", ErrForbiddenWord, maxFilterAttempts)
+}
 
-	return string(id), nil
+// recordProviderError counts a failure of the i-th entropy provider
+func (g *Generator) recordProviderError(i int) {
+	if len(g.providerErrors) != len(g.entropy) {
+		g.providerErrors = make([]uint64, len(g.entropy))
+	}
+	g.providerErrors[i]++
 }
 
 // MustGenerate generates an ID, panicking on error
@@ -92,17 +105,53 @@ func (g *Generator) MustGenerate() string {
 
 // Validate checks if an ID meets the generator's criteria
 func (g *Generator) Validate(id string) bool {
-	if len(id) != g.size {
-		return false
+	return g.Check(id) == nil
+}
+
+// Check explains why Validate rejects an ID. Rule violations are reported
+// as a *ValidationError; a failing revocation or replay store is returned
+// wrapped.
+func (g *Generator) Check(id string) error {
+	alphabet := g.charset
+	if alphabet == nil {
+		alphabet = newCharset(g.alphabet)
+	}
+	err := alphabet.check(id, g.size)
+	if g.forbidden != nil {
+		if word, ok := g.forbidden.Find(id); ok {
+			err = addViolation(err, id, g.forbidden.violation(word))
+		}
+	}
+	if err != nil {
+		return err
 	}
 
-	for _, char := range id {
-		if !strings.ContainsRune(g.alphabet, char) {
-			return false
+	if g.revocations != nil {
+		revoked, err := g.revocations.Contains(context.Background(), id)
+		if err != nil {
+			return fmt.Errorf("revocation check: %w", err)
+		}
+		if revoked {
+			return &ValidationError{ID: id, violations: []Violation{{
+				Rule:    RuleRevoked,
+				Message: "ID has been revoked",
+			}}}
 		}
 	}
 
-	return true
+	if g.replays != nil {
+		replayed, err := g.replays.Check(context.Background(), id)
+		if err != nil {
+			return fmt.Errorf("replay check: %w", err)
+		}
+		if replayed {
+			return &ValidationError{ID: id, violations: []Violation{{
+				Rule:    RuleReplayed,
+				Message: "ID has already been used",
+			}}}
+		}
+	}
+	return nil
 }
 
 // Quick generation functions for convenience
@@ -0,0 +1,80 @@
+package idforge
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+var ErrInvalidGS1Prefix = fmt.Errorf("%w: GS1 prefix must be digits shorter than the ID", ErrInvalidConfig)
+
+// GS1Generator creates numeric identifiers with a fixed prefix, such as a
+// GS1 company prefix or the 978 ISBN prefix, and a trailing GS1 modulo 10
+// check digit, as used by EAN-13, UPC-A, GTIN-14 and SSCC codes
+type GS1Generator struct {
+	prefix string
+	length int
+}
+
+// NewGS1Generator creates a generator of length-digit IDs, prefix and check
+// digit included; 13 gives EAN-13 compatible numbers
+func NewGS1Generator(prefix string, length int) (*GS1Generator, error) {
+	if length < 2 {
+		return nil, ErrInvalidSize
+	}
+	if len(prefix) >= length || !isDigits(prefix) {
+		return nil, ErrInvalidGS1Prefix
+	}
+	return &GS1Generator{prefix: prefix, length: length}, nil
+}
+
+// Generate creates a number starting with the prefix and ending in its
+// check digit
+func (g *GS1Generator) Generate() (string, error) {
+	random, err := buildID(rand.Reader, newSymbols("0123456789"), g.length-len(g.prefix)-1, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	body := g.prefix + random
+	return body + string(gs1CheckDigit(body)), nil
+}
+
+// Validate checks the length, prefix and check digit of id
+func (g *GS1Generator) Validate(id string) bool {
+	return len(id) == g.length && id[:len(g.prefix)] == g.prefix && ValidateGS1(id)
+}
+
+// GS1CheckDigit returns the check digit for digits, weighting them 3 and 1
+// alternately from the right
+func GS1CheckDigit(digits string) (byte, error) {
+	if digits == "" || !isDigits(digits) {
+		return 0, ErrInvalidEncoding
+	}
+	return gs1CheckDigit(digits), nil
+}
+
+// ValidateGS1 checks that id is all digits and ends in a correct check digit
+func ValidateGS1(id string) bool {
+	if len(id) < 2 || !isDigits(id) {
+		return false
+	}
+	return id[len(id)-1] == gs1CheckDigit(id[:len(id)-1])
+}
+
+func gs1CheckDigit(digits string) byte {
+	sum := 0
+	weight := 3
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += weight * int(digits[i]-'0')
+		weight = 4 - weight
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package idforge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGS1CheckDigit(t *testing.T) {
+	// EAN-13 of an ISBN and a UPC-A code
+	for _, id := range []string{"9780306406157", "036000291452"} {
+		if !ValidateGS1(id) {
+			t.Errorf("Expected %s to validate", id)
+		}
+	}
+	if ValidateGS1("9780306406158") {
+		t.Error("Expected a wrong check digit to be rejected")
+	}
+
+	digit, err := GS1CheckDigit("978030640615")
+	if err != nil || digit != '7' {
+		t.Errorf("Expected check digit 7, got %q, %v", digit, err)
+	}
+	if _, err := GS1CheckDigit("12a"); err != ErrInvalidEncoding {
+		t.Errorf("Expected ErrInvalidEncoding, got %v", err)
+	}
+}
+
+func TestGS1Generator(t *testing.T) {
+	gen, err := NewGS1Generator("4006381", 13)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		id, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(id) != 13 || !strings.HasPrefix(id, "4006381") {
+			t.Errorf("Expected 13 digits starting with 4006381, got %s", id)
+		}
+		if !gen.Validate(id) {
+			t.Errorf("Expected %s to validate", id)
+		}
+	}
+	if gen.Validate("9780306406157") {
+		t.Error("Expected a foreign prefix to be rejected")
+	}
+}
+
+func TestGS1GeneratorInvalid(t *testing.T) {
+	for _, prefix := range []string{"12a", "1234567890123"} {
+		if _, err := NewGS1Generator(prefix, 13); err != ErrInvalidGS1Prefix {
+			t.Errorf("Prefix %q: expected ErrInvalidGS1Prefix, got %v", prefix, err)
+		}
+	}
+	if _, err := NewGS1Generator("", 1); err != ErrInvalidSize {
+		t.Errorf("Expected ErrInvalidSize, got %v", err)
+	}
+}
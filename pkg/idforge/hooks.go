@@ -0,0 +1,41 @@
+package idforge
+
+import "context"
+
+// Hook intercepts Generate calls. Before may return a derived context,
+// e.g. carrying a trace span, which the rest of the call uses; After sees
+// the outcome. Either may be nil.
+type Hook struct {
+	Before func(ctx context.Context) context.Context
+	After  func(id string, err error)
+}
+
+// WithHooks adds an interceptor around Generate for cross-cutting concerns
+// such as metrics, audit or enrichment. Hooks form a chain: Before hooks
+// run in the order they were added and After hooks in reverse, so the
+// first hook added wraps all the others.
+func WithHooks(before func(ctx context.Context) context.Context, after func(id string, err error)) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		hooks := make([]Hook, len(c.Hooks), len(c.Hooks)+1)
+		copy(hooks, c.Hooks)
+		c.Hooks = append(hooks, Hook{Before: before, After: after})
+	}
+}
+
+// runHooks calls generate inside the hook chain
+func runHooks(ctx context.Context, hooks []Hook, generate func(ctx context.Context) (GenResult, error)) (GenResult, error) {
+	for _, hook := range hooks {
+		if hook.Before != nil {
+			ctx = hook.Before(ctx)
+		}
+	}
+
+	result, err := generate(ctx)
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if hooks[i].After != nil {
+			hooks[i].After(result.ID, err)
+		}
+	}
+	return result, err
+}
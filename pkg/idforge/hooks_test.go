@@ -0,0 +1,71 @@
+package idforge
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type hookKey struct{}
+
+func TestWithHooksChain(t *testing.T) {
+	var calls []string
+	var seen any
+	gen := NewExtendedGenerator(
+		WithHooks(func(ctx context.Context) context.Context {
+			calls = append(calls, "before 1")
+			return context.WithValue(ctx, hookKey{}, "enriched")
+		}, func(id string, err error) {
+			calls = append(calls, "after 1")
+		}),
+		WithHooks(func(ctx context.Context) context.Context {
+			calls = append(calls, "before 2")
+			seen = ctx.Value(hookKey{})
+			return ctx
+		}, func(id string, err error) {
+			calls = append(calls, "after 2")
+		}),
+	)
+
+	if _, err := gen.Generate(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []string{"before 1", "before 2", "after 2", "after 1"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected %v, got %v", want, calls)
+	}
+	if seen != "enriched" {
+		t.Errorf("Expected later hooks to see the derived context, got %v", seen)
+	}
+}
+
+func TestWithHooksSeesOutcome(t *testing.T) {
+	var gotID string
+	var gotErr error
+	after := func(id string, err error) { gotID, gotErr = id, err }
+
+	gen := NewExtendedGenerator(WithHooks(nil, after))
+	id, err := gen.Generate(context.Background())
+	if err != nil || gotID != id || gotErr != nil {
+		t.Errorf("Expected the hook to see %s, got %s, %v", id, gotID, gotErr)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	gen = NewExtendedGenerator(WithHooks(func(context.Context) context.Context { return ctx }, after))
+	cancel()
+	if _, err := gen.Generate(context.Background()); err == nil {
+		t.Fatal("Expected the cancelled context from Before to be used")
+	}
+	if gotErr == nil || gotID != "" {
+		t.Errorf("Expected the hook to see the error, got %q, %v", gotID, gotErr)
+	}
+}
+
+func TestWithHooksDoesNotShareSlices(t *testing.T) {
+	base := []func(*GeneratorConfig){WithHooks(nil, nil)}
+	a := NewExtendedGenerator(append(base, WithHooks(nil, nil))...)
+	b := NewExtendedGenerator(base...)
+	if len(a.Config().Hooks) != 2 || len(b.Config().Hooks) != 1 {
+		t.Errorf("Expected 2 and 1 hooks, got %d and %d", len(a.Config().Hooks), len(b.Config().Hooks))
+	}
+}
@@ -0,0 +1,157 @@
+package idforgetest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// DefaultSamples is the number of IDs RunFormatConformance generates when
+// FormatSpec.Samples is zero
+const DefaultSamples = 1000
+
+// FormatSpec describes the IDs a generator must produce. Zero-valued
+// fields skip their check.
+type FormatSpec struct {
+	Samples  int    // IDs to generate, 0 uses DefaultSamples
+	Length   int    // Exact length in characters
+	Charset  string // Every character that may appear, including literals
+	Pattern  string // Regular expression every ID must match
+	Sortable bool   // Each ID sorts strictly after the one generated before it
+	Unique   bool   // No ID repeats within the samples
+
+	// RoundTrip parses an ID and formats it again; the result must equal
+	// the input. For a LayoutGenerator, join the Segments of Parse.
+	RoundTrip func(id string) (string, error)
+}
+
+// RunFormatConformance generates spec.Samples IDs with gen and checks them
+// against spec, one subtest per property, so teams extending the library
+// with their own layouts or alphabets can assert they stay correct:
+//
+//	idforgetest.RunFormatConformance(t, layout.Generate, idforgetest.FormatSpec{
+//		Length:   26,
+//		Charset:  "0123456789ABCDEFGHJKMNPQRSTVWXYZ",
+//		Sortable: true,
+//		Unique:   true,
+//	})
+func RunFormatConformance(t *testing.T, gen func() (string, error), spec FormatSpec) {
+	t.Helper()
+
+	samples := spec.Samples
+	if samples <= 0 {
+		samples = DefaultSamples
+	}
+	ids := make([]string, samples)
+	for i := range ids {
+		id, err := gen()
+		if err != nil {
+			t.Fatalf("Generating sample %d: %v", i, err)
+		}
+		ids[i] = id
+	}
+
+	for _, check := range conformanceChecks(spec) {
+		t.Run(check.name, func(t *testing.T) {
+			for _, failure := range check.run(ids) {
+				t.Error(failure)
+			}
+		})
+	}
+}
+
+// conformanceCheck is one property of a FormatSpec
+type conformanceCheck struct {
+	name string
+	run  func(ids []string) []string // Returns a message per failure
+}
+
+// maxFailures caps the messages a check reports
+const maxFailures = 10
+
+// conformanceChecks returns the checks spec asks for
+func conformanceChecks(spec FormatSpec) []conformanceCheck {
+	var checks []conformanceCheck
+	if spec.Length > 0 {
+		checks = append(checks, conformanceCheck{"length", eachID(func(id string) string {
+			if n := utf8.RuneCountInString(id); n != spec.Length {
+				return fmt.Sprintf("ID %q has %d characters, expected %d", id, n, spec.Length)
+			}
+			return ""
+		})})
+	}
+	if spec.Charset != "" {
+		checks = append(checks, conformanceCheck{"charset", eachID(func(id string) string {
+			for _, char := range id {
+				if !strings.ContainsRune(spec.Charset, char) {
+					return fmt.Sprintf("ID %q contains %q outside the charset", id, char)
+				}
+			}
+			return ""
+		})})
+	}
+	if spec.Pattern != "" {
+		re := regexp.MustCompile(spec.Pattern)
+		checks = append(checks, conformanceCheck{"pattern", eachID(func(id string) string {
+			if !re.MatchString(id) {
+				return fmt.Sprintf("ID %q does not match %s", id, spec.Pattern)
+			}
+			return ""
+		})})
+	}
+	if spec.Sortable {
+		checks = append(checks, conformanceCheck{"sortable", func(ids []string) []string {
+			var failures []string
+			for i := 1; i < len(ids) && len(failures) < maxFailures; i++ {
+				if ids[i] <= ids[i-1] {
+					failures = append(failures, fmt.Sprintf("ID %q does not sort after the earlier %q", ids[i], ids[i-1]))
+				}
+			}
+			return failures
+		}})
+	}
+	if spec.Unique {
+		checks = append(checks, conformanceCheck{"unique", func(ids []string) []string {
+			var failures []string
+			seen := make(map[string]bool, len(ids))
+			for _, id := range ids {
+				if seen[id] && len(failures) < maxFailures {
+					failures = append(failures, fmt.Sprintf("ID %q repeats", id))
+				}
+				seen[id] = true
+			}
+			return failures
+		}})
+	}
+	if spec.RoundTrip != nil {
+		checks = append(checks, conformanceCheck{"round-trip", eachID(func(id string) string {
+			got, err := spec.RoundTrip(id)
+			if err != nil {
+				return fmt.Sprintf("ID %q does not parse: %v", id, err)
+			}
+			if got != id {
+				return fmt.Sprintf("ID %q round-trips to %q", id, got)
+			}
+			return ""
+		})})
+	}
+	return checks
+}
+
+// eachID applies check to every ID, collecting up to maxFailures messages
+func eachID(check func(id string) string) func(ids []string) []string {
+	return func(ids []string) []string {
+		var failures []string
+		for _, id := range ids {
+			if msg := check(id); msg != "" {
+				failures = append(failures, msg)
+				if len(failures) == maxFailures {
+					break
+				}
+			}
+		}
+		return failures
+	}
+}
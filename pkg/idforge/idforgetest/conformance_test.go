@@ -0,0 +1,83 @@
+package idforgetest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mrityunjay-vashisth/go-idforge/pkg/idforge"
+)
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+func TestRunFormatConformanceGenerator(t *testing.T) {
+	gen := idforge.New()
+	RunFormatConformance(t, gen.Generate, FormatSpec{
+		Length:  idforge.DefaultSize,
+		Charset: idforge.DefaultAlphabet,
+		Pattern: gen.Pattern(),
+		Unique:  true,
+	})
+}
+
+func TestRunFormatConformanceLayout(t *testing.T) {
+	layout, err := idforge.NewLayout().
+		Timestamp(crockford, 10, time.Millisecond).
+		Literal("-").
+		Random(crockford, 16).
+		Build(idforge.WithMonotonic())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	RunFormatConformance(t, layout.Generate, FormatSpec{
+		Samples:  200,
+		Length:   27,
+		Charset:  crockford + "-",
+		Pattern:  layout.Pattern(),
+		Sortable: true,
+		Unique:   true,
+		RoundTrip: func(id string) (string, error) {
+			parsed, err := layout.Parse(id)
+			return strings.Join(parsed.Segments, ""), err
+		},
+	})
+}
+
+func TestConformanceChecksReportFailures(t *testing.T) {
+	spec := FormatSpec{
+		Length:   3,
+		Charset:  "abc",
+		Pattern:  "^a",
+		Sortable: true,
+		Unique:   true,
+		RoundTrip: func(id string) (string, error) {
+			return strings.ToUpper(id), nil
+		},
+	}
+	ids := []string{"abc", "abcd", "abz", "abc", "b"}
+
+	want := map[string]int{
+		"length":     2, // abcd, b
+		"charset":    2, // abcd, abz
+		"pattern":    1, // b
+		"sortable":   1, // abc after abz
+		"unique":     1, // abc
+		"round-trip": 5, // every ID changes case
+	}
+	checks := conformanceChecks(spec)
+	if len(checks) != len(want) {
+		t.Fatalf("Expected %d checks, got %d", len(want), len(checks))
+	}
+	for _, check := range checks {
+		if got := len(check.run(ids)); got != want[check.name] {
+			t.Errorf("Check %s: expected %d failures, got %d", check.name, want[check.name], got)
+		}
+	}
+}
+
+func TestConformanceChecksSkipZeroFields(t *testing.T) {
+	if checks := conformanceChecks(FormatSpec{}); len(checks) != 0 {
+		t.Errorf("Expected no checks for an empty spec, got %d", len(checks))
+	}
+}
@@ -0,0 +1,202 @@
+// Package idforgetest provides deterministic entropy providers, scriptable
+// fake generators and assertion helpers for testing code that generates
+// identifiers.
+//
+// Fake generators hand out a scripted sequence of IDs and errors, so tests
+// can assert on exact values instead of matching random output:
+//
+//	gen := idforgetest.NewGenerator("order-1", "order-2")
+//	gen.PushError(idforge.ErrCollision)
+//	svc := NewOrderService(gen.Generate)
+package idforgetest
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/mrityunjay-vashisth/go-idforge/pkg/idforge"
+)
+
+// ErrScriptExhausted is returned once a fake has handed out every scripted
+// value
+var ErrScriptExhausted = errors.New("idforgetest: script exhausted")
+
+// Entropy is a deterministic entropy provider that cycles through fixed
+// values, or always fails with Err when it is set
+type Entropy struct {
+	mu     sync.Mutex
+	values []string
+	err    error
+	calls  int
+}
+
+// NewEntropy creates a provider returning values in turn, starting over
+// after the last one
+func NewEntropy(values ...string) *Entropy {
+	if len(values) == 0 {
+		values = []string{"idforgetest"}
+	}
+	return &Entropy{values: values}
+}
+
+// FailingEntropy creates a provider that always returns err
+func FailingEntropy(err error) *Entropy {
+	return &Entropy{err: err}
+}
+
+func (e *Entropy) Provide(ctx context.Context) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.calls++
+	if e.err != nil {
+		return "", e.err
+	}
+	return e.values[(e.calls-1)%len(e.values)], nil
+}
+
+// Calls returns how often the provider was queried
+func (e *Entropy) Calls() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.calls
+}
+
+// script is a queue of IDs and errors shared by the fake generators
+type script struct {
+	mu    sync.Mutex
+	steps []step
+	calls int
+}
+
+type step struct {
+	id  string
+	err error
+}
+
+// Push appends IDs to the script
+func (s *script) Push(ids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		s.steps = append(s.steps, step{id: id})
+	}
+}
+
+// PushError appends a failing call to the script
+func (s *script) PushError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.steps = append(s.steps, step{err: err})
+}
+
+// Calls returns how many IDs were requested, including failed requests
+func (s *script) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// Remaining returns how many scripted steps are left
+func (s *script) Remaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.steps)
+}
+
+// next pops the next step
+func (s *script) next() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if len(s.steps) == 0 {
+		return "", ErrScriptExhausted
+	}
+	st := s.steps[0]
+	s.steps = s.steps[1:]
+	return st.id, st.err
+}
+
+// Generator is a fake with the method set of idforge.Generator's Generate
+// and MustGenerate, returning scripted IDs and errors
+type Generator struct {
+	script
+}
+
+// NewGenerator creates a fake generator that returns ids in order
+func NewGenerator(ids ...string) *Generator {
+	g := &Generator{}
+	g.Push(ids...)
+	return g
+}
+
+// Generate returns the next scripted ID or error
+func (g *Generator) Generate() (string, error) {
+	return g.next()
+}
+
+// MustGenerate returns the next scripted ID, panicking on a scripted error
+func (g *Generator) MustGenerate() string {
+	id, err := g.Generate()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// ContextGenerator is a fake with the Generate method of
+// idforge.ExtendedGenerator, returning scripted IDs and errors
+type ContextGenerator struct {
+	script
+}
+
+// NewContextGenerator creates a fake generator that returns ids in order
+func NewContextGenerator(ids ...string) *ContextGenerator {
+	g := &ContextGenerator{}
+	g.Push(ids...)
+	return g
+}
+
+// Generate returns the next scripted ID or error, or the context's error
+// without consuming a step once ctx is done
+func (g *ContextGenerator) Generate(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return g.next()
+}
+
+// AssertValid fails the test unless id has size characters from alphabet
+func AssertValid(t testing.TB, id, alphabet string, size int) {
+	t.Helper()
+	if err := idforge.CheckID(id, alphabet, size); err != nil {
+		t.Errorf("ID %q is invalid: %v", id, err)
+	}
+}
+
+// AssertMatches fails the test unless id matches the pattern of the IDs
+// gen produces
+func AssertMatches(t testing.TB, id string, gen idforge.Shaped) {
+	t.Helper()
+	pattern := gen.Shape().Pattern
+	if !regexp.MustCompile(pattern).MatchString(id) {
+		t.Errorf("ID %q does not match %s", id, pattern)
+	}
+}
+
+// AssertUnique fails the test if any ID repeats
+func AssertUnique(t testing.TB, ids []string) {
+	t.Helper()
+	seen := make(map[string]int, len(ids))
+	for i, id := range ids {
+		if first, ok := seen[id]; ok {
+			t.Errorf("ID %q at index %d repeats index %d", id, i, first)
+			continue
+		}
+		seen[id] = i
+	}
+}
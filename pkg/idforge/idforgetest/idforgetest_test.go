@@ -0,0 +1,94 @@
+package idforgetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+	"github.com/mrityunjay-vashisth/go-idforge/pkg/idforge"
+)
+
+// recorder captures assertion failures instead of failing the test
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestEntropyCycles(t *testing.T) {
+	provider := NewEntropy("a", "b")
+	for _, want := range []string{"a", "b", "a"} {
+		got, err := provider.Provide(context.Background())
+		if err != nil || got != want {
+			t.Errorf("Expected %s, got %s, %v", want, got, err)
+		}
+	}
+	if provider.Calls() != 3 {
+		t.Errorf("Expected 3 calls, got %d", provider.Calls())
+	}
+}
+
+func TestFailingEntropy(t *testing.T) {
+	boom := errors.New("boom")
+	gen := idforge.NewExtendedGenerator(idforge.WithEntropyProviders([]entropy.EntropyProvider{FailingEntropy(boom)}))
+	if _, err := gen.Generate(context.Background()); !errors.Is(err, boom) {
+		t.Errorf("Expected the scripted error, got %v", err)
+	}
+}
+
+func TestGeneratorScript(t *testing.T) {
+	gen := NewGenerator("id-1", "id-2")
+	gen.PushError(idforge.ErrCollision)
+
+	for _, want := range []string{"id-1", "id-2"} {
+		if got := gen.MustGenerate(); got != want {
+			t.Errorf("Expected %s, got %s", want, got)
+		}
+	}
+	if _, err := gen.Generate(); !errors.Is(err, idforge.ErrCollision) {
+		t.Errorf("Expected the scripted error, got %v", err)
+	}
+	if _, err := gen.Generate(); !errors.Is(err, ErrScriptExhausted) {
+		t.Errorf("Expected ErrScriptExhausted, got %v", err)
+	}
+	if gen.Calls() != 4 || gen.Remaining() != 0 {
+		t.Errorf("Expected 4 calls and nothing remaining, got %d and %d", gen.Calls(), gen.Remaining())
+	}
+}
+
+func TestContextGenerator(t *testing.T) {
+	gen := NewContextGenerator("id-1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := gen.Generate(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if id, err := gen.Generate(context.Background()); err != nil || id != "id-1" {
+		t.Errorf("Expected id-1 after a cancelled call, got %s, %v", id, err)
+	}
+}
+
+func TestAssertions(t *testing.T) {
+	r := &recorder{TB: t}
+	AssertValid(r, "abc", "abc", 3)
+	AssertMatches(r, idforge.Generate(), idforge.New())
+	AssertUnique(r, []string{"a", "b"})
+	if len(r.failures) != 0 {
+		t.Errorf("Expected no failures, got %v", r.failures)
+	}
+
+	AssertValid(r, "abd", "abc", 3)
+	AssertMatches(r, "short", idforge.New())
+	AssertUnique(r, []string{"a", "b", "a"})
+	if len(r.failures) != 3 {
+		t.Errorf("Expected 3 failures, got %v", r.failures)
+	}
+}
@@ -0,0 +1,165 @@
+package idforge
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+	"unicode/utf8"
+)
+
+var ErrInvalidMessage = errors.New("invalid ID message")
+
+// IDMessage is the Go form of the idforge.v1.ID protobuf message in
+// proto/idforge/v1/id.proto. Its binary encoding is the protobuf wire
+// format, so it interoperates with code generated from the definition
+// without this module depending on a protobuf runtime.
+type IDMessage struct {
+	Format    string
+	Value     string
+	CreatedAt time.Time // Zero when unknown
+}
+
+// NewIDMessage wraps id, naming its format when ValidateAny recognizes it
+func NewIDMessage(id string, createdAt time.Time) IDMessage {
+	msg := IDMessage{Value: id, CreatedAt: createdAt}
+	if format, err := ValidateAny(id); err == nil {
+		msg.Format = format.String()
+	}
+	return msg
+}
+
+// Protobuf field numbers and wire types
+const (
+	fieldFormat    = 1
+	fieldValue     = 2
+	fieldCreatedAt = 3
+	fieldSeconds   = 1
+	fieldNanos     = 2
+
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// MarshalBinary encodes the message in the protobuf wire format, omitting
+// empty fields as proto3 does
+func (m IDMessage) MarshalBinary() ([]byte, error) {
+	var data []byte
+	if m.Format != "" {
+		data = appendBytesField(data, fieldFormat, []byte(m.Format))
+	}
+	if m.Value != "" {
+		data = appendBytesField(data, fieldValue, []byte(m.Value))
+	}
+	if !m.CreatedAt.IsZero() {
+		var ts []byte
+		if secs := m.CreatedAt.Unix(); secs != 0 {
+			ts = binary.AppendUvarint(ts, fieldSeconds<<3|wireVarint)
+			ts = binary.AppendUvarint(ts, uint64(secs))
+		}
+		if nanos := m.CreatedAt.Nanosecond(); nanos != 0 {
+			ts = binary.AppendUvarint(ts, fieldNanos<<3|wireVarint)
+			ts = binary.AppendUvarint(ts, uint64(nanos))
+		}
+		data = appendBytesField(data, fieldCreatedAt, ts)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a message in the protobuf wire format, skipping
+// fields it does not know
+func (m *IDMessage) UnmarshalBinary(data []byte) error {
+	var msg IDMessage
+	err := walkFields(data, func(field uint64, value []byte, _ uint64) error {
+		switch field {
+		case fieldFormat, fieldValue:
+			if value == nil || !utf8.Valid(value) {
+				return ErrInvalidMessage
+			}
+			if field == fieldFormat {
+				msg.Format = string(value)
+			} else {
+				msg.Value = string(value)
+			}
+		case fieldCreatedAt:
+			if value == nil {
+				return ErrInvalidMessage
+			}
+			var secs, nanos int64
+			err := walkFields(value, func(field uint64, _ []byte, n uint64) error {
+				switch field {
+				case fieldSeconds:
+					secs = int64(n)
+				case fieldNanos:
+					nanos = int64(int32(n))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			msg.CreatedAt = time.Unix(secs, nanos).UTC()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	*m = msg
+	return nil
+}
+
+// appendBytesField appends a length-delimited field
+func appendBytesField(data []byte, field uint64, value []byte) []byte {
+	data = binary.AppendUvarint(data, field<<3|wireBytes)
+	data = binary.AppendUvarint(data, uint64(len(value)))
+	return append(data, value...)
+}
+
+// walkFields calls fn for each field in data with its number and either
+// its length-delimited bytes or its numeric value
+func walkFields(data []byte, fn func(field uint64, value []byte, n uint64) error) error {
+	for len(data) > 0 {
+		key, k := binary.Uvarint(data)
+		if k <= 0 || key>>3 == 0 {
+			return ErrInvalidMessage
+		}
+		data = data[k:]
+
+		var (
+			value []byte
+			n     uint64
+		)
+		switch key & 7 {
+		case wireVarint:
+			if n, k = binary.Uvarint(data); k <= 0 {
+				return ErrInvalidMessage
+			}
+			data = data[k:]
+		case wireFixed64, wireFixed32:
+			size := 8
+			if key&7 == wireFixed32 {
+				size = 4
+			}
+			if len(data) < size {
+				return ErrInvalidMessage
+			}
+			data = data[size:]
+		case wireBytes:
+			length, k := binary.Uvarint(data)
+			if k <= 0 || length > uint64(len(data)-k) {
+				return ErrInvalidMessage
+			}
+			value = data[k : k+int(length) : k+int(length)]
+			data = data[k+int(length):]
+		default:
+			return ErrInvalidMessage
+		}
+
+		if err := fn(key>>3, value, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package idforge
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIDMessageRoundTrip(t *testing.T) {
+	msg := NewIDMessage("01ARZ3NDEKTSV4RRFFQ69G5FAV", time.Date(2024, 5, 1, 12, 0, 0, 123, time.UTC))
+	if msg.Format != "ulid" {
+		t.Errorf("Expected format ulid, got %q", msg.Format)
+	}
+
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var got IDMessage
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Format != msg.Format || got.Value != msg.Value || !got.CreatedAt.Equal(msg.CreatedAt) {
+		t.Errorf("Expected %+v, got %+v", msg, got)
+	}
+}
+
+func TestIDMessageWireFormat(t *testing.T) {
+	msg := IDMessage{Format: "uuid", Value: "x", CreatedAt: time.Unix(1, 5)}
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []byte{0x0a, 4, 'u', 'u', 'i', 'd', 0x12, 1, 'x', 0x1a, 4, 0x08, 1, 0x10, 5}
+	if !bytes.Equal(data, want) {
+		t.Errorf("Expected % x, got % x", want, data)
+	}
+
+	empty, _ := IDMessage{}.MarshalBinary()
+	if len(empty) != 0 {
+		t.Errorf("Expected an empty message to encode to no bytes, got % x", empty)
+	}
+}
+
+func TestIDMessageSkipsUnknownFields(t *testing.T) {
+	data := []byte{
+		0x48, 0x96, 0x01, // Field 9, varint
+		0x12, 2, 'i', 'd',
+		0x55, 1, 2, 3, 4, // Field 10, fixed32
+	}
+	var msg IDMessage
+	if err := msg.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Value != "id" || msg.Format != "" || !msg.CreatedAt.IsZero() {
+		t.Errorf("Unexpected message %+v", msg)
+	}
+}
+
+func TestIDMessageRejectsMalformed(t *testing.T) {
+	for _, data := range [][]byte{
+		{0x12, 5, 'a'},  // Truncated value
+		{0x10, 1},       // Value with the wrong wire type
+		{0x12, 1, 0xff}, // Invalid UTF-8
+		{0x1a, 2, 0x08}, // Truncated timestamp
+		{0x0b},          // Unsupported wire type
+		{0x08, 0x80},    // Truncated varint
+		{0x00, 0x01},    // Field number zero
+	} {
+		var msg IDMessage
+		if err := msg.UnmarshalBinary(data); !errors.Is(err, ErrInvalidMessage) {
+			t.Errorf("UnmarshalBinary(% x): expected ErrInvalidMessage, got %v", data, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package idforge
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+var ErrNoUniquenessStore = errors.New("generator has no uniqueness store")
+
+// ImportResult counts the IDs seen by ImportExisting
+type ImportResult struct {
+	Imported int // Newly reserved in the uniqueness store
+	Existing int // Already reserved, by an earlier import or generation
+}
+
+// ImportExisting reserves IDs issued before adopting idforge in the
+// generator's uniqueness store, for the generator's UniquenessTTL, so that
+// no instance sharing the store generates them again. It fails with
+// ErrNoUniquenessStore when the generator has none, and stops at the first
+// store error or when ctx is done, reporting what it imported so far.
+// Importing the same IDs twice is harmless.
+func (g *ExtendedGenerator) ImportExisting(ctx context.Context, ids iter.Seq[string]) (ImportResult, error) {
+	var result ImportResult
+	cfg := g.Config()
+	if cfg.UniquenessStore == nil {
+		return result, ErrNoUniquenessStore
+	}
+
+	for id := range ids {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		reserved, err := cfg.UniquenessStore.Reserve(ctx, id, cfg.UniquenessTTL)
+		if err != nil {
+			return result, fmt.Errorf("uniqueness store: importing %q: %w", id, err)
+		}
+		if reserved {
+			result.Imported++
+		} else {
+			result.Existing++
+		}
+	}
+	return result, nil
+}
+
+// ImportExistingFrom imports IDs read from r, one per line, as written by
+// ExportBatch in ExportText format. Surrounding whitespace is trimmed and
+// blank lines are skipped.
+func (g *ExtendedGenerator) ImportExistingFrom(ctx context.Context, r io.Reader) (ImportResult, error) {
+	scanner := bufio.NewScanner(r)
+	lines := func(yield func(string) bool) {
+		for scanner.Scan() {
+			if id := strings.TrimSpace(scanner.Text()); id != "" && !yield(id) {
+				return
+			}
+		}
+	}
+
+	result, err := g.ImportExisting(ctx, lines)
+	if err == nil {
+		err = scanner.Err()
+	}
+	return result, err
+}
@@ -0,0 +1,111 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingUniquenessStore refuses every reservation with an error
+type failingUniquenessStore struct{}
+
+func (failingUniquenessStore) Reserve(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	return false, errors.New("store down")
+}
+
+func TestImportExisting(t *testing.T) {
+	store := NewMemoryUniquenessStore()
+	// A seeded stream keeps the collision retries below deterministic
+	gen := NewExtendedGenerator(
+		WithCustomAlphabet("0123"),
+		func(cfg *GeneratorConfig) { cfg.Size = 3 },
+		WithUniquenessStore(store, 0),
+		WithRNG(NewRNG(rand.NewChaCha8([32]byte{}))),
+	)
+	ctx := context.Background()
+
+	// Import the 48 of the 64 possible IDs that do not start with 3
+	imported := make(map[string]bool)
+	for _, a := range "012" {
+		for _, b := range "0123" {
+			for _, c := range "0123" {
+				imported[string([]rune{a, b, c})] = true
+			}
+		}
+	}
+	result, err := gen.ImportExisting(ctx, maps.Keys(imported))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Imported != 48 || result.Existing != 0 {
+		t.Errorf("Expected 48 imported and 0 existing, got %+v", result)
+	}
+
+	for i := 0; i < 8; i++ {
+		id, err := gen.Generate(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if imported[id] {
+			t.Errorf("Expected no imported ID to be generated, got %s", id)
+		}
+	}
+
+	result, err = gen.ImportExisting(ctx, slices.Values([]string{"000", "123"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Imported != 0 || result.Existing != 2 {
+		t.Errorf("Expected 2 existing on reimport, got %+v", result)
+	}
+}
+
+func TestImportExistingFrom(t *testing.T) {
+	store := NewMemoryUniquenessStore()
+	gen := NewExtendedGenerator(WithUniquenessStore(store, 0))
+
+	result, err := gen.ImportExistingFrom(context.Background(), strings.NewReader("abc\n\n  def \r\nabc\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Imported != 2 || result.Existing != 1 {
+		t.Errorf("Expected 2 imported and 1 existing, got %+v", result)
+	}
+	if ok, _ := store.Reserve(context.Background(), "def", 0); ok {
+		t.Error("Expected trimmed ID def to be reserved")
+	}
+}
+
+func TestImportExistingErrors(t *testing.T) {
+	ctx := context.Background()
+	ids := slices.Values([]string{"abc"})
+
+	_, err := NewExtendedGenerator().ImportExisting(ctx, ids)
+	if !errors.Is(err, ErrNoUniquenessStore) {
+		t.Errorf("Expected ErrNoUniquenessStore, got %v", err)
+	}
+	if code := ErrorCode(err); code != CodeNoUniquenessStore {
+		t.Errorf("Expected code %s, got %s", CodeNoUniquenessStore, code)
+	}
+
+	gen := NewExtendedGenerator(WithUniquenessStore(failingUniquenessStore{}, 0))
+	if _, err := gen.ImportExisting(ctx, ids); err == nil || !strings.Contains(err.Error(), "store down") {
+		t.Errorf("Expected the store error, got %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	gen = NewExtendedGenerator(WithUniquenessStore(NewMemoryUniquenessStore(), 0))
+	result, err := gen.ImportExisting(cancelled, ids)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if result.Imported != 0 {
+		t.Errorf("Expected nothing imported, got %+v", result)
+	}
+}
@@ -0,0 +1,87 @@
+package idforge
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// InvitePlan is a code alphabet and length sized for an invite campaign
+type InvitePlan struct {
+	Alphabet string
+	Length   int
+	Invites  int // Codes the campaign is expected to hand out
+
+	// GuessProbability is the chance that a single random guess matches
+	// one of Invites valid codes
+	GuessProbability float64
+
+	// CollisionProbability is the chance that two of Invites random codes
+	// coincide before duplicate detection redraws one of them
+	CollisionProbability float64
+}
+
+// PlanInvites picks the shortest code length for which one random guess
+// matches any of invites valid codes with probability at most
+// guessProbability. To bound an attacker allowed k attempts, divide the
+// acceptable overall probability by k.
+//
+// Each of alphabets is tried in turn and the one giving the shortest codes
+// wins, ties going to the earlier, so list preferred alphabets first.
+// Without alphabets, the easily typed VoucherAlphabet is used.
+func PlanInvites(invites int, guessProbability float64, alphabets ...string) (InvitePlan, error) {
+	if invites < 1 {
+		return InvitePlan{}, fmt.Errorf("%w: invite plan needs at least one invite", ErrInvalidConfig)
+	}
+	if !(guessProbability > 0 && guessProbability < 1) {
+		return InvitePlan{}, fmt.Errorf("%w: guess probability must be between 0 and 1", ErrInvalidConfig)
+	}
+	if len(alphabets) == 0 {
+		alphabets = []string{VoucherAlphabet}
+	}
+
+	var best InvitePlan
+	for _, alphabet := range alphabets {
+		if !validAlphabet(alphabet) {
+			return InvitePlan{}, ErrInvalidAlphabet
+		}
+		// invites / n^length <= guessProbability
+		bitsPerChar := math.Log2(float64(alphabetLen(alphabet)))
+		bits := math.Log2(float64(invites)) - math.Log2(guessProbability)
+		length := max(int(math.Ceil(bits/bitsPerChar-1e-9)), 1) // Tolerate rounding in the logarithms
+		if best.Length == 0 || length < best.Length {
+			best = newInvitePlan(alphabet, length, invites)
+		}
+	}
+	return best, nil
+}
+
+func newInvitePlan(alphabet string, length, invites int) InvitePlan {
+	space := math.Pow(float64(alphabetLen(alphabet)), float64(length))
+	pairs := float64(invites) * float64(invites-1) / 2
+	return InvitePlan{
+		Alphabet:             alphabet,
+		Length:               length,
+		Invites:              invites,
+		GuessProbability:     math.Min(float64(invites)/space, 1),
+		CollisionProbability: -math.Expm1(-pairs / space),
+	}
+}
+
+// Generator returns a generator minting codes to the plan. It remembers at
+// least Invites codes for duplicate detection; opts are applied on top,
+// such as WithUniquenessStore to rule out duplicates across instances.
+func (p InvitePlan) Generator(opts ...func(*GeneratorConfig)) *ExtendedGenerator {
+	plan := func(c *GeneratorConfig) {
+		c.Alphabet = p.Alphabet
+		c.Size = p.Length
+		c.MaxUniqueIDs = max(c.MaxUniqueIDs, p.Invites)
+	}
+	return NewExtendedGenerator(append([]func(*GeneratorConfig){plan}, opts...)...)
+}
+
+// Mint generates all Invites codes of the plan in one batch, with no two
+// alike
+func (p InvitePlan) Mint(ctx context.Context, opts ...func(*GeneratorConfig)) ([]string, error) {
+	return p.Generator(opts...).GenerateBulk(ctx, p.Invites, 0)
+}
@@ -0,0 +1,89 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPlanInvites(t *testing.T) {
+	// 1000 invites at 1 in a billion per guess need 2^39.9 codes, 8
+	// characters of 5 bits each
+	plan, err := PlanInvites(1000, 1e-9)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if plan.Alphabet != VoucherAlphabet {
+		t.Errorf("Expected VoucherAlphabet by default, got %s", plan.Alphabet)
+	}
+	if plan.Length != 8 {
+		t.Errorf("Expected length 8, got %d", plan.Length)
+	}
+	if plan.GuessProbability > 1e-9 || plan.GuessProbability < 1e-10 {
+		t.Errorf("Expected guess probability just under 1e-9, got %g", plan.GuessProbability)
+	}
+	expected := 1000.0 * 999 / 2 / math.Pow(32, 8)
+	if math.Abs(plan.CollisionProbability-expected)/expected > 1e-6 {
+		t.Errorf("Expected collision probability %g, got %g", expected, plan.CollisionProbability)
+	}
+
+	// An exact power needs no extra character
+	plan, _ = PlanInvites(1, 1.0/1024, "01")
+	if plan.Length != 10 {
+		t.Errorf("Expected length 10 for 2^-10 in binary, got %d", plan.Length)
+	}
+}
+
+func TestPlanInvitesPicksAlphabet(t *testing.T) {
+	plan, err := PlanInvites(1000, 1e-9, "0123456789", DefaultAlphabet, base62Alphabet)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if plan.Alphabet != DefaultAlphabet {
+		t.Errorf("Expected the first of the shortest alphabets, got %s", plan.Alphabet)
+	}
+	if plan.Length != 7 {
+		t.Errorf("Expected length 7, got %d", plan.Length)
+	}
+}
+
+func TestPlanInvitesErrors(t *testing.T) {
+	for _, tc := range []struct {
+		invites     int
+		probability float64
+		alphabet    string
+	}{{0, 0.01, VoucherAlphabet}, {10, 0, VoucherAlphabet}, {10, 1, VoucherAlphabet}, {10, math.NaN(), VoucherAlphabet}} {
+		if _, err := PlanInvites(tc.invites, tc.probability, tc.alphabet); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("Expected ErrInvalidConfig for %d invites at %g, got %v", tc.invites, tc.probability, err)
+		}
+	}
+	if _, err := PlanInvites(10, 0.01, "A"); !errors.Is(err, ErrInvalidAlphabet) {
+		t.Errorf("Expected ErrInvalidAlphabet, got %v", err)
+	}
+}
+
+func TestInvitePlanMint(t *testing.T) {
+	plan, _ := PlanInvites(20000, 0.5, "0123456789")
+	codes, err := plan.Mint(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(codes) != 20000 {
+		t.Fatalf("Expected 20000 codes, got %d", len(codes))
+	}
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		if len(code) != plan.Length {
+			t.Fatalf("Expected length %d, got %s", plan.Length, code)
+		}
+		if seen[code] {
+			t.Fatalf("Expected unique codes, got %s twice", code)
+		}
+		seen[code] = true
+	}
+
+	if cfg := plan.Generator().Config(); cfg.MaxUniqueIDs < plan.Invites {
+		t.Errorf("Expected at least %d remembered IDs, got %d", plan.Invites, cfg.MaxUniqueIDs)
+	}
+}
@@ -0,0 +1,247 @@
+package idforge
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IssuanceResolution is the bucket width of an issuance series
+type IssuanceResolution int
+
+const (
+	PerMinute IssuanceResolution = iota
+	PerHour
+)
+
+func (r IssuanceResolution) String() string {
+	switch r {
+	case PerMinute:
+		return "minute"
+	case PerHour:
+		return "hour"
+	default:
+		return "unknown"
+	}
+}
+
+// duration returns the bucket width
+func (r IssuanceResolution) duration() time.Duration {
+	if r == PerHour {
+		return time.Hour
+	}
+	return time.Minute
+}
+
+const (
+	DefaultMinuteRetention = 24 * time.Hour
+	DefaultHourRetention   = 30 * 24 * time.Hour
+)
+
+// IssuanceBucket counts the IDs issued in one minute or hour
+type IssuanceBucket struct {
+	Start time.Time
+	Count uint64
+}
+
+// IssuanceCollector counts issued IDs per minute and per hour for each
+// namespace, such as a tenant or product, so issuance trends can be
+// queried or exported to metrics without scraping logs. Buckets are kept
+// in fixed rings, so memory per namespace depends only on the retention.
+type IssuanceCollector struct {
+	mu         sync.Mutex
+	minutes    int // Minute buckets kept per namespace
+	hours      int // Hour buckets kept per namespace
+	namespaces map[string]*issuanceCounts
+	clock      Clock
+}
+
+// IssuanceOption configures an IssuanceCollector
+type IssuanceOption func(*IssuanceCollector)
+
+// WithIssuanceClock places recorded IDs in buckets by clock instead of the
+// system clock
+func WithIssuanceClock(clock Clock) IssuanceOption {
+	return func(c *IssuanceCollector) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// issuanceCounts holds the buckets of one namespace
+type issuanceCounts struct {
+	total   uint64
+	minutes []issuanceSlot
+	hours   []issuanceSlot
+}
+
+// issuanceSlot is a ring entry; index identifies the bucket it currently
+// holds, so stale entries read as zero
+type issuanceSlot struct {
+	index int64
+	count uint64
+}
+
+// NewIssuanceCollector keeps per-minute counts for minuteRetention and
+// per-hour counts for hourRetention, or DefaultMinuteRetention and
+// DefaultHourRetention when they are not positive
+func NewIssuanceCollector(minuteRetention, hourRetention time.Duration, opts ...IssuanceOption) *IssuanceCollector {
+	if minuteRetention <= 0 {
+		minuteRetention = DefaultMinuteRetention
+	}
+	if hourRetention <= 0 {
+		hourRetention = DefaultHourRetention
+	}
+	c := &IssuanceCollector{
+		minutes:    int(max((minuteRetention+time.Minute-1)/time.Minute, 1)),
+		hours:      int(max((hourRetention+time.Hour-1)/time.Hour, 1)),
+		namespaces: make(map[string]*issuanceCounts),
+		clock:      SystemClock,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithIssuanceCollector counts every ID the generator issues, including
+// those from GenerateBulk, under namespace in c
+func WithIssuanceCollector(c *IssuanceCollector, namespace string) func(*GeneratorConfig) {
+	return func(cfg *GeneratorConfig) {
+		cfg.Issuance = c
+		cfg.IssuanceNamespace = namespace
+	}
+}
+
+// Record counts n IDs issued now under namespace. A nil collector ignores
+// the call.
+func (c *IssuanceCollector) Record(namespace string, n int) {
+	if c != nil && n > 0 {
+		c.record(namespace, n, c.clock.Now())
+	}
+}
+
+func (c *IssuanceCollector) record(namespace string, n int, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts, ok := c.namespaces[namespace]
+	if !ok {
+		counts = &issuanceCounts{
+			minutes: make([]issuanceSlot, c.minutes),
+			hours:   make([]issuanceSlot, c.hours),
+		}
+		c.namespaces[namespace] = counts
+	}
+	counts.total += uint64(n)
+	addToRing(counts.minutes, bucketIndex(now, time.Minute), uint64(n))
+	addToRing(counts.hours, bucketIndex(now, time.Hour), uint64(n))
+}
+
+// Series returns the counts for namespace in every bucket from the one
+// containing from up to the one containing to, oldest first. At most the
+// retention is returned, so a from further back is moved forward to the
+// oldest bucket a ring holds before to. Buckets older than the retention,
+// or without issuance, count zero.
+func (c *IssuanceCollector) Series(namespace string, resolution IssuanceResolution, from, to time.Time) []IssuanceBucket {
+	width := resolution.duration()
+	first, last := bucketIndex(from, width), bucketIndex(to, width)
+	if last < first {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	slots := c.minutes
+	if resolution == PerHour {
+		slots = c.hours
+	}
+	first = max(first, last-int64(slots)+1)
+
+	var ring []issuanceSlot
+	if counts, ok := c.namespaces[namespace]; ok {
+		ring = counts.minutes
+		if resolution == PerHour {
+			ring = counts.hours
+		}
+	}
+
+	series := make([]IssuanceBucket, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		bucket := IssuanceBucket{Start: time.Unix(0, i*int64(width)).UTC()}
+		if len(ring) > 0 {
+			if slot := ring[ringSlot(i, len(ring))]; slot.index == i {
+				bucket.Count = slot.count
+			}
+		}
+		series = append(series, bucket)
+	}
+	return series
+}
+
+// Total returns the IDs counted under namespace since the collector was
+// created
+func (c *IssuanceCollector) Total(namespace string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if counts, ok := c.namespaces[namespace]; ok {
+		return counts.total
+	}
+	return 0
+}
+
+// Namespaces lists the namespaces with recorded issuance, sorted
+func (c *IssuanceCollector) Namespaces() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make([]string, 0, len(c.namespaces))
+	for name := range c.namespaces {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// WriteMetrics writes the total per namespace as a counter in the
+// Prometheus text exposition format, for serving on a /metrics endpoint
+func (c *IssuanceCollector) WriteMetrics(w io.Writer) error {
+	if _, err := io.WriteString(w, "# HELP idforge_ids_issued_total IDs issued per namespace.\n# TYPE idforge_ids_issued_total counter\n"); err != nil {
+		return err
+	}
+	for _, name := range c.Namespaces() {
+		if _, err := fmt.Fprintf(w, "idforge_ids_issued_total{namespace=\"%s\"} %d\n", labelEscaper.Replace(name), c.Total(name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// labelEscaper escapes a Prometheus label value
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// bucketIndex numbers the bucket of width containing t
+func bucketIndex(t time.Time, width time.Duration) int64 {
+	return t.UnixNano() / int64(width)
+}
+
+// ringSlot maps a bucket index onto a ring of size n
+func ringSlot(index int64, n int) int {
+	return int(((index % int64(n)) + int64(n)) % int64(n))
+}
+
+// addToRing adds n to the bucket index, reusing its slot if it holds an
+// older bucket
+func addToRing(ring []issuanceSlot, index int64, n uint64) {
+	slot := &ring[ringSlot(index, len(ring))]
+	if slot.index != index {
+		*slot = issuanceSlot{index: index}
+	}
+	slot.count += n
+}
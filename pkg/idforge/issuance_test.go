@@ -0,0 +1,124 @@
+package idforge
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIssuanceCollectorSeries(t *testing.T) {
+	c := NewIssuanceCollector(10*time.Minute, 3*time.Hour)
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	c.record("orders", 2, base)
+	c.record("orders", 3, base.Add(30*time.Second))
+	c.record("orders", 1, base.Add(2*time.Minute))
+	c.record("users", 7, base.Add(time.Minute))
+
+	series := c.Series("orders", PerMinute, base, base.Add(2*time.Minute+59*time.Second))
+	expected := []IssuanceBucket{
+		{Start: base, Count: 5},
+		{Start: base.Add(time.Minute), Count: 0},
+		{Start: base.Add(2 * time.Minute), Count: 1},
+	}
+	if !reflect.DeepEqual(series, expected) {
+		t.Errorf("Expected %v, got %v", expected, series)
+	}
+
+	hourly := c.Series("orders", PerHour, base, base)
+	if len(hourly) != 1 || hourly[0].Count != 6 {
+		t.Errorf("Expected 6 IDs in the hour, got %v", hourly)
+	}
+	if total := c.Total("users"); total != 7 {
+		t.Errorf("Expected 7 users IDs, got %d", total)
+	}
+	if names := c.Namespaces(); !reflect.DeepEqual(names, []string{"orders", "users"}) {
+		t.Errorf("Expected both namespaces, got %v", names)
+	}
+	if series := c.Series("missing", PerMinute, base, base); len(series) != 1 || series[0].Count != 0 {
+		t.Errorf("Expected one empty bucket for an unknown namespace, got %v", series)
+	}
+	if series := c.Series("orders", PerMinute, base, base.Add(-time.Minute)); series != nil {
+		t.Errorf("Expected no buckets for an empty range, got %v", series)
+	}
+}
+
+func TestIssuanceCollectorRetention(t *testing.T) {
+	c := NewIssuanceCollector(10*time.Minute, 0)
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	c.record("orders", 4, base)
+	c.record("orders", 1, base.Add(10*time.Minute)) // Reuses the first slot
+
+	series := c.Series("orders", PerMinute, base, base)
+	if series[0].Count != 0 {
+		t.Errorf("Expected an expired minute to count zero, got %d", series[0].Count)
+	}
+	if hourly := c.Series("orders", PerHour, base, base); hourly[0].Count != 5 {
+		t.Errorf("Expected the hour to keep all 5 IDs, got %d", hourly[0].Count)
+	}
+	if total := c.Total("orders"); total != 5 {
+		t.Errorf("Expected a total of 5, got %d", total)
+	}
+
+	to := base.Add(10 * time.Minute)
+	series = c.Series("orders", PerMinute, time.Time{}, to)
+	if len(series) != 10 || !series[0].Start.Equal(base.Add(time.Minute)) || series[9].Count != 1 {
+		t.Errorf("Expected a zero from to return the 10 retained minutes, got %d buckets", len(series))
+	}
+	if hourly := c.Series("unknown", PerHour, time.Time{}, to); len(hourly) != 30*24 {
+		t.Errorf("Expected a zero from to return the retained hours, got %d buckets", len(hourly))
+	}
+}
+
+func TestWithIssuanceCollector(t *testing.T) {
+	c := NewIssuanceCollector(0, 0)
+	gen := NewExtendedGenerator(WithIssuanceCollector(c, "orders"))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := gen.Generate(ctx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if _, err := gen.GenerateBulk(ctx, 100, 2); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if total := c.Total("orders"); total != 103 {
+		t.Errorf("Expected 103 IDs counted, got %d", total)
+	}
+
+	now := time.Now()
+	var counted uint64
+	for _, bucket := range c.Series("orders", PerMinute, now.Add(-time.Minute), now) {
+		counted += bucket.Count
+	}
+	if counted != 103 {
+		t.Errorf("Expected 103 IDs in the last minutes, got %d", counted)
+	}
+}
+
+func TestIssuanceCollectorWriteMetrics(t *testing.T) {
+	c := NewIssuanceCollector(0, 0)
+	c.Record("orders", 3)
+	c.Record(`a"b`, 1)
+	var nilCollector *IssuanceCollector
+	nilCollector.Record("ignored", 1)
+
+	var buf bytes.Buffer
+	if err := c.WriteMetrics(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, line := range []string{
+		"# TYPE idforge_ids_issued_total counter",
+		`idforge_ids_issued_total{namespace="a\"b"} 1`,
+		`idforge_ids_issued_total{namespace="orders"} 3`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", line, buf.String())
+		}
+	}
+}
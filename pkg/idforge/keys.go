@@ -0,0 +1,207 @@
+package idforge
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+var ErrKeyNotFound = errors.New("signing key not found")
+
+// Key is a secret used to sign IDs. The ID is embedded in signed IDs so the
+// matching key can be found after rotation.
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// KeyProvider supplies signing keys. CurrentKey returns the key new IDs are
+// signed with; Key looks up any key still accepted for verification. The
+// providers in this package return a copy of the secret, so keys stay
+// valid after the provider is closed.
+type KeyProvider interface {
+	CurrentKey(ctx context.Context) (Key, error)
+	Key(ctx context.Context, id string) (Key, error)
+}
+
+// keySet holds the keys loaded from a source and which one is current
+type keySet struct {
+	current string
+	keys    map[string][]byte
+}
+
+// parseKeySet builds a key set from base64-encoded secrets
+func parseKeySet(current string, encoded map[string]string) (keySet, error) {
+	set := keySet{current: current, keys: make(map[string][]byte, len(encoded))}
+	for id, value := range encoded {
+		if id == "" || strings.Contains(id, ".") {
+			return keySet{}, fmt.Errorf("invalid key ID %q", id)
+		}
+		secret, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return keySet{}, fmt.Errorf("key %q: %w", id, err)
+		}
+		set.keys[id] = secret
+	}
+	if _, ok := set.keys[current]; !ok {
+		return keySet{}, fmt.Errorf("current key %q: %w", current, ErrKeyNotFound)
+	}
+	return set, nil
+}
+
+func (s keySet) key(id string) (Key, error) {
+	secret, ok := s.keys[id]
+	if !ok {
+		return Key{}, ErrKeyNotFound
+	}
+	return Key{ID: id, Secret: bytes.Clone(secret)}, nil
+}
+
+// EnvKeyProvider reads keys from environment variables. The current key ID
+// is read from <prefix>_CURRENT and each base64-encoded secret from
+// <prefix>_<keyID>.
+type EnvKeyProvider struct {
+	prefix string
+}
+
+// NewEnvKeyProvider creates a provider for variables named with prefix
+func NewEnvKeyProvider(prefix string) *EnvKeyProvider {
+	return &EnvKeyProvider{prefix: prefix}
+}
+
+func (p *EnvKeyProvider) CurrentKey(ctx context.Context) (Key, error) {
+	id, ok := os.LookupEnv(p.prefix + "_CURRENT")
+	if !ok {
+		return Key{}, ErrKeyNotFound
+	}
+	return p.Key(ctx, id)
+}
+
+func (p *EnvKeyProvider) Key(ctx context.Context, id string) (Key, error) {
+	value, ok := os.LookupEnv(p.prefix + "_" + id)
+	if !ok || id == "" || id == "CURRENT" {
+		return Key{}, ErrKeyNotFound
+	}
+	secret, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return Key{}, fmt.Errorf("key %q: %w", id, err)
+	}
+	return Key{ID: id, Secret: secret}, nil
+}
+
+// keyFile is the JSON layout read by FileKeyProvider
+type keyFile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// FileKeyProvider reads keys from a JSON file of the form
+// {"current": "k2", "keys": {"k1": "<base64>", "k2": "<base64>"}}.
+// Call Reload after rotating keys in the file.
+type FileKeyProvider struct {
+	path string
+
+	mu  sync.RWMutex
+	set keySet
+}
+
+// NewFileKeyProvider loads keys from the file at path
+func NewFileKeyProvider(path string) (*FileKeyProvider, error) {
+	p := &FileKeyProvider{path: path}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reload re-reads the key file, keeping the previous keys on error
+func (p *FileKeyProvider) Reload() error {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return err
+	}
+
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+	set, err := parseKeySet(file.Current, file.Keys)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.set = set
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *FileKeyProvider) CurrentKey(ctx context.Context) (Key, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.set.key(p.set.current)
+}
+
+func (p *FileKeyProvider) Key(ctx context.Context, id string) (Key, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.set.key(id)
+}
+
+// KMSDecrypter decrypts a data key. It is satisfied by a thin wrapper around
+// the AWS KMS Decrypt API, keeping the SDK out of this module.
+type KMSDecrypter interface {
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// KMSKeyProvider holds data keys encrypted under a KMS key and decrypts each
+// one on first use
+type KMSKeyProvider struct {
+	decrypter KMSDecrypter
+	current   string
+	encrypted map[string][]byte
+
+	mu        sync.Mutex
+	decrypted map[string][]byte
+}
+
+// NewKMSKeyProvider creates a provider for encryptedKeys, keyed by key ID,
+// signing new IDs with the key named current
+func NewKMSKeyProvider(decrypter KMSDecrypter, current string, encryptedKeys map[string][]byte) *KMSKeyProvider {
+	return &KMSKeyProvider{
+		decrypter: decrypter,
+		current:   current,
+		encrypted: encryptedKeys,
+		decrypted: make(map[string][]byte),
+	}
+}
+
+func (p *KMSKeyProvider) CurrentKey(ctx context.Context) (Key, error) {
+	return p.Key(ctx, p.current)
+}
+
+func (p *KMSKeyProvider) Key(ctx context.Context, id string) (Key, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if secret, ok := p.decrypted[id]; ok {
+		return Key{ID: id, Secret: bytes.Clone(secret)}, nil
+	}
+
+	ciphertext, ok := p.encrypted[id]
+	if !ok {
+		return Key{}, ErrKeyNotFound
+	}
+	secret, err := p.decrypter.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return Key{}, fmt.Errorf("kms decrypt key %q: %w", id, err)
+	}
+	p.decrypted[id] = secret
+	return Key{ID: id, Secret: bytes.Clone(secret)}, nil
+}
@@ -0,0 +1,120 @@
+package idforge
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func encodeKey(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func TestEnvKeyProvider(t *testing.T) {
+	t.Setenv("TEST_IDKEY_CURRENT", "k2")
+	t.Setenv("TEST_IDKEY_k1", encodeKey("first"))
+	t.Setenv("TEST_IDKEY_k2", encodeKey("second"))
+
+	provider := NewEnvKeyProvider("TEST_IDKEY")
+	ctx := context.Background()
+
+	key, err := provider.CurrentKey(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if key.ID != "k2" || string(key.Secret) != "second" {
+		t.Errorf("Expected current key k2, got %s=%q", key.ID, key.Secret)
+	}
+
+	if key, _ := provider.Key(ctx, "k1"); string(key.Secret) != "first" {
+		t.Errorf("Expected k1 secret, got %q", key.Secret)
+	}
+	if _, err := provider.Key(ctx, "k3"); err != ErrKeyNotFound {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+	if _, err := provider.Key(ctx, "CURRENT"); err != ErrKeyNotFound {
+		t.Errorf("Expected the current marker not to be a key, got %v", err)
+	}
+}
+
+func TestFileKeyProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"current": "k1", "keys": {"k1": "` + encodeKey("first") + `"}}`)
+
+	provider, err := NewFileKeyProvider(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	// Rotate to a new key while keeping the old one for verification
+	write(`{"current": "k2", "keys": {"k1": "` + encodeKey("first") + `", "k2": "` + encodeKey("second") + `"}}`)
+	if err := provider.Reload(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if key, _ := provider.CurrentKey(ctx); key.ID != "k2" {
+		t.Errorf("Expected current key k2 after reload, got %s", key.ID)
+	}
+	if _, err := provider.Key(ctx, "k1"); err != nil {
+		t.Errorf("Expected rotated-out key to remain available, got %v", err)
+	}
+
+	// A broken file leaves the loaded keys in place
+	write(`{"current": "k9", "keys": {}}`)
+	if err := provider.Reload(); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected missing current key error, got %v", err)
+	}
+	if key, _ := provider.CurrentKey(ctx); key.ID != "k2" {
+		t.Errorf("Expected previous keys to be kept, got %s", key.ID)
+	}
+
+	write(`{"current": "a.b", "keys": {"a.b": "` + encodeKey("x") + `"}}`)
+	if err := provider.Reload(); err == nil {
+		t.Error("Expected key IDs containing '.' to be rejected")
+	}
+}
+
+// reverseDecrypter "decrypts" by reversing the ciphertext and counts calls
+type reverseDecrypter struct {
+	calls int
+}
+
+func (d *reverseDecrypter) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	d.calls++
+	plain := make([]byte, len(ciphertext))
+	for i, b := range ciphertext {
+		plain[len(ciphertext)-1-i] = b
+	}
+	return plain, nil
+}
+
+func TestKMSKeyProvider(t *testing.T) {
+	decrypter := &reverseDecrypter{}
+	provider := NewKMSKeyProvider(decrypter, "k1", map[string][]byte{"k1": []byte("terces")})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		key, err := provider.CurrentKey(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !bytes.Equal(key.Secret, []byte("secret")) {
+			t.Errorf("Expected decrypted secret, got %q", key.Secret)
+		}
+	}
+	if decrypter.calls != 1 {
+		t.Errorf("Expected the data key to be decrypted once, got %d calls", decrypter.calls)
+	}
+	if _, err := provider.Key(ctx, "k2"); err != ErrKeyNotFound {
+		t.Errorf("Expected ErrKeyNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,432 @@
+package idforge
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidLayout    = fmt.Errorf("%w: invalid layout", ErrInvalidConfig)
+	ErrLayoutMismatch   = errors.New("ID does not match layout")
+	ErrClockBeforeEpoch = errors.New("clock is before the layout epoch")
+)
+
+// segmentKind identifies what a layout segment encodes
+type segmentKind int
+
+const (
+	segmentLiteral segmentKind = iota
+	segmentRandom
+	segmentTimestamp
+	segmentShard
+	segmentChecksum
+)
+
+// segmentPlaceholders names each kind in LayoutGenerator.String
+var segmentPlaceholders = map[segmentKind]string{
+	segmentRandom:    "R",
+	segmentTimestamp: "T",
+	segmentShard:     "S",
+	segmentChecksum:  "C",
+}
+
+// layoutSegment is one fixed-width part of a layout
+type layoutSegment struct {
+	kind       segmentKind
+	literal    string
+	alphabet   symbols
+	length     int           // Width in characters
+	resolution time.Duration // Timestamp unit
+	epoch      time.Time     // Timestamp origin, zero for the Unix epoch
+	bits       int           // Timestamp counter width, 0 wraps at the character width
+	shards     int
+}
+
+// TimestampFormat tunes how a Layout encodes time to a product's expected
+// lifetime and length budget
+type TimestampFormat struct {
+	Epoch     time.Time     // Origin of the counter, zero for the Unix epoch
+	Precision time.Duration // Unit of the counter, such as time.Millisecond
+	Bits      int           // Counter width in bits, from 1 to 63
+}
+
+// Lifetime returns how long after Epoch the counter wraps around
+func (f TimestampFormat) Lifetime() time.Duration {
+	if f.Bits >= 63 || f.Precision > math.MaxInt64>>f.Bits {
+		return time.Duration(math.MaxInt64)
+	}
+	return f.Precision << f.Bits
+}
+
+// Layout describes IDs made of fixed-width segments, each with its own
+// alphabet, such as a timestamp, a dash, random characters and a check
+// character (TTTT-RRRRRR-C). Segments are added in order with the builder
+// methods; the first invalid argument is reported by Build.
+type Layout struct {
+	segments []layoutSegment
+	err      error
+}
+
+// NewLayout starts an empty layout
+func NewLayout() *Layout {
+	return &Layout{}
+}
+
+// Literal appends fixed text, typically a separator
+func (l *Layout) Literal(text string) *Layout {
+	if text == "" || !utf8.ValidString(text) {
+		return l.fail("literal must be non-empty UTF-8")
+	}
+	return l.add(layoutSegment{kind: segmentLiteral, literal: text, length: alphabetLen(text)})
+}
+
+// Random appends length random characters of alphabet
+func (l *Layout) Random(alphabet string, length int) *Layout {
+	return l.addEncoded(layoutSegment{kind: segmentRandom, length: length}, alphabet)
+}
+
+// Timestamp appends the current time in units of resolution, written in
+// alphabet with the most significant character first. Times that need more
+// than length characters wrap around, so choose the width for the range of
+// dates the IDs must sort and decode correctly over.
+func (l *Layout) Timestamp(alphabet string, length int, resolution time.Duration) *Layout {
+	if resolution <= 0 {
+		return l.fail("timestamp resolution must be positive")
+	}
+	return l.addEncoded(layoutSegment{kind: segmentTimestamp, length: length, resolution: resolution}, alphabet)
+}
+
+// TimestampWith appends the time elapsed since format.Epoch in units of
+// format.Precision, using as many characters of alphabet as format.Bits
+// require. Times before the epoch fail to generate.
+func (l *Layout) TimestampWith(alphabet string, format TimestampFormat) *Layout {
+	if format.Precision <= 0 || format.Bits < 1 || format.Bits > 63 {
+		return l.fail("timestamp format needs a positive precision and 1 to 63 bits")
+	}
+	if !validAlphabet(alphabet) {
+		return l.fail("segment alphabet must contain at least 2 characters")
+	}
+	bitsPerChar := math.Log2(float64(alphabetLen(alphabet)))
+	length := int(math.Ceil(float64(format.Bits) / bitsPerChar))
+	return l.addEncoded(layoutSegment{
+		kind:       segmentTimestamp,
+		length:     length,
+		resolution: format.Precision,
+		epoch:      format.Epoch,
+		bits:       format.Bits,
+	}, alphabet)
+}
+
+// Shard appends a shard number in [0, shards), as wide as alphabet needs
+func (l *Layout) Shard(alphabet string, shards int) *Layout {
+	if shards < 1 || !validAlphabet(alphabet) {
+		return l.fail("shard segment needs at least one shard and a valid alphabet")
+	}
+	n := alphabetLen(alphabet)
+	length := 1
+	for capacity := n; capacity < shards; capacity *= n {
+		length++
+	}
+	return l.addEncoded(layoutSegment{kind: segmentShard, length: length, shards: shards}, alphabet)
+}
+
+// Checksum appends length check characters computed over everything before
+// them, so most mistyped or truncated IDs are rejected by Parse
+func (l *Layout) Checksum(alphabet string, length int) *Layout {
+	return l.addEncoded(layoutSegment{kind: segmentChecksum, length: length}, alphabet)
+}
+
+// Build validates the layout and returns a generator for it
+func (l *Layout) Build(opts ...LayoutOption) (*LayoutGenerator, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	shards := 0
+	encoded := false
+	for _, seg := range l.segments {
+		if seg.kind == segmentShard {
+			if shards > 0 {
+				return nil, fmt.Errorf("%w: at most one shard segment", ErrInvalidLayout)
+			}
+			shards = seg.shards
+		}
+		if seg.kind != segmentLiteral {
+			encoded = true
+		}
+	}
+	if !encoded {
+		return nil, fmt.Errorf("%w: layout has no generated segments", ErrInvalidLayout)
+	}
+
+	g := &LayoutGenerator{
+		segments:     append([]layoutSegment(nil), l.segments...),
+		shards:       shards,
+		now:          time.Now,
+		timestampSeg: -1,
+		randomSeg:    -1,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	// Monotonic IDs increment the first random segment after the timestamp
+	for i, seg := range g.segments {
+		if seg.kind == segmentTimestamp && g.timestampSeg < 0 {
+			g.timestampSeg = i
+		}
+		if seg.kind == segmentRandom && g.timestampSeg >= 0 && g.randomSeg < 0 {
+			g.randomSeg = i
+		}
+	}
+	if g.monotonic && g.randomSeg < 0 {
+		return nil, fmt.Errorf("%w: monotonic layouts need a random segment after a timestamp", ErrInvalidLayout)
+	}
+	return g, nil
+}
+
+// addEncoded appends a segment written in alphabet
+func (l *Layout) addEncoded(seg layoutSegment, alphabet string) *Layout {
+	if !validAlphabet(alphabet) {
+		return l.fail("segment alphabet must contain at least 2 characters")
+	}
+	if seg.length <= 0 {
+		return l.fail("segment length must be positive")
+	}
+	seg.alphabet = newSymbols(alphabet)
+	return l.add(seg)
+}
+
+func (l *Layout) add(seg layoutSegment) *Layout {
+	if l.err == nil {
+		l.segments = append(l.segments, seg)
+	}
+	return l
+}
+
+func (l *Layout) fail(msg string) *Layout {
+	if l.err == nil {
+		l.err = fmt.Errorf("%w: %s", ErrInvalidLayout, msg)
+	}
+	return l
+}
+
+// origin returns the instant a timestamp segment counts from
+func (seg layoutSegment) origin() time.Time {
+	if seg.epoch.IsZero() {
+		return time.Unix(0, 0)
+	}
+	return seg.epoch
+}
+
+// ticks returns the timestamp counter for now, wrapped to the segment's bits
+func (seg layoutSegment) ticks(now time.Time) (uint64, error) {
+	elapsed := now.Sub(seg.origin())
+	if elapsed < 0 {
+		return 0, ErrClockBeforeEpoch
+	}
+	ticks := uint64(elapsed / seg.resolution)
+	if seg.bits > 0 {
+		ticks &= 1<<seg.bits - 1
+	}
+	return ticks, nil
+}
+
+// LayoutGenerator creates and parses IDs following a Layout
+type LayoutGenerator struct {
+	segments []layoutSegment
+	shards   int
+	counter  atomic.Uint64
+	now      func() time.Time
+
+	monotonic    bool
+	overflow     MonotonicOverflow
+	timestampSeg int // Index of the first timestamp segment, -1 without one
+	randomSeg    int // Index of the random segment incremented when monotonic
+
+	mu   sync.Mutex // Guards last
+	last monotonicState
+}
+
+// LayoutID is the decoded content of an ID
+type LayoutID struct {
+	Segments []string  // Text of every segment, literals included
+	Time     time.Time // Timestamp segment, zero without one
+	Shard    int       // Shard segment, 0 without one
+}
+
+// Generate creates an ID, assigning shards round-robin when the layout has
+// a shard segment
+func (g *LayoutGenerator) Generate() (string, error) {
+	shard := 0
+	if g.shards > 0 {
+		shard = int((g.counter.Add(1) - 1) % uint64(g.shards))
+	}
+	return g.GenerateForShard(shard)
+}
+
+// GenerateForShard creates an ID embedding the given shard
+func (g *LayoutGenerator) GenerateForShard(shard int) (string, error) {
+	if shard < 0 || shard >= max(g.shards, 1) {
+		return "", ErrInvalidShard
+	}
+
+	var (
+		ticks  uint64
+		digits []int // Random part of a monotonic ID
+		err    error
+	)
+	if g.timestampSeg >= 0 {
+		if ticks, err = g.segments[g.timestampSeg].ticks(g.now()); err != nil {
+			return "", err
+		}
+	}
+	if g.monotonic {
+		g.mu.Lock()
+		ticks, digits, err = g.nextMonotonic(ticks)
+		g.mu.Unlock()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var id []byte
+	for i, seg := range g.segments {
+		switch seg.kind {
+		case segmentLiteral:
+			id = append(id, seg.literal...)
+		case segmentRandom:
+			if digits != nil && i == g.randomSeg {
+				for _, digit := range digits {
+					id = seg.alphabet.append(id, digit)
+				}
+				continue
+			}
+			random, err := buildID(rand.Reader, seg.alphabet, seg.length, nil, nil)
+			if err != nil {
+				return "", err
+			}
+			id = append(id, random...)
+		case segmentTimestamp:
+			segTicks := ticks
+			if i != g.timestampSeg {
+				if segTicks, err = seg.ticks(g.now()); err != nil {
+					return "", err
+				}
+			}
+			id = appendFixed(id, seg.alphabet, segTicks, seg.length)
+		case segmentShard:
+			id = appendFixed(id, seg.alphabet, uint64(shard), seg.length)
+		case segmentChecksum:
+			id = appendFixed(id, seg.alphabet, uint64(crc32.ChecksumIEEE(id)), seg.length)
+		}
+	}
+	return string(id), nil
+}
+
+// Parse splits id into its segments, decoding the timestamp and shard and
+// verifying the checksum
+func (g *LayoutGenerator) Parse(id string) (LayoutID, error) {
+	parsed := LayoutID{Segments: make([]string, 0, len(g.segments))}
+	rest := id
+	for _, seg := range g.segments {
+		text, tail, ok := splitChars(rest, seg.length)
+		if !ok {
+			return LayoutID{}, ErrLayoutMismatch
+		}
+		prefix := id[:len(id)-len(rest)]
+		rest = tail
+
+		if seg.kind == segmentLiteral {
+			if text != seg.literal {
+				return LayoutID{}, ErrLayoutMismatch
+			}
+			parsed.Segments = append(parsed.Segments, text)
+			continue
+		}
+
+		value, ok := decodeFixed(text, seg.alphabet)
+		if !ok {
+			return LayoutID{}, ErrLayoutMismatch
+		}
+		switch seg.kind {
+		case segmentTimestamp:
+			if seg.bits > 0 && value >= 1<<seg.bits {
+				return LayoutID{}, ErrLayoutMismatch
+			}
+			parsed.Time = seg.origin().Add(time.Duration(value) * seg.resolution)
+		case segmentShard:
+			if value >= uint64(seg.shards) {
+				return LayoutID{}, ErrLayoutMismatch
+			}
+			parsed.Shard = int(value)
+		case segmentChecksum:
+			want := appendFixed(nil, seg.alphabet, uint64(crc32.ChecksumIEEE([]byte(prefix))), seg.length)
+			if text != string(want) {
+				return LayoutID{}, ErrLayoutMismatch
+			}
+		}
+		parsed.Segments = append(parsed.Segments, text)
+	}
+
+	if rest != "" {
+		return LayoutID{}, ErrLayoutMismatch
+	}
+	return parsed, nil
+}
+
+// Validate checks if id follows the layout
+func (g *LayoutGenerator) Validate(id string) bool {
+	_, err := g.Parse(id)
+	return err == nil
+}
+
+// String describes the layout with one placeholder per character, such as
+// TTTT-RRRRRR-C
+func (g *LayoutGenerator) String() string {
+	var b strings.Builder
+	for _, seg := range g.segments {
+		if seg.kind == segmentLiteral {
+			b.WriteString(seg.literal)
+		} else {
+			b.WriteString(strings.Repeat(segmentPlaceholders[seg.kind], seg.length))
+		}
+	}
+	return b.String()
+}
+
+// appendFixed writes the lowest length digits of value in alphabet, most
+// significant first
+func appendFixed(dst []byte, alphabet symbols, value uint64, length int) []byte {
+	n := uint64(alphabet.len())
+	digits := make([]int, length)
+	for i := length - 1; i >= 0; i-- {
+		digits[i] = int(value % n)
+		value /= n
+	}
+	for _, digit := range digits {
+		dst = alphabet.append(dst, digit)
+	}
+	return dst
+}
+
+// decodeFixed reverses appendFixed, reporting false for characters outside
+// the alphabet
+func decodeFixed(text string, alphabet symbols) (uint64, bool) {
+	var value uint64
+	for _, char := range text {
+		digit := alphabet.index(char)
+		if digit < 0 {
+			return 0, false
+		}
+		value = value*uint64(alphabet.len()) + uint64(digit)
+	}
+	return value, true
+}
@@ -0,0 +1,165 @@
+package idforge
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func testLayout(t *testing.T) *LayoutGenerator {
+	t.Helper()
+	gen, err := NewLayout().
+		Timestamp("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ", 6, time.Second).
+		Literal("-").
+		Shard("0123456789", 16).
+		Random(DefaultAlphabet, 8).
+		Literal("-").
+		Checksum("ABCDEFGHJKLMNPQRSTUVWXYZ", 1).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return gen
+}
+
+func TestLayoutGenerateAndParse(t *testing.T) {
+	gen := testLayout(t)
+	now := time.Unix(1700000000, 0)
+	gen.now = func() time.Time { return now }
+
+	id, err := gen.GenerateForShard(11)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := utf8.RuneCountInString(id); n != 6+1+2+8+1+1 {
+		t.Errorf("Expected 19 characters, got %d in %s", n, id)
+	}
+
+	parsed, err := gen.Parse(id)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing %s: %v", id, err)
+	}
+	if !parsed.Time.Equal(now) {
+		t.Errorf("Expected time %v, got %v", now, parsed.Time)
+	}
+	if parsed.Shard != 11 {
+		t.Errorf("Expected shard 11, got %d", parsed.Shard)
+	}
+	if len(parsed.Segments) != 6 || parsed.Segments[2] != "11" {
+		t.Errorf("Unexpected segments %q", parsed.Segments)
+	}
+	if gen.String() != "TTTTTT-SSRRRRRRRR-C" {
+		t.Errorf("Expected TTTTTT-SSRRRRRRRR-C, got %s", gen.String())
+	}
+}
+
+func TestLayoutRejectsTampering(t *testing.T) {
+	gen := testLayout(t)
+	id, _ := gen.Generate()
+
+	// Swap the check character for a different one
+	runes := []rune(id)
+	if runes[len(runes)-1] == 'A' {
+		runes[len(runes)-1] = 'B'
+	} else {
+		runes[len(runes)-1] = 'A'
+	}
+
+	for _, bad := range []string{"", id[:len(id)-1], id + "A", string(runes), "x" + id[1:]} {
+		if gen.Validate(bad) {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+	if !gen.Validate(id) {
+		t.Errorf("Expected %s to validate", id)
+	}
+}
+
+func TestLayoutShardsRoundRobin(t *testing.T) {
+	gen := testLayout(t)
+	for i := 0; i < 20; i++ {
+		id, _ := gen.Generate()
+		parsed, err := gen.Parse(id)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if parsed.Shard != i%16 {
+			t.Errorf("Expected shard %d, got %d", i%16, parsed.Shard)
+		}
+	}
+	if _, err := gen.GenerateForShard(16); err != ErrInvalidShard {
+		t.Errorf("Expected ErrInvalidShard, got %v", err)
+	}
+}
+
+func TestLayoutInvalid(t *testing.T) {
+	layouts := []*Layout{
+		NewLayout(),
+		NewLayout().Literal("-"),
+		NewLayout().Random("a", 4),
+		NewLayout().Random(DefaultAlphabet, 0),
+		NewLayout().Timestamp(DefaultAlphabet, 4, 0),
+		NewLayout().Shard("01", 0),
+		NewLayout().Shard("01", 2).Shard("01", 2),
+	}
+	for i, layout := range layouts {
+		if _, err := layout.Build(); !errors.Is(err, ErrInvalidLayout) || !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("Layout %d: expected ErrInvalidLayout, got %v", i, err)
+		}
+	}
+}
+
+func TestLayoutTimestampFormat(t *testing.T) {
+	format := TimestampFormat{
+		Epoch:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Precision: time.Millisecond,
+		Bits:      41,
+	}
+	gen, err := NewLayout().
+		TimestampWith("0123456789abcdefghijklmnopqrstuv", format).
+		Random(DefaultAlphabet, 6).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// 41 bits need nine base-32 characters
+	if gen.String() != "TTTTTTTTTRRRRRR" {
+		t.Errorf("Expected TTTTTTTTTRRRRRR, got %s", gen.String())
+	}
+
+	now := format.Epoch.Add(400*24*time.Hour + 1234*time.Microsecond)
+	gen.now = func() time.Time { return now }
+	id, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parsed, err := gen.Parse(id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := now.Truncate(time.Millisecond); !parsed.Time.Equal(want) {
+		t.Errorf("Expected time %v, got %v", want, parsed.Time)
+	}
+
+	gen.now = func() time.Time { return format.Epoch.Add(-time.Second) }
+	if _, err := gen.Generate(); err != ErrClockBeforeEpoch {
+		t.Errorf("Expected ErrClockBeforeEpoch, got %v", err)
+	}
+}
+
+func TestTimestampFormatLifetime(t *testing.T) {
+	format := TimestampFormat{Precision: time.Millisecond, Bits: 41}
+	if years := format.Lifetime().Hours() / 24 / 365; years < 69 || years > 70 {
+		t.Errorf("Expected about 69.7 years, got %.1f", years)
+	}
+	if (TimestampFormat{Precision: time.Second, Bits: 63}).Lifetime() <= 0 {
+		t.Error("Expected an overflowing lifetime to saturate")
+	}
+
+	for _, bad := range []TimestampFormat{{Bits: 41}, {Precision: time.Second}, {Precision: time.Second, Bits: 64}} {
+		if _, err := NewLayout().TimestampWith(DefaultAlphabet, bad).Build(); !errors.Is(err, ErrInvalidLayout) {
+			t.Errorf("Format %+v: expected ErrInvalidLayout, got %v", bad, err)
+		}
+	}
+}
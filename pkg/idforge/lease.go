@@ -0,0 +1,70 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrLeaseUnsupported = errors.New("uniqueness store does not support leases")
+	ErrLeaseExpired     = errors.New("ID reservation expired")
+)
+
+// LeaseStore is a UniquenessStore whose reservations can be extended and
+// released, as needed by ExtendedGenerator.Reserve
+type LeaseStore interface {
+	UniquenessStore
+	// Extend changes the reservation of a held id to ttl, 0 meaning no
+	// expiry, and fails with ErrLeaseExpired when it is no longer held
+	Extend(ctx context.Context, id string, ttl time.Duration) error
+	Release(ctx context.Context, id string) error
+}
+
+// ReservedID is an ID held in the uniqueness store until it is committed,
+// released or its lease expires
+type ReservedID struct {
+	ID        string
+	ExpiresAt time.Time
+
+	store LeaseStore
+	ttl   time.Duration // Reservation after commit
+}
+
+// Reserve mints an ID that stays reserved for ttl, for workflows that need
+// an ID before the entity is persisted. The ID must be committed to keep it
+// or released to give it back; a zero ttl reserves it until then. Reserve
+// requires a uniqueness store that implements LeaseStore.
+func (g *ExtendedGenerator) Reserve(ctx context.Context, ttl time.Duration) (ReservedID, error) {
+	cfg := g.Config()
+	store, ok := cfg.UniquenessStore.(LeaseStore)
+	if !ok {
+		return ReservedID{}, ErrLeaseUnsupported
+	}
+
+	id, err := g.Generate(ctx)
+	if err != nil {
+		return ReservedID{}, err
+	}
+
+	// Shorten or lengthen the reservation made during generation
+	if err := store.Extend(ctx, id, ttl); err != nil {
+		return ReservedID{}, err
+	}
+	reserved := ReservedID{ID: id, store: store, ttl: cfg.UniquenessTTL}
+	if ttl > 0 {
+		reserved.ExpiresAt = cfg.now().Add(ttl)
+	}
+	return reserved, nil
+}
+
+// Commit keeps the ID reserved for the generator's UniquenessTTL. It fails
+// with ErrLeaseExpired when the lease ran out first.
+func (r ReservedID) Commit(ctx context.Context) error {
+	return r.store.Extend(ctx, r.ID, r.ttl)
+}
+
+// Release gives the ID back to the store so other instances may issue it
+func (r ReservedID) Release(ctx context.Context) error {
+	return r.store.Release(ctx, r.ID)
+}
@@ -0,0 +1,72 @@
+package idforge
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReserveCommit(t *testing.T) {
+	redis := newFakeRedis()
+	store := NewRedisUniquenessStore(redis, "ids:")
+	gen := NewExtendedGenerator(WithUniquenessStore(store, 24*time.Hour))
+	ctx := context.Background()
+
+	reserved, err := gen.Reserve(ctx, time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ttl := redis.keys["ids:"+reserved.ID]; ttl != time.Minute {
+		t.Errorf("Expected a one minute lease, got %v", ttl)
+	}
+	if time.Until(reserved.ExpiresAt) > time.Minute {
+		t.Errorf("Unexpected lease expiry %v", reserved.ExpiresAt)
+	}
+
+	if err := reserved.Commit(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ttl := redis.keys["ids:"+reserved.ID]; ttl != 24*time.Hour {
+		t.Errorf("Expected commit to apply UniquenessTTL, got %v", ttl)
+	}
+}
+
+func TestReserveRelease(t *testing.T) {
+	store := NewMemoryUniquenessStore()
+	gen := NewExtendedGenerator(WithUniquenessStore(store, time.Hour))
+	ctx := context.Background()
+
+	reserved, err := gen.Reserve(ctx, time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := reserved.Release(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Released IDs can be claimed by another instance
+	if ok, _ := store.Reserve(ctx, reserved.ID, time.Minute); !ok {
+		t.Error("Expected released ID to be available")
+	}
+}
+
+func TestReserveExpired(t *testing.T) {
+	gen := NewExtendedGenerator(WithUniquenessStore(NewMemoryUniquenessStore(), time.Hour))
+	ctx := context.Background()
+
+	reserved, err := gen.Reserve(ctx, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if err := reserved.Commit(ctx); err != ErrLeaseExpired {
+		t.Errorf("Expected ErrLeaseExpired, got %v", err)
+	}
+}
+
+func TestReserveUnsupported(t *testing.T) {
+	if _, err := NewExtendedGenerator().Reserve(context.Background(), time.Minute); err != ErrLeaseUnsupported {
+		t.Errorf("Expected ErrLeaseUnsupported without a store, got %v", err)
+	}
+}
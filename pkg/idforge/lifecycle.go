@@ -0,0 +1,97 @@
+package idforge
+
+import (
+	"context"
+	"time"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/secmem"
+)
+
+// Lifecycle is implemented by components that can run background
+// goroutines. Start launches them, bounded by ctx; Close stops them and
+// waits for them to exit. Both are safe to call more than once.
+type Lifecycle interface {
+	Start(ctx context.Context) error
+	Close() error
+}
+
+var (
+	_ Lifecycle = (*ExtendedGenerator)(nil)
+	_ Lifecycle = (*IDPool)(nil)
+)
+
+// Start reseeds the DRBG in the background every DRBGReseedInterval, so
+// Generate never waits for entropy collection. It does nothing unless
+// WithDRBG is configured. Without Start the DRBG is reseeded lazily.
+func (g *ExtendedGenerator) Start(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.stop != nil || !g.config.DRBG {
+		return nil
+	}
+	if err := g.ensureDRBG(ctx); err != nil {
+		return err
+	}
+
+	g.stop = make(chan struct{})
+	g.wg.Add(1)
+	go g.reseedLoop(ctx, g.stop, g.config.DRBGReseedInterval)
+	return nil
+}
+
+// Close stops background reseeding and wipes the DRBG state. The generator
+// remains usable and seeds a fresh DRBG for the next ID.
+func (g *ExtendedGenerator) Close() error {
+	g.mu.Lock()
+	stop := g.stop
+	g.stop = nil
+	g.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		g.wg.Wait()
+	}
+
+	g.mu.Lock()
+	g.wipeDRBG()
+	g.mu.Unlock()
+	return nil
+}
+
+// reseedLoop collects seed material without holding the generator lock and
+// reseeds the DRBG every interval until stopped
+func (g *ExtendedGenerator) reseedLoop(ctx context.Context, stop <-chan struct{}, interval time.Duration) {
+	defer g.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		g.mu.Lock()
+		cfg, log := g.config, g.mixing
+		g.mu.Unlock()
+
+		seed, err := drbgSeed(ctx, cfg, log)
+		if err != nil {
+			// Keep the current seed; Generate falls back to lazy reseeding
+			continue
+		}
+
+		g.mu.Lock()
+		if g.drbg != nil {
+			g.drbg.Reseed(seed, nil)
+			g.seededAt = g.config.now()
+		}
+		g.mu.Unlock()
+		secmem.Wipe(seed)
+	}
+}
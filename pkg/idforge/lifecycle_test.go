@@ -0,0 +1,81 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExtendedGeneratorBackgroundReseed(t *testing.T) {
+	gen := NewExtendedGenerator(WithDRBG(20 * time.Millisecond))
+	ctx := context.Background()
+
+	if err := gen.Start(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := gen.Start(ctx); err != nil {
+		t.Fatalf("Expected repeated Start to succeed, got %v", err)
+	}
+
+	gen.mu.Lock()
+	seeded := gen.seededAt
+	gen.mu.Unlock()
+
+	time.Sleep(70 * time.Millisecond)
+
+	gen.mu.Lock()
+	reseeded := gen.seededAt
+	gen.mu.Unlock()
+	if !reseeded.After(seeded) {
+		t.Error("Expected the DRBG to be reseeded in the background")
+	}
+
+	if err := gen.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := gen.Generate(ctx); err != nil {
+		t.Errorf("Expected the generator to remain usable after Close, got %v", err)
+	}
+	if err := gen.Close(); err != nil {
+		t.Errorf("Expected repeated Close to succeed, got %v", err)
+	}
+}
+
+func TestExtendedGeneratorStartWithoutDRBG(t *testing.T) {
+	gen := NewExtendedGenerator()
+	if err := gen.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	gen.mu.Lock()
+	running := gen.stop != nil
+	gen.mu.Unlock()
+	if running {
+		t.Error("Expected no background work without a DRBG")
+	}
+	gen.Close()
+}
+
+func TestExtendedGeneratorZeroReseedInterval(t *testing.T) {
+	gen := NewExtendedGenerator(func(cfg *GeneratorConfig) {
+		cfg.DRBG = true
+	})
+	ctx := context.Background()
+
+	if err := gen.Start(ctx); !errors.Is(err, ErrInvalidReseedInterval) {
+		t.Errorf("Expected ErrInvalidReseedInterval from Start, got %v", err)
+	}
+	if _, err := gen.Generate(ctx); !errors.Is(err, ErrInvalidReseedInterval) {
+		t.Errorf("Expected ErrInvalidReseedInterval from Generate, got %v", err)
+	}
+	if err := gen.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	cfg := gen.Config()
+	cfg.DRBG = true
+	if err := NewExtendedGenerator().UpdateConfig(cfg); !errors.Is(err, ErrInvalidReseedInterval) {
+		t.Errorf("Expected UpdateConfig to reject a zero reseed interval, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrUnknownTenant = errors.New("no generator configured for tenant")
+	ErrInvalidTenant = errors.New("tenant ID must not be empty")
+)
+
+// TenantConfig describes how IDs are generated for a single tenant
+type TenantConfig struct {
+	Alphabet string
+	Size     int
+	Prefix   string                   // Prepended to every generated ID
+	Policy   []func(*GeneratorConfig) // Additional generator options applied last
+}
+
+// tenantEntry pairs a tenant's configuration with its lazily built generator
+type tenantEntry struct {
+	once      sync.Once
+	tenantID  string
+	config    TenantConfig
+	generator *ExtendedGenerator
+}
+
+// GeneratorManager holds per-tenant generators keyed by tenant ID
+type GeneratorManager struct {
+	mu      sync.RWMutex
+	tenants map[string]*tenantEntry
+}
+
+// NewGeneratorManager creates an empty tenant generator manager
+func NewGeneratorManager() *GeneratorManager {
+	return &GeneratorManager{
+		tenants: make(map[string]*tenantEntry),
+	}
+}
+
+// Register adds or replaces the configuration for a tenant.
+// The tenant's generator is constructed on first use.
+func (m *GeneratorManager) Register(tenantID string, cfg TenantConfig) error {
+	if tenantID == "" {
+		return ErrInvalidTenant
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tenants[tenantID] = &tenantEntry{tenantID: tenantID, config: cfg}
+	return nil
+}
+
+// Remove drops a tenant and its generator
+func (m *GeneratorManager) Remove(tenantID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.tenants, tenantID)
+}
+
+// Tenants returns the IDs of all registered tenants
+func (m *GeneratorManager) Tenants() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := make([]string, 0, len(m.tenants))
+	for id := range m.tenants {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Generator returns the tenant's generator, constructing it on first lookup
+func (m *GeneratorManager) Generator(tenantID string) (*ExtendedGenerator, error) {
+	entry, err := m.lookup(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return entry.build(), nil
+}
+
+// Generate creates an ID for the given tenant, including its prefix
+func (m *GeneratorManager) Generate(ctx context.Context, tenantID string) (string, error) {
+	entry, err := m.lookup(tenantID)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := entry.build().Generate(ctx)
+	if err != nil {
+		return "", err
+	}
+	return entry.config.Prefix + id, nil
+}
+
+// lookup finds the registered entry for a tenant
+func (m *GeneratorManager) lookup(tenantID string) (*tenantEntry, error) {
+	m.mu.RLock()
+	entry, ok := m.tenants[tenantID]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrUnknownTenant
+	}
+	return entry, nil
+}
+
+// build constructs the tenant's generator exactly once
+func (e *tenantEntry) build() *ExtendedGenerator {
+	e.once.Do(func() {
+		opts := []func(*GeneratorConfig){
+			WithName(e.tenantID),
+			WithCustomAlphabet(e.config.Alphabet),
+			func(cfg *GeneratorConfig) {
+				if e.config.Size > 0 {
+					cfg.Size = e.config.Size
+				}
+			},
+		}
+		opts = append(opts, e.config.Policy...)
+		e.generator = NewExtendedGenerator(opts...)
+	})
+	return e.generator
+}
@@ -0,0 +1,119 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGeneratorManagerGenerate(t *testing.T) {
+	manager := NewGeneratorManager()
+	ctx := context.Background()
+
+	err := manager.Register("acme", TenantConfig{
+		Alphabet: "0123456789",
+		Size:     12,
+		Prefix:   "acme_",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error registering tenant: %v", err)
+	}
+
+	id, err := manager.Generate(ctx, "acme")
+	if err != nil {
+		t.Fatalf("Unexpected error generating ID: %v", err)
+	}
+
+	if !strings.HasPrefix(id, "acme_") {
+		t.Errorf("Expected ID to start with 'acme_', got %s", id)
+	}
+
+	body := strings.TrimPrefix(id, "acme_")
+	if !IsValidID(body, "0123456789", 12) {
+		t.Errorf("Generated ID body does not match tenant configuration: %s", body)
+	}
+}
+
+func TestGeneratorManagerUnknownTenant(t *testing.T) {
+	manager := NewGeneratorManager()
+
+	_, err := manager.Generate(context.Background(), "missing")
+	if !errors.Is(err, ErrUnknownTenant) {
+		t.Errorf("Expected ErrUnknownTenant, got %v", err)
+	}
+
+	if err := manager.Register("", TenantConfig{}); !errors.Is(err, ErrInvalidTenant) {
+		t.Errorf("Expected ErrInvalidTenant, got %v", err)
+	}
+}
+
+func TestGeneratorManagerLazyConstruction(t *testing.T) {
+	manager := NewGeneratorManager()
+	manager.Register("acme", TenantConfig{
+		Policy: []func(*GeneratorConfig){
+			func(cfg *GeneratorConfig) {
+				cfg.MaxUniqueIDs = 5
+			},
+		},
+	})
+
+	// Concurrent lookups must all observe the same generator
+	var wg sync.WaitGroup
+	generators := make([]*ExtendedGenerator, 10)
+	for i := range generators {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			gen, err := manager.Generator("acme")
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			generators[i] = gen
+		}(i)
+	}
+	wg.Wait()
+
+	for i, gen := range generators {
+		if gen != generators[0] {
+			t.Errorf("Lookup %d returned a different generator instance", i)
+		}
+	}
+
+	if generators[0].config.Size != DefaultSize {
+		t.Errorf("Expected default size %d, got %d", DefaultSize, generators[0].config.Size)
+	}
+	if generators[0].config.MaxUniqueIDs != 5 {
+		t.Errorf("Expected policy to set MaxUniqueIDs to 5, got %d", generators[0].config.MaxUniqueIDs)
+	}
+}
+
+func TestGeneratorManagerRemove(t *testing.T) {
+	manager := NewGeneratorManager()
+	manager.Register("acme", TenantConfig{})
+	manager.Register("globex", TenantConfig{})
+
+	if len(manager.Tenants()) != 2 {
+		t.Errorf("Expected 2 tenants, got %d", len(manager.Tenants()))
+	}
+
+	manager.Remove("acme")
+
+	if _, err := manager.Generator("acme"); !errors.Is(err, ErrUnknownTenant) {
+		t.Errorf("Expected ErrUnknownTenant after removal, got %v", err)
+	}
+}
+
+func TestGeneratorManagerNamesGenerators(t *testing.T) {
+	manager := NewGeneratorManager()
+	manager.Register("acme", TenantConfig{})
+
+	gen, err := manager.Generator("acme")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gen.Config().Name != "acme" {
+		t.Errorf("Expected generator to be named after its tenant, got %q", gen.Config().Name)
+	}
+}
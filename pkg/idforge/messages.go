@@ -0,0 +1,54 @@
+package idforge
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Translator renders a violation in the end user's language
+type Translator interface {
+	Translate(v Violation) string
+}
+
+// Messages is a Translator backed by a catalog of fmt templates keyed by
+// rule. Templates receive the violation's Args, so indexed verbs such as
+// %[2]d can reorder them. Rules missing from the catalog keep their English
+// message.
+//
+//	spanish := idforge.Messages{
+//		idforge.RuleLength:   "la longitud es %d, se esperaba %d",
+//		idforge.RuleAlphabet: "%d caracteres fuera del alfabeto, el primero en el byte %d",
+//	}
+type Messages map[string]string
+
+func (m Messages) Translate(v Violation) string {
+	template, ok := m[v.Rule]
+	if !ok {
+		return v.Message
+	}
+	return fmt.Sprintf(template, v.Args...)
+}
+
+// Translate returns the error's violations rendered by t, in order
+func (e *ValidationError) Translate(t Translator) []string {
+	messages := make([]string, len(e.violations))
+	for i, v := range e.violations {
+		messages[i] = t.Translate(v)
+	}
+	return messages
+}
+
+// TranslateError renders err with t when it is a *ValidationError, joining
+// its violations like Error does without the English prefix. Other errors
+// are returned as err.Error() and nil as an empty string.
+func TranslateError(err error, t Translator) string {
+	if err == nil {
+		return ""
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		return err.Error()
+	}
+	return strings.Join(verr.Translate(t), "; ")
+}
@@ -0,0 +1,50 @@
+package idforge
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var spanish = Messages{
+	RuleLength:   "la longitud es %d, se esperaba %d",
+	RuleAlphabet: "%d caracteres fuera del alfabeto, el primero en el byte %d",
+}
+
+func TestMessagesTranslate(t *testing.T) {
+	var verr *ValidationError
+	if !errors.As(CheckID("xyzw", "abc", 3), &verr) {
+		t.Fatal("Expected a *ValidationError")
+	}
+
+	want := []string{
+		"la longitud es 4, se esperaba 3",
+		"4 caracteres fuera del alfabeto, el primero en el byte 0",
+	}
+	if got := verr.Translate(spanish); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestMessagesReorderArgs(t *testing.T) {
+	reordered := Messages{RuleLength: "expected %[2]d, got %[1]d"}
+	if got := TranslateError(CheckID("ab", "abc", 3), reordered); got != "expected 3, got 2" {
+		t.Errorf("Expected reordered arguments, got %q", got)
+	}
+}
+
+func TestMessagesFallback(t *testing.T) {
+	got := TranslateError(CheckID("a\xffb", "abc", 3), spanish)
+	if got != "not valid UTF-8" {
+		t.Errorf("Expected the English message for an untranslated rule, got %q", got)
+	}
+}
+
+func TestTranslateErrorOtherErrors(t *testing.T) {
+	if got := TranslateError(ErrCollision, spanish); got != ErrCollision.Error() {
+		t.Errorf("Expected %q, got %q", ErrCollision.Error(), got)
+	}
+	if got := TranslateError(nil, spanish); got != "" {
+		t.Errorf("Expected an empty string for nil, got %q", got)
+	}
+}
@@ -0,0 +1,106 @@
+//go:build !idforge_lite
+
+package idforge
+
+import "net/http"
+
+// DefaultRequestIDHeader is the header read and written by RequestIDMiddleware
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// maxIncomingRequestIDLength bounds request IDs accepted from clients
+const maxIncomingRequestIDLength = 128
+
+// MiddlewareOption configures RequestIDMiddleware
+type MiddlewareOption func(*middlewareConfig)
+
+type middlewareConfig struct {
+	header        string
+	trustIncoming bool
+	generate      func() (string, error)
+	accept        func(string) bool
+}
+
+// WithRequestIDHeader changes the header used to read and write request IDs
+func WithRequestIDHeader(header string) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		if header != "" {
+			c.header = header
+		}
+	}
+}
+
+// WithTrustIncoming controls whether a request ID supplied by the client is
+// reused. Incoming IDs are trusted by default.
+func WithTrustIncoming(trust bool) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.trustIncoming = trust
+	}
+}
+
+// WithRequestIDSource sets the function that generates new request IDs,
+// such as a Generator's Generate method
+func WithRequestIDSource(generate func() (string, error)) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		if generate != nil {
+			c.generate = generate
+		}
+	}
+}
+
+// WithIncomingValidator decides which incoming request IDs are reused;
+// rejected IDs are replaced with a generated one
+func WithIncomingValidator(accept func(string) bool) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		if accept != nil {
+			c.accept = accept
+		}
+	}
+}
+
+// RequestIDMiddleware assigns each request an ID, stores it in the request
+// context for FromContext and echoes it in the response header. Requests
+// whose ID cannot be generated are answered with 500 Internal Server Error.
+func RequestIDMiddleware(next http.Handler, opts ...MiddlewareOption) http.Handler {
+	cfg := middlewareConfig{
+		header:        DefaultRequestIDHeader,
+		trustIncoming: true,
+		generate:      New().Generate,
+		accept:        isSafeRequestID,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(cfg.header)
+		if !cfg.trustIncoming || !cfg.accept(id) {
+			var err error
+			id, err = cfg.generate()
+			if err != nil {
+				http.Error(w, "failed to generate request ID", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set(cfg.header, id)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+// isSafeRequestID accepts short IDs made of characters that cannot break
+// headers or log lines
+func isSafeRequestID(id string) bool {
+	if id == "" || len(id) > maxIncomingRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+//go:build !idforge_lite
+
+package idforge
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// echoRequestID writes the request ID found in the context as the body
+var echoRequestID = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	id, ok := FromContext(r.Context())
+	if !ok {
+		http.Error(w, "missing request ID", http.StatusBadRequest)
+		return
+	}
+	w.Write([]byte(id))
+})
+
+func serve(handler http.Handler, incoming string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if incoming != "" {
+		req.Header.Set(DefaultRequestIDHeader, incoming)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRequestIDMiddlewareGenerates(t *testing.T) {
+	rec := serve(RequestIDMiddleware(echoRequestID), "")
+
+	id := rec.Header().Get(DefaultRequestIDHeader)
+	if !IsValidID(id, DefaultAlphabet, DefaultSize) {
+		t.Errorf("Expected a generated ID in the response header, got %q", id)
+	}
+	if rec.Body.String() != id {
+		t.Errorf("Expected context ID %q to match header, got %q", id, rec.Body.String())
+	}
+}
+
+func TestRequestIDMiddlewareIncoming(t *testing.T) {
+	rec := serve(RequestIDMiddleware(echoRequestID), "upstream-123")
+	if got := rec.Header().Get(DefaultRequestIDHeader); got != "upstream-123" {
+		t.Errorf("Expected incoming ID to be reused, got %q", got)
+	}
+
+	rec = serve(RequestIDMiddleware(echoRequestID, WithTrustIncoming(false)), "upstream-123")
+	if got := rec.Header().Get(DefaultRequestIDHeader); got == "upstream-123" {
+		t.Error("Expected incoming ID to be replaced when not trusted")
+	}
+
+	for _, unsafe := range []string{"bad id", "line\r\nbreak", strings.Repeat("a", 200)} {
+		rec = serve(RequestIDMiddleware(echoRequestID), unsafe)
+		if got := rec.Header().Get(DefaultRequestIDHeader); got == unsafe {
+			t.Errorf("Expected unsafe incoming ID %q to be replaced", unsafe)
+		}
+	}
+}
+
+func TestRequestIDMiddlewareOptions(t *testing.T) {
+	handler := RequestIDMiddleware(echoRequestID,
+		WithRequestIDHeader("X-Correlation-ID"),
+		WithRequestIDSource(func() (string, error) { return "fixed", nil }),
+	)
+
+	rec := serve(handler, "ignored")
+	if got := rec.Header().Get("X-Correlation-ID"); got != "fixed" {
+		t.Errorf("Expected custom header to carry the sourced ID, got %q", got)
+	}
+
+	failing := RequestIDMiddleware(echoRequestID,
+		WithRequestIDSource(func() (string, error) { return "", errors.New("no entropy") }),
+	)
+	if rec := serve(failing, ""); rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500 when generation fails, got %d", rec.Code)
+	}
+}
+
+func TestRequestIDMiddlewareMissingContext(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, ok := FromContext(req.Context()); ok {
+		t.Error("Expected no request ID outside the middleware")
+	}
+}
@@ -0,0 +1,42 @@
+package idforge
+
+import (
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+// WithMixingLog records the most recent entropy aggregations, up to rounds
+// of them, listing a SHA-256 digest of each provider's output or why it contributed
+// nothing. Read it with MixingLog to confirm every configured source
+// actually contributes; it is meant for debugging and security reviews.
+func WithMixingLog(rounds int) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		if rounds > 0 {
+			c.MixingLogSize = rounds
+		}
+	}
+}
+
+// MixingLog returns the recorded entropy aggregation rounds, oldest first,
+// or nil unless WithMixingLog is configured. Both per-ID collection and DRBG
+// seeding are recorded.
+func (g *ExtendedGenerator) MixingLog() []entropy.MixingRound {
+	g.mu.Lock()
+	log := g.mixing
+	g.mu.Unlock()
+
+	if log == nil {
+		return nil
+	}
+	return log.Rounds()
+}
+
+// resizeMixingLog creates, drops or replaces the mixing log to match the
+// configuration; the caller must hold g.mu or own g exclusively
+func (g *ExtendedGenerator) resizeMixingLog() {
+	switch {
+	case g.config.MixingLogSize <= 0:
+		g.mixing = nil
+	case g.mixing == nil || g.mixing.Cap() != g.config.MixingLogSize:
+		g.mixing = entropy.NewMixingLog(g.config.MixingLogSize)
+	}
+}
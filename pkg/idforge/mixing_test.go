@@ -0,0 +1,75 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+func TestMixingLogRecordsRounds(t *testing.T) {
+	gen := NewExtendedGenerator(
+		WithEntropyProviders([]entropy.EntropyProvider{&entropy.UUIDEntropy{}, &entropy.TimestampEntropy{}}),
+		WithMixingLog(2),
+	)
+	for i := 0; i < 3; i++ {
+		if _, err := gen.Generate(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	rounds := gen.MixingLog()
+	if len(rounds) != 2 {
+		t.Fatalf("Expected the last 2 rounds, got %d", len(rounds))
+	}
+	for _, round := range rounds {
+		if len(round.Contributions) != 2 {
+			t.Fatalf("Expected 2 contributions, got %d", len(round.Contributions))
+		}
+		for _, c := range round.Contributions {
+			if len(c.Digest) != 64 || c.Err != nil || c.Skipped {
+				t.Errorf("Expected a digest for %s, got %+v", c.Provider, c)
+			}
+		}
+	}
+	if rounds[0].Time.After(rounds[1].Time) {
+		t.Error("Expected rounds oldest first")
+	}
+}
+
+func TestMixingLogRecordsFailures(t *testing.T) {
+	gen := NewExtendedGenerator(
+		WithEntropyProviders([]entropy.EntropyProvider{&entropy.UUIDEntropy{}, failingEntropy{}}),
+		WithMixingLog(4),
+	)
+	if _, err := gen.Generate(context.Background()); !errors.Is(err, ErrEntropyUnavailable) {
+		t.Fatalf("Expected ErrEntropyUnavailable, got %v", err)
+	}
+
+	rounds := gen.MixingLog()
+	if len(rounds) != 1 {
+		t.Fatalf("Expected 1 round, got %d", len(rounds))
+	}
+	if c := rounds[0].Contributions[1]; c.Err == nil || c.Digest != "" {
+		t.Errorf("Expected the failing provider without a digest, got %+v", c)
+	}
+}
+
+func TestMixingLogRecordsDRBGSeeding(t *testing.T) {
+	gen := NewExtendedGenerator(WithDRBG(time.Hour), WithMixingLog(4))
+	if rounds := gen.MixingLog(); len(rounds) != 1 {
+		t.Errorf("Expected the initial seeding to be recorded, got %d rounds", len(rounds))
+	}
+}
+
+func TestMixingLogDisabled(t *testing.T) {
+	gen := NewExtendedGenerator()
+	if _, err := gen.Generate(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rounds := gen.MixingLog(); rounds != nil {
+		t.Errorf("Expected no log by default, got %d rounds", len(rounds))
+	}
+}
@@ -0,0 +1,128 @@
+package idforge
+
+import (
+	"crypto/rand"
+	"errors"
+	"time"
+)
+
+var ErrMonotonicOverflow = errors.New("monotonic random part exhausted within one tick")
+
+// LayoutOption configures a LayoutGenerator
+type LayoutOption func(*LayoutGenerator)
+
+// MonotonicOverflow decides what a monotonic LayoutGenerator does when the
+// random part cannot be incremented further within the current tick
+type MonotonicOverflow int
+
+const (
+	// MonotonicFail returns ErrMonotonicOverflow until the clock advances
+	MonotonicFail MonotonicOverflow = iota
+	// MonotonicNextTick borrows the next tick, so IDs may run slightly ahead
+	// of the clock under sustained bursts
+	MonotonicNextTick
+	// MonotonicWait blocks until the clock reaches the next tick
+	MonotonicWait
+)
+
+func (o MonotonicOverflow) String() string {
+	switch o {
+	case MonotonicFail:
+		return "fail"
+	case MonotonicNextTick:
+		return "next-tick"
+	case MonotonicWait:
+		return "wait"
+	default:
+		return "unknown"
+	}
+}
+
+// WithMonotonic makes every ID strictly greater than the previous one, even
+// within the same tick: instead of drawing a fresh random part, the first
+// Random segment after the timestamp is incremented by a small random step,
+// as in ULID's monotonic mode. If the clock moves backwards the last tick is
+// reused. IDs also sort lexicographically when the timestamp and random
+// alphabets are in ascending byte order.
+func WithMonotonic() LayoutOption {
+	return func(g *LayoutGenerator) {
+		g.monotonic = true
+	}
+}
+
+// WithLayoutClock reads timestamps from clock instead of the system clock.
+// With a FakeClock, MonotonicWait blocks until the clock is advanced.
+func WithLayoutClock(clock Clock) LayoutOption {
+	return func(g *LayoutGenerator) {
+		if clock != nil {
+			g.now = clock.Now
+		}
+	}
+}
+
+// WithMonotonicOverflow enables WithMonotonic and selects what happens when
+// the random part overflows within one tick
+func WithMonotonicOverflow(overflow MonotonicOverflow) LayoutOption {
+	return func(g *LayoutGenerator) {
+		g.monotonic = true
+		g.overflow = overflow
+	}
+}
+
+// monotonicState remembers the last tick and random digits issued
+type monotonicState struct {
+	ticks  uint64
+	digits []int
+}
+
+// nextMonotonic returns the tick and random digits for the next ID; the
+// caller must hold g.mu
+func (g *LayoutGenerator) nextMonotonic(ticks uint64) (uint64, []int, error) {
+	ts, random := g.segments[g.timestampSeg], g.segments[g.randomSeg]
+	n := random.alphabet.len()
+
+	if g.last.digits != nil && ticks <= g.last.ticks {
+		var step [1]byte
+		if _, err := rand.Read(step[:]); err != nil {
+			return 0, nil, err
+		}
+		digits := append([]int(nil), g.last.digits...)
+		if increment(digits, n, 1+int(step[0])) {
+			g.last.digits = digits
+			return g.last.ticks, digits, nil
+		}
+
+		switch g.overflow {
+		case MonotonicNextTick:
+			ticks = g.last.ticks + 1
+		case MonotonicWait:
+			for ticks <= g.last.ticks {
+				time.Sleep(ts.resolution)
+				var err error
+				if ticks, err = ts.ticks(g.now()); err != nil {
+					return 0, nil, err
+				}
+			}
+		default:
+			return 0, nil, ErrMonotonicOverflow
+		}
+	}
+
+	digits := make([]int, random.length)
+	if err := sampleIndices(rand.Reader, n, digits); err != nil {
+		return 0, nil, err
+	}
+	g.last = monotonicState{ticks: ticks, digits: digits}
+	return ticks, digits, nil
+}
+
+// increment adds step to the base-n number in digits, most significant
+// first, reporting false when the result does not fit
+func increment(digits []int, n, step int) bool {
+	for i := len(digits) - 1; i >= 0 && step > 0; i-- {
+		sum := digits[i] + step
+		digits[i] = sum % n
+		step = sum / n
+	}
+	return step == 0
+}
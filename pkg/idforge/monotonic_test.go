@@ -0,0 +1,110 @@
+package idforge
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const sortedAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+func TestMonotonicLayoutSameTick(t *testing.T) {
+	gen, err := NewLayout().
+		Timestamp(sortedAlphabet, 10, time.Millisecond).
+		Random(sortedAlphabet, 16).
+		Build(WithMonotonic())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	gen.now = func() time.Time { return now }
+
+	prev := ""
+	for i := 0; i < 1000; i++ {
+		id, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if id <= prev {
+			t.Fatalf("Expected %s to sort after %s", id, prev)
+		}
+		if prev != "" && id[:10] != prev[:10] {
+			t.Fatalf("Expected the timestamp to stay fixed, got %s after %s", id, prev)
+		}
+		prev = id
+	}
+
+	// A clock moving backwards keeps the last tick
+	now = now.Add(-time.Second)
+	if id, _ := gen.Generate(); id <= prev {
+		t.Errorf("Expected %s to sort after %s despite the clock going back", id, prev)
+	}
+}
+
+func TestMonotonicOverflow(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	build := func(overflow MonotonicOverflow) *LayoutGenerator {
+		gen, err := NewLayout().
+			Timestamp(sortedAlphabet, 10, time.Millisecond).
+			Random("01", 2).
+			Build(WithMonotonicOverflow(overflow))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		gen.now = func() time.Time { return now }
+		return gen
+	}
+
+	// Two binary digits overflow on the first increment of at least 1
+	gen := build(MonotonicFail)
+	gen.last = monotonicState{ticks: uint64(now.UnixMilli()), digits: []int{1, 1}}
+	if _, err := gen.Generate(); !errors.Is(err, ErrMonotonicOverflow) {
+		t.Errorf("Expected ErrMonotonicOverflow, got %v", err)
+	}
+
+	gen = build(MonotonicNextTick)
+	gen.last = monotonicState{ticks: uint64(now.UnixMilli()), digits: []int{1, 1}}
+	id, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parsed, _ := gen.Parse(id)
+	if want := now.Add(time.Millisecond); !parsed.Time.Equal(want) {
+		t.Errorf("Expected borrowed tick %v, got %v", want, parsed.Time)
+	}
+
+	gen = build(MonotonicWait)
+	gen.last = monotonicState{ticks: uint64(now.UnixMilli()), digits: []int{1, 1}}
+	calls := 0
+	gen.now = func() time.Time {
+		calls++
+		return now.Add(time.Duration(calls/2) * time.Millisecond)
+	}
+	id, err = gen.Generate()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parsed, _ := gen.Parse(id); !parsed.Time.After(now) {
+		t.Errorf("Expected to wait for a later tick, got %v", parsed.Time)
+	}
+}
+
+func TestMonotonicLayoutNeedsRandomAfterTimestamp(t *testing.T) {
+	_, err := NewLayout().
+		Random(sortedAlphabet, 8).
+		Timestamp(sortedAlphabet, 10, time.Millisecond).
+		Build(WithMonotonic())
+	if !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout, got %v", err)
+	}
+}
+
+func TestIncrement(t *testing.T) {
+	digits := []int{0, 9, 9}
+	if !increment(digits, 10, 1) || digits[0] != 1 || digits[1] != 0 || digits[2] != 0 {
+		t.Errorf("Expected 100, got %v", digits)
+	}
+	if increment([]int{9, 9}, 10, 1) {
+		t.Error("Expected overflow")
+	}
+}
@@ -0,0 +1,99 @@
+package idforge
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+)
+
+const (
+	// JTISize gives JWT IDs 132 bits of randomness over the URL-safe
+	// NanoID alphabet, enough that random collisions can be ignored
+	JTISize = 22
+
+	// DefaultNonceBytes is the nonce size used when none is given
+	DefaultNonceBytes = 32
+
+	// PKCEMethod is the code_challenge_method of challenges made by this
+	// package
+	PKCEMethod = "S256"
+)
+
+// NewJTIGenerator creates a generator for JWT "jti" claims: URL-safe and
+// collision resistant, with the generator's duplicate check on top. opts
+// are applied after the defaults.
+//
+//	jti := idforge.NewJTIGenerator()
+//	claims.ID, err = jti.Generate(ctx)
+func NewJTIGenerator(opts ...func(*GeneratorConfig)) *ExtendedGenerator {
+	defaults := []func(*GeneratorConfig){
+		WithCustomAlphabet(nanoIDAlphabet),
+		func(c *GeneratorConfig) { c.Size = JTISize },
+	}
+	return NewExtendedGenerator(append(defaults, opts...)...)
+}
+
+// NonceGenerator creates unguessable, URL-safe nonces, such as the OpenID
+// Connect nonce or OAuth state parameters
+type NonceGenerator struct {
+	bytes int
+}
+
+// NewNonceGenerator creates nonces of bytes random bytes, written as
+// unpadded base64url; DefaultNonceBytes is used when bytes is not positive
+func NewNonceGenerator(bytes int) *NonceGenerator {
+	if bytes <= 0 {
+		bytes = DefaultNonceBytes
+	}
+	return &NonceGenerator{bytes: bytes}
+}
+
+// Generate returns a fresh nonce
+func (g *NonceGenerator) Generate() (string, error) {
+	b := make([]byte, g.bytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// MustGenerate returns a fresh nonce, panicking on error
+func (g *NonceGenerator) MustGenerate() string {
+	nonce, err := g.Generate()
+	if err != nil {
+		panic(err)
+	}
+	return nonce
+}
+
+// PKCE is a proof key for an OAuth authorization code flow (RFC 7636). The
+// client sends Challenge and Method with the authorization request and
+// Verifier with the token request.
+type PKCE struct {
+	Verifier  string
+	Challenge string
+	Method    string
+}
+
+// NewPKCE creates a verifier from 32 random bytes, 43 characters as
+// recommended by RFC 7636, and its S256 challenge
+func NewPKCE() (PKCE, error) {
+	verifier, err := NewNonceGenerator(32).Generate()
+	if err != nil {
+		return PKCE{}, err
+	}
+	return PKCE{Verifier: verifier, Challenge: PKCEChallenge(verifier), Method: PKCEMethod}, nil
+}
+
+// PKCEChallenge returns the S256 challenge of verifier
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// VerifyPKCE reports whether verifier matches an S256 challenge, for
+// authorization servers checking a token request
+func VerifyPKCE(verifier, challenge string) bool {
+	return subtle.ConstantTimeCompare([]byte(PKCEChallenge(verifier)), []byte(challenge)) == 1
+}
@@ -0,0 +1,73 @@
+package idforge
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewJTIGenerator(t *testing.T) {
+	gen := NewJTIGenerator()
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		jti, err := gen.Generate(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(jti) != JTISize {
+			t.Errorf("Expected %d characters, got %d", JTISize, len(jti))
+		}
+		if err := CheckID(jti, nanoIDAlphabet, JTISize); err != nil {
+			t.Errorf("Expected a URL-safe JTI, got %s: %v", jti, err)
+		}
+		if seen[jti] {
+			t.Fatalf("Duplicate JTI %s", jti)
+		}
+		seen[jti] = true
+	}
+
+	if cfg := NewJTIGenerator(func(c *GeneratorConfig) { c.Size = 30 }).Config(); cfg.Size != 30 {
+		t.Errorf("Expected options to override the defaults, got size %d", cfg.Size)
+	}
+}
+
+func TestNonceGenerator(t *testing.T) {
+	for _, tc := range []struct{ bytes, length int }{{0, 43}, {16, 22}, {32, 43}, {64, 86}} {
+		nonce, err := NewNonceGenerator(tc.bytes).Generate()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(nonce) != tc.length {
+			t.Errorf("Expected %d characters for %d bytes, got %d", tc.length, tc.bytes, len(nonce))
+		}
+		if err := CheckID(nonce, nanoIDAlphabet, tc.length); err != nil {
+			t.Errorf("Expected a URL-safe nonce, got %s: %v", nonce, err)
+		}
+	}
+
+	g := NewNonceGenerator(16)
+	if g.MustGenerate() == g.MustGenerate() {
+		t.Error("Expected distinct nonces")
+	}
+}
+
+func TestPKCE(t *testing.T) {
+	// Example from RFC 7636, Appendix B
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	if challenge := PKCEChallenge(verifier); challenge != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Errorf("Expected the RFC 7636 challenge, got %s", challenge)
+	}
+
+	pkce, err := NewPKCE()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pkce.Verifier) != 43 || pkce.Method != "S256" {
+		t.Errorf("Expected a 43-character S256 verifier, got %+v", pkce)
+	}
+	if !VerifyPKCE(pkce.Verifier, pkce.Challenge) {
+		t.Error("Expected the verifier to match its challenge")
+	}
+	if VerifyPKCE(verifier, pkce.Challenge) {
+		t.Error("Expected another verifier not to match")
+	}
+}
@@ -0,0 +1,132 @@
+package idforge
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+var ErrInvalidObjectID = errors.New("invalid ObjectID")
+
+// ObjectID is a 12-byte MongoDB-compatible identifier: a 4-byte big-endian
+// Unix timestamp in seconds, a 3-byte machine identifier, a 2-byte process
+// identifier and a 3-byte counter
+type ObjectID [12]byte
+
+// ParseObjectID decodes the 24-character hex form of an ObjectID
+func ParseObjectID(s string) (ObjectID, error) {
+	var oid ObjectID
+	if len(s) != 2*len(oid) {
+		return oid, ErrInvalidObjectID
+	}
+	if _, err := hex.Decode(oid[:], []byte(s)); err != nil {
+		return ObjectID{}, ErrInvalidObjectID
+	}
+	return oid, nil
+}
+
+// Hex returns the 24-character lowercase hex form used by MongoDB
+func (oid ObjectID) Hex() string {
+	return hex.EncodeToString(oid[:])
+}
+
+func (oid ObjectID) String() string {
+	return oid.Hex()
+}
+
+// Timestamp returns the creation time, to the second
+func (oid ObjectID) Timestamp() time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint32(oid[0:4])), 0)
+}
+
+// Machine returns the machine identifier
+func (oid ObjectID) Machine() uint32 {
+	return uint32(oid[4])<<16 | uint32(oid[5])<<8 | uint32(oid[6])
+}
+
+// Process returns the process identifier
+func (oid ObjectID) Process() uint16 {
+	return binary.BigEndian.Uint16(oid[7:9])
+}
+
+// Counter returns the per-process counter
+func (oid ObjectID) Counter() uint32 {
+	return uint32(oid[9])<<16 | uint32(oid[10])<<8 | uint32(oid[11])
+}
+
+// ObjectIDGenerator creates ObjectIDs for the current machine and process.
+// The counter starts at a random value and wraps after 2^24 IDs.
+type ObjectIDGenerator struct {
+	machine [3]byte
+	pid     uint16
+	counter atomic.Uint32
+	now     func() time.Time
+}
+
+// ObjectIDOption configures an ObjectIDGenerator
+type ObjectIDOption func(*ObjectIDGenerator)
+
+// WithObjectIDClock reads timestamps from clock instead of the system clock
+func WithObjectIDClock(clock Clock) ObjectIDOption {
+	return func(g *ObjectIDGenerator) {
+		if clock != nil {
+			g.now = clock.Now
+		}
+	}
+}
+
+// NewObjectIDGenerator derives the machine identifier from a hash of the
+// hostname, or from random bytes when the hostname is unavailable
+func NewObjectIDGenerator(opts ...ObjectIDOption) (*ObjectIDGenerator, error) {
+	g := &ObjectIDGenerator{
+		pid: uint16(os.Getpid()),
+		now: time.Now,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if host, err := os.Hostname(); err == nil && host != "" {
+		sum := sha256.Sum256([]byte(host))
+		copy(g.machine[:], sum[:])
+	} else if _, err := rand.Read(g.machine[:]); err != nil {
+		return nil, err
+	}
+
+	var start [4]byte
+	if _, err := rand.Read(start[:]); err != nil {
+		return nil, err
+	}
+	g.counter.Store(binary.BigEndian.Uint32(start[:]))
+	return g, nil
+}
+
+// NewObjectID creates the next ObjectID
+func (g *ObjectIDGenerator) NewObjectID() ObjectID {
+	var oid ObjectID
+	binary.BigEndian.PutUint32(oid[0:4], uint32(g.now().Unix()))
+	copy(oid[4:7], g.machine[:])
+	binary.BigEndian.PutUint16(oid[7:9], g.pid)
+
+	counter := g.counter.Add(1)
+	oid[9] = byte(counter >> 16)
+	oid[10] = byte(counter >> 8)
+	oid[11] = byte(counter)
+	return oid
+}
+
+// Generate creates the next ObjectID in hex form
+func (g *ObjectIDGenerator) Generate() (string, error) {
+	return g.NewObjectID().Hex(), nil
+}
+
+// Validate checks if id is the hex form of an ObjectID
+func (g *ObjectIDGenerator) Validate(id string) bool {
+	_, err := ParseObjectID(id)
+	return err == nil
+}
@@ -0,0 +1,64 @@
+package idforge
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestObjectIDGenerator(t *testing.T) {
+	gen, err := NewObjectIDGenerator()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	gen.now = func() time.Time { return now }
+
+	a, b := gen.NewObjectID(), gen.NewObjectID()
+	if !a.Timestamp().Equal(now) {
+		t.Errorf("Expected timestamp %v, got %v", now, a.Timestamp())
+	}
+	if a.Process() != uint16(os.Getpid()) {
+		t.Errorf("Expected process %d, got %d", uint16(os.Getpid()), a.Process())
+	}
+	if a.Machine() != b.Machine() {
+		t.Error("Expected the same machine identifier")
+	}
+	if b.Counter() != (a.Counter()+1)&0xFFFFFF {
+		t.Errorf("Expected counter %d to follow %d", b.Counter(), a.Counter())
+	}
+	if a.Hex()[:8] != "6553f100" {
+		t.Errorf("Expected timestamp prefix 6553f100, got %s", a.Hex())
+	}
+}
+
+func TestParseObjectID(t *testing.T) {
+	const hexID = "507f1f77bcf86cd799439011"
+	oid, err := ParseObjectID(hexID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if oid.Hex() != hexID {
+		t.Errorf("Expected %s, got %s", hexID, oid.Hex())
+	}
+	if oid.Timestamp().Unix() != 0x507f1f77 {
+		t.Errorf("Unexpected timestamp %v", oid.Timestamp())
+	}
+	if oid.Counter() != 0x439011 {
+		t.Errorf("Expected counter 0x439011, got %#x", oid.Counter())
+	}
+
+	for _, bad := range []string{"", "507f1f77bcf86cd79943901", "507f1f77bcf86cd79943901z"} {
+		if _, err := ParseObjectID(bad); err != ErrInvalidObjectID {
+			t.Errorf("ParseObjectID(%q): expected ErrInvalidObjectID, got %v", bad, err)
+		}
+	}
+}
+
+func TestObjectIDGeneratorValidate(t *testing.T) {
+	gen, _ := NewObjectIDGenerator()
+	id, _ := gen.Generate()
+	if !gen.Validate(id) {
+		t.Errorf("Expected %s to validate", id)
+	}
+}
@@ -0,0 +1,228 @@
+package idforge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// TempIDPrefix marks IDs a client generated offline, before the server
+// assigned final ones
+const TempIDPrefix = "tmp_"
+
+var ErrConflictingMapping = errors.New("temporary ID is already mapped to another final ID")
+
+// TempIDGenerator creates temporary IDs for offline clients. The marker
+// prefix tells them apart from final IDs, so a sync can find every ID that
+// still needs reconciling.
+type TempIDGenerator struct {
+	prefix string
+	gen    *Generator
+}
+
+// NewTempIDGenerator creates a generator of IDs starting with prefix, or
+// TempIDPrefix when prefix is empty; opts configure the random part
+func NewTempIDGenerator(prefix string, opts ...Option) *TempIDGenerator {
+	if prefix == "" {
+		prefix = TempIDPrefix
+	}
+	return &TempIDGenerator{prefix: prefix, gen: New(opts...)}
+}
+
+// Generate creates a temporary ID
+func (g *TempIDGenerator) Generate() (string, error) {
+	id, err := g.gen.Generate()
+	if err != nil {
+		return "", err
+	}
+	return g.prefix + id, nil
+}
+
+// IsTemp reports whether id carries the generator's marker
+func (g *TempIDGenerator) IsTemp(id string) bool {
+	return strings.HasPrefix(id, g.prefix)
+}
+
+// IsTempID reports whether id carries TempIDPrefix
+func IsTempID(id string) bool {
+	return strings.HasPrefix(id, TempIDPrefix)
+}
+
+// ReconciliationMap translates temporary IDs into the final IDs the server
+// assigned, for rewriting a client's records when it syncs. It is safe
+// for concurrent use.
+type ReconciliationMap struct {
+	mu    sync.RWMutex
+	final map[string]string
+}
+
+// NewReconciliationMap creates an empty map
+func NewReconciliationMap() *ReconciliationMap {
+	return &ReconciliationMap{final: make(map[string]string)}
+}
+
+// Add maps tempID to finalID. Adding the same pair again is a no-op;
+// remapping tempID to another final ID fails with ErrConflictingMapping.
+func (m *ReconciliationMap) Add(tempID, finalID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if prev, ok := m.final[tempID]; ok && prev != finalID {
+		return fmt.Errorf("%w: %s is %s, not %s", ErrConflictingMapping, tempID, prev, finalID)
+	}
+	m.final[tempID] = finalID
+	return nil
+}
+
+// Assign gives every temporary ID in tempIDs that is not mapped yet a
+// final ID from generate, such as a generator's Generate method, and
+// returns the mapping for all of them
+func (m *ReconciliationMap) Assign(tempIDs []string, generate func() (string, error)) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assigned := make(map[string]string, len(tempIDs))
+	for _, tempID := range tempIDs {
+		finalID, ok := m.final[tempID]
+		if !ok {
+			var err error
+			if finalID, err = generate(); err != nil {
+				return nil, err
+			}
+			m.final[tempID] = finalID
+		}
+		assigned[tempID] = finalID
+	}
+	return assigned, nil
+}
+
+// Resolve returns the final ID for id, or id itself when it is not a
+// mapped temporary ID
+func (m *ReconciliationMap) Resolve(id string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if finalID, ok := m.final[id]; ok {
+		return finalID
+	}
+	return id
+}
+
+// ResolveAll resolves every ID in ids
+func (m *ReconciliationMap) ResolveAll(ids []string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	resolved := make([]string, len(ids))
+	for i, id := range ids {
+		if finalID, ok := m.final[id]; ok {
+			id = finalID
+		}
+		resolved[i] = id
+	}
+	return resolved
+}
+
+// Len returns the number of mapped temporary IDs
+func (m *ReconciliationMap) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.final)
+}
+
+// PatchOperation is one operation of a JSON Patch (RFC 6902)
+type PatchOperation struct {
+	Op    string `json:"op"`
+	From  string `json:"from,omitempty"`
+	Path  string `json:"path"`
+	Value string `json:"value,omitempty"`
+}
+
+// Patch returns the JSON Patch that rewrites every mapped temporary ID in
+// doc: string values are replaced, and object keys are moved. Only whole
+// strings match, not IDs embedded in longer text. Operations for a value
+// come before the move of any key above it, so the patch applies in order.
+func (m *ReconciliationMap) Patch(doc []byte) ([]PatchOperation, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("reconciliation patch: %w", err)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var ops []PatchOperation
+	m.patch(value, "", &ops)
+	return ops, nil
+}
+
+// patch appends the operations for value at path; the caller must hold
+// m.mu
+func (m *ReconciliationMap) patch(value any, path string, ops *[]PatchOperation) {
+	switch v := value.(type) {
+	case string:
+		if finalID, ok := m.final[v]; ok {
+			*ops = append(*ops, PatchOperation{Op: "replace", Path: path, Value: finalID})
+		}
+	case []any:
+		for i, item := range v {
+			m.patch(item, fmt.Sprintf("%s/%d", path, i), ops)
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			child := path + "/" + escapePointer(key)
+			m.patch(v[key], child, ops)
+			if finalID, ok := m.final[key]; ok {
+				*ops = append(*ops, PatchOperation{Op: "move", From: child, Path: path + "/" + escapePointer(finalID)})
+			}
+		}
+	}
+}
+
+// Rewrite returns a copy of a decoded JSON document, as produced by
+// json.Unmarshal into an any, with every mapped temporary ID replaced in
+// string values and object keys
+func (m *ReconciliationMap) Rewrite(doc any) any {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.rewrite(doc)
+}
+
+// rewrite copies value; the caller must hold m.mu
+func (m *ReconciliationMap) rewrite(value any) any {
+	switch v := value.(type) {
+	case string:
+		if finalID, ok := m.final[v]; ok {
+			return finalID
+		}
+		return v
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = m.rewrite(item)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			if finalID, ok := m.final[key]; ok {
+				key = finalID
+			}
+			out[key] = m.rewrite(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// escapePointer escapes a key for use in a JSON Pointer (RFC 6901)
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
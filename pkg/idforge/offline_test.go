@@ -0,0 +1,129 @@
+package idforge
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTempIDGenerator(t *testing.T) {
+	gen := NewTempIDGenerator("", WithSize(12))
+	id, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(id, TempIDPrefix) || len(id) != len(TempIDPrefix)+12 {
+		t.Errorf("Expected tmp_ and 12 characters, got %s", id)
+	}
+	if !gen.IsTemp(id) || !IsTempID(id) || IsTempID(id[len(TempIDPrefix):]) {
+		t.Errorf("Expected only the marked ID to be temporary")
+	}
+
+	local := NewTempIDGenerator("local-")
+	id, _ = local.Generate()
+	if !local.IsTemp(id) || IsTempID(id) {
+		t.Errorf("Expected the custom marker, got %s", id)
+	}
+}
+
+func TestReconciliationMap(t *testing.T) {
+	m := NewReconciliationMap()
+	if err := m.Add("tmp_a", "ord_1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := m.Add("tmp_a", "ord_1"); err != nil {
+		t.Errorf("Expected adding the same pair again to succeed, got %v", err)
+	}
+	if err := m.Add("tmp_a", "ord_2"); !errors.Is(err, ErrConflictingMapping) {
+		t.Errorf("Expected ErrConflictingMapping, got %v", err)
+	}
+
+	n := 0
+	assigned, err := m.Assign([]string{"tmp_a", "tmp_b", "tmp_c"}, func() (string, error) {
+		n++
+		return "ord_" + strings.Repeat("x", n), nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := map[string]string{"tmp_a": "ord_1", "tmp_b": "ord_x", "tmp_c": "ord_xx"}
+	if !reflect.DeepEqual(assigned, want) {
+		t.Errorf("Expected %v, got %v", want, assigned)
+	}
+	if m.Len() != 3 {
+		t.Errorf("Expected 3 mappings, got %d", m.Len())
+	}
+
+	if got := m.ResolveAll([]string{"tmp_b", "ord_9", "tmp_z"}); !reflect.DeepEqual(got, []string{"ord_x", "ord_9", "tmp_z"}) {
+		t.Errorf("Expected only mapped IDs to change, got %v", got)
+	}
+	if m.Resolve("tmp_c") != "ord_xx" {
+		t.Errorf("Expected ord_xx, got %s", m.Resolve("tmp_c"))
+	}
+
+	failing := errors.New("generator down")
+	if _, err := m.Assign([]string{"tmp_d"}, func() (string, error) { return "", failing }); !errors.Is(err, failing) {
+		t.Errorf("Expected the generator error, got %v", err)
+	}
+}
+
+func TestReconciliationMapPatch(t *testing.T) {
+	m := NewReconciliationMap()
+	m.Add("tmp_order", "ord_1")
+	m.Add("tmp_item/1", "itm_1")
+	m.Add("tmp_note", "note_1")
+
+	doc := []byte(`{
+		"id": "tmp_order",
+		"items": [{"id": "tmp_item/1", "qty": 2}, {"id": "itm_0"}],
+		"notes": {"tmp_note": {"order": "tmp_order", "text": "see tmp_order"}},
+		"total": 12.50
+	}`)
+	ops, err := m.Patch(doc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []PatchOperation{
+		{Op: "replace", Path: "/id", Value: "ord_1"},
+		{Op: "replace", Path: "/items/0/id", Value: "itm_1"},
+		{Op: "replace", Path: "/notes/tmp_note/order", Value: "ord_1"},
+		{Op: "move", From: "/notes/tmp_note", Path: "/notes/note_1"},
+	}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("Expected %+v, got %+v", want, ops)
+	}
+
+	data, _ := json.Marshal(ops[:1])
+	if string(data) != `[{"op":"replace","path":"/id","value":"ord_1"}]` {
+		t.Errorf("Expected RFC 6902 JSON, got %s", data)
+	}
+
+	ops, _ = m.Patch([]byte(`{"tmp_item/1": 1}`))
+	if len(ops) != 1 || ops[0].From != "/tmp_item~11" || ops[0].Path != "/itm_1" {
+		t.Errorf("Expected an escaped pointer, got %+v", ops)
+	}
+	if _, err := m.Patch([]byte(`{`)); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
+
+func TestReconciliationMapRewrite(t *testing.T) {
+	m := NewReconciliationMap()
+	m.Add("tmp_order", "ord_1")
+	m.Add("tmp_note", "note_1")
+
+	var doc any
+	json.Unmarshal([]byte(`{"id": "tmp_order", "refs": ["tmp_order", 3, null], "notes": {"tmp_note": true}}`), &doc)
+	got := m.Rewrite(doc)
+
+	var want any
+	json.Unmarshal([]byte(`{"id": "ord_1", "refs": ["ord_1", 3, null], "notes": {"note_1": true}}`), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if doc.(map[string]any)["id"] != "tmp_order" {
+		t.Error("Expected the original document to stay unchanged")
+	}
+}
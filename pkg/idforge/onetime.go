@@ -0,0 +1,284 @@
+package idforge
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultOneTimeTokenTTL is how long one-time tokens stay valid when no
+	// ttl is given
+	DefaultOneTimeTokenTTL = time.Hour
+
+	oneTimeLookupBytes = 12 // Store key, not secret
+	oneTimeSecretBytes = 32 // Only its salted hash is stored
+	oneTimeSaltBytes   = 16
+)
+
+// TokenStatus is the outcome of verifying a one-time token
+type TokenStatus int
+
+const (
+	TokenValid    TokenStatus = iota // Accepted; Verify has consumed it
+	TokenInvalid                     // Unknown, malformed, tampered or for another purpose
+	TokenExpired                     // Issued but past its expiry
+	TokenConsumed                    // Already used
+)
+
+func (s TokenStatus) String() string {
+	switch s {
+	case TokenValid:
+		return "valid"
+	case TokenInvalid:
+		return "invalid"
+	case TokenExpired:
+		return "expired"
+	case TokenConsumed:
+		return "consumed"
+	default:
+		return "unknown"
+	}
+}
+
+// TokenResult describes a verified one-time token. Subject and the times
+// are set for every status but TokenInvalid.
+type TokenResult struct {
+	Status     TokenStatus
+	Subject    string // Who the token was issued for, such as a user ID
+	IssuedAt   time.Time
+	ExpiresAt  time.Time
+	ConsumedAt time.Time // Zero unless consumed
+}
+
+// TokenRecord is what a OneTimeTokenStore keeps per token: a salted hash
+// instead of the token, so a leaked store cannot be used to log in
+type TokenRecord struct {
+	Salt       []byte
+	Hash       []byte
+	Subject    string
+	IssuedAt   time.Time
+	ExpiresAt  time.Time
+	ConsumedAt time.Time
+}
+
+// OneTimeTokenStore persists one-time token records under a lookup key
+type OneTimeTokenStore interface {
+	Save(ctx context.Context, lookup string, record TokenRecord) error
+	// Get returns the record for lookup, reporting false when there is none
+	Get(ctx context.Context, lookup string) (TokenRecord, bool, error)
+	// Consume atomically sets ConsumedAt to at unless the record was
+	// already consumed, reporting whether it did
+	Consume(ctx context.Context, lookup string, at time.Time) (bool, error)
+}
+
+// OneTimeTokenService runs the whole lifecycle of single-use tokens for
+// password resets, magic links or email confirmation: it issues URL-safe
+// tokens, stores only salted hashes, and accepts each token once before
+// it expires.
+type OneTimeTokenService struct {
+	purpose string
+	store   OneTimeTokenStore
+	ttl     time.Duration
+	clock   Clock
+}
+
+// OneTimeTokenOption configures a OneTimeTokenService
+type OneTimeTokenOption func(*OneTimeTokenService)
+
+// WithOneTimeTokenClock reads issue, expiry and consumption times from
+// clock instead of the system clock. The default in-memory store uses the
+// same clock.
+func WithOneTimeTokenClock(clock Clock) OneTimeTokenOption {
+	return func(s *OneTimeTokenService) {
+		if clock != nil {
+			s.clock = clock
+		}
+	}
+}
+
+// NewOneTimeTokenService creates a service issuing tokens for purpose, such
+// as "password-reset", that expire after ttl, or DefaultOneTimeTokenTTL
+// when it is not positive. The purpose is part of each hash, so services
+// sharing a store never accept each other's tokens. A nil store selects an
+// in-memory store.
+func NewOneTimeTokenService(purpose string, store OneTimeTokenStore, ttl time.Duration, opts ...OneTimeTokenOption) *OneTimeTokenService {
+	if ttl <= 0 {
+		ttl = DefaultOneTimeTokenTTL
+	}
+	s := &OneTimeTokenService{purpose: purpose, store: store, ttl: ttl, clock: SystemClock}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.store == nil {
+		s.store = NewMemoryOneTimeTokenStore(WithMemoryClock(s.clock))
+	}
+	return s
+}
+
+// Issue creates a token for subject, to be sent to its owner, e.g. in a
+// link. The token itself is not stored and cannot be recovered.
+func (s *OneTimeTokenService) Issue(ctx context.Context, subject string) (string, error) {
+	random := make([]byte, oneTimeLookupBytes+oneTimeSecretBytes+oneTimeSaltBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	lookup := base64.RawURLEncoding.EncodeToString(random[:oneTimeLookupBytes])
+	secret := random[oneTimeLookupBytes : oneTimeLookupBytes+oneTimeSecretBytes]
+	salt := append([]byte(nil), random[oneTimeLookupBytes+oneTimeSecretBytes:]...) // Not sharing memory with secret
+
+	now := s.clock.Now()
+	record := TokenRecord{
+		Salt:      salt,
+		Hash:      s.hash(salt, secret),
+		Subject:   subject,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+	if err := s.store.Save(ctx, lookup, record); err != nil {
+		return "", err
+	}
+	return lookup + "." + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// Verify checks token and, when it is valid, consumes it so it is never
+// accepted again. Concurrent verifications of one token see exactly one
+// TokenValid. The error is only set when the store fails.
+func (s *OneTimeTokenService) Verify(ctx context.Context, token string) (TokenResult, error) {
+	result, lookup, err := s.check(ctx, token)
+	if err != nil || result.Status != TokenValid {
+		return result, err
+	}
+
+	now := s.clock.Now()
+	consumed, err := s.store.Consume(ctx, lookup, now)
+	if err != nil {
+		return TokenResult{Status: TokenInvalid}, err
+	}
+	if !consumed {
+		// Consumed by a concurrent call since the check
+		result.Status = TokenConsumed
+		if record, ok, err := s.store.Get(ctx, lookup); err == nil && ok {
+			result.ConsumedAt = record.ConsumedAt
+		}
+		return result, nil
+	}
+	result.ConsumedAt = now
+	return result, nil
+}
+
+// Peek checks token like Verify without consuming it, e.g. to decide
+// whether to show a password reset form before it is submitted
+func (s *OneTimeTokenService) Peek(ctx context.Context, token string) (TokenResult, error) {
+	result, _, err := s.check(ctx, token)
+	return result, err
+}
+
+// check looks token up and classifies it
+func (s *OneTimeTokenService) check(ctx context.Context, token string) (TokenResult, string, error) {
+	invalid := TokenResult{Status: TokenInvalid}
+	lookup, encoded, ok := strings.Cut(token, ".")
+	if !ok {
+		return invalid, "", nil
+	}
+	secret, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(secret) != oneTimeSecretBytes {
+		return invalid, "", nil
+	}
+
+	record, ok, err := s.store.Get(ctx, lookup)
+	if err != nil || !ok {
+		return invalid, "", err
+	}
+	if subtle.ConstantTimeCompare(s.hash(record.Salt, secret), record.Hash) != 1 {
+		return invalid, "", nil
+	}
+
+	result := TokenResult{
+		Status:     TokenValid,
+		Subject:    record.Subject,
+		IssuedAt:   record.IssuedAt,
+		ExpiresAt:  record.ExpiresAt,
+		ConsumedAt: record.ConsumedAt,
+	}
+	switch {
+	case !record.ConsumedAt.IsZero():
+		result.Status = TokenConsumed
+	case !s.clock.Now().Before(record.ExpiresAt):
+		result.Status = TokenExpired
+	}
+	return result, lookup, nil
+}
+
+// hash computes the stored hash of a token secret
+func (s *OneTimeTokenService) hash(salt, secret []byte) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(s.purpose))
+	h.Write([]byte{0})
+	h.Write(secret)
+	return h.Sum(nil)
+}
+
+// MemoryOneTimeTokenStore keeps token records in process memory, for a
+// single instance or tests. Records are dropped a while after they expire.
+type MemoryOneTimeTokenStore struct {
+	mu        sync.Mutex
+	records   map[string]TokenRecord
+	nextSweep time.Time
+	clock     Clock
+}
+
+// NewMemoryOneTimeTokenStore creates an empty in-memory store
+func NewMemoryOneTimeTokenStore(opts ...MemoryOption) *MemoryOneTimeTokenStore {
+	return &MemoryOneTimeTokenStore{records: make(map[string]TokenRecord), clock: memoryClock(opts)}
+}
+
+func (s *MemoryOneTimeTokenStore) Save(ctx context.Context, lookup string, record TokenRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(s.clock.Now())
+	s.records[lookup] = record
+	return nil
+}
+
+func (s *MemoryOneTimeTokenStore) Get(ctx context.Context, lookup string) (TokenRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[lookup]
+	return record, ok, nil
+}
+
+func (s *MemoryOneTimeTokenStore) Consume(ctx context.Context, lookup string, at time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[lookup]
+	if !ok || !record.ConsumedAt.IsZero() {
+		return false, nil
+	}
+	record.ConsumedAt = at
+	s.records[lookup] = record
+	return true, nil
+}
+
+// sweep drops records expired for over a day, at most once a minute, so
+// late visitors still learn their token expired; the caller must hold s.mu
+func (s *MemoryOneTimeTokenStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	for lookup, record := range s.records {
+		if now.Sub(record.ExpiresAt) > 24*time.Hour {
+			delete(s.records, lookup)
+		}
+	}
+	s.nextSweep = now.Add(time.Minute)
+}
@@ -0,0 +1,127 @@
+package idforge
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOneTimeTokenLifecycle(t *testing.T) {
+	store := NewMemoryOneTimeTokenStore()
+	svc := NewOneTimeTokenService("password-reset", store, time.Hour)
+	ctx := context.Background()
+
+	token, err := svc.Issue(ctx, "user-42")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Trim(token, nanoIDAlphabet+".") != "" {
+		t.Errorf("Expected a URL-safe token, got %s", token)
+	}
+	lookup, secret, _ := strings.Cut(token, ".")
+	record, ok := store.records[lookup]
+	if !ok || len(record.Hash) != 32 || strings.Contains(string(record.Hash), secret) {
+		t.Errorf("Expected the store to hold a hash under the lookup key, got %+v", record)
+	}
+
+	peeked, err := svc.Peek(ctx, token)
+	if err != nil || peeked.Status != TokenValid || peeked.Subject != "user-42" {
+		t.Fatalf("Expected a valid peek for user-42, got %+v, %v", peeked, err)
+	}
+
+	result, err := svc.Verify(ctx, token)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Status != TokenValid || result.Subject != "user-42" || result.ConsumedAt.IsZero() {
+		t.Errorf("Expected a valid, consumed token for user-42, got %+v", result)
+	}
+
+	result, _ = svc.Verify(ctx, token)
+	if result.Status != TokenConsumed {
+		t.Errorf("Expected a second use to be refused as consumed, got %s", result.Status)
+	}
+}
+
+func TestOneTimeTokenInvalid(t *testing.T) {
+	store := NewMemoryOneTimeTokenStore()
+	reset := NewOneTimeTokenService("password-reset", store, time.Hour)
+	magic := NewOneTimeTokenService("magic-link", store, time.Hour)
+	ctx := context.Background()
+
+	token, _ := reset.Issue(ctx, "user-42")
+	lookup, _, _ := strings.Cut(token, ".")
+	other, _ := reset.Issue(ctx, "user-43")
+	_, otherSecret, _ := strings.Cut(other, ".")
+
+	for name, candidate := range map[string]string{
+		"other purpose": token,
+		"empty":         "",
+		"no separator":  "abc",
+		"bad encoding":  lookup + ".!!!",
+		"short secret":  lookup + ".abcd",
+		"wrong secret":  lookup + "." + otherSecret,
+		"unknown":       "nope." + otherSecret,
+	} {
+		svc := reset
+		if name == "other purpose" {
+			svc = magic
+		}
+		result, err := svc.Verify(ctx, candidate)
+		if err != nil || result.Status != TokenInvalid || result.Subject != "" {
+			t.Errorf("Expected %s to be invalid, got %+v, %v", name, result, err)
+		}
+	}
+
+	// None of the failed attempts used the token up
+	if result, _ := reset.Verify(ctx, token); result.Status != TokenValid {
+		t.Errorf("Expected the token to still be valid, got %s", result.Status)
+	}
+}
+
+func TestOneTimeTokenExpired(t *testing.T) {
+	svc := NewOneTimeTokenService("magic-link", nil, 10*time.Millisecond)
+	ctx := context.Background()
+	token, _ := svc.Issue(ctx, "user-42")
+
+	time.Sleep(20 * time.Millisecond)
+	result, err := svc.Verify(ctx, token)
+	if err != nil || result.Status != TokenExpired || result.Subject != "user-42" {
+		t.Errorf("Expected an expired token for user-42, got %+v, %v", result, err)
+	}
+}
+
+func TestOneTimeTokenConcurrentVerify(t *testing.T) {
+	svc := NewOneTimeTokenService("magic-link", nil, time.Hour)
+	ctx := context.Background()
+	token, _ := svc.Issue(ctx, "user-42")
+
+	var valid atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if result, _ := svc.Verify(ctx, token); result.Status == TokenValid {
+				valid.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if valid.Load() != 1 {
+		t.Errorf("Expected exactly one valid verification, got %d", valid.Load())
+	}
+}
+
+func TestTokenStatusString(t *testing.T) {
+	for status, expected := range map[TokenStatus]string{
+		TokenValid: "valid", TokenInvalid: "invalid", TokenExpired: "expired", TokenConsumed: "consumed", TokenStatus(9): "unknown",
+	} {
+		if got := status.String(); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
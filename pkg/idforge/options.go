@@ -1,6 +1,10 @@
 package idforge
 
-import "github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+import (
+	"time"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
 
 // Option defines a function type for configuring the generator
 type Option func(*Generator)
@@ -8,7 +12,7 @@ type Option func(*Generator)
 // WithAlphabet allows customizing the character set for ID generation
 func WithAlphabet(alphabet string) Option {
 	return func(g *Generator) {
-		if len(alphabet) >= 2 {
+		if validAlphabet(alphabet) {
 			g.alphabet = alphabet
 		}
 	}
@@ -17,7 +21,7 @@ func WithAlphabet(alphabet string) Option {
 // WithCustomAlphabet sets a custom character set for ID generation
 func WithCustomAlphabet(alphabet string) func(*GeneratorConfig) {
 	return func(c *GeneratorConfig) {
-		if len(alphabet) >= 2 {
+		if validAlphabet(alphabet) {
 			c.Alphabet = alphabet
 		}
 	}
@@ -32,6 +36,52 @@ func WithEntropyProviders(providers []entropy.EntropyProvider) func(*GeneratorCo
 	}
 }
 
+// WithPrivacySafeEntropy uses only entropy providers that reveal nothing
+// about the host, such as hardware addresses or memory statistics
+func WithPrivacySafeEntropy() func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.Entropy = entropy.PrivacySafeEntropyProviders()
+	}
+}
+
+// WithCollisionHook reports candidates that collide with already issued IDs,
+// so a too-small alphabet or size shows up long before generation fails.
+// The hook runs while the generator is locked and must not call back into it.
+func WithCollisionHook(hook func(id string, attempt int)) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.OnCollision = hook
+	}
+}
+
+// WithEntropyConcurrency limits how many entropy providers are queried at once
+func WithEntropyConcurrency(n int) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		if n > 0 {
+			c.EntropyConcurrency = n
+		}
+	}
+}
+
+// WithDRBG seeds an HMAC-DRBG from the entropy providers and draws IDs from
+// it, so providers are only queried when reseeding every reseedInterval
+func WithDRBG(reseedInterval time.Duration) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		if reseedInterval <= 0 {
+			reseedInterval = 10 * time.Minute
+		}
+		c.DRBG = true
+		c.DRBGReseedInterval = reseedInterval
+	}
+}
+
+// WithClock reads the time from clock instead of the system clock, for
+// GeneratedAt, the retention window, provider breakers and DRBG reseeding
+func WithClock(clock Clock) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.Clock = clock
+	}
+}
+
 // WithSize sets the length of generated IDs
 func WithSize(size int) Option {
 	return func(g *Generator) {
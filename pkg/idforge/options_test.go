@@ -2,6 +2,8 @@ package idforge
 
 import (
 	"testing"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
 )
 
 func TestWithAlphabet(t *testing.T) {
@@ -83,3 +85,17 @@ func TestWithMaxSizeInvalid(t *testing.T) {
 		t.Errorf("Expected size to be 20, got %d", gen.size)
 	}
 }
+
+func TestWithPrivacySafeEntropy(t *testing.T) {
+	cfg := GeneratorConfig{Entropy: entropy.DefaultEntropyProviders()}
+
+	WithPrivacySafeEntropy()(&cfg)
+
+	for _, provider := range cfg.Entropy {
+		switch provider.(type) {
+		case *entropy.TimestampEntropy, *entropy.UUIDEntropy, *entropy.RandomBytesEntropy:
+		default:
+			t.Errorf("Unexpected host-dependent provider %T", provider)
+		}
+	}
+}
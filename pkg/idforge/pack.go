@@ -0,0 +1,254 @@
+package idforge
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math/bits"
+	"unicode/utf8"
+)
+
+// packVersion is the first byte of data written by PackIDs
+const packVersion = 1
+
+// PackIDs stores ids drawn from alphabet in a compact binary form, for
+// archiving or transferring large batches. Each ID is read as a number in
+// base len(alphabet) and written with only as many bits as that needs, so
+// 21-character IDs over DefaultAlphabet take 126 bits instead of 168, and
+// hex IDs half their text size. IDs may differ in length, at the cost of a
+// length prefix each.
+//
+// The data records a checksum of the alphabet rather than the alphabet
+// itself; UnpackIDs must be given the same one. PackIDs fails with
+// ErrInvalidEncoding when an ID has a character outside the alphabet.
+func PackIDs(ids []string, alphabet string) ([]byte, error) {
+	if !validAlphabet(alphabet) {
+		return nil, ErrInvalidAlphabet
+	}
+	codec := newPackCodec(alphabet)
+
+	size := -1
+	for _, id := range ids {
+		n := utf8.RuneCountInString(id)
+		if size == -1 {
+			size = n
+		} else if n != size {
+			size = 0 // Variable length
+			break
+		}
+	}
+
+	out := []byte{packVersion}
+	out = binary.BigEndian.AppendUint32(out, crc32.ChecksumIEEE([]byte(alphabet)))
+	out = binary.AppendUvarint(out, uint64(len(ids)))
+	out = binary.AppendUvarint(out, uint64(max(size, 0)))
+	if size == 0 {
+		for _, id := range ids {
+			out = binary.AppendUvarint(out, uint64(utf8.RuneCountInString(id)))
+		}
+	}
+
+	w := bitWriter{out: out}
+	for _, id := range ids {
+		if err := codec.write(&w, id); err != nil {
+			return nil, err
+		}
+	}
+	return w.flush(), nil
+}
+
+// UnpackIDs reverses PackIDs. It fails with ErrInvalidEncoding when data is
+// truncated, corrupt or was packed with a different alphabet.
+func UnpackIDs(data []byte, alphabet string) ([]string, error) {
+	if !validAlphabet(alphabet) {
+		return nil, ErrInvalidAlphabet
+	}
+	if len(data) < 5 || data[0] != packVersion {
+		return nil, fmt.Errorf("%w: not packed IDs", ErrInvalidEncoding)
+	}
+	if binary.BigEndian.Uint32(data[1:]) != crc32.ChecksumIEEE([]byte(alphabet)) {
+		return nil, fmt.Errorf("%w: IDs were packed with a different alphabet", ErrInvalidEncoding)
+	}
+	data = data[5:]
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: truncated header", ErrInvalidEncoding)
+	}
+	data = data[n:]
+	size, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: truncated header", ErrInvalidEncoding)
+	}
+	data = data[n:]
+
+	// Bound count before allocating for it: every ID takes a byte of
+	// length prefix, or at least one bit when they share a length
+	limit := uint64(len(data))
+	if size > 0 {
+		limit *= 8
+	}
+	if count > limit {
+		return nil, fmt.Errorf("%w: truncated data", ErrInvalidEncoding)
+	}
+	lengths := make([]int, count)
+	for i := range lengths {
+		length := size
+		if size == 0 {
+			if length, n = binary.Uvarint(data); n <= 0 {
+				return nil, fmt.Errorf("%w: truncated lengths", ErrInvalidEncoding)
+			}
+			data = data[n:]
+		}
+		if length > uint64(len(data))*8 {
+			return nil, fmt.Errorf("%w: truncated data", ErrInvalidEncoding)
+		}
+		lengths[i] = int(length)
+	}
+
+	codec := newPackCodec(alphabet)
+	r := bitReader{in: data}
+	ids := make([]string, count)
+	for i, length := range lengths {
+		id, err := codec.read(&r, length)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	if len(r.in) > 0 {
+		return nil, fmt.Errorf("%w: trailing data", ErrInvalidEncoding)
+	}
+	return ids, nil
+}
+
+// packCodec converts IDs to and from chunks of base-N digits, each chunk
+// as large as fits in 64 bits
+type packCodec struct {
+	alphabet symbols
+	base     uint64
+	chunk    int      // Digits per full chunk
+	pow      []uint64 // pow[k] is base^k for k <= chunk
+	width    []int    // width[k] is the bits needed for k digits
+}
+
+func newPackCodec(alphabet string) packCodec {
+	c := packCodec{alphabet: newSymbols(alphabet)}
+	c.base = uint64(c.alphabet.len())
+	c.pow = []uint64{1}
+	c.width = []int{0}
+	for {
+		hi, lo := bits.Mul64(c.pow[c.chunk], c.base)
+		if hi != 0 {
+			break
+		}
+		c.chunk++
+		c.pow = append(c.pow, lo)
+		c.width = append(c.width, bits.Len64(lo-1))
+	}
+	return c
+}
+
+// write appends the digits of id to w
+func (c packCodec) write(w *bitWriter, id string) error {
+	var value uint64
+	digits := 0
+	for _, char := range id {
+		digit := c.alphabet.index(char)
+		if digit < 0 {
+			return fmt.Errorf("%w: %q is outside the alphabet", ErrInvalidEncoding, char)
+		}
+		value = value*c.base + uint64(digit)
+		if digits++; digits == c.chunk {
+			w.write(value, c.width[digits])
+			value, digits = 0, 0
+		}
+	}
+	if digits > 0 {
+		w.write(value, c.width[digits])
+	}
+	return nil
+}
+
+// read takes an ID of length characters from r
+func (c packCodec) read(r *bitReader, length int) (string, error) {
+	id := make([]byte, 0, length*c.alphabet.maxBytes())
+	digits := make([]int, c.chunk)
+	for length > 0 {
+		k := min(length, c.chunk)
+		value, ok := r.read(c.width[k])
+		if !ok {
+			return "", fmt.Errorf("%w: truncated data", ErrInvalidEncoding)
+		}
+		if value >= c.pow[k] {
+			return "", fmt.Errorf("%w: corrupt data", ErrInvalidEncoding)
+		}
+		for i := k - 1; i >= 0; i-- {
+			digits[i] = int(value % c.base)
+			value /= c.base
+		}
+		for _, digit := range digits[:k] {
+			id = c.alphabet.append(id, digit)
+		}
+		length -= k
+	}
+	return string(id), nil
+}
+
+// bitWriter appends values of up to 64 bits, most significant bit first
+type bitWriter struct {
+	out  []byte
+	acc  uint64
+	bits int // Pending bits in acc, always fewer than 8 between writes
+}
+
+func (w *bitWriter) write(value uint64, width int) {
+	if width > 32 {
+		w.write(value>>32, width-32)
+		value, width = value&(1<<32-1), 32
+	}
+	w.acc = w.acc<<width | value
+	w.bits += width
+	for w.bits >= 8 {
+		w.bits -= 8
+		w.out = append(w.out, byte(w.acc>>w.bits))
+	}
+}
+
+// flush pads the last byte with zero bits and returns the output
+func (w *bitWriter) flush() []byte {
+	if w.bits > 0 {
+		w.out = append(w.out, byte(w.acc<<(8-w.bits)))
+		w.bits = 0
+	}
+	return w.out
+}
+
+// bitReader reads values written by bitWriter. in holds the bytes not yet
+// started, so it is empty once the padded last byte is reached.
+type bitReader struct {
+	in   []byte
+	acc  uint64
+	bits int
+}
+
+func (r *bitReader) read(width int) (uint64, bool) {
+	if width > 32 {
+		hi, ok := r.read(width - 32)
+		if !ok {
+			return 0, false
+		}
+		lo, ok := r.read(32)
+		return hi<<32 | lo, ok
+	}
+	for r.bits < width {
+		if len(r.in) == 0 {
+			return 0, false
+		}
+		r.acc = r.acc<<8 | uint64(r.in[0])
+		r.in = r.in[1:]
+		r.bits += 8
+	}
+	r.bits -= width
+	return r.acc >> r.bits & (1<<width - 1), true
+}
@@ -0,0 +1,115 @@
+package idforge
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPackIDsRoundTrip(t *testing.T) {
+	gen := New()
+	ids := make([]string, 1000)
+	for i := range ids {
+		ids[i] = gen.MustGenerate()
+	}
+
+	packed, err := PackIDs(ids, DefaultAlphabet)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// 126 bits per ID plus a small header
+	if expected := 1000*126/8 + 16; len(packed) > expected {
+		t.Errorf("Expected at most %d bytes, got %d", expected, len(packed))
+	}
+
+	unpacked, err := UnpackIDs(packed, DefaultAlphabet)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(unpacked, ids) {
+		t.Error("Expected unpacked IDs to match the originals")
+	}
+}
+
+func TestPackIDsHex(t *testing.T) {
+	ids := []string{
+		strings.Repeat("f", 32),
+		strings.Repeat("0", 32),
+		"0123456789abcdef0123456789abcdef",
+	}
+	packed, err := PackIDs(ids, "0123456789abcdef")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if header := len(packed) - 3*16; header != 7 {
+		t.Errorf("Expected 16 bytes per hex ID after a 7-byte header, got %d header bytes", header)
+	}
+	unpacked, err := UnpackIDs(packed, "0123456789abcdef")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(unpacked, ids) {
+		t.Errorf("Expected %v, got %v", ids, unpacked)
+	}
+}
+
+func TestPackIDsVariableLength(t *testing.T) {
+	for _, ids := range [][]string{
+		{"a", "", "bcd", "ééé", strings.Repeat("z", 40)},
+		{"", ""},
+		{},
+	} {
+		alphabet := "abcdéz"
+		packed, err := PackIDs(ids, alphabet)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		unpacked, err := UnpackIDs(packed, alphabet)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(unpacked) != len(ids) || (len(ids) > 0 && !reflect.DeepEqual(unpacked, ids)) {
+			t.Errorf("Expected %q, got %q", ids, unpacked)
+		}
+	}
+}
+
+func TestPackIDsErrors(t *testing.T) {
+	if _, err := PackIDs([]string{"abc"}, "ab"); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("Expected ErrInvalidEncoding for a character outside the alphabet, got %v", err)
+	}
+	if _, err := PackIDs([]string{"a"}, "a"); !errors.Is(err, ErrInvalidAlphabet) {
+		t.Errorf("Expected ErrInvalidAlphabet, got %v", err)
+	}
+
+	packed, err := PackIDs([]string{"abc", "cab"}, "abc")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := UnpackIDs(packed, "abd"); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("Expected ErrInvalidEncoding for another alphabet, got %v", err)
+	}
+	for i := range packed {
+		if _, err := UnpackIDs(packed[:i], "abc"); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("Expected ErrInvalidEncoding for %d truncated bytes, got %v", i, err)
+		}
+	}
+	if _, err := UnpackIDs(append(packed, 0), "abc"); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("Expected ErrInvalidEncoding for trailing data, got %v", err)
+	}
+}
+
+func BenchmarkPackIDs(b *testing.B) {
+	gen := New()
+	ids := make([]string, 10000)
+	for i := range ids {
+		ids[i] = gen.MustGenerate()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := PackIDs(ids, DefaultAlphabet); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package idforge
+
+import (
+	"errors"
+	"hash/fnv"
+	"strings"
+	"unicode/utf8"
+)
+
+// partitionHashSize is the number of characters encoding the partition key
+const partitionHashSize = 6
+
+var ErrInvalidPartitionedID = errors.New("invalid partitioned ID")
+
+// PartitionedGenerator creates IDs of the form <prefix>-<keyHash>-<random>.
+// The key hash depends only on the caller-supplied partition key, so all IDs
+// for the same key map to the same partition.
+type PartitionedGenerator struct {
+	prefix string
+	gen    *Generator
+}
+
+// NewPartitionedGenerator creates a partition-affine generator. The prefix
+// may be empty; opts configure the alphabet and size of the random part,
+// and the key hash uses the same alphabet.
+func NewPartitionedGenerator(prefix string, opts ...Option) *PartitionedGenerator {
+	return &PartitionedGenerator{
+		prefix: prefix,
+		gen:    New(opts...),
+	}
+}
+
+// Generate creates an ID carrying the hash of key
+func (p *PartitionedGenerator) Generate(key string) (string, error) {
+	random, err := p.gen.Generate()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if p.prefix != "" {
+		b.WriteString(p.prefix)
+		b.WriteByte('-')
+	}
+	b.WriteString(p.KeyHash(key))
+	b.WriteByte('-')
+	b.WriteString(random)
+	return b.String(), nil
+}
+
+// KeyHash returns the segment embedded in IDs generated for key
+func (p *PartitionedGenerator) KeyHash(key string) string {
+	return keyHash(key, p.gen.alphabet)
+}
+
+// keyHash encodes the FNV-1a hash of key in partitionHashSize characters of
+// alphabet
+func keyHash(key, alphabet string) string {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	sum := h.Sum64()
+
+	symbols := newSymbols(alphabet)
+	n := uint64(symbols.len())
+	hash := make([]byte, 0, partitionHashSize*symbols.maxBytes())
+	for i := 0; i < partitionHashSize; i++ {
+		hash = symbols.append(hash, int(sum%n))
+		sum /= n
+	}
+	return string(hash)
+}
+
+// PartitionFor returns the partition, in [0, partitions), for a generated
+// ID. Kafka's default partitioner hashes the whole message key, so producers
+// should pass this value explicitly or use a manual partitioner.
+func (p *PartitionedGenerator) PartitionFor(id string, partitions int) (int, error) {
+	hash, err := p.keyHashOf(id)
+	if err != nil {
+		return 0, err
+	}
+	return partitionOf(hash, partitions), nil
+}
+
+// PartitionForKey returns the partition that IDs generated for key map to
+func (p *PartitionedGenerator) PartitionForKey(key string, partitions int) int {
+	return partitionOf(p.KeyHash(key), partitions)
+}
+
+// Validate checks if id was produced by this generator
+func (p *PartitionedGenerator) Validate(id string) bool {
+	hash, err := p.keyHashOf(id)
+	if err != nil || p.gen.charset.check(hash, partitionHashSize) != nil {
+		return false
+	}
+	return p.gen.Validate(id[len(p.head())+len(hash)+1:])
+}
+
+// head returns the prefix and separator that start every ID
+func (p *PartitionedGenerator) head() string {
+	if p.prefix == "" {
+		return ""
+	}
+	return p.prefix + "-"
+}
+
+// keyHashOf locates the key hash segment by character position, since the
+// random part may itself contain '-'
+func (p *PartitionedGenerator) keyHashOf(id string) (string, error) {
+	rest, ok := strings.CutPrefix(id, p.head())
+	if !ok {
+		return "", ErrInvalidPartitionedID
+	}
+	hash, rest, ok := splitChars(rest, partitionHashSize)
+	if !ok || !strings.HasPrefix(rest, "-") || utf8.RuneCountInString(rest) != 1+p.gen.size {
+		return "", ErrInvalidPartitionedID
+	}
+	return hash, nil
+}
+
+// partitionOf maps a key hash segment onto one of partitions
+func partitionOf(hash string, partitions int) int {
+	if partitions <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(hash))
+	return int(h.Sum32() % uint32(partitions))
+}
@@ -0,0 +1,106 @@
+package idforge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPartitionedGenerator(t *testing.T) {
+	gen := NewPartitionedGenerator("ord", WithSize(12))
+
+	a, err := gen.Generate("tenant-a")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	b, _ := gen.Generate("tenant-a")
+
+	if !strings.HasPrefix(a, "ord-"+gen.KeyHash("tenant-a")+"-") {
+		t.Errorf("Expected ord-<hash>- prefix, got %s", a)
+	}
+	if len(a) != len("ord-")+partitionHashSize+1+12 {
+		t.Errorf("Unexpected ID length %d for %s", len(a), a)
+	}
+	if a == b {
+		t.Error("Expected distinct IDs for the same key")
+	}
+	if !gen.Validate(a) {
+		t.Errorf("Expected %s to validate", a)
+	}
+}
+
+func TestPartitionForIsStable(t *testing.T) {
+	gen := NewPartitionedGenerator("ord")
+	const partitions = 12
+
+	want := gen.PartitionForKey("tenant-a", partitions)
+	for i := 0; i < 20; i++ {
+		id, _ := gen.Generate("tenant-a")
+		got, err := gen.PartitionFor(id, partitions)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got != want {
+			t.Errorf("Expected partition %d for %s, got %d", want, id, got)
+		}
+	}
+
+	// Keys should spread across partitions
+	seen := make(map[int]bool)
+	for i := 0; i < 100; i++ {
+		seen[gen.PartitionForKey(string(rune('a'+i%26))+strings.Repeat("x", i), partitions)] = true
+	}
+	if len(seen) < partitions/2 {
+		t.Errorf("Expected keys to spread over partitions, only hit %d", len(seen))
+	}
+}
+
+func TestPartitionedGeneratorDashAlphabet(t *testing.T) {
+	gen := NewPartitionedGenerator("", WithAlphabet("ab-"), WithSize(10))
+
+	id, err := gen.Generate("key")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := gen.PartitionFor(id, 4); err != nil {
+		t.Errorf("Expected IDs containing '-' to parse, got %v", err)
+	}
+	if !gen.Validate(id) {
+		t.Errorf("Expected %s to validate", id)
+	}
+}
+
+func TestPartitionForInvalid(t *testing.T) {
+	gen := NewPartitionedGenerator("ord")
+
+	for _, id := range []string{"", "ord-abc", "inv-" + strings.Repeat("a", partitionHashSize+1+DefaultSize)} {
+		if _, err := gen.PartitionFor(id, 8); err != ErrInvalidPartitionedID {
+			t.Errorf("Expected ErrInvalidPartitionedID for %q, got %v", id, err)
+		}
+		if gen.Validate(id) {
+			t.Errorf("Expected %q to be invalid", id)
+		}
+	}
+}
+
+func TestPartitionedGeneratorRuneAlphabet(t *testing.T) {
+	gen := NewPartitionedGenerator("ord", WithAlphabet(cyrillicAlphabet), WithSize(8))
+
+	id, err := gen.Generate("tenant-a")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(id, "ord-"+gen.KeyHash("tenant-a")+"-") {
+		t.Errorf("Expected ord-<hash>- prefix, got %s", id)
+	}
+	if !gen.Validate(id) {
+		t.Errorf("Expected %s to validate", id)
+	}
+
+	got, err := gen.PartitionFor(id, 8)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := gen.PartitionForKey("tenant-a", 8); got != want {
+		t.Errorf("Expected partition %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,176 @@
+package idforge
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Pattern returns an anchored regular expression matching exactly the IDs
+// the generator can produce. It uses RE2 syntax, which Go, JavaScript,
+// OpenAPI validators and most databases accept.
+func (g *Generator) Pattern() string {
+	return "^" + charClass(newSymbols(g.alphabet), alphabetLen(g.alphabet)) + repeat(g.size) + "$"
+}
+
+// Pattern returns an anchored regular expression matching the IDs the
+// generator produces with its current configuration, including the format
+// version marker. With CollisionGrowSize IDs may be longer than Size.
+func (g *ExtendedGenerator) Pattern() string {
+	cfg := g.Config()
+
+	var b strings.Builder
+	b.WriteByte('^')
+	if cfg.FormatVersion != 0 {
+		b.WriteString(quoteMeta(string(cfg.FormatVersion)))
+	}
+	b.WriteString(charClass(newSymbols(cfg.Alphabet), alphabetLen(cfg.Alphabet)))
+	if cfg.CollisionStrategy == CollisionGrowSize {
+		fmt.Fprintf(&b, "{%d,}", cfg.Size)
+	} else {
+		b.WriteString(repeat(cfg.Size))
+	}
+	b.WriteByte('$')
+	return b.String()
+}
+
+// Pattern returns an anchored regular expression matching the IDs the
+// layout produces. Timestamp and shard segments only match values the
+// generator can encode; checksum segments match any value in range, so
+// Validate is still needed to verify them.
+func (g *LayoutGenerator) Pattern() string {
+	var b strings.Builder
+	b.WriteByte('^')
+	for _, seg := range g.segments {
+		switch seg.kind {
+		case segmentLiteral:
+			b.WriteString(quoteMeta(seg.literal))
+		case segmentRandom:
+			b.WriteString(charClass(seg.alphabet, seg.alphabet.len()) + repeat(seg.length))
+		case segmentTimestamp:
+			limit := uint64(math.MaxUint64)
+			if seg.bits > 0 && seg.bits < 64 {
+				limit = 1 << seg.bits
+			}
+			b.WriteString(rangePattern(seg.alphabet, seg.length, limit))
+		case segmentShard:
+			b.WriteString(rangePattern(seg.alphabet, seg.length, uint64(seg.shards)))
+		case segmentChecksum:
+			b.WriteString(rangePattern(seg.alphabet, seg.length, 1<<32))
+		}
+	}
+	b.WriteByte('$')
+	return b.String()
+}
+
+// repeat returns the quantifier for exactly n characters
+func repeat(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "{" + strconv.Itoa(n) + "}"
+}
+
+// charClass returns a bracket expression matching the first n characters
+// of alphabet, with runs of consecutive characters written as ranges
+func charClass(alphabet symbols, n int) string {
+	chars := make([]rune, n)
+	for i := range chars {
+		chars[i] = alphabet.at(i)
+	}
+	slices.Sort(chars)
+	chars = slices.Compact(chars)
+	if len(chars) == 1 {
+		return classChar(chars[0])
+	}
+
+	var b strings.Builder
+	b.WriteByte('[')
+	for i := 0; i < len(chars); {
+		j := i
+		for j+1 < len(chars) && chars[j+1] == chars[j]+1 {
+			j++
+		}
+		b.WriteString(classChar(chars[i]))
+		if j-i >= 2 {
+			b.WriteByte('-')
+			b.WriteString(classChar(chars[j]))
+			i = j + 1
+		} else {
+			i++
+		}
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// classChar escapes char for use inside or outside a bracket expression
+func classChar(char rune) string {
+	switch {
+	case char == '-' || strings.ContainsRune(metaChars, char):
+		return `\` + string(char)
+	case !unicode.IsPrint(char):
+		return fmt.Sprintf(`\x{%x}`, char)
+	default:
+		return string(char)
+	}
+}
+
+// metaChars are the characters with a meaning in RE2 syntax, escaped by
+// hand as in regexp.QuoteMeta so lite builds do not link regexp
+const metaChars = `\.+*?()|[]{}^$`
+
+// quoteMeta escapes the metacharacters in s
+func quoteMeta(s string) string {
+	var b strings.Builder
+	for _, char := range s {
+		if strings.ContainsRune(metaChars, char) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(char)
+	}
+	return b.String()
+}
+
+// rangePattern matches the length-character encodings, as written by
+// appendFixed, of the values below limit
+func rangePattern(alphabet symbols, length int, limit uint64) string {
+	n := uint64(alphabet.len())
+	all := charClass(alphabet, alphabet.len())
+
+	// Every encoding is reachable unless limit is below n^length
+	capacity, i := uint64(1), 0
+	for ; i < length && capacity <= limit/n; i++ {
+		capacity *= n
+	}
+	if i == length {
+		return all + repeat(length)
+	}
+
+	// Digits of the largest value, most significant first
+	hi := make([]int, length)
+	for i, v := length-1, limit-1; i >= 0; i-- {
+		hi[i] = int(v % n)
+		v /= n
+	}
+
+	// Values sharing the first i digits with hi and smaller in digit i,
+	// followed by anything, and finally hi itself
+	var alts []string
+	prefix := ""
+	for i, digit := range hi {
+		if digit > 0 {
+			alt := prefix + charClass(alphabet, digit)
+			if rest := length - i - 1; rest > 0 {
+				alt += all + repeat(rest)
+			}
+			alts = append(alts, alt)
+		}
+		prefix += classChar(alphabet.at(digit))
+	}
+	alts = append(alts, prefix)
+	return "(?:" + strings.Join(alts, "|") + ")"
+}
@@ -0,0 +1,120 @@
+package idforge
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGeneratorPattern(t *testing.T) {
+	gen := New()
+	if p := gen.Pattern(); p != "^[0-9A-Za-z]{21}$" {
+		t.Errorf("Expected ^[0-9A-Za-z]{21}$, got %s", p)
+	}
+
+	re := regexp.MustCompile(gen.Pattern())
+	for i := 0; i < 100; i++ {
+		if id := gen.MustGenerate(); !re.MatchString(id) {
+			t.Fatalf("Expected %s to match %s", id, re)
+		}
+	}
+	for _, id := range []string{"", "abc", strings.Repeat("a", 22), strings.Repeat("-", 21)} {
+		if re.MatchString(id) {
+			t.Errorf("Expected %q not to match %s", id, re)
+		}
+	}
+}
+
+func TestPatternEscapesAlphabet(t *testing.T) {
+	alphabet := `-]^\.[日`
+	gen := New(WithAlphabet(alphabet), WithSize(8))
+	re, err := regexp.Compile(gen.Pattern())
+	if err != nil {
+		t.Fatalf("Pattern %s does not compile: %v", gen.Pattern(), err)
+	}
+	for i := 0; i < 100; i++ {
+		if id := gen.MustGenerate(); !re.MatchString(id) {
+			t.Fatalf("Expected %s to match %s", id, re)
+		}
+	}
+	if re.MatchString("aaaaaaaa") {
+		t.Errorf("Expected characters outside the alphabet not to match %s", re)
+	}
+}
+
+func TestQuoteMeta(t *testing.T) {
+	for _, s := range []string{`a.b+c*d?e(f)g|h[i]j{k}l^m$n\\o`, "plain-日", ""} {
+		if got, want := quoteMeta(s), regexp.QuoteMeta(s); got != want {
+			t.Errorf("Expected %s, got %s", want, got)
+		}
+	}
+}
+
+func TestExtendedGeneratorPattern(t *testing.T) {
+	gen := NewExtendedGenerator(WithFormatVersion('v'), WithCustomAlphabet("abc"))
+	if p := gen.Pattern(); p != "^v[a-c]{21}$" {
+		t.Errorf("Expected ^v[a-c]{21}$, got %s", p)
+	}
+	id, err := gen.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !regexp.MustCompile(gen.Pattern()).MatchString(id) {
+		t.Errorf("Expected %s to match %s", id, gen.Pattern())
+	}
+
+	grow := NewExtendedGenerator(WithCollisionStrategy(CollisionGrowSize))
+	if p := grow.Pattern(); !strings.HasSuffix(p, "{21,}$") {
+		t.Errorf("Expected an open-ended length with CollisionGrowSize, got %s", p)
+	}
+}
+
+func TestLayoutPattern(t *testing.T) {
+	gen, err := NewLayout().
+		Literal("ord.").
+		TimestampWith(sortedAlphabet, TimestampFormat{Precision: time.Second, Bits: 33}).
+		Literal("-").
+		Shard("0123456789", 37).
+		Random(DefaultAlphabet, 8).
+		Checksum(sortedAlphabet, 2).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	re := regexp.MustCompile(gen.Pattern())
+	for i := 0; i < 100; i++ {
+		id, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !re.MatchString(id) {
+			t.Fatalf("Expected %s to match %s", id, re)
+		}
+	}
+	if id, _ := gen.GenerateForShard(36); !re.MatchString(id) {
+		t.Errorf("Expected the last shard %s to match %s", id, re)
+	}
+	if re.MatchString("ordX" + strings.Repeat("0", 7) + "-00abcdefgh00") {
+		t.Errorf("Expected an escaped literal not to match any character")
+	}
+}
+
+func TestRangePatternExact(t *testing.T) {
+	alphabet := newSymbols("xyz")
+	capacity := uint64(1)
+	for length := 1; length <= 3; length++ {
+		capacity *= 3
+		for limit := uint64(1); limit <= capacity+1; limit++ {
+			re := regexp.MustCompile("^" + rangePattern(alphabet, length, limit) + "$")
+			for value := uint64(0); value < capacity; value++ {
+				encoded := string(appendFixed(nil, alphabet, value, length))
+				if got, want := re.MatchString(encoded), value < limit; got != want {
+					t.Errorf("length %d, limit %d: %s matched %t, expected %t (pattern %s)", length, limit, encoded, got, want, re)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package idforge
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// podNamespaceFile holds the pod's namespace in every pod that mounts a
+// service account token
+const podNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+var (
+	ErrNotInKubernetes = errors.New("not running in a Kubernetes pod")
+	ErrNodeIDRange     = errors.New("pod ordinal exceeds the node ID range")
+)
+
+// PodIdentity is the downward-API metadata of a Kubernetes pod
+type PodIdentity struct {
+	Namespace string
+	Name      string
+	Ordinal   int // StatefulSet ordinal, -1 for pods outside a StatefulSet
+}
+
+// ParsePodIdentity builds the identity of pod name in namespace, without
+// an ordinal. Names are not searched for one, as Deployment pod names can
+// also end in digits; use ParseStatefulSetPodIdentity for StatefulSet pods.
+func ParsePodIdentity(namespace, name string) PodIdentity {
+	return PodIdentity{Namespace: namespace, Name: name, Ordinal: -1}
+}
+
+// ParseStatefulSetPodIdentity is ParsePodIdentity for a pod the caller
+// knows belongs to a StatefulSet, taking the ordinal from the trailing -N
+// its name ends in. The ordinal stays -1 if the name has none.
+func ParseStatefulSetPodIdentity(namespace, name string) PodIdentity {
+	identity := ParsePodIdentity(namespace, name)
+	if i := strings.LastIndexByte(name, '-'); i >= 0 {
+		if ordinal, err := strconv.Atoi(name[i+1:]); err == nil && ordinal >= 0 && name[i+1] != '+' {
+			identity.Ordinal = ordinal
+		}
+	}
+	return identity
+}
+
+// DetectPodIdentity reads the identity of the pod the process runs in.
+// The namespace comes from POD_NAMESPACE or the service account mount, the
+// name from POD_NAME or the hostname, which Kubernetes sets to the pod
+// name, and the ordinal from POD_INDEX, fed from the
+// apps.kubernetes.io/pod-index label. Without POD_INDEX the pod gets no
+// ordinal; on clusters without the label, StatefulSet pods can pass their
+// name to ParseStatefulSetPodIdentity instead. Expose the variables
+// through the downward API:
+//
+//	env:
+//	- name: POD_NAMESPACE
+//	  valueFrom: {fieldRef: {fieldPath: metadata.namespace}}
+//	- name: POD_NAME
+//	  valueFrom: {fieldRef: {fieldPath: metadata.name}}
+//
+// Outside Kubernetes it returns ErrNotInKubernetes.
+func DetectPodIdentity() (PodIdentity, error) {
+	return detectPodIdentity(os.Getenv, os.ReadFile)
+}
+
+func detectPodIdentity(getenv func(string) string, readFile func(string) ([]byte, error)) (PodIdentity, error) {
+	if getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return PodIdentity{}, ErrNotInKubernetes
+	}
+
+	namespace := getenv("POD_NAMESPACE")
+	if namespace == "" {
+		data, err := readFile(podNamespaceFile)
+		if err != nil {
+			return PodIdentity{}, fmt.Errorf("%w: namespace unknown: %w", ErrNotInKubernetes, err)
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+	name := getenv("POD_NAME")
+	if name == "" {
+		name = getenv("HOSTNAME")
+	}
+	if namespace == "" || name == "" {
+		return PodIdentity{}, fmt.Errorf("%w: pod name unknown", ErrNotInKubernetes)
+	}
+
+	identity := ParsePodIdentity(namespace, name)
+	if index := getenv("POD_INDEX"); index != "" {
+		ordinal, err := strconv.Atoi(index)
+		if err != nil || ordinal < 0 {
+			return PodIdentity{}, fmt.Errorf("%w: POD_INDEX %q is not an ordinal", ErrInvalidConfig, index)
+		}
+		identity.Ordinal = ordinal
+	}
+	return identity, nil
+}
+
+// NodeID returns a worker ID in [0, 2^bits) for snowflake-style
+// generators. StatefulSet pods use their ordinal, so each replica keeps
+// its ID across restarts and no two replicas share one; an ordinal out of
+// range fails with ErrNodeIDRange. Other pods get a hash of their
+// namespace and name, which is stable but may collide.
+func (p PodIdentity) NodeID(bits int) (int64, error) {
+	if p.Ordinal < 0 {
+		return hashedNodeID(p.Namespace+"/"+p.Name, bits)
+	}
+	if err := checkNodeIDBits(bits); err != nil {
+		return 0, err
+	}
+	if int64(p.Ordinal) >= int64(1)<<bits {
+		return 0, fmt.Errorf("%w: ordinal %d needs more than %d bits", ErrNodeIDRange, p.Ordinal, bits)
+	}
+	return int64(p.Ordinal), nil
+}
+
+// hashedNodeID maps key onto a node ID in [0, 2^bits)
+func hashedNodeID(key string, bits int) (int64, error) {
+	if err := checkNodeIDBits(bits); err != nil {
+		return 0, err
+	}
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return int64(h.Sum64() % (uint64(1) << bits)), nil
+}
+
+func checkNodeIDBits(bits int) error {
+	if bits < 1 || bits > 63 {
+		return fmt.Errorf("%w: node ID bits must be between 1 and 63", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// PodIDGenerator creates IDs of the form
+// <namespace>-<podHash>-<ordinal>-<random>, leaving out the ordinal for
+// pods outside a StatefulSet. IDs tell at a glance which workload and pod
+// issued them, while the random part keeps them unique.
+type PodIDGenerator struct {
+	identity PodIdentity
+	head     string
+	gen      *Generator
+}
+
+// NewPodIDGenerator creates a generator for identity; opts configure the
+// alphabet and size of the random part, and the pod hash uses the same
+// alphabet
+func NewPodIDGenerator(identity PodIdentity, opts ...Option) *PodIDGenerator {
+	gen := New(opts...)
+	head := identity.Namespace + "-" + keyHash(identity.Name, gen.alphabet) + "-"
+	if identity.Ordinal >= 0 {
+		head += strconv.Itoa(identity.Ordinal) + "-"
+	}
+	return &PodIDGenerator{identity: identity, head: head, gen: gen}
+}
+
+// NewPodIDGeneratorFromEnv is NewPodIDGenerator for the detected identity
+func NewPodIDGeneratorFromEnv(opts ...Option) (*PodIDGenerator, error) {
+	identity, err := DetectPodIdentity()
+	if err != nil {
+		return nil, err
+	}
+	return NewPodIDGenerator(identity, opts...), nil
+}
+
+// Generate creates an ID
+func (g *PodIDGenerator) Generate() (string, error) {
+	random, err := g.gen.Generate()
+	if err != nil {
+		return "", err
+	}
+	return g.head + random, nil
+}
+
+// Identity returns the pod the generator's IDs name
+func (g *PodIDGenerator) Identity() PodIdentity {
+	return g.identity
+}
+
+// Validate checks if id was produced by a generator for the same pod
+func (g *PodIDGenerator) Validate(id string) bool {
+	random, ok := strings.CutPrefix(id, g.head)
+	return ok && g.gen.Validate(random)
+}
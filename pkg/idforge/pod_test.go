@@ -0,0 +1,111 @@
+package idforge
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParsePodIdentity(t *testing.T) {
+	// Deployment pod names can end in digits too
+	if got := ParsePodIdentity("prod", "web-7d9f8b-24567").Ordinal; got != -1 {
+		t.Errorf("Expected no ordinal outside a StatefulSet, got %d", got)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		ordinal int
+	}{{"web-0", 0}, {"web-12", 12}, {"api-7d9f8-x2kqz", -1}, {"web", -1}, {"web-", -1}, {"web-+1", -1}} {
+		if got := ParseStatefulSetPodIdentity("prod", tc.name).Ordinal; got != tc.ordinal {
+			t.Errorf("Expected ordinal %d for %s, got %d", tc.ordinal, tc.name, got)
+		}
+	}
+}
+
+func TestDetectPodIdentity(t *testing.T) {
+	env := map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "HOSTNAME": "web-3"}
+	readFile := func(path string) ([]byte, error) {
+		if path != podNamespaceFile {
+			t.Errorf("Expected the service account namespace file, got %s", path)
+		}
+		return []byte("prod\n"), nil
+	}
+	getenv := func(key string) string { return env[key] }
+
+	identity, err := detectPodIdentity(getenv, readFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if identity != (PodIdentity{Namespace: "prod", Name: "web-3", Ordinal: -1}) {
+		t.Errorf("Expected prod/web-3 without an ordinal, got %+v", identity)
+	}
+
+	env["POD_NAMESPACE"], env["POD_NAME"], env["POD_INDEX"] = "staging", "worker-abc", "5"
+	identity, _ = detectPodIdentity(getenv, readFile)
+	if identity != (PodIdentity{Namespace: "staging", Name: "worker-abc", Ordinal: 5}) {
+		t.Errorf("Expected downward API values to win, got %+v", identity)
+	}
+
+	env["POD_INDEX"] = "five"
+	if _, err := detectPodIdentity(getenv, readFile); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for a bad POD_INDEX, got %v", err)
+	}
+
+	delete(env, "POD_NAMESPACE")
+	if _, err := detectPodIdentity(getenv, func(string) ([]byte, error) { return nil, os.ErrNotExist }); !errors.Is(err, ErrNotInKubernetes) {
+		t.Errorf("Expected ErrNotInKubernetes without a namespace, got %v", err)
+	}
+
+	delete(env, "KUBERNETES_SERVICE_HOST")
+	if _, err := detectPodIdentity(getenv, readFile); !errors.Is(err, ErrNotInKubernetes) {
+		t.Errorf("Expected ErrNotInKubernetes, got %v", err)
+	}
+}
+
+func TestPodIdentityNodeID(t *testing.T) {
+	id, err := ParseStatefulSetPodIdentity("prod", "web-7").NodeID(10)
+	if err != nil || id != 7 {
+		t.Errorf("Expected node ID 7, got %d (%v)", id, err)
+	}
+	if _, err := ParseStatefulSetPodIdentity("prod", "web-1024").NodeID(10); !errors.Is(err, ErrNodeIDRange) {
+		t.Errorf("Expected ErrNodeIDRange, got %v", err)
+	}
+	if _, err := ParseStatefulSetPodIdentity("prod", "web-1").NodeID(0); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for 0 bits, got %v", err)
+	}
+
+	pod := ParsePodIdentity("prod", "web-7d9f8b-24567")
+	a, _ := pod.NodeID(10)
+	b, _ := pod.NodeID(10)
+	if a != b || a < 0 || a >= 1024 {
+		t.Errorf("Expected a stable node ID below 1024, got %d and %d", a, b)
+	}
+}
+
+func TestPodIDGenerator(t *testing.T) {
+	gen := NewPodIDGenerator(ParseStatefulSetPodIdentity("prod", "web-2"), WithSize(10))
+	id, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parts := strings.Split(id, "-")
+	if len(parts) != 4 || parts[0] != "prod" || len(parts[1]) != partitionHashSize || parts[2] != "2" || len(parts[3]) != 10 {
+		t.Errorf("Expected prod-<hash>-2-<random>, got %s", id)
+	}
+	if !gen.Validate(id) {
+		t.Errorf("Expected %s to validate", id)
+	}
+	if NewPodIDGenerator(ParseStatefulSetPodIdentity("prod", "web-3"), WithSize(10)).Validate(id) {
+		t.Error("Expected another pod's generator to reject the ID")
+	}
+	if gen.Identity().Name != "web-2" {
+		t.Errorf("Expected web-2, got %s", gen.Identity().Name)
+	}
+
+	plain := NewPodIDGenerator(ParsePodIdentity("prod", "api-x2kqz"), WithSize(10))
+	id, _ = plain.Generate()
+	if parts := strings.Split(id, "-"); len(parts) != 3 {
+		t.Errorf("Expected no ordinal segment, got %s", id)
+	}
+}
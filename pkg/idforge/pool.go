@@ -0,0 +1,187 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var ErrPoolClosed = errors.New("ID pool is closed")
+
+// PoolOption configures an IDPool
+type PoolOption func(*IDPool)
+
+// WithPoolSize sets how many IDs the pool keeps ready
+func WithPoolSize(size int) PoolOption {
+	return func(p *IDPool) {
+		if size > 0 {
+			p.size = size
+		}
+	}
+}
+
+// WithLowWaterMark sets the number of ready IDs below which the pool refills
+func WithLowWaterMark(n int) PoolOption {
+	return func(p *IDPool) {
+		if n >= 0 {
+			p.lowWater = n
+		}
+	}
+}
+
+// IDPool keeps pre-generated IDs ready so latency-critical paths don't pay
+// for entropy collection. Once started, a background goroutine tops the
+// pool up whenever it falls below the low water mark.
+type IDPool struct {
+	generate func() (string, error)
+	size     int
+	lowWater int
+
+	ids     chan string
+	wake    chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+	started atomic.Bool
+	closed  atomic.Bool
+}
+
+// NewIDPool creates a pool filled by generate, such as a Generator's
+// Generate method. The pool holds 1024 IDs and refills below a quarter of
+// its size unless configured otherwise. Call Start to begin filling it.
+func NewIDPool(generate func() (string, error), opts ...PoolOption) *IDPool {
+	p := &IDPool{
+		generate: generate,
+		size:     1024,
+		lowWater: -1,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.lowWater < 0 || p.lowWater > p.size {
+		p.lowWater = p.size / 4
+	}
+
+	p.ids = make(chan string, p.size)
+	p.wake = make(chan struct{}, 1)
+	p.done = make(chan struct{})
+	return p
+}
+
+// Start launches the refill goroutine, which runs until Close is called or
+// ctx is done
+func (p *IDPool) Start(ctx context.Context) error {
+	if p.closed.Load() {
+		return ErrPoolClosed
+	}
+	if p.started.Swap(true) {
+		return nil
+	}
+
+	p.wg.Add(1)
+	go p.refill(ctx)
+	p.signal()
+	return nil
+}
+
+// Get returns a ready ID, or generates one directly when the pool is empty
+// or not started
+func (p *IDPool) Get() (string, error) {
+	if p.closed.Load() {
+		return "", ErrPoolClosed
+	}
+
+	select {
+	case id := <-p.ids:
+		if len(p.ids) < p.lowWater {
+			p.signal()
+		}
+		return id, nil
+	default:
+		p.signal()
+		return p.generate()
+	}
+}
+
+// Len reports how many IDs are ready
+func (p *IDPool) Len() int {
+	return len(p.ids)
+}
+
+// Close stops refilling and makes Get fail with ErrPoolClosed. IDs that
+// were never handed out remain available through Drain.
+func (p *IDPool) Close() error {
+	if p.closed.Swap(true) {
+		return nil
+	}
+	close(p.done)
+	p.wg.Wait()
+	return nil
+}
+
+// Drain removes and returns the ready IDs, e.g. to release reservations or
+// record them after Close
+func (p *IDPool) Drain() []string {
+	var ids []string
+	for {
+		select {
+		case id := <-p.ids:
+			ids = append(ids, id)
+		default:
+			return ids
+		}
+	}
+}
+
+// signal wakes the refill goroutine without blocking
+func (p *IDPool) signal() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// refill tops the pool up each time it is woken. A generation error ends
+// the round; the next Get triggers another attempt.
+func (p *IDPool) refill(ctx context.Context) {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.done:
+			return
+		case <-p.wake:
+		}
+
+		for len(p.ids) < p.size {
+			// Stop before generating, not after, so Close does not wait
+			// for the rest of the round
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.done:
+				return
+			default:
+			}
+
+			id, err := p.generate()
+			if err != nil {
+				break
+			}
+			select {
+			case p.ids <- id:
+			case <-ctx.Done():
+				return
+			case <-p.done:
+				// Keep the ID for Drain if there is room
+				select {
+				case p.ids <- id:
+				default:
+				}
+				return
+			}
+		}
+	}
+}
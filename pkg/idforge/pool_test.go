@@ -0,0 +1,134 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForLen polls until the pool holds n IDs
+func waitForLen(t *testing.T, pool *IDPool, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for pool.Len() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Pool did not reach %d IDs, has %d", n, pool.Len())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestIDPoolFillsAndRefills(t *testing.T) {
+	gen := New()
+	pool := NewIDPool(gen.Generate, WithPoolSize(16), WithLowWaterMark(8))
+	pool.Start(context.Background())
+	defer pool.Close()
+
+	waitForLen(t, pool, 16)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 40; i++ {
+		id, err := pool.Get()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !gen.Validate(id) || seen[id] {
+			t.Fatalf("Unexpected ID %q from pool", id)
+		}
+		seen[id] = true
+	}
+
+	waitForLen(t, pool, 16)
+}
+
+func TestIDPoolFallsBackWhenEmpty(t *testing.T) {
+	var calls atomic.Int32
+	genErr := errors.New("no entropy")
+	pool := NewIDPool(func() (string, error) {
+		calls.Add(1)
+		return "", genErr
+	}, WithPoolSize(4))
+	pool.Start(context.Background())
+	defer pool.Close()
+
+	if _, err := pool.Get(); err != genErr {
+		t.Errorf("Expected generation error from an empty pool, got %v", err)
+	}
+	if calls.Load() == 0 {
+		t.Error("Expected the generator to be called")
+	}
+}
+
+func TestIDPoolCloseAndDrain(t *testing.T) {
+	pool := NewIDPool(New().Generate, WithPoolSize(8))
+	pool.Start(context.Background())
+	waitForLen(t, pool, 8)
+
+	if err := pool.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := pool.Get(); err != ErrPoolClosed {
+		t.Errorf("Expected ErrPoolClosed, got %v", err)
+	}
+	if ids := pool.Drain(); len(ids) != 8 {
+		t.Errorf("Expected 8 drained IDs, got %d", len(ids))
+	}
+	if err := pool.Close(); err != nil {
+		t.Errorf("Expected repeated Close to succeed, got %v", err)
+	}
+	if err := pool.Start(context.Background()); err != ErrPoolClosed {
+		t.Errorf("Expected ErrPoolClosed when starting a closed pool, got %v", err)
+	}
+}
+
+func TestIDPoolStopsWithContext(t *testing.T) {
+	var calls atomic.Int32
+	pool := NewIDPool(func() (string, error) {
+		calls.Add(1)
+		return "id", nil
+	}, WithPoolSize(4))
+
+	if pool.Len() != 0 || calls.Load() != 0 {
+		t.Error("Expected an unstarted pool to stay empty")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pool.Start(ctx)
+	waitForLen(t, pool, 4)
+	cancel()
+
+	// Close returns promptly once the refill goroutine has exited
+	done := make(chan struct{})
+	go func() {
+		pool.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not return after the context was cancelled")
+	}
+}
+
+func TestIDPoolCloseStopsRefill(t *testing.T) {
+	var calls atomic.Int32
+	closing := make(chan struct{})
+	pool := NewIDPool(func() (string, error) {
+		if calls.Add(1) == 4 {
+			close(closing)
+		}
+		time.Sleep(time.Millisecond)
+		return "id", nil
+	}, WithPoolSize(1000))
+	pool.Start(context.Background())
+
+	<-closing
+	if err := pool.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := calls.Load(); n > 4 {
+		t.Errorf("Expected refilling to stop at Close, got %d generate calls", n)
+	}
+}
@@ -0,0 +1,111 @@
+//go:build !idforge_lite
+
+package idforge
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// ProblemDetails is an RFC 7807 error body. Code and Violations are
+// extension members; Violations lists each broken rule of a rejected ID.
+type ProblemDetails struct {
+	Type       string             `json:"type"`
+	Title      string             `json:"title"`
+	Status     int                `json:"status"`
+	Detail     string             `json:"detail,omitempty"`
+	Instance   string             `json:"instance,omitempty"`
+	Code       Code               `json:"code"`
+	Violations []ProblemViolation `json:"violations,omitempty"`
+}
+
+// ProblemViolation is one broken rule in ProblemDetails
+type ProblemViolation struct {
+	Rule   string `json:"rule"`
+	Code   Code   `json:"code"`
+	Detail string `json:"detail"`
+}
+
+// ToProblemDetails describes err as problem details, using its ErrorCode
+// for the type and status. Rejected IDs get 400 Bad Request with their
+// violations, CSRF failures 403, rate limiting and exhausted quotas 429
+// and unavailable entropy, timeouts or a missing sequencer leader 503.
+// Other errors get 500 without a detail, so internal messages are not
+// exposed to clients. A nil error gives zero ProblemDetails.
+func ToProblemDetails(err error) ProblemDetails {
+	if err == nil {
+		return ProblemDetails{}
+	}
+
+	code := ErrorCode(err)
+	p := ProblemDetails{
+		Type:   "urn:idforge:error:" + string(code),
+		Title:  problemTitle(code),
+		Status: problemStatus(code),
+		Code:   code,
+	}
+	if p.Status < http.StatusInternalServerError {
+		p.Detail = err.Error()
+	}
+
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		p.Violations = make([]ProblemViolation, len(verr.violations))
+		for i, v := range verr.violations {
+			p.Violations[i] = ProblemViolation{Rule: v.Rule, Code: v.Code(), Detail: v.Message}
+		}
+	}
+	return p
+}
+
+// WriteProblemDetails answers a request with the problem details of err,
+// which must not be nil
+func WriteProblemDetails(w http.ResponseWriter, err error) {
+	p := ToProblemDetails(err)
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// problemStatus maps a code to an HTTP status
+func problemStatus(code Code) int {
+	switch {
+	case code == CodeRateLimited, code == CodeQuotaExceeded:
+		return http.StatusTooManyRequests
+	case code == CodeInvalidCSRFToken, code == CodeCSRFTokenExpired:
+		return http.StatusForbidden
+	case code == CodeEntropyUnavailable, code == CodeProviderTimeout, code == CodeGenerationTimeout,
+		code == CodeNotLeader:
+		return http.StatusServiceUnavailable
+	case strings.HasPrefix(string(code), "IDF-VAL-"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// problemTitle gives a short summary that is the same for every
+// occurrence of a code
+func problemTitle(code Code) string {
+	switch {
+	case code == CodeRateLimited:
+		return "Too many ID requests"
+	case code == CodeQuotaExceeded:
+		return "ID quota exceeded"
+	case code == CodeInvalidCSRFToken, code == CodeCSRFTokenExpired:
+		return "Invalid CSRF token"
+	case strings.HasPrefix(string(code), "IDF-VAL-"):
+		return "Invalid ID"
+	case strings.HasPrefix(string(code), "IDF-GEN-"):
+		return "ID generation failed"
+	case strings.HasPrefix(string(code), "IDF-CFG-"):
+		return "Invalid ID configuration"
+	default:
+		return "Internal error"
+	}
+}
@@ -0,0 +1,86 @@
+//go:build !idforge_lite
+
+package idforge
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestToProblemDetailsValidation(t *testing.T) {
+	p := ToProblemDetails(CheckID("xyzw", "abc", 3))
+
+	if p.Status != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", p.Status)
+	}
+	if p.Code != CodeInvalidID || p.Type != "urn:idforge:error:IDF-VAL-001" {
+		t.Errorf("Expected code %s and a matching type, got %s and %s", CodeInvalidID, p.Code, p.Type)
+	}
+	if p.Title != "Invalid ID" || p.Detail == "" {
+		t.Errorf("Expected a title and detail, got %q and %q", p.Title, p.Detail)
+	}
+
+	want := []ProblemViolation{
+		{Rule: RuleLength, Code: CodeInvalidLength, Detail: "length is 4, expected 3"},
+		{Rule: RuleAlphabet, Code: CodeInvalidCharacter, Detail: "4 characters outside the alphabet, first at byte 0"},
+	}
+	if !reflect.DeepEqual(p.Violations, want) {
+		t.Errorf("Expected violations %+v, got %+v", want, p.Violations)
+	}
+}
+
+func TestToProblemDetailsStatus(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{ErrRateLimited, http.StatusTooManyRequests},
+		{ErrQuotaExceeded, http.StatusTooManyRequests},
+		{ErrCSRFTokenExpired, http.StatusForbidden},
+		{ErrGenerationTimeout, http.StatusServiceUnavailable},
+		{ErrNotLeader, http.StatusServiceUnavailable},
+		{ErrInvalidSignature, http.StatusBadRequest},
+		{ErrInvalidAlphabet, http.StatusInternalServerError},
+		{errors.New("database password rejected"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		p := ToProblemDetails(tt.err)
+		if p.Status != tt.status {
+			t.Errorf("Expected status %d for %v, got %d", tt.status, tt.err, p.Status)
+		}
+		if p.Status >= http.StatusInternalServerError && p.Detail != "" {
+			t.Errorf("Expected no detail for server errors, got %q", p.Detail)
+		}
+	}
+
+	if p := ToProblemDetails(nil); !reflect.DeepEqual(p, ProblemDetails{}) {
+		t.Errorf("Expected zero details for nil, got %+v", p)
+	}
+}
+
+func TestWriteProblemDetails(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteProblemDetails(rec, CheckID("ab", "abc", 3))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("Expected content type %s, got %s", ProblemContentType, ct)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if body["code"] != string(CodeInvalidLength) || body["status"] != float64(400) {
+		t.Errorf("Expected code and status members, got %v", body)
+	}
+	if _, ok := body["instance"]; ok {
+		t.Errorf("Expected an empty instance to be omitted, got %v", body)
+	}
+}
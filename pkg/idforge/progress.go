@@ -0,0 +1,90 @@
+package idforge
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Progress reports how far a batch operation has got
+type Progress struct {
+	Done    int
+	Total   int
+	Elapsed time.Duration
+	Rate    float64       // Items per second so far
+	ETA     time.Duration // Estimated time remaining, 0 until the rate is known
+}
+
+// ProgressFunc receives progress reports, one call at a time
+type ProgressFunc func(Progress)
+
+// progressTracker counts finished items and reports them every interval
+// from its own goroutine, then once more when the operation ends. A nil
+// tracker does nothing.
+type progressTracker struct {
+	fn    ProgressFunc
+	total int
+	start time.Time
+	done  atomic.Int64
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// startProgress begins reporting to fn, or returns nil when fn is nil
+func startProgress(fn ProgressFunc, total int, interval time.Duration) *progressTracker {
+	if fn == nil {
+		return nil
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	p := &progressTracker{fn: fn, total: total, start: time.Now(), stop: make(chan struct{})}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.fn(p.snapshot())
+			}
+		}
+	}()
+	return p
+}
+
+// add counts n more finished items
+func (p *progressTracker) add(n int) {
+	if p != nil {
+		p.done.Add(int64(n))
+	}
+}
+
+// finish stops periodic reports and sends the final one
+func (p *progressTracker) finish() {
+	if p == nil {
+		return
+	}
+	close(p.stop)
+	p.wg.Wait()
+	p.fn(p.snapshot())
+}
+
+// snapshot computes the current progress
+func (p *progressTracker) snapshot() Progress {
+	progress := Progress{
+		Done:    int(p.done.Load()),
+		Total:   p.total,
+		Elapsed: time.Since(p.start),
+	}
+	if seconds := progress.Elapsed.Seconds(); seconds > 0 && progress.Done > 0 {
+		progress.Rate = float64(progress.Done) / seconds
+		remaining := max(progress.Total-progress.Done, 0)
+		progress.ETA = time.Duration(float64(remaining) / progress.Rate * float64(time.Second))
+	}
+	return progress
+}
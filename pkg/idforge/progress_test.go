@@ -0,0 +1,72 @@
+package idforge
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGenerateBulkProgress(t *testing.T) {
+	var mu sync.Mutex
+	var reports []Progress
+	record := func(p Progress) {
+		mu.Lock()
+		defer mu.Unlock()
+		reports = append(reports, p)
+	}
+
+	gen := NewExtendedGenerator()
+	if _, err := gen.GenerateBulk(context.Background(), 5000, 4, WithProgress(record, time.Millisecond)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reports) == 0 {
+		t.Fatal("Expected at least one progress report")
+	}
+	for i, p := range reports {
+		if p.Total != 5000 {
+			t.Errorf("Expected total 5000, got %d", p.Total)
+		}
+		if i > 0 && p.Done < reports[i-1].Done {
+			t.Errorf("Expected done to never decrease, got %d after %d", p.Done, reports[i-1].Done)
+		}
+	}
+	last := reports[len(reports)-1]
+	if last.Done != 5000 {
+		t.Errorf("Expected final report with 5000 done, got %d", last.Done)
+	}
+	if last.ETA != 0 {
+		t.Errorf("Expected no time remaining, got %v", last.ETA)
+	}
+	if last.Rate <= 0 {
+		t.Errorf("Expected a positive rate, got %f", last.Rate)
+	}
+}
+
+func TestProgressSnapshot(t *testing.T) {
+	p := &progressTracker{total: 100, start: time.Now().Add(-2 * time.Second)}
+	if got := p.snapshot(); got.Rate != 0 || got.ETA != 0 {
+		t.Errorf("Expected no rate or ETA before any work, got %+v", got)
+	}
+
+	p.add(50)
+	got := p.snapshot()
+	if got.Rate < 20 || got.Rate > 25 {
+		t.Errorf("Expected a rate near 25/s, got %f", got.Rate)
+	}
+	if got.ETA < time.Second || got.ETA > 3*time.Second {
+		t.Errorf("Expected an ETA near 2s, got %v", got.ETA)
+	}
+}
+
+func TestNilProgressTracker(t *testing.T) {
+	p := startProgress(nil, 10, 0)
+	if p != nil {
+		t.Fatal("Expected no tracker without a callback")
+	}
+	p.add(1)
+	p.finish()
+}
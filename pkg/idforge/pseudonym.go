@@ -0,0 +1,121 @@
+package idforge
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"unicode/utf8"
+)
+
+// PseudonymOption configures a Pseudonymizer
+type PseudonymOption func(*Pseudonymizer)
+
+// WithPseudonymAlphabet sets the characters pseudonyms are written in
+func WithPseudonymAlphabet(alphabet string) PseudonymOption {
+	return func(p *Pseudonymizer) {
+		if validAlphabet(alphabet) {
+			p.alphabet = newSymbols(alphabet)
+		}
+	}
+}
+
+// WithPseudonymSize sets the number of characters in each pseudonym
+func WithPseudonymSize(size int) PseudonymOption {
+	return func(p *Pseudonymizer) {
+		if size > 0 {
+			p.size = size
+		}
+	}
+}
+
+// WithPreservedLength makes each pseudonym as long as the ID it replaces.
+// Short IDs then get short pseudonyms, which collide more often.
+func WithPreservedLength() PseudonymOption {
+	return func(p *Pseudonymizer) {
+		p.preserveLength = true
+	}
+}
+
+// Pseudonymizer maps IDs to stable keyed pseudonyms, so datasets can be
+// shared with third parties without exposing real identifiers. The same ID
+// and key always give the same pseudonym; without the key, pseudonyms
+// cannot be linked back to IDs or computed for new ones.
+type Pseudonymizer struct {
+	key            []byte
+	alphabet       symbols
+	size           int
+	preserveLength bool
+}
+
+// NewPseudonymizer creates a pseudonymizer using key, which should be at
+// least 32 random bytes and kept secret. Pseudonyms default to DefaultSize
+// characters of DefaultAlphabet.
+func NewPseudonymizer(key []byte, opts ...PseudonymOption) *Pseudonymizer {
+	p := &Pseudonymizer{
+		key:      append([]byte(nil), key...),
+		alphabet: newSymbols(DefaultAlphabet),
+		size:     DefaultSize,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Pseudonymize returns the pseudonym of id
+func (p *Pseudonymizer) Pseudonymize(id string) string {
+	size := p.size
+	if p.preserveLength {
+		size = max(utf8.RuneCountInString(id), 1)
+	}
+
+	// Characters are drawn from an HMAC keystream by the same unbiased
+	// sampling used for generated IDs, so reading never fails
+	stream := &hmacStream{mac: hmac.New(sha256.New, p.key), id: id}
+	pseudonym, _ := buildID(stream, p.alphabet, size, nil, nil)
+	return pseudonym
+}
+
+// PseudonymizeAll returns the pseudonyms of ids, in order
+func (p *Pseudonymizer) PseudonymizeAll(ids []string) []string {
+	pseudonyms := make([]string, len(ids))
+	for i, id := range ids {
+		pseudonyms[i] = p.Pseudonymize(id)
+	}
+	return pseudonyms
+}
+
+// Pseudonymize returns a stable pseudonym of id under key, DefaultSize
+// characters of DefaultAlphabet long
+func Pseudonymize(id string, key []byte) string {
+	return NewPseudonymizer(key).Pseudonymize(id)
+}
+
+// hmacStream is a deterministic keystream of HMAC(key, counter || id) blocks
+type hmacStream struct {
+	mac     hash.Hash
+	id      string
+	counter uint64
+	block   []byte
+}
+
+func (s *hmacStream) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(s.block) == 0 {
+			var counter [8]byte
+			binary.BigEndian.PutUint64(counter[:], s.counter)
+			s.counter++
+
+			s.mac.Reset()
+			s.mac.Write(counter[:])
+			s.mac.Write([]byte(s.id))
+			s.block = s.mac.Sum(nil)
+		}
+		copied := copy(p[n:], s.block)
+		s.block = s.block[copied:]
+		n += copied
+	}
+	return n, nil
+}
@@ -0,0 +1,56 @@
+package idforge
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+var pseudonymKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestPseudonymizeStable(t *testing.T) {
+	a := Pseudonymize("user-42", pseudonymKey)
+	if a != Pseudonymize("user-42", pseudonymKey) {
+		t.Error("Expected the same pseudonym for the same ID and key")
+	}
+	if len(a) != DefaultSize || !IsValidID(a, DefaultAlphabet, DefaultSize) {
+		t.Errorf("Expected a %d-character pseudonym in the default alphabet, got %q", DefaultSize, a)
+	}
+	if a == Pseudonymize("user-43", pseudonymKey) {
+		t.Error("Expected different IDs to get different pseudonyms")
+	}
+	if a == Pseudonymize("user-42", []byte("another key")) {
+		t.Error("Expected different keys to give different pseudonyms")
+	}
+}
+
+func TestPseudonymizerOptions(t *testing.T) {
+	p := NewPseudonymizer(pseudonymKey, WithPseudonymAlphabet(cyrillicAlphabet), WithPreservedLength())
+
+	for _, id := range []string{"a", "order-1234", "ж🙂x"} {
+		pseudonym := p.Pseudonymize(id)
+		if utf8.RuneCountInString(pseudonym) != utf8.RuneCountInString(id) {
+			t.Errorf("Expected %q to keep the length of %q", pseudonym, id)
+		}
+		if !IsValidID(pseudonym, cyrillicAlphabet, utf8.RuneCountInString(id)) {
+			t.Errorf("Expected %q to use the configured alphabet", pseudonym)
+		}
+	}
+
+	long := NewPseudonymizer(pseudonymKey, WithPseudonymSize(300)).Pseudonymize("x")
+	if len(long) != 300 {
+		t.Errorf("Expected 300 characters, got %d", len(long))
+	}
+}
+
+func TestPseudonymizeAll(t *testing.T) {
+	p := NewPseudonymizer(pseudonymKey)
+	ids := []string{"a", "b", "a"}
+
+	got := p.PseudonymizeAll(ids)
+	if len(got) != 3 || got[0] != got[2] || got[0] == got[1] {
+		t.Errorf("Unexpected pseudonyms %q", got)
+	}
+	if got[1] != p.Pseudonymize("b") {
+		t.Error("Expected batch and single pseudonyms to agree")
+	}
+}
@@ -0,0 +1,108 @@
+package idforge
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+const (
+	// QRAlphanumericAlphabet holds the characters QR codes encode in their
+	// dense alphanumeric mode, except space
+	QRAlphanumericAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ$%*+-./:"
+
+	// QRSafeAlphabet is the URL- and filename-safe subset of
+	// QRAlphanumericAlphabet: digits and uppercase letters
+	QRSafeAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+var ErrNotQRAlphanumeric = errors.New("payload contains characters outside QR alphanumeric mode")
+
+// QRLevel is a QR code error correction level
+type QRLevel int
+
+const (
+	QRLevelL QRLevel = iota // Recovers about 7% of the symbol
+	QRLevelM                // Recovers about 15% of the symbol
+	QRLevelQ                // Recovers about 25% of the symbol
+	QRLevelH                // Recovers about 30% of the symbol
+)
+
+// qrCapacity lists how many alphanumeric characters QR versions 1 to 10
+// hold at each error correction level
+var qrCapacity = [4][10]int{
+	QRLevelL: {25, 47, 77, 114, 154, 195, 224, 279, 335, 395},
+	QRLevelM: {20, 38, 61, 90, 122, 154, 178, 221, 262, 311},
+	QRLevelQ: {16, 29, 47, 67, 87, 108, 125, 157, 189, 221},
+	QRLevelH: {10, 20, 35, 50, 64, 84, 93, 122, 143, 174},
+}
+
+// QRSizing describes how an ID of a given length fits into a QR code
+type QRSizing struct {
+	Length      int     // ID characters
+	EntropyBits float64 // Randomness carried by the ID
+	DataBits    int     // Bits the ID occupies in alphanumeric mode
+	Version     int     // Smallest QR version that holds it, 0 if above 10
+}
+
+// SuggestQRSize returns the shortest length of IDs in alphabet, a subset of
+// QRAlphanumericAlphabet, that carries at least minBits of entropy. When
+// one more character still fits in the same QR version it is added, since
+// it costs no extra modules.
+func SuggestQRSize(alphabet string, minBits float64, level QRLevel) QRSizing {
+	bitsPerChar := math.Log2(float64(alphabetLen(alphabet)))
+	length := max(int(math.Ceil(minBits/bitsPerChar)), 1)
+
+	sizing := qrSizing(length, bitsPerChar, level)
+	if longer := qrSizing(length+1, bitsPerChar, level); longer.Version == sizing.Version && sizing.Version > 0 {
+		return longer
+	}
+	return sizing
+}
+
+func qrSizing(length int, bitsPerChar float64, level QRLevel) QRSizing {
+	sizing := QRSizing{
+		Length:      length,
+		EntropyBits: float64(length) * bitsPerChar,
+		// Alphanumeric mode packs two characters into 11 bits
+		DataBits: 11*(length/2) + 6*(length%2),
+	}
+	for version, capacity := range qrCapacity[level] {
+		if length <= capacity {
+			sizing.Version = version + 1
+			break
+		}
+	}
+	return sizing
+}
+
+// QRPayload joins prefix and id into the text to encode, checking that it
+// stays within alphanumeric mode so the code remains as small as possible
+func QRPayload(prefix, id string) (string, error) {
+	payload := prefix + id
+	for _, char := range payload {
+		if char != ' ' && !strings.ContainsRune(QRAlphanumericAlphabet, char) {
+			return "", ErrNotQRAlphanumeric
+		}
+	}
+	return payload, nil
+}
+
+// WithQRAlphabet restricts IDs to QRSafeAlphabet
+func WithQRAlphabet() Option {
+	return WithAlphabet(QRSafeAlphabet)
+}
+
+// GenerateQR creates an ID together with the QR payload for it, such as
+// "TKT:" followed by the ID for a ticket
+func (g *Generator) GenerateQR(prefix string) (id, payload string, err error) {
+	id, err = g.Generate()
+	if err != nil {
+		return "", "", err
+	}
+	payload, err = QRPayload(prefix, id)
+	if err != nil {
+		return "", "", err
+	}
+	return id, payload, nil
+}
@@ -0,0 +1,48 @@
+package idforge
+
+import (
+	"testing"
+)
+
+func TestSuggestQRSize(t *testing.T) {
+	// 64 bits need 13 base-36 characters; 14 still fit version 1 at level M
+	sizing := SuggestQRSize(QRSafeAlphabet, 64, QRLevelM)
+	if sizing.Length != 14 || sizing.Version != 1 {
+		t.Errorf("Expected 14 characters in version 1, got %+v", sizing)
+	}
+	if sizing.DataBits != 77 {
+		t.Errorf("Expected 77 data bits, got %d", sizing.DataBits)
+	}
+	if sizing.EntropyBits < 64 {
+		t.Errorf("Expected at least 64 bits of entropy, got %.1f", sizing.EntropyBits)
+	}
+
+	// 10 characters fill version 1 at level H, so none is added
+	sizing = SuggestQRSize(QRSafeAlphabet, 50, QRLevelH)
+	if sizing.Length != 10 || sizing.Version != 1 {
+		t.Errorf("Expected 10 characters in version 1, got %+v", sizing)
+	}
+
+	if sizing := SuggestQRSize(QRSafeAlphabet, 5000, QRLevelL); sizing.Version != 0 {
+		t.Errorf("Expected no version for oversized IDs, got %+v", sizing)
+	}
+}
+
+func TestGenerateQR(t *testing.T) {
+	gen := New(WithQRAlphabet(), WithSize(12))
+
+	id, payload, err := gen.GenerateQR("TKT:")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !IsValidID(id, QRSafeAlphabet, 12) {
+		t.Errorf("Expected a QR-safe ID, got %s", id)
+	}
+	if payload != "TKT:"+id {
+		t.Errorf("Expected payload TKT:%s, got %s", id, payload)
+	}
+
+	if _, _, err := gen.GenerateQR("https://"); err != ErrNotQRAlphanumeric {
+		t.Errorf("Expected ErrNotQRAlphanumeric for a lowercase prefix, got %v", err)
+	}
+}
@@ -0,0 +1,318 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrQuotaExceeded = errors.New("ID issuance quota exceeded")
+
+// QuotaPeriod is the window over which a quota is counted. Windows follow
+// the UTC calendar.
+type QuotaPeriod int
+
+const (
+	QuotaDaily QuotaPeriod = iota
+	QuotaMonthly
+)
+
+func (p QuotaPeriod) String() string {
+	switch p {
+	case QuotaDaily:
+		return "daily"
+	case QuotaMonthly:
+		return "monthly"
+	default:
+		return "unknown"
+	}
+}
+
+// window names the period containing now and returns when it ends
+func (p QuotaPeriod) window(now time.Time) (string, time.Time) {
+	now = now.UTC()
+	if p == QuotaMonthly {
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start.Format("2006-01"), start.AddDate(0, 1, 0)
+	}
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return start.Format("2006-01-02"), start.AddDate(0, 0, 1)
+}
+
+// Quota caps the IDs a caller may be issued per period
+type Quota struct {
+	Limit  int64
+	Period QuotaPeriod
+}
+
+// QuotaUsage reports how much of a quota has been used in the current period
+type QuotaUsage struct {
+	Quota
+	Used     int64
+	ResetsAt time.Time
+}
+
+// QuotaCounter keeps issuance counts shared by the instances enforcing a
+// quota
+type QuotaCounter interface {
+	// Add atomically adds n, which may be negative or zero, to the counter
+	// key, creating it to expire at expiry, and returns the new value
+	Add(ctx context.Context, key string, n int64, expiry time.Time) (int64, error)
+}
+
+// QuotaManager enforces per-caller issuance quotas, where a caller is
+// whatever the service bills by, such as an API key or a namespace
+type QuotaManager struct {
+	counter  QuotaCounter
+	mu       sync.RWMutex
+	quotas   map[string][]Quota
+	defaults []Quota
+	clock    Clock
+}
+
+// QuotaOption configures a QuotaManager
+type QuotaOption func(*QuotaManager)
+
+// WithQuotaClock picks quota windows by clock instead of the system clock.
+// A MemoryQuotaCounter should be given the same clock with WithMemoryClock.
+func WithQuotaClock(clock Clock) QuotaOption {
+	return func(m *QuotaManager) {
+		if clock != nil {
+			m.clock = clock
+		}
+	}
+}
+
+// NewQuotaManager creates a manager counting in counter. Callers without
+// quotas of their own get the default quotas, none until SetDefaultQuota
+// is called.
+func NewQuotaManager(counter QuotaCounter, opts ...QuotaOption) *QuotaManager {
+	m := &QuotaManager{counter: counter, quotas: make(map[string][]Quota), clock: SystemClock}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// SetQuota replaces the quotas of caller; no quotas reverts it to the
+// defaults
+func (m *QuotaManager) SetQuota(caller string, quotas ...Quota) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(quotas) == 0 {
+		delete(m.quotas, caller)
+		return
+	}
+	m.quotas[caller] = append([]Quota(nil), quotas...)
+}
+
+// SetDefaultQuota replaces the quotas of callers without their own
+func (m *QuotaManager) SetDefaultQuota(quotas ...Quota) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.defaults = append([]Quota(nil), quotas...)
+}
+
+// quotasFor returns the quotas applying to caller
+func (m *QuotaManager) quotasFor(caller string) []Quota {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if quotas, ok := m.quotas[caller]; ok {
+		return quotas
+	}
+	return m.defaults
+}
+
+// Consume charges n IDs to caller, failing with ErrQuotaExceeded without
+// charging anything when that would exceed any of its quotas
+func (m *QuotaManager) Consume(ctx context.Context, caller string, n int) error {
+	return m.consume(ctx, caller, m.quotasFor(caller), n, m.clock.Now())
+}
+
+// consume charges n IDs to quotas of caller in the windows containing now
+func (m *QuotaManager) consume(ctx context.Context, caller string, quotas []Quota, n int, now time.Time) error {
+	for i, q := range quotas {
+		key, end := quotaKey(caller, q.Period, now)
+		used, err := m.counter.Add(ctx, key, int64(n), end)
+		if err != nil {
+			m.release(ctx, caller, quotas[:i], n, now)
+			return fmt.Errorf("quota counter: %w", err)
+		}
+		if used > q.Limit {
+			m.release(ctx, caller, quotas[:i+1], n, now)
+			return fmt.Errorf("%w: %s limit of %d for %q", ErrQuotaExceeded, q.Period, q.Limit, caller)
+		}
+	}
+	return nil
+}
+
+// Usage reports the current period of each quota of caller
+func (m *QuotaManager) Usage(ctx context.Context, caller string) ([]QuotaUsage, error) {
+	quotas := m.quotasFor(caller)
+	now := m.clock.Now()
+	usage := make([]QuotaUsage, len(quotas))
+	for i, q := range quotas {
+		key, end := quotaKey(caller, q.Period, now)
+		used, err := m.counter.Add(ctx, key, 0, end)
+		if err != nil {
+			return nil, fmt.Errorf("quota counter: %w", err)
+		}
+		usage[i] = QuotaUsage{Quota: q, Used: used, ResetsAt: end}
+	}
+	return usage, nil
+}
+
+// release gives n IDs back to quotas of caller. It is best effort: a
+// counter that fails here has already failed the call being undone.
+func (m *QuotaManager) release(ctx context.Context, caller string, quotas []Quota, n int, now time.Time) {
+	for _, q := range quotas {
+		key, end := quotaKey(caller, q.Period, now)
+		m.counter.Add(ctx, key, -int64(n), end)
+	}
+}
+
+// quotaKey names the counter of caller for the period containing now
+func quotaKey(caller string, period QuotaPeriod, now time.Time) (string, time.Time) {
+	window, end := period.window(now)
+	return caller + ":" + period.String() + ":" + window, end
+}
+
+// WithQuota charges every generated ID to the caller in the Generate
+// context, set with NewCallerContext, failing with ErrQuotaExceeded once
+// its quota is used up. Calls without a caller are charged to "".
+// GenerateBulk charges the whole batch up front.
+func WithQuota(m *QuotaManager) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.Quota = m
+	}
+}
+
+// callerContextKey is the context key under which quota callers are stored
+type callerContextKey struct{}
+
+// NewCallerContext returns a copy of ctx charging generated IDs to caller
+func NewCallerContext(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerContextKey{}, caller)
+}
+
+// CallerFromContext returns the caller stored in ctx by NewCallerContext
+func CallerFromContext(ctx context.Context) (string, bool) {
+	caller, ok := ctx.Value(callerContextKey{}).(string)
+	return caller, ok
+}
+
+// quotaCharge records what a charge counted against, so a refund reaches
+// the same counters even after a window ends or the quotas change
+type quotaCharge struct {
+	m      *QuotaManager
+	caller string
+	quotas []Quota
+	at     time.Time
+}
+
+// charge consumes n IDs for the caller in ctx; a nil manager charges nothing
+func (m *QuotaManager) charge(ctx context.Context, n int) (quotaCharge, error) {
+	if m == nil {
+		return quotaCharge{}, nil
+	}
+	caller, _ := CallerFromContext(ctx)
+	c := quotaCharge{m: m, caller: caller, quotas: m.quotasFor(caller), at: m.clock.Now()}
+	if err := m.consume(ctx, c.caller, c.quotas, n, c.at); err != nil {
+		return quotaCharge{}, err
+	}
+	return c, nil
+}
+
+// refund gives back n of the charged IDs that were not issued
+func (c quotaCharge) refund(ctx context.Context, n int) {
+	if c.m == nil || n <= 0 {
+		return
+	}
+	// The call being undone may have failed because ctx was cancelled
+	c.m.release(context.WithoutCancel(ctx), c.caller, c.quotas, n, c.at)
+}
+
+// MemoryQuotaCounter keeps quota counters in process memory, for a single
+// instance or tests
+type MemoryQuotaCounter struct {
+	mu        sync.Mutex
+	counters  map[string]memoryCount
+	nextSweep time.Time
+	clock     Clock
+}
+
+type memoryCount struct {
+	value  int64
+	expiry time.Time
+}
+
+// NewMemoryQuotaCounter creates an empty in-memory counter
+func NewMemoryQuotaCounter(opts ...MemoryOption) *MemoryQuotaCounter {
+	return &MemoryQuotaCounter{counters: make(map[string]memoryCount), clock: memoryClock(opts)}
+}
+
+func (c *MemoryQuotaCounter) Add(ctx context.Context, key string, n int64, expiry time.Time) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	count, ok := c.counters[key]
+	if !ok || !now.Before(count.expiry) {
+		count = memoryCount{expiry: expiry}
+		c.sweep(now)
+	}
+	count.value += n
+	c.counters[key] = count
+	return count.value, nil
+}
+
+// sweep drops expired counters, at most once a minute; the caller must
+// hold c.mu
+func (c *MemoryQuotaCounter) sweep(now time.Time) {
+	if now.Before(c.nextSweep) {
+		return
+	}
+	for key, count := range c.counters {
+		if !now.Before(count.expiry) {
+			delete(c.counters, key)
+		}
+	}
+	c.nextSweep = now.Add(time.Minute)
+}
+
+// RedisCounterClient is the Redis command RedisQuotaCounter needs. With
+// go-redis it is INCRBY and EXPIREAT NX in one transaction, e.g.
+//
+//	func (c client) IncrBy(ctx context.Context, key string, n int64, expiry time.Time) (int64, error) {
+//		var incr *redis.IntCmd
+//		_, err := c.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+//			incr = p.IncrBy(ctx, key, n)
+//			p.ExpireNX(ctx, key, time.Until(expiry))
+//			return nil
+//		})
+//		return incr.Val(), err
+//	}
+type RedisCounterClient interface {
+	IncrBy(ctx context.Context, key string, n int64, expiry time.Time) (int64, error)
+}
+
+// RedisQuotaCounter keeps quota counters as Redis keys, so every instance
+// enforces the same quotas
+type RedisQuotaCounter struct {
+	client RedisCounterClient
+	prefix string
+}
+
+// NewRedisQuotaCounter creates a counter stored under the key prefix+key
+func NewRedisQuotaCounter(client RedisCounterClient, prefix string) *RedisQuotaCounter {
+	return &RedisQuotaCounter{client: client, prefix: prefix}
+}
+
+func (c *RedisQuotaCounter) Add(ctx context.Context, key string, n int64, expiry time.Time) (int64, error) {
+	return c.client.IncrBy(ctx, c.prefix+key, n, expiry)
+}
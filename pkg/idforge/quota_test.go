@@ -0,0 +1,187 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+// failingQuotaCounter fails for keys containing broken
+type failingQuotaCounter struct {
+	*MemoryQuotaCounter
+	broken string
+}
+
+func (c *failingQuotaCounter) Add(ctx context.Context, key string, n int64, expiry time.Time) (int64, error) {
+	if strings.Contains(key, c.broken) {
+		return 0, errors.New("counter down")
+	}
+	return c.MemoryQuotaCounter.Add(ctx, key, n, expiry)
+}
+
+func TestQuotaManagerConsume(t *testing.T) {
+	m := NewQuotaManager(NewMemoryQuotaCounter())
+	m.SetQuota("acme", Quota{Limit: 5, Period: QuotaDaily}, Quota{Limit: 8, Period: QuotaMonthly})
+	ctx := context.Background()
+
+	if err := m.Consume(ctx, "acme", 5); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err := m.Consume(ctx, "acme", 1)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Expected ErrQuotaExceeded, got %v", err)
+	}
+
+	usage, err := m.Usage(ctx, "acme")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(usage) != 2 || usage[0].Used != 5 || usage[1].Used != 5 {
+		t.Errorf("Expected a refused call to charge nothing, got %+v", usage)
+	}
+	if !usage[0].ResetsAt.After(time.Now()) || usage[1].ResetsAt.Before(usage[0].ResetsAt) {
+		t.Errorf("Expected the monthly quota to reset no earlier than the daily one, got %+v", usage)
+	}
+
+	if err := m.Consume(ctx, "other", 1000); err != nil {
+		t.Errorf("Expected callers without quotas to be unlimited, got %v", err)
+	}
+	m.SetDefaultQuota(Quota{Limit: 1, Period: QuotaDaily})
+	if err := m.Consume(ctx, "new", 2); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected the default quota to apply, got %v", err)
+	}
+	m.SetQuota("acme")
+	if err := m.Consume(ctx, "acme", 1); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected acme to fall back to the default quota, got %v", err)
+	}
+}
+
+func TestQuotaManagerCounterError(t *testing.T) {
+	counter := &failingQuotaCounter{MemoryQuotaCounter: NewMemoryQuotaCounter(), broken: "monthly"}
+	m := NewQuotaManager(counter)
+	m.SetDefaultQuota(Quota{Limit: 5, Period: QuotaDaily}, Quota{Limit: 5, Period: QuotaMonthly})
+
+	if err := m.Consume(context.Background(), "acme", 1); err == nil || errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected the counter error, got %v", err)
+	}
+	key, end := quotaKey("acme", QuotaDaily, time.Now())
+	if used, _ := counter.MemoryQuotaCounter.Add(context.Background(), key, 0, end); used != 0 {
+		t.Errorf("Expected the daily charge to be released, got %d", used)
+	}
+}
+
+func TestQuotaPeriodWindow(t *testing.T) {
+	now := time.Date(2024, 2, 29, 23, 30, 0, 0, time.UTC)
+	if window, end := QuotaDaily.window(now); window != "2024-02-29" || !end.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the 29th ending on March 1st, got %s ending %v", window, end)
+	}
+	if window, end := QuotaMonthly.window(now); window != "2024-02" || !end.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected February ending on March 1st, got %s ending %v", window, end)
+	}
+}
+
+func TestMemoryQuotaCounterExpiry(t *testing.T) {
+	c := NewMemoryQuotaCounter()
+	ctx := context.Background()
+	c.Add(ctx, "a", 3, time.Now().Add(20*time.Millisecond))
+	time.Sleep(30 * time.Millisecond)
+	if value, _ := c.Add(ctx, "a", 1, time.Now().Add(time.Minute)); value != 1 {
+		t.Errorf("Expected an expired counter to restart, got %d", value)
+	}
+}
+
+func TestWithQuota(t *testing.T) {
+	m := NewQuotaManager(NewMemoryQuotaCounter())
+	m.SetQuota("acme", Quota{Limit: 3, Period: QuotaDaily})
+	gen := NewExtendedGenerator(WithQuota(m))
+	ctx := NewCallerContext(context.Background(), "acme")
+
+	if caller, ok := CallerFromContext(ctx); !ok || caller != "acme" {
+		t.Errorf("Expected caller acme, got %q", caller)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := gen.Generate(ctx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	_, err := gen.Generate(ctx)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Expected ErrQuotaExceeded, got %v", err)
+	}
+	if code := ErrorCode(err); code != CodeQuotaExceeded {
+		t.Errorf("Expected %s, got %s", CodeQuotaExceeded, code)
+	}
+
+	if _, err := gen.GenerateBulk(context.Background(), 10, 2); err != nil {
+		t.Errorf("Expected calls without a caller to be unlimited, got %v", err)
+	}
+	m.SetQuota("bulk", Quota{Limit: 10, Period: QuotaDaily})
+	bulkCtx := NewCallerContext(context.Background(), "bulk")
+	if _, err := gen.GenerateBulk(bulkCtx, 11, 2); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected a batch over the quota to fail, got %v", err)
+	}
+	if _, err := gen.GenerateBulk(bulkCtx, 10, 2); err != nil {
+		t.Errorf("Expected a batch within the quota to succeed, got %v", err)
+	}
+}
+
+func TestQuotaRefundOnFailure(t *testing.T) {
+	m := NewQuotaManager(NewMemoryQuotaCounter())
+	m.SetDefaultQuota(Quota{Limit: 10, Period: QuotaDaily})
+	broken := &flakyEntropy{}
+	broken.failing.Store(true)
+	gen := NewExtendedGenerator(WithQuota(m), WithEntropyProviders([]entropy.EntropyProvider{broken}))
+
+	if _, err := gen.Generate(context.Background()); err == nil {
+		t.Fatal("Expected generation to fail")
+	}
+	usage, _ := m.Usage(context.Background(), "")
+	if usage[0].Used != 0 {
+		t.Errorf("Expected a failed call to be refunded, got %d used", usage[0].Used)
+	}
+
+	gen = NewExtendedGenerator(WithQuota(m), WithAuditor(failingAuditor{}))
+	if _, err := gen.Generate(context.Background()); err == nil {
+		t.Fatal("Expected auditing to fail")
+	}
+	usage, _ = m.Usage(context.Background(), "")
+	if usage[0].Used != 0 {
+		t.Errorf("Expected a call failing its audit to be refunded, got %d used", usage[0].Used)
+	}
+}
+
+// keyRecordingCounter records the keys it is asked to add to
+type keyRecordingCounter struct {
+	*MemoryQuotaCounter
+	keys []string
+}
+
+func (c *keyRecordingCounter) Add(ctx context.Context, key string, n int64, expiry time.Time) (int64, error) {
+	c.keys = append(c.keys, key)
+	return c.MemoryQuotaCounter.Add(ctx, key, n, expiry)
+}
+
+func TestQuotaRefundUsesChargedWindow(t *testing.T) {
+	counter := &keyRecordingCounter{MemoryQuotaCounter: NewMemoryQuotaCounter()}
+	m := NewQuotaManager(counter)
+	m.SetDefaultQuota(Quota{Limit: 10, Period: QuotaDaily})
+	ctx := context.Background()
+
+	charge, err := m.charge(ctx, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Charged before midnight, refunded after it and after a quota change
+	charge.at = charge.at.Add(-24 * time.Hour)
+	charged, _ := quotaKey("", QuotaDaily, charge.at)
+	m.SetDefaultQuota(Quota{Limit: 10, Period: QuotaMonthly})
+
+	charge.refund(ctx, 1)
+	if got := counter.keys[len(counter.keys)-1]; got != charged {
+		t.Errorf("Expected the refund to reach the charged window %s, got %s", charged, got)
+	}
+}
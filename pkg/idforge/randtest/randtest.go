@@ -0,0 +1,290 @@
+// Package randtest runs NIST SP 800-22 style statistical tests over batches
+// of generated identifiers.
+//
+// Identifiers are first converted into a bit stream: every character is
+// mapped to its index in the alphabet and, for an alphabet of N characters,
+// indices below the largest power of two 2^k <= N contribute k bits. Indices
+// at or above 2^k are skipped, which keeps the bit stream unbiased for
+// alphabets whose size is not a power of two.
+package randtest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DefaultSignificance is the significance level recommended by SP 800-22
+const DefaultSignificance = 0.01
+
+// MinBits is the smallest bit stream the tests accept
+const MinBits = 100
+
+var (
+	ErrInvalidAlphabet  = errors.New("alphabet must contain at least 2 unique characters")
+	ErrUnknownCharacter = errors.New("ID contains a character outside the alphabet")
+	ErrInsufficientData = errors.New("not enough bits for statistical testing")
+)
+
+// Result is the outcome of a single statistical test
+type Result struct {
+	Name    string
+	PValues []float64
+	Passed  bool
+}
+
+// Report collects the results of all tests run over a batch of IDs
+type Report struct {
+	IDs          int
+	Bits         int
+	Significance float64
+	Results      []Result
+}
+
+// Passed reports whether every test passed
+func (r Report) Passed() bool {
+	for _, result := range r.Results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders the report as a human-readable table
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "IDs: %d, bits: %d, significance: %g\n", r.IDs, r.Bits, r.Significance)
+	for _, result := range r.Results {
+		status := "PASS"
+		if !result.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(&b, "%-10s %s", result.Name, status)
+		for _, p := range result.PValues {
+			fmt.Fprintf(&b, " p=%.6f", p)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Run converts ids into a bit stream and runs the frequency, runs and
+// serial tests at the default significance level
+func Run(ids []string, alphabet string) (Report, error) {
+	bits, err := Bits(ids, alphabet)
+	if err != nil {
+		return Report{}, err
+	}
+	if len(bits) < MinBits {
+		return Report{}, ErrInsufficientData
+	}
+
+	report := Report{
+		IDs:          len(ids),
+		Bits:         len(bits),
+		Significance: DefaultSignificance,
+	}
+
+	// SP 800-22 requires m < floor(log2 n) - 2 for the serial test
+	m := 3
+	if maxM := int(math.Log2(float64(len(bits)))) - 3; m > maxM {
+		m = maxM
+	}
+
+	report.Results = []Result{
+		Frequency(bits, report.Significance),
+		Runs(bits, report.Significance),
+		Serial(bits, m, report.Significance),
+	}
+	return report, nil
+}
+
+// RunGenerator generates n IDs with generate and runs the test suite over them
+func RunGenerator(n int, alphabet string, generate func() (string, error)) (Report, error) {
+	ids := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		id, err := generate()
+		if err != nil {
+			return Report{}, err
+		}
+		ids = append(ids, id)
+	}
+	return Run(ids, alphabet)
+}
+
+// Bits converts ids into an unbiased stream of 0/1 values
+func Bits(ids []string, alphabet string) ([]uint8, error) {
+	symbols := []rune(alphabet)
+	index := make(map[rune]int, len(symbols))
+	for i, r := range symbols {
+		if _, dup := index[r]; dup {
+			return nil, ErrInvalidAlphabet
+		}
+		index[r] = i
+	}
+	if len(symbols) < 2 {
+		return nil, ErrInvalidAlphabet
+	}
+
+	k := int(math.Floor(math.Log2(float64(len(symbols)))))
+	limit := 1 << k
+
+	var bits []uint8
+	for _, id := range ids {
+		for _, r := range id {
+			i, ok := index[r]
+			if !ok {
+				return nil, ErrUnknownCharacter
+			}
+			if i >= limit {
+				continue
+			}
+			for shift := k - 1; shift >= 0; shift-- {
+				bits = append(bits, uint8(i>>shift)&1)
+			}
+		}
+	}
+	return bits, nil
+}
+
+// Frequency runs the monobit frequency test (SP 800-22 section 2.1)
+func Frequency(bits []uint8, significance float64) Result {
+	n := float64(len(bits))
+	sum := 0.0
+	for _, b := range bits {
+		sum += 2*float64(b) - 1
+	}
+
+	sObs := math.Abs(sum) / math.Sqrt(n)
+	p := math.Erfc(sObs / math.Sqrt2)
+	return Result{Name: "frequency", PValues: []float64{p}, Passed: p >= significance}
+}
+
+// Runs runs the runs test (SP 800-22 section 2.3)
+func Runs(bits []uint8, significance float64) Result {
+	n := float64(len(bits))
+	ones := 0.0
+	for _, b := range bits {
+		ones += float64(b)
+	}
+	pi := ones / n
+
+	// The test is not applicable when the frequency test would already fail
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(n) {
+		return Result{Name: "runs", PValues: []float64{0}, Passed: false}
+	}
+
+	runs := 1.0
+	for i := 1; i < len(bits); i++ {
+		if bits[i] != bits[i-1] {
+			runs++
+		}
+	}
+
+	p := math.Erfc(math.Abs(runs-2*n*pi*(1-pi)) / (2 * math.Sqrt(2*n) * pi * (1 - pi)))
+	return Result{Name: "runs", PValues: []float64{p}, Passed: p >= significance}
+}
+
+// Serial runs the serial test for m-bit patterns (SP 800-22 section 2.11)
+func Serial(bits []uint8, m int, significance float64) Result {
+	if m < 2 {
+		m = 2
+	}
+
+	psiM := psiSquared(bits, m)
+	psiM1 := psiSquared(bits, m-1)
+	psiM2 := psiSquared(bits, m-2)
+
+	delta1 := psiM - psiM1
+	delta2 := psiM - 2*psiM1 + psiM2
+
+	p1 := igamc(math.Pow(2, float64(m-2)), delta1/2)
+	p2 := igamc(math.Pow(2, float64(m-3)), delta2/2)
+	return Result{
+		Name:    "serial",
+		PValues: []float64{p1, p2},
+		Passed:  p1 >= significance && p2 >= significance,
+	}
+}
+
+// psiSquared computes the ψ²ₘ statistic over overlapping m-bit blocks,
+// wrapping around the end of the sequence
+func psiSquared(bits []uint8, m int) float64 {
+	if m <= 0 {
+		return 0
+	}
+
+	n := len(bits)
+	counts := make([]float64, 1<<m)
+	for i := 0; i < n; i++ {
+		pattern := 0
+		for j := 0; j < m; j++ {
+			pattern = pattern<<1 | int(bits[(i+j)%n])
+		}
+		counts[pattern]++
+	}
+
+	sum := 0.0
+	for _, c := range counts {
+		sum += c * c
+	}
+	return sum*math.Pow(2, float64(m))/float64(n) - float64(n)
+}
+
+// igamc computes the regularized upper incomplete gamma function Q(a, x)
+func igamc(a, x float64) float64 {
+	if x <= 0 || a <= 0 {
+		return 1
+	}
+	if x < a+1 {
+		return 1 - gammaSeries(a, x)
+	}
+	return gammaContinuedFraction(a, x)
+}
+
+// gammaSeries evaluates P(a, x) by its series representation
+func gammaSeries(a, x float64) float64 {
+	lgamma, _ := math.Lgamma(a)
+	sum := 1 / a
+	term := sum
+	for n := 1; n < 500; n++ {
+		term *= x / (a + float64(n))
+		sum += term
+		if math.Abs(term) < math.Abs(sum)*1e-15 {
+			break
+		}
+	}
+	return sum * math.Exp(-x+a*math.Log(x)-lgamma)
+}
+
+// gammaContinuedFraction evaluates Q(a, x) with Lentz's continued fraction
+func gammaContinuedFraction(a, x float64) float64 {
+	const tiny = 1e-300
+	lgamma, _ := math.Lgamma(a)
+
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < 500; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lgamma) * h
+}
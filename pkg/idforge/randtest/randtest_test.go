@@ -0,0 +1,113 @@
+package randtest
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mrityunjay-vashisth/go-idforge/pkg/idforge"
+)
+
+// parseBits turns a string of '0'/'1' characters into a bit stream
+func parseBits(s string) []uint8 {
+	bits := make([]uint8, len(s))
+	for i, c := range s {
+		bits[i] = uint8(c - '0')
+	}
+	return bits
+}
+
+func assertPValue(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("%s: expected p-value %.6f, got %.6f", name, want, got)
+	}
+}
+
+// The expected values below are the worked examples from SP 800-22
+func TestFrequencyReferenceExample(t *testing.T) {
+	result := Frequency(parseBits("1011010101"), DefaultSignificance)
+	assertPValue(t, "frequency", result.PValues[0], 0.527089)
+}
+
+func TestRunsReferenceExample(t *testing.T) {
+	result := Runs(parseBits("1001101011"), DefaultSignificance)
+	assertPValue(t, "runs", result.PValues[0], 0.147232)
+}
+
+func TestSerialReferenceExample(t *testing.T) {
+	result := Serial(parseBits("0011011101"), 3, DefaultSignificance)
+	assertPValue(t, "serial p1", result.PValues[0], 0.808792)
+	assertPValue(t, "serial p2", result.PValues[1], 0.670320)
+}
+
+func TestBits(t *testing.T) {
+	// Six symbols: indices 0-3 yield two bits each, 4 and 5 are skipped
+	bits, err := Bits([]string{"abcdef"}, "abcdef")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []uint8{0, 0, 0, 1, 1, 0, 1, 1}
+	if len(bits) != len(want) {
+		t.Fatalf("Expected %d bits, got %d", len(want), len(bits))
+	}
+	for i := range want {
+		if bits[i] != want[i] {
+			t.Errorf("Bit %d: expected %d, got %d", i, want[i], bits[i])
+		}
+	}
+
+	if _, err := Bits([]string{"xyz"}, "abc"); !errors.Is(err, ErrUnknownCharacter) {
+		t.Errorf("Expected ErrUnknownCharacter, got %v", err)
+	}
+	if _, err := Bits(nil, "aa"); !errors.Is(err, ErrInvalidAlphabet) {
+		t.Errorf("Expected ErrInvalidAlphabet, got %v", err)
+	}
+}
+
+func TestRunGeneratedIDs(t *testing.T) {
+	gen := idforge.New()
+
+	report, err := RunGenerator(500, idforge.DefaultAlphabet, gen.Generate)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Logf("\n%s", report)
+	if len(report.Results) != 3 {
+		t.Errorf("Expected 3 test results, got %d", len(report.Results))
+	}
+
+	// A single batch can fail by chance at 1% significance per test,
+	// so only flag results that are wildly off
+	for _, result := range report.Results {
+		for _, p := range result.PValues {
+			if p < 1e-6 {
+				t.Errorf("%s test rejected generated IDs with p=%g", result.Name, p)
+			}
+		}
+	}
+}
+
+func TestRunDetectsBias(t *testing.T) {
+	ids := make([]string, 50)
+	for i := range ids {
+		ids[i] = strings.Repeat("0", 21)
+	}
+
+	report, err := Run(ids, "01")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.Passed() {
+		t.Errorf("Expected constant IDs to fail the test suite:\n%s", report)
+	}
+}
+
+func TestRunInsufficientData(t *testing.T) {
+	if _, err := Run([]string{"0101"}, "01"); !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("Expected ErrInsufficientData, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package idforge
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// Range is a contiguous run of sequence numbers leased to a client, such
+// as an offline-first app that assigns them without a connection and syncs
+// later. The numbers are the client's alone: no generator issues them
+// again, and they sort after every number issued before the lease.
+type Range struct {
+	Start uint64 `json:"start"`
+	Count uint64 `json:"count"`
+}
+
+// End returns the number just past the range
+func (r Range) End() uint64 {
+	return r.Start + r.Count
+}
+
+// Contains reports whether n belongs to the range, for checking numbers a
+// client reports at sync time
+func (r Range) Contains(n uint64) bool {
+	return n >= r.Start && n < r.End()
+}
+
+// All yields the numbers of the range in increasing order
+func (r Range) All() iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for n := r.Start; n < r.End(); n++ {
+			if !yield(n) {
+				return
+			}
+		}
+	}
+}
+
+// String writes the range as [start, end)
+func (r Range) String() string {
+	return fmt.Sprintf("[%d, %d)", r.Start, r.End())
+}
+
+// AllocateRange leases n consecutive numbers to the caller, fetched from
+// the generator's source as a block of their own, so they never interleave
+// with the numbers Next issues
+func (g *SequenceGenerator) AllocateRange(ctx context.Context, n int) (Range, error) {
+	if n < 1 {
+		return Range{}, fmt.Errorf("%w: range needs at least one number", ErrInvalidConfig)
+	}
+	block, err := g.source.AllocateBlock(ctx, uint64(n))
+	if err != nil {
+		return Range{}, err
+	}
+	if block.Size != uint64(n) {
+		return Range{}, fmt.Errorf("sequence source returned %d numbers, asked for %d", block.Size, n)
+	}
+	return Range{Start: block.Start, Count: block.Size}, nil
+}
@@ -0,0 +1,68 @@
+package idforge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestAllocateRange(t *testing.T) {
+	ctx := context.Background()
+	leader := NewSequencer("a", NewMemoryLeaderLock(), NewMemorySequenceStore(), WithSequencerChunk(100))
+	gen, _ := NewSequenceGenerator(leader, 10)
+
+	before, _ := gen.Next(ctx)
+	r, err := gen.AllocateRange(ctx, 25)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r.Count != 25 || r.Start <= before {
+		t.Errorf("Expected 25 numbers above %d, got %s", before, r)
+	}
+
+	// Numbers issued later never fall inside the leased range
+	for i := 0; i < 50; i++ {
+		n, err := gen.Next(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if r.Contains(n) {
+			t.Fatalf("Expected %d to stay outside the leased range %s", n, r)
+		}
+	}
+
+	numbers := slices.Collect(r.All())
+	if len(numbers) != 25 || numbers[0] != r.Start || numbers[24] != r.End()-1 {
+		t.Errorf("Expected %s in order, got %v", r, numbers)
+	}
+
+	if _, err := gen.AllocateRange(ctx, 0); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig, got %v", err)
+	}
+	follower, _ := NewSequenceGenerator(NewSequencer("b", NewMemoryLeaderLock(), failingStore{ErrNotLeader}), 10)
+	if _, err := follower.AllocateRange(ctx, 5); !errors.Is(err, ErrNotLeader) {
+		t.Errorf("Expected the source error, got %v", err)
+	}
+}
+
+func TestRange(t *testing.T) {
+	r := Range{Start: 100, Count: 3}
+	if r.End() != 103 || r.String() != "[100, 103)" {
+		t.Errorf("Expected [100, 103), got %s", r)
+	}
+	if !r.Contains(100) || !r.Contains(102) || r.Contains(103) || r.Contains(99) {
+		t.Error("Expected Contains to cover exactly 100 to 102")
+	}
+	for n := range r.All() {
+		if n == 101 {
+			break
+		}
+	}
+
+	data, _ := json.Marshal(r)
+	if string(data) != `{"start":100,"count":3}` {
+		t.Errorf("Expected the JSON a client receives, got %s", data)
+	}
+}
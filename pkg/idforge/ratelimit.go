@@ -0,0 +1,98 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+var ErrRateLimited = errors.New("ID generation rate limit exceeded")
+
+// WithRateLimit throttles generation to perSecond IDs with the given burst.
+// By default calls over the limit fail with ErrRateLimited; combine with
+// WithRateLimitWait to block until a token is available instead.
+func WithRateLimit(perSecond float64, burst int) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		if perSecond > 0 {
+			c.RateLimit = perSecond
+			c.RateBurst = burst
+		}
+	}
+}
+
+// WithRateLimitWait makes rate-limited calls wait for capacity
+func WithRateLimitWait() func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.RateLimitWait = true
+	}
+}
+
+// rateLimiter is a token bucket refilled continuously at rate tokens per second
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter builds a limiter, or returns nil when rate limiting is disabled
+func newRateLimiter(perSecond float64, burst int) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token if one is available, otherwise it reports how long
+// the caller has to wait for the next one
+func (r *rateLimiter) reserve() (bool, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.tokens = math.Min(r.burst, r.tokens+now.Sub(r.last).Seconds()*r.rate)
+	r.last = now
+
+	if r.tokens >= 1 {
+		r.tokens--
+		return true, 0
+	}
+
+	missing := 1 - r.tokens
+	return false, time.Duration(missing / r.rate * float64(time.Second))
+}
+
+// allow reports whether a token could be taken without waiting
+func (r *rateLimiter) allow() bool {
+	ok, _ := r.reserve()
+	return ok
+}
+
+// wait blocks until a token is taken or the context is done
+func (r *rateLimiter) wait(ctx context.Context) error {
+	for {
+		ok, delay := r.reserve()
+		if ok {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ErrRateLimited
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithRateLimitRejects(t *testing.T) {
+	gen := NewExtendedGenerator(WithRateLimit(1, 2))
+	ctx := context.Background()
+
+	// The burst allows two IDs immediately
+	for i := 0; i < 2; i++ {
+		if _, err := gen.Generate(ctx); err != nil {
+			t.Fatalf("Unexpected error within burst: %v", err)
+		}
+	}
+
+	if _, err := gen.Generate(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestWithRateLimitWait(t *testing.T) {
+	gen := NewExtendedGenerator(WithRateLimit(20, 1), WithRateLimitWait())
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := gen.Generate(ctx); err != nil {
+			t.Fatalf("Unexpected error while waiting for capacity: %v", err)
+		}
+	}
+
+	// Two of the three calls had to wait roughly 50ms each
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Expected throttled generation to take at least 80ms, took %v", elapsed)
+	}
+}
+
+func TestWithRateLimitWaitContextCancelled(t *testing.T) {
+	gen := NewExtendedGenerator(WithRateLimit(0.1, 1), WithRateLimitWait())
+
+	if _, err := gen.Generate(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := gen.Generate(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited after cancellation, got %v", err)
+	}
+}
+
+func TestWithRateLimitInvalid(t *testing.T) {
+	gen := NewExtendedGenerator(WithRateLimit(-1, 5))
+
+	if gen.limiter != nil {
+		t.Errorf("Expected negative rate to leave rate limiting disabled")
+	}
+}
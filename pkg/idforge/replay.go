@@ -0,0 +1,48 @@
+package idforge
+
+import (
+	"context"
+	"time"
+)
+
+// RuleReplayed is reported in validation violations for IDs presented
+// before, when a ReplayGuard is in use
+const RuleReplayed = "replayed"
+
+// ReplayGuard remembers IDs presented to it for a while, so one-time IDs
+// such as tokens or nonces are accepted only once. Any UniquenessStore
+// works as its memory; a Redis store shares it between instances.
+type ReplayGuard struct {
+	store UniquenessStore
+	ttl   time.Duration
+}
+
+// NewReplayGuard creates a guard that remembers IDs in store for ttl, 0
+// remembering them for good. A nil store selects an in-memory store.
+func NewReplayGuard(store UniquenessStore, ttl time.Duration) *ReplayGuard {
+	if store == nil {
+		store = NewMemoryUniquenessStore()
+	}
+	return &ReplayGuard{store: store, ttl: ttl}
+}
+
+// Check records id as presented and reports whether it had already been
+// presented within the guard's ttl. Concurrent checks of the same ID see
+// exactly one first presentation.
+func (r *ReplayGuard) Check(ctx context.Context, id string) (bool, error) {
+	first, err := r.store.Reserve(ctx, id, r.ttl)
+	if err != nil {
+		return false, err
+	}
+	return !first, nil
+}
+
+// WithReplayProtection makes Check and Validate accept each ID only once
+// within the guard's ttl. An ID is only recorded once it passes every
+// other rule, so a malformed or revoked ID does not use it up. Validation
+// fails closed when the guard's store cannot be queried.
+func WithReplayProtection(guard *ReplayGuard) Option {
+	return func(g *Generator) {
+		g.replays = guard
+	}
+}
@@ -0,0 +1,104 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReplayGuardCheck(t *testing.T) {
+	guard := NewReplayGuard(nil, 20*time.Millisecond)
+	ctx := context.Background()
+
+	if replayed, err := guard.Check(ctx, "abc"); err != nil || replayed {
+		t.Fatalf("Expected the first presentation to pass, got %v, %v", replayed, err)
+	}
+	if replayed, _ := guard.Check(ctx, "abc"); !replayed {
+		t.Error("Expected the second presentation to be a replay")
+	}
+	if replayed, _ := guard.Check(ctx, "def"); replayed {
+		t.Error("Expected another ID to pass")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if replayed, _ := guard.Check(ctx, "abc"); replayed {
+		t.Error("Expected an ID to be accepted again after the ttl")
+	}
+}
+
+func TestReplayGuardConcurrent(t *testing.T) {
+	guard := NewReplayGuard(nil, time.Minute)
+	var first atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if replayed, _ := guard.Check(context.Background(), "token"); !replayed {
+				first.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if first.Load() != 1 {
+		t.Errorf("Expected exactly one first presentation, got %d", first.Load())
+	}
+}
+
+func TestWithReplayProtection(t *testing.T) {
+	revoked := NewRevocationList(nil)
+	gen := New(WithReplayProtection(NewReplayGuard(nil, time.Minute)), WithRevocationCheck(revoked))
+	id := gen.MustGenerate()
+
+	if gen.Validate("not valid!") {
+		t.Error("Expected an invalid ID to be rejected")
+	}
+	if err := gen.Check(id); err != nil {
+		t.Fatalf("Expected the first use to pass, got %v", err)
+	}
+	err := gen.Check(id)
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Violations()[0].Rule != RuleReplayed {
+		t.Fatalf("Expected a replayed violation, got %v", err)
+	}
+	if code := ErrorCode(err); code != CodeReplayed {
+		t.Errorf("Expected code %s, got %s", CodeReplayed, code)
+	}
+
+	// A revoked ID is rejected without being used up
+	other := gen.MustGenerate()
+	revoked.Add(context.Background(), other)
+	gen.Validate(other)
+	revoked.Remove(context.Background(), other)
+	if !gen.Validate(other) {
+		t.Error("Expected a revoked presentation not to use up the ID")
+	}
+}
+
+func TestWithReplayProtectionStoreError(t *testing.T) {
+	gen := New(WithReplayProtection(NewReplayGuard(failingUniquenessStore{}, time.Minute)))
+	err := gen.Check(gen.MustGenerate())
+	if err == nil || errors.Is(err, ErrValidation) {
+		t.Errorf("Expected the store error, got %v", err)
+	}
+}
+
+func TestMemoryUniquenessStoreSweep(t *testing.T) {
+	store := NewMemoryUniquenessStore()
+	ctx := context.Background()
+	store.Reserve(ctx, "old", time.Millisecond)
+	store.Reserve(ctx, "kept", 0)
+	time.Sleep(2 * time.Millisecond)
+
+	store.nextSweep = time.Time{}
+	store.Reserve(ctx, "new", time.Minute)
+	if _, ok := store.reserved["old"]; ok {
+		t.Error("Expected the expired reservation to be swept")
+	}
+	if len(store.reserved) != 2 {
+		t.Errorf("Expected 2 reservations left, got %d", len(store.reserved))
+	}
+}
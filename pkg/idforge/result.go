@@ -0,0 +1,25 @@
+package idforge
+
+import "time"
+
+// Random sources reported in GenResult
+const (
+	RandomSourceCrypto = "crypto/rand"
+	RandomSourceDRBG   = "drbg"
+	RandomSourceCustom = "custom" // An RNG injected with WithRNG
+)
+
+// GenResult describes a generated ID and how it was made
+type GenResult struct {
+	ID            string
+	Generator     string    // The configured Name, if any
+	GeneratedAt   time.Time // When the ID passed its uniqueness checks
+	Attempts      int       // Candidates drawn, including rejected collisions
+	RandomSource  string    // RandomSourceCrypto, RandomSourceDRBG or RandomSourceCustom
+	FormatVersion rune      // Zero unless WithFormatVersion is set
+
+	// EntropySources names the providers mixed into this ID. It is empty
+	// with a DRBG, which only queries providers when reseeding, with an
+	// injected RNG and in FIPS mode.
+	EntropySources []string
+}
@@ -0,0 +1,76 @@
+package idforge
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+func TestGenerateWithInfo(t *testing.T) {
+	gen := NewExtendedGenerator(
+		WithName("orders"),
+		WithFormatVersion('v'),
+		WithEntropyProviders([]entropy.EntropyProvider{&entropy.TimestampEntropy{}}),
+	)
+
+	before := time.Now()
+	result, err := gen.GenerateWithInfo(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(result.ID) != DefaultSize+1 || result.ID[0] != 'v' {
+		t.Errorf("Expected a versioned ID, got %q", result.ID)
+	}
+	if result.Generator != "orders" {
+		t.Errorf("Expected generator orders, got %q", result.Generator)
+	}
+	if result.FormatVersion != 'v' {
+		t.Errorf("Expected format version v, got %q", result.FormatVersion)
+	}
+	if result.Attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", result.Attempts)
+	}
+	if result.GeneratedAt.Before(before) || result.GeneratedAt.After(time.Now()) {
+		t.Errorf("Expected a generation time after %v, got %v", before, result.GeneratedAt)
+	}
+	if result.RandomSource != RandomSourceCrypto {
+		t.Errorf("Expected random source %s, got %s", RandomSourceCrypto, result.RandomSource)
+	}
+	if want := []string{"*entropy.TimestampEntropy"}; !reflect.DeepEqual(result.EntropySources, want) {
+		t.Errorf("Expected entropy sources %v, got %v", want, result.EntropySources)
+	}
+	if stats := gen.Stats(); stats.Generated != 1 {
+		t.Errorf("Expected 1 generated ID in stats, got %d", stats.Generated)
+	}
+}
+
+func TestGenerateWithInfoDRBG(t *testing.T) {
+	gen := NewExtendedGenerator(WithDRBG(time.Hour))
+
+	result, err := gen.GenerateWithInfo(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.RandomSource != RandomSourceDRBG {
+		t.Errorf("Expected random source %s, got %s", RandomSourceDRBG, result.RandomSource)
+	}
+	if len(result.EntropySources) != 0 {
+		t.Errorf("Expected no per-ID entropy sources, got %v", result.EntropySources)
+	}
+}
+
+func TestGenerateWithInfoError(t *testing.T) {
+	gen := NewExtendedGenerator(WithEntropyProviders([]entropy.EntropyProvider{failingEntropy{}}))
+
+	result, err := gen.GenerateWithInfo(context.Background())
+	if err == nil {
+		t.Fatal("Expected an error from a failing provider")
+	}
+	if !reflect.DeepEqual(result, GenResult{}) {
+		t.Errorf("Expected an empty result, got %+v", result)
+	}
+}
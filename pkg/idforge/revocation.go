@@ -0,0 +1,85 @@
+package idforge
+
+import (
+	"context"
+	"sync"
+)
+
+// RevocationStore persists revoked identifiers
+type RevocationStore interface {
+	Add(ctx context.Context, id string) error
+	Contains(ctx context.Context, id string) (bool, error)
+	Remove(ctx context.Context, id string) error
+}
+
+// RevocationList tracks IDs that must no longer be accepted
+type RevocationList struct {
+	store RevocationStore
+}
+
+// NewRevocationList creates a revocation list backed by store.
+// A nil store selects an in-memory store.
+func NewRevocationList(store RevocationStore) *RevocationList {
+	if store == nil {
+		store = NewMemoryRevocationStore()
+	}
+	return &RevocationList{store: store}
+}
+
+// Add revokes an ID
+func (l *RevocationList) Add(ctx context.Context, id string) error {
+	return l.store.Add(ctx, id)
+}
+
+// Contains reports whether an ID has been revoked
+func (l *RevocationList) Contains(ctx context.Context, id string) (bool, error) {
+	return l.store.Contains(ctx, id)
+}
+
+// Remove reinstates a previously revoked ID
+func (l *RevocationList) Remove(ctx context.Context, id string) error {
+	return l.store.Remove(ctx, id)
+}
+
+// WithRevocationCheck makes Validate reject revoked IDs.
+// Validation fails closed when the revocation store cannot be queried.
+func WithRevocationCheck(list *RevocationList) Option {
+	return func(g *Generator) {
+		g.revocations = list
+	}
+}
+
+// MemoryRevocationStore keeps revoked IDs in process memory
+type MemoryRevocationStore struct {
+	mu  sync.RWMutex
+	ids map[string]struct{}
+}
+
+// NewMemoryRevocationStore creates an empty in-memory store
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{ids: make(map[string]struct{})}
+}
+
+func (s *MemoryRevocationStore) Add(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ids[id] = struct{}{}
+	return nil
+}
+
+func (s *MemoryRevocationStore) Contains(ctx context.Context, id string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.ids[id]
+	return ok, nil
+}
+
+func (s *MemoryRevocationStore) Remove(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.ids, id)
+	return nil
+}
@@ -0,0 +1,62 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type unavailableRevocationStore struct {
+	MemoryRevocationStore
+}
+
+func (s *unavailableRevocationStore) Contains(ctx context.Context, id string) (bool, error) {
+	return false, errors.New("store unavailable")
+}
+
+func TestRevocationList(t *testing.T) {
+	list := NewRevocationList(nil)
+	ctx := context.Background()
+
+	if err := list.Add(ctx, "abc"); err != nil {
+		t.Fatalf("Unexpected error adding ID: %v", err)
+	}
+
+	revoked, err := list.Contains(ctx, "abc")
+	if err != nil || !revoked {
+		t.Errorf("Expected 'abc' to be revoked, got %v (err %v)", revoked, err)
+	}
+
+	if err := list.Remove(ctx, "abc"); err != nil {
+		t.Fatalf("Unexpected error removing ID: %v", err)
+	}
+
+	revoked, _ = list.Contains(ctx, "abc")
+	if revoked {
+		t.Errorf("Expected 'abc' to be reinstated after removal")
+	}
+}
+
+func TestValidateWithRevocationCheck(t *testing.T) {
+	list := NewRevocationList(NewMemoryRevocationStore())
+	gen := New(WithRevocationCheck(list))
+
+	id := gen.MustGenerate()
+	if !gen.Validate(id) {
+		t.Fatalf("Expected fresh ID %s to validate", id)
+	}
+
+	list.Add(context.Background(), id)
+	if gen.Validate(id) {
+		t.Errorf("Expected revoked ID %s to fail validation", id)
+	}
+}
+
+func TestValidateRevocationStoreFailure(t *testing.T) {
+	list := NewRevocationList(&unavailableRevocationStore{})
+	gen := New(WithRevocationCheck(list))
+
+	if gen.Validate(gen.MustGenerate()) {
+		t.Errorf("Expected validation to fail closed when the store errors")
+	}
+}
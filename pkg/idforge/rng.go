@@ -0,0 +1,77 @@
+package idforge
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+var ErrConflictingRNG = fmt.Errorf("%w: WithRNG cannot be combined with WithDRBG", ErrInvalidConfig)
+
+// RNG is a source of random bytes and uniformly distributed integers
+type RNG interface {
+	io.Reader
+
+	// Intn returns a uniformly random value in [0, n). It panics if n <= 0.
+	Intn(n int) (int, error)
+}
+
+// CryptoRNG draws from crypto/rand and is used unless WithRNG or WithDRBG
+// is set
+var CryptoRNG = NewRNG(rand.Reader)
+
+// NewRNG adapts a reader, such as an HSM client or a recorded byte stream,
+// into an RNG. Intn uses rejection sampling, so its values stay uniform as
+// long as the reader's bytes are.
+func NewRNG(r io.Reader) RNG {
+	return readerRNG{r}
+}
+
+type readerRNG struct {
+	io.Reader
+}
+
+func (r readerRNG) Intn(n int) (int, error) {
+	if n <= 0 {
+		panic("idforge: invalid argument to Intn")
+	}
+
+	// Reject values above the largest multiple of n so every residue is
+	// equally likely
+	bound := uint64(n)
+	limit := ^uint64(0) - ^uint64(0)%bound
+	var buf [8]byte
+	for {
+		if _, err := io.ReadFull(r.Reader, buf[:]); err != nil {
+			return 0, err
+		}
+		if v := binary.BigEndian.Uint64(buf[:]); v < limit {
+			return int(v % bound), nil
+		}
+	}
+}
+
+// WithRNG draws ID characters from r instead of crypto/rand. The injected
+// RNG replaces per-ID entropy mixing, as with WithDRBG, so a recorded
+// stream replays the same IDs. It cannot be combined with WithDRBG, and
+// FIPS mode rejects it as a non-approved component.
+func WithRNG(r RNG) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.RNG = r
+	}
+}
+
+// validateRNG rejects an injected RNG alongside another random source
+func (c GeneratorConfig) validateRNG() error {
+	if c.RNG == nil {
+		return nil
+	}
+	if c.DRBG {
+		return ErrConflictingRNG
+	}
+	if c.FIPSMode {
+		return ErrFIPSIncompatible
+	}
+	return nil
+}
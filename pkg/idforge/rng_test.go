@@ -0,0 +1,90 @@
+package idforge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestNewRNGIntn(t *testing.T) {
+	rng := NewRNG(bytes.NewReader([]byte{0, 0, 0, 0, 0, 0, 0, 7, 0, 0, 0, 0, 0, 0, 0, 12}))
+
+	for _, want := range []int{7, 2} {
+		v, err := rng.Intn(10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if v != want {
+			t.Errorf("Expected %d, got %d", want, v)
+		}
+	}
+
+	if _, err := rng.Intn(10); err != io.EOF {
+		t.Errorf("Expected io.EOF from an exhausted stream, got %v", err)
+	}
+}
+
+func TestNewRNGIntnRejectsBiasedValues(t *testing.T) {
+	// The all-ones value lies above the largest multiple of 10 and is skipped
+	stream := append(bytes.Repeat([]byte{0xff}, 8), 0, 0, 0, 0, 0, 0, 0, 3)
+	v, err := NewRNG(bytes.NewReader(stream)).Intn(10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v != 3 {
+		t.Errorf("Expected 3, got %d", v)
+	}
+}
+
+func TestCryptoRNGIntn(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		v, err := CryptoRNG.Intn(6)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if v < 0 || v >= 6 {
+			t.Fatalf("Expected a value in [0, 6), got %d", v)
+		}
+	}
+}
+
+func TestWithRNGReplays(t *testing.T) {
+	stream := make([]byte, 4096)
+	for i := range stream {
+		stream[i] = byte(i * 7)
+	}
+
+	generate := func() string {
+		gen := NewExtendedGenerator(WithRNG(NewRNG(bytes.NewReader(stream))))
+		result, err := gen.GenerateWithInfo(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.RandomSource != RandomSourceCustom {
+			t.Errorf("Expected random source %s, got %s", RandomSourceCustom, result.RandomSource)
+		}
+		return result.ID
+	}
+
+	first, second := generate(), generate()
+	if first != second {
+		t.Errorf("Expected a replayed stream to give the same ID, got %q and %q", first, second)
+	}
+}
+
+func TestWithRNGConflicts(t *testing.T) {
+	rng := NewRNG(bytes.NewReader(nil))
+
+	_, err := NewExtendedGenerator(WithRNG(rng), WithDRBG(time.Hour)).Generate(context.Background())
+	if !errors.Is(err, ErrConflictingRNG) {
+		t.Errorf("Expected ErrConflictingRNG, got %v", err)
+	}
+
+	_, err = NewExtendedGenerator(WithFIPSMode(), WithRNG(rng)).Generate(context.Background())
+	if !errors.Is(err, ErrFIPSIncompatible) {
+		t.Errorf("Expected ErrFIPSIncompatible, got %v", err)
+	}
+}
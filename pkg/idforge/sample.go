@@ -0,0 +1,107 @@
+package idforge
+
+import (
+	"encoding/binary"
+	"io"
+	"math/bits"
+)
+
+// sampleChunk bounds how many indices are drawn per batch, so callers can
+// check for cancellation between batches of very long IDs
+const sampleChunk = 256
+
+// sampleIndices fills dst with uniformly random indices below n read from r.
+// Alphabets of up to 256 characters use one masked byte per draw, read in
+// batches; larger alphabets fall back to rejection sampling on uint32 values.
+// Neither path allocates beyond its read buffer.
+func sampleIndices(r io.Reader, n int, dst []int) error {
+	if n <= 256 {
+		return sampleSmall(r, n, dst)
+	}
+	return sampleLarge(r, n, dst)
+}
+
+// sampleSmall draws byte-sized indices. Masking to the next power of two
+// keeps the rejection rate below one half.
+func sampleSmall(r io.Reader, n int, dst []int) error {
+	mask := byte(1<<bits.Len(uint(n-1)) - 1)
+
+	var buf [sampleChunk + sampleChunk/2 + 1]byte
+	for filled := 0; filled < len(dst); {
+		// Request a little more than needed to absorb rejections
+		want := min(len(dst)-filled, sampleChunk)
+		batch := buf[:want+want/2+1]
+		if _, err := io.ReadFull(r, batch); err != nil {
+			return err
+		}
+		for _, b := range batch {
+			if v := int(b & mask); v < n {
+				dst[filled] = v
+				filled++
+				if filled == len(dst) {
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sampleLarge draws uint32 values, rejecting those above the largest
+// multiple of n so every residue is equally likely
+func sampleLarge(r io.Reader, n int, dst []int) error {
+	bound := uint32(n)
+	limit := ^uint32(0) - ^uint32(0)%bound
+
+	var buf [4 * sampleChunk]byte
+	for filled := 0; filled < len(dst); {
+		want := min(len(dst)-filled, sampleChunk)
+		batch := buf[:4*want]
+		if _, err := io.ReadFull(r, batch); err != nil {
+			return err
+		}
+		for i := 0; i < len(batch) && filled < len(dst); i += 4 {
+			if v := binary.BigEndian.Uint32(batch[i:]); v < limit {
+				dst[filled] = int(v % bound)
+				filled++
+			}
+		}
+	}
+	return nil
+}
+
+// buildID returns size random characters of alphabet, shifting each index
+// by the matching byte of seed, and calls check between batches
+func buildID(r io.Reader, alphabet symbols, size int, seed []byte, check func() error) (string, error) {
+	var indices [sampleChunk]int
+	n := alphabet.len()
+	id := make([]byte, 0, size*alphabet.maxBytes())
+
+	for start := 0; start < size; start += sampleChunk {
+		if check != nil {
+			if err := check(); err != nil {
+				return "", err
+			}
+		}
+
+		chunk := indices[:min(sampleChunk, size-start)]
+		if err := sampleIndices(r, n, chunk); err != nil {
+			return "", err
+		}
+		if len(seed) > 0 {
+			for i, index := range chunk {
+				chunk[i] = (index + int(seed[(start+i)%len(seed)])) % n
+			}
+		}
+		// Shifted columns stay uniform, so weighting applies after the seed
+		if alphabet.weights != nil {
+			if err := alphabet.weights.resolve(r, chunk); err != nil {
+				return "", err
+			}
+		}
+		for _, index := range chunk {
+			id = alphabet.append(id, index)
+		}
+	}
+	return string(id), nil
+}
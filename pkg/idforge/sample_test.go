@@ -0,0 +1,114 @@
+package idforge
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestSampleIndicesRejection(t *testing.T) {
+	// n=3 masks to two bits, so 3 (and 7&3) are rejected
+	r := bytes.NewReader([]byte{3, 2, 7, 1, 0, 0, 0})
+	dst := make([]int, 2)
+
+	if err := sampleIndices(r, 3, dst); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dst[0] != 2 || dst[1] != 1 {
+		t.Errorf("Expected [2 1], got %v", dst)
+	}
+}
+
+func TestSampleIndicesLargeAlphabet(t *testing.T) {
+	// 0xFFFFFFFF lies above the largest multiple of 300 and is rejected
+	r := bytes.NewReader([]byte{
+		0xFF, 0xFF, 0xFF, 0xFF,
+		0x00, 0x00, 0x01, 0x2D, // 301
+	})
+	dst := make([]int, 1)
+
+	if err := sampleIndices(r, 300, dst); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if dst[0] != 1 {
+		t.Errorf("Expected 1, got %d", dst[0])
+	}
+}
+
+func TestSampleIndicesUniform(t *testing.T) {
+	for _, n := range []int{2, 10, 62, 129, 256, 1000} {
+		const draws = 200000
+		dst := make([]int, draws)
+		if err := sampleIndices(rand.Reader, n, dst); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		counts := make([]int, n)
+		for _, v := range dst {
+			if v < 0 || v >= n {
+				t.Fatalf("Index %d out of range for n=%d", v, n)
+			}
+			counts[v]++
+		}
+
+		expected := float64(draws) / float64(n)
+		chi := 0.0
+		for _, c := range counts {
+			diff := float64(c) - expected
+			chi += diff * diff / expected
+		}
+		// Mean n-1, standard deviation sqrt(2(n-1)); allow a generous margin
+		if limit := float64(n-1) + 6*sqrtInt(2*(n-1)); chi > limit {
+			t.Errorf("n=%d: chi-square %.1f exceeds %.1f", n, chi, limit)
+		}
+	}
+}
+
+func sqrtInt(v int) float64 {
+	x := float64(v)
+	r := x
+	for i := 0; i < 30; i++ {
+		r = (r + x/r) / 2
+	}
+	return r
+}
+
+func TestSampleIndicesReaderError(t *testing.T) {
+	readErr := errors.New("entropy exhausted")
+	if err := sampleIndices(iotest.ErrReader(readErr), 62, make([]int, 4)); err != readErr {
+		t.Errorf("Expected reader error, got %v", err)
+	}
+}
+
+func TestBuildIDLongAndSeeded(t *testing.T) {
+	checks := 0
+	id, err := buildID(rand.Reader, newSymbols("abc"), 3*sampleChunk+7, []byte{1, 2, 3}, func() error {
+		checks++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if checks != 4 {
+		t.Errorf("Expected a check per batch, got %d", checks)
+	}
+	if len(id) != 3*sampleChunk+7 {
+		t.Errorf("Expected %d characters, got %d", 3*sampleChunk+7, len(id))
+	}
+	if strings.Trim(id, "abc") != "" {
+		t.Errorf("ID contains characters outside the alphabet: %q", id)
+	}
+}
+
+func BenchmarkSampleIndices(b *testing.B) {
+	dst := make([]int, DefaultSize)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := sampleIndices(rand.Reader, len(DefaultAlphabet), dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package idforge
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CaseFolding selects how Sanitize changes letter case
+type CaseFolding int
+
+const (
+	KeepCase CaseFolding = iota
+	LowerCase
+	UpperCase
+)
+
+// DefaultLookalikes maps characters commonly misread in IDs onto the digit
+// they resemble, following Crockford's base32 decoding rules
+var DefaultLookalikes = map[rune]rune{
+	'O': '0', 'o': '0',
+	'I': '1', 'i': '1',
+	'L': '1', 'l': '1',
+}
+
+// SanitizeOptions controls how Sanitize cleans IDs received from external
+// systems. Steps run in field order; the zero value only applies SanitizeID.
+type SanitizeOptions struct {
+	// Normalize applies Unicode normalization, for example
+	// norm.NFKC.String from golang.org/x/text
+	Normalize func(string) string
+	// FoldWidth maps fullwidth forms such as "ＡＢＣ１２３" to ASCII, the
+	// compatibility mapping IDs pasted from CJK input methods need most
+	FoldWidth bool
+	Case      CaseFolding
+	// Lookalikes replaces each key with its value, see DefaultLookalikes
+	Lookalikes map[rune]rune
+
+	// MaxLength truncates IDs to this many characters, 0 keeps all
+	MaxLength int
+	// Checksum recomputes the trailing ChecksumLength check characters
+	// from the rest of the ID after cleaning and truncation
+	Checksum       func(body string) string
+	ChecksumLength int
+}
+
+// SanitizeID trims surrounding whitespace and removes control and
+// invisible formatting characters, such as zero-width spaces and byte
+// order marks, that survive copy and paste. Invalid UTF-8 is dropped.
+func SanitizeID(id string) string {
+	id = strings.Map(func(r rune) rune {
+		if r == utf8.RuneError || unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, id)
+	return strings.TrimSpace(id)
+}
+
+// Sanitize cleans id with SanitizeID and then the steps selected in opts
+func Sanitize(id string, opts SanitizeOptions) string {
+	id = SanitizeID(id)
+	if opts.Normalize != nil {
+		id = opts.Normalize(id)
+	}
+	if opts.FoldWidth {
+		id = strings.Map(foldWidth, id)
+	}
+	switch opts.Case {
+	case LowerCase:
+		id = strings.ToLower(id)
+	case UpperCase:
+		id = strings.ToUpper(id)
+	}
+	if opts.Lookalikes != nil {
+		id = strings.Map(func(r rune) rune {
+			if mapped, ok := opts.Lookalikes[r]; ok {
+				return mapped
+			}
+			return r
+		}, id)
+	}
+
+	if opts.Checksum == nil {
+		if opts.MaxLength > 0 {
+			id = strings.TrimSpace(cutChars(id, opts.MaxLength))
+		}
+		return id
+	}
+
+	// Drop the old check characters, truncate the body and recompute them
+	body := cutChars(id, max(utf8.RuneCountInString(id)-opts.ChecksumLength, 0))
+	if opts.MaxLength > 0 {
+		body = cutChars(body, max(opts.MaxLength-opts.ChecksumLength, 0))
+	}
+	return body + opts.Checksum(body)
+}
+
+// cutChars returns at most the first n characters of s
+func cutChars(s string, n int) string {
+	if head, _, ok := splitChars(s, n); ok {
+		return head
+	}
+	return s
+}
+
+// foldWidth maps fullwidth ASCII variants and the ideographic space to ASCII
+func foldWidth(r rune) rune {
+	switch {
+	case r >= '！' && r <= '～':
+		return r - '！' + '!'
+	case r == '　':
+		return ' '
+	}
+	return r
+}
@@ -0,0 +1,54 @@
+package idforge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeID(t *testing.T) {
+	cases := map[string]string{
+		"  abc123\n":         "abc123",
+		"\ufeffabc\u200b123": "abc123",
+		"ab\x00c\xff":        "abc",
+		"клиент-7":           "клиент-7",
+	}
+	for in, want := range cases {
+		if got := SanitizeID(in); got != want {
+			t.Errorf("SanitizeID(%q) = %q, expected %q", in, got, want)
+		}
+	}
+}
+
+func TestSanitizeFolding(t *testing.T) {
+	opts := SanitizeOptions{
+		FoldWidth:  true,
+		Case:       UpperCase,
+		Lookalikes: DefaultLookalikes,
+	}
+	if got := Sanitize(" ＡＢｃ-ｏl9 ", opts); got != "ABC-019" {
+		t.Errorf("Expected ABC-019, got %q", got)
+	}
+
+	custom := SanitizeOptions{Normalize: strings.TrimSpace, Case: LowerCase, MaxLength: 4}
+	if got := Sanitize("ABCDEF", custom); got != "abcd" {
+		t.Errorf("Expected abcd, got %q", got)
+	}
+}
+
+func TestSanitizeRecomputesChecksum(t *testing.T) {
+	// A toy check character encoding the body length
+	checksum := func(body string) string {
+		return string(sortedAlphabet[len(body)])
+	}
+
+	opts := SanitizeOptions{Case: UpperCase, MaxLength: 6, Checksum: checksum, ChecksumLength: 1}
+	if got := Sanitize("abcdefghjkX", opts); got != "ABCDE5" {
+		t.Errorf("Expected ABCDE5, got %q", got)
+	}
+
+	// Without truncation only the check character is replaced
+	opts.MaxLength = 0
+	if got := Sanitize("abcZ", opts); got != "ABC3" {
+		t.Errorf("Expected ABC3, got %q", got)
+	}
+}
@@ -0,0 +1,89 @@
+package idforge
+
+import (
+	"fmt"
+	"math"
+)
+
+// IDShape describes the IDs a generator produces
+type IDShape struct {
+	Pattern     string  // Anchored regular expression, as returned by Pattern
+	MinLength   int     // Shortest ID in characters
+	MaxLength   int     // Longest ID in characters, 0 when unbounded
+	EntropyBits float64 // Randomness carried by each ID
+}
+
+// Shaped is implemented by generators that can describe their IDs
+type Shaped interface {
+	Shape() IDShape
+}
+
+// Shape describes the generator's IDs
+func (g *Generator) Shape() IDShape {
+	return IDShape{
+		Pattern:     g.Pattern(),
+		MinLength:   g.size,
+		MaxLength:   g.size,
+		EntropyBits: float64(g.size) * math.Log2(float64(alphabetLen(g.alphabet))),
+	}
+}
+
+// Shape describes the IDs of the generator's current configuration
+func (g *ExtendedGenerator) Shape() IDShape {
+	cfg := g.Config()
+	shape := IDShape{
+		Pattern:     g.Pattern(),
+		MinLength:   cfg.Size,
+		MaxLength:   cfg.Size,
+		EntropyBits: float64(cfg.Size) * cfg.BitsPerCharacter(),
+	}
+	if cfg.FormatVersion != 0 {
+		shape.MinLength++
+		shape.MaxLength++
+	}
+	if cfg.CollisionStrategy == CollisionGrowSize {
+		shape.MaxLength = 0
+	}
+	return shape
+}
+
+// Shape describes the layout's IDs. Only random segments count towards
+// EntropyBits.
+func (g *LayoutGenerator) Shape() IDShape {
+	shape := IDShape{Pattern: g.Pattern()}
+	for _, seg := range g.segments {
+		shape.MinLength += seg.length
+		if seg.kind == segmentRandom {
+			shape.EntropyBits += float64(seg.length) * math.Log2(float64(seg.alphabet.len()))
+		}
+	}
+	shape.MaxLength = shape.MinLength
+	return shape
+}
+
+// JSONSchema is a JSON Schema fragment for a string ID
+type JSONSchema struct {
+	Type        string `json:"type"`
+	Pattern     string `json:"pattern"`
+	MinLength   int    `json:"minLength,omitempty"`
+	MaxLength   int    `json:"maxLength,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// SchemaFor returns a JSON Schema fragment for the IDs gen produces, to
+// embed in OpenAPI specs. Lengths count characters, as JSON Schema does.
+func SchemaFor(gen Shaped) JSONSchema {
+	shape := gen.Shape()
+
+	length := fmt.Sprintf("%d", shape.MinLength)
+	if shape.MaxLength == 0 {
+		length = fmt.Sprintf("at least %d", shape.MinLength)
+	}
+	return JSONSchema{
+		Type:        "string",
+		Pattern:     shape.Pattern,
+		MinLength:   shape.MinLength,
+		MaxLength:   shape.MaxLength,
+		Description: fmt.Sprintf("ID of %s characters with about %.0f bits of entropy", length, shape.EntropyBits),
+	}
+}
@@ -0,0 +1,64 @@
+package idforge
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSchemaForGenerator(t *testing.T) {
+	schema := SchemaFor(New())
+	if schema.Type != "string" || schema.Pattern != "^[0-9A-Za-z]{21}$" {
+		t.Errorf("Unexpected schema %+v", schema)
+	}
+	if schema.MinLength != 21 || schema.MaxLength != 21 {
+		t.Errorf("Expected length 21, got %d to %d", schema.MinLength, schema.MaxLength)
+	}
+	if schema.Description != "ID of 21 characters with about 125 bits of entropy" {
+		t.Errorf("Unexpected description %q", schema.Description)
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), `"minLength":21`) || !strings.Contains(string(data), `"pattern":"^[0-9A-Za-z]{21}$"`) {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+}
+
+func TestSchemaForExtendedGenerator(t *testing.T) {
+	schema := SchemaFor(NewExtendedGenerator(WithFormatVersion('2'), WithCollisionStrategy(CollisionGrowSize)))
+	if schema.MinLength != 22 || schema.MaxLength != 0 {
+		t.Errorf("Expected at least 22 characters without a maximum, got %d to %d", schema.MinLength, schema.MaxLength)
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), "maxLength") {
+		t.Errorf("Expected no maxLength, got %s", data)
+	}
+	if !strings.HasPrefix(schema.Description, "ID of at least 22 characters") {
+		t.Errorf("Unexpected description %q", schema.Description)
+	}
+}
+
+func TestLayoutShape(t *testing.T) {
+	gen, err := NewLayout().
+		Literal("ord-").
+		Timestamp(sortedAlphabet, 6, time.Second).
+		Random("0123456789abcdef", 8).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	shape := gen.Shape()
+	if shape.MinLength != 18 || shape.MaxLength != 18 {
+		t.Errorf("Expected length 18, got %d to %d", shape.MinLength, shape.MaxLength)
+	}
+	if shape.EntropyBits != 32 {
+		t.Errorf("Expected 32 bits from the random segment, got %v", shape.EntropyBits)
+	}
+}
@@ -0,0 +1,108 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrNoScheme        = errors.New("no ID scheme registered")
+	ErrDuplicateScheme = fmt.Errorf("%w: scheme version already registered", ErrInvalidConfig)
+)
+
+// Scheme is one registered generation of IDs
+type Scheme struct {
+	Version rune
+	Config  GeneratorConfig
+}
+
+// SchemeRegistry keeps every historical ID scheme of a long-lived system.
+// New IDs always come from the latest scheme, while IDs issued under any
+// registered scheme still parse and validate.
+type SchemeRegistry struct {
+	mu      sync.RWMutex
+	schemes []Scheme // Oldest first
+	latest  *ExtendedGenerator
+}
+
+// NewSchemeRegistry creates an empty scheme registry
+func NewSchemeRegistry() *SchemeRegistry {
+	return &SchemeRegistry{}
+}
+
+// Register adds a scheme whose IDs start with the version marker and
+// makes it the latest. Schemes are registered oldest first.
+func (r *SchemeRegistry) Register(version rune, opts ...func(*GeneratorConfig)) error {
+	if version == 0 {
+		return ErrInvalidFormatVersion
+	}
+	gen := NewExtendedGenerator(append(opts, WithFormatVersion(version))...)
+	cfg := gen.Config()
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.schemes {
+		if s.Version == version {
+			return ErrDuplicateScheme
+		}
+	}
+	r.schemes = append(r.schemes, Scheme{Version: version, Config: cfg})
+	r.latest = gen
+	return nil
+}
+
+// Schemes returns the registered schemes, oldest first
+func (r *SchemeRegistry) Schemes() []Scheme {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]Scheme(nil), r.schemes...)
+}
+
+// Generate creates an ID with the latest scheme
+func (r *SchemeRegistry) Generate(ctx context.Context) (string, error) {
+	r.mu.RLock()
+	gen := r.latest
+	r.mu.RUnlock()
+
+	if gen == nil {
+		return "", ErrNoScheme
+	}
+	return gen.Generate(ctx)
+}
+
+// Parse returns the scheme id was issued under. It fails with
+// ErrUnknownFormat if no registered version matches, or with the scheme's
+// validation error if the ID is malformed.
+func (r *SchemeRegistry) Parse(id string) (Scheme, error) {
+	r.mu.RLock()
+	schemes := r.schemes
+	r.mu.RUnlock()
+
+	formats := make([]VersionedFormat, len(schemes))
+	for i, s := range schemes {
+		formats[i] = FormatOf(s.Config)
+	}
+	f, err := DetectFormat(id, formats...)
+	if err != nil {
+		return Scheme{}, err
+	}
+	for _, s := range schemes {
+		if s.Version == f.Marker {
+			return s, nil
+		}
+	}
+	return Scheme{}, ErrUnknownFormat
+}
+
+// Validate reports whether id is valid under any registered scheme
+func (r *SchemeRegistry) Validate(id string) bool {
+	_, err := r.Parse(id)
+	return err == nil
+}
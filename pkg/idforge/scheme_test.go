@@ -0,0 +1,73 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSchemeRegistryGeneratesLatest(t *testing.T) {
+	r := NewSchemeRegistry()
+	if _, err := r.Generate(context.Background()); !errors.Is(err, ErrNoScheme) {
+		t.Errorf("Expected ErrNoScheme, got %v", err)
+	}
+
+	if err := r.Register('1', WithCustomAlphabet("0123456789")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	old, err := r.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := r.Register('2', func(c *GeneratorConfig) { c.Size = 12 }); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	id, err := r.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id[0] != '2' || len(id) != 13 {
+		t.Errorf("Expected a 13-character ID from scheme 2, got %s", id)
+	}
+
+	for want, id := range map[rune]string{'1': old, '2': id} {
+		s, err := r.Parse(id)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing %s: %v", id, err)
+		}
+		if s.Version != want {
+			t.Errorf("Expected version %c for %s, got %c", want, id, s.Version)
+		}
+		if !r.Validate(id) {
+			t.Errorf("Expected %s to validate", id)
+		}
+	}
+}
+
+func TestSchemeRegistryRejects(t *testing.T) {
+	r := NewSchemeRegistry()
+	if err := r.Register('1', WithCustomAlphabet("0123456789")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := r.Register('1'); !errors.Is(err, ErrDuplicateScheme) {
+		t.Errorf("Expected ErrDuplicateScheme, got %v", err)
+	}
+	if err := r.Register(0); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for a zero version, got %v", err)
+	}
+	if err := r.Register('3', func(c *GeneratorConfig) { c.Alphabet = "a" }); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for a bad alphabet, got %v", err)
+	}
+	if n := len(r.Schemes()); n != 1 {
+		t.Errorf("Expected 1 scheme after rejected registrations, got %d", n)
+	}
+
+	if r.Validate("1abc") {
+		t.Error("Expected an ID outside the scheme alphabet to be invalid")
+	}
+	if _, err := r.Parse("9" + "012345678901234567890"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package idforge
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/secmem"
+)
+
+// WithLockedMemory keeps the DRBG state in locked memory so it is never
+// written to swap. Platforms without mlock skip the lock; where it is
+// available but fails, for example past RLIMIT_MEMLOCK, Generate returns
+// the error. It only has an effect together with WithDRBG.
+func WithLockedMemory() func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.LockedMemory = true
+	}
+}
+
+// lockDRBG locks the DRBG state if LockedMemory is set, wiping and dropping
+// the DRBG when locking fails; the caller must hold g.mu
+func (g *ExtendedGenerator) lockDRBG() error {
+	if !g.config.LockedMemory {
+		return nil
+	}
+	err := g.drbg.Lock()
+	if err == nil || errors.Is(err, secmem.ErrUnsupported) {
+		return nil
+	}
+	g.wipeDRBG()
+	return fmt.Errorf("lock DRBG state: %w", err)
+}
+
+// wipeDRBG zeroizes and drops the DRBG, so the next ID seeds a fresh one;
+// the caller must hold g.mu
+func (g *ExtendedGenerator) wipeDRBG() {
+	if g.drbg != nil {
+		g.drbg.Wipe()
+		g.drbg = nil
+	}
+}
+
+// Close wipes the keys decrypted so far. The provider remains usable and
+// decrypts keys again on demand.
+func (p *KMSKeyProvider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for id, secret := range p.decrypted {
+		secmem.Wipe(secret)
+		delete(p.decrypted, id)
+	}
+	return nil
+}
+
+// Close wipes the loaded keys; the provider must not be used afterwards
+// unless Reload succeeds
+func (p *FileKeyProvider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.set.wipe()
+	p.set = keySet{}
+	return nil
+}
+
+// wipe zeroizes every secret in the set
+func (s keySet) wipe() {
+	for _, secret := range s.keys {
+		secmem.Wipe(secret)
+	}
+}
+
+// Close wipes the pseudonymization key; the Pseudonymizer must not be used
+// afterwards
+func (p *Pseudonymizer) Close() error {
+	secmem.Wipe(p.key)
+	p.key = nil
+	return nil
+}
@@ -0,0 +1,112 @@
+package idforge
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCloseWipesDRBG(t *testing.T) {
+	gen := NewExtendedGenerator(WithDRBG(time.Hour), WithLockedMemory())
+	if _, err := gen.Generate(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	state := gen.drbg
+	if state == nil {
+		t.Fatal("Expected a seeded DRBG")
+	}
+	if err := gen.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gen.drbg != nil {
+		t.Error("Expected Close to drop the DRBG")
+	}
+	if _, err := state.Read(make([]byte, 8)); err == nil {
+		t.Error("Expected the wiped DRBG to refuse output")
+	}
+
+	// The generator seeds a fresh DRBG after Close
+	if _, err := gen.Generate(context.Background()); err != nil {
+		t.Errorf("Expected Generate to work after Close, got %v", err)
+	}
+}
+
+func TestKMSKeyProviderClose(t *testing.T) {
+	decrypter := &reverseDecrypter{}
+	provider := NewKMSKeyProvider(decrypter, "k1", map[string][]byte{"k1": []byte("terces")})
+	ctx := context.Background()
+
+	key, err := provider.CurrentKey(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	inner := provider.decrypted["k1"]
+	if err := provider.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(inner, make([]byte, len(inner))) {
+		t.Errorf("Expected Close to wipe the decrypted key, got %q", inner)
+	}
+	if !bytes.Equal(key.Secret, []byte("secret")) {
+		t.Errorf("Expected a key returned before Close to stay usable, got %q", key.Secret)
+	}
+
+	// Keys are decrypted again on demand
+	key, err = provider.CurrentKey(ctx)
+	if err != nil || !bytes.Equal(key.Secret, []byte("secret")) {
+		t.Errorf("Expected the key to be decrypted again, got %q (err %v)", key.Secret, err)
+	}
+	if decrypter.calls != 2 {
+		t.Errorf("Expected 2 decrypt calls, got %d", decrypter.calls)
+	}
+}
+
+func TestFileKeyProviderClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	content := `{"current": "k1", "keys": {"k1": "` + encodeKey("first") + `"}}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider, err := NewFileKeyProvider(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	key, err := provider.CurrentKey(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	inner := provider.set.keys["k1"]
+	if err := provider.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(inner, make([]byte, len(inner))) {
+		t.Errorf("Expected Close to wipe the loaded key, got %q", inner)
+	}
+	if !bytes.Equal(key.Secret, []byte("first")) {
+		t.Errorf("Expected a key returned before Close to stay usable, got %q", key.Secret)
+	}
+	if _, err := provider.CurrentKey(context.Background()); err != ErrKeyNotFound {
+		t.Errorf("Expected ErrKeyNotFound after Close, got %v", err)
+	}
+}
+
+func TestPseudonymizerClose(t *testing.T) {
+	key := []byte("pseudonymization-key-of-32-bytes")
+	p := NewPseudonymizer(key)
+	inner := p.key
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(inner, make([]byte, len(inner))) {
+		t.Errorf("Expected Close to wipe the key copy, got %q", inner)
+	}
+	if bytes.Equal(key, make([]byte, len(key))) {
+		t.Error("Expected the caller's key to be left alone")
+	}
+}
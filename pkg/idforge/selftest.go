@@ -0,0 +1,87 @@
+package idforge
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrSelfTestFailed = errors.New("self-test failed")
+
+const (
+	selfTestIDs             = 16
+	selfTestProviderTimeout = time.Second
+)
+
+// SelfTest checks that crypto/rand works, that every entropy provider in the
+// configuration built from opts answers, and that generated IDs match the
+// configured format version, alphabet and size. Call it at startup so misconfiguration fails
+// fast. Errors wrap ErrSelfTestFailed.
+func SelfTest(opts ...func(*GeneratorConfig)) error {
+	if err := checkCryptoRand(); err != nil {
+		return fmt.Errorf("%w: crypto/rand: %v", ErrSelfTestFailed, err)
+	}
+
+	gen := NewExtendedGenerator(opts...)
+	cfg := gen.Config()
+	if err := cfg.validate(); err != nil {
+		return fmt.Errorf("%w: configuration: %w", ErrSelfTestFailed, err)
+	}
+
+	timeout := cfg.ProviderTimeout
+	if timeout <= 0 {
+		timeout = selfTestProviderTimeout
+	}
+	for _, provider := range cfg.Entropy {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		value, err := provide(ctx, provider)
+		cancel()
+
+		if err == nil && value == "" {
+			err = errors.New("returned no entropy")
+		}
+		if err != nil {
+			return fmt.Errorf("%w: provider %s: %w", ErrSelfTestFailed, providerName(provider), err)
+		}
+	}
+
+	seen := make(map[string]bool, selfTestIDs)
+	for i := 0; i < selfTestIDs; i++ {
+		id, err := gen.Generate(context.Background())
+		if err != nil {
+			return fmt.Errorf("%w: generate: %w", ErrSelfTestFailed, err)
+		}
+		body, marked := id, true
+		if cfg.FormatVersion != 0 {
+			body, marked = strings.CutPrefix(id, string(cfg.FormatVersion))
+		}
+		if !marked || !IsValidID(body, cfg.Alphabet, cfg.Size) {
+			return fmt.Errorf("%w: generated ID %q does not match alphabet and size", ErrSelfTestFailed, id)
+		}
+		seen[id] = true
+	}
+	if len(seen) < selfTestIDs/2 {
+		return fmt.Errorf("%w: only %d distinct IDs in %d", ErrSelfTestFailed, len(seen), selfTestIDs)
+	}
+	return nil
+}
+
+// checkCryptoRand verifies that crypto/rand returns fresh, non-constant output
+func checkCryptoRand() error {
+	a := make([]byte, 32)
+	b := make([]byte, 32)
+	if _, err := rand.Read(a); err != nil {
+		return err
+	}
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	if bytes.Equal(a, b) || bytes.Equal(a, make([]byte, 32)) {
+		return errors.New("output is not random")
+	}
+	return nil
+}
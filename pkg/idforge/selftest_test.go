@@ -0,0 +1,69 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+// emptyEntropy succeeds without producing entropy
+type emptyEntropy struct{}
+
+func (emptyEntropy) Provide(ctx context.Context) (string, error) {
+	return "", nil
+}
+
+// hangingEntropy never answers
+type hangingEntropy struct{}
+
+func (hangingEntropy) Provide(ctx context.Context) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestSelfTest(t *testing.T) {
+	if err := SelfTest(); err != nil {
+		t.Errorf("Expected default configuration to pass, got %v", err)
+	}
+	if err := SelfTest(WithFIPSMode(), WithDRBG(time.Minute)); err != nil {
+		t.Errorf("Expected FIPS configuration to pass, got %v", err)
+	}
+	if err := SelfTest(WithFormatVersion('v')); err != nil {
+		t.Errorf("Expected a versioned configuration to pass, got %v", err)
+	}
+}
+
+func TestSelfTestFailures(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []func(*GeneratorConfig)
+	}{
+		{"invalid size", []func(*GeneratorConfig){func(c *GeneratorConfig) { c.Size = 0 }}},
+		{"failing provider", []func(*GeneratorConfig){
+			WithEntropyProviders([]entropy.EntropyProvider{failingEntropy{}}),
+		}},
+		{"empty provider", []func(*GeneratorConfig){
+			WithEntropyProviders([]entropy.EntropyProvider{emptyEntropy{}}),
+		}},
+		{"hanging provider", []func(*GeneratorConfig){
+			WithEntropyProviders([]entropy.EntropyProvider{hangingEntropy{}}),
+			WithProviderTimeout(10 * time.Millisecond),
+		}},
+		{"tiny keyspace", []func(*GeneratorConfig){
+			WithCustomAlphabet("01"),
+			func(c *GeneratorConfig) { c.Size = 2 },
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SelfTest(tt.opts...)
+			if !errors.Is(err, ErrSelfTestFailed) {
+				t.Errorf("Expected ErrSelfTestFailed, got %v", err)
+			}
+		})
+	}
+}
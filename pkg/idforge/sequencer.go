@@ -0,0 +1,418 @@
+package idforge
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSequencerLease is how long a leader holds its lock between
+	// renewals
+	DefaultSequencerLease = 10 * time.Second
+
+	// DefaultSequencerChunk is how many numbers a leader reserves in the
+	// SequenceStore at a time
+	DefaultSequencerChunk = 1 << 20
+)
+
+var (
+	ErrNotLeader         = errors.New("not the sequencer leader")
+	ErrSequenceRegressed = errors.New("sequence block does not follow the previous one")
+	errInvalidBlockSize  = fmt.Errorf("%w: sequence block size must be positive", ErrInvalidConfig)
+)
+
+// LeaderLock elects the instance that allocates sequence blocks, such as a
+// Redis key set with NX and a TTL or an etcd lease. Acquire takes the lock
+// for owner, or extends it when owner already holds it, for ttl and
+// reports whether owner holds it now; Release gives it up if owner holds
+// it.
+type LeaderLock interface {
+	Acquire(ctx context.Context, owner string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, owner string) error
+}
+
+// MemoryLeaderLock elects a leader among sequencers in one process, for
+// tests
+type MemoryLeaderLock struct {
+	mu     sync.Mutex
+	owner  string
+	expiry time.Time
+	clock  Clock
+}
+
+// NewMemoryLeaderLock creates an unheld lock
+func NewMemoryLeaderLock(opts ...MemoryOption) *MemoryLeaderLock {
+	return &MemoryLeaderLock{clock: memoryClock(opts)}
+}
+
+func (l *MemoryLeaderLock) Acquire(ctx context.Context, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+	if l.owner != owner && now.Before(l.expiry) {
+		return false, nil
+	}
+	l.owner, l.expiry = owner, now.Add(ttl)
+	return true, nil
+}
+
+func (l *MemoryLeaderLock) Release(ctx context.Context, owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.owner == owner {
+		l.owner, l.expiry = "", time.Time{}
+	}
+	return nil
+}
+
+// SequenceStore keeps the cluster's high-water mark, so a new leader
+// continues above every block handed out before it. Reserve atomically
+// claims the next n numbers and returns the first; numbers start at 1, so
+// 0 is never issued. Redis INCRBY, returning the new value minus n plus 1,
+// or an etcd transaction implement it.
+type SequenceStore interface {
+	Reserve(ctx context.Context, n uint64) (uint64, error)
+}
+
+// MemorySequenceStore keeps the high-water mark in process memory, for
+// tests and single-process deployments
+type MemorySequenceStore struct {
+	mu   sync.Mutex
+	next uint64
+}
+
+// NewMemorySequenceStore creates a store whose first number is 1
+func NewMemorySequenceStore() *MemorySequenceStore {
+	return &MemorySequenceStore{next: 1}
+}
+
+func (s *MemorySequenceStore) Reserve(ctx context.Context, n uint64) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	start := s.next
+	s.next += n
+	return start, nil
+}
+
+// Block is the run of sequence numbers [Start, Start+Size). It is the Go
+// form of the idforge.v1.Block message in proto/idforge/v1/sequencer.proto
+// and encodes the same protobuf wire format.
+type Block struct {
+	Start uint64
+	Size  uint64
+}
+
+// End returns the number just past the block
+func (b Block) End() uint64 {
+	return b.Start + b.Size
+}
+
+// Protobuf field numbers of the sequencer messages
+const (
+	fieldBlockStart  = 1
+	fieldBlockSize   = 2
+	fieldRequestSize = 1
+)
+
+// MarshalBinary encodes the block in the protobuf wire format
+func (b Block) MarshalBinary() ([]byte, error) {
+	data := appendVarintField(nil, fieldBlockStart, b.Start)
+	return appendVarintField(data, fieldBlockSize, b.Size), nil
+}
+
+// UnmarshalBinary decodes a block in the protobuf wire format
+func (b *Block) UnmarshalBinary(data []byte) error {
+	var block Block
+	err := walkFields(data, func(field uint64, value []byte, n uint64) error {
+		switch field {
+		case fieldBlockStart:
+			block.Start = n
+		case fieldBlockSize:
+			block.Size = n
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	*b = block
+	return nil
+}
+
+// BlockRequest is the Go form of the idforge.v1.AllocateBlockRequest
+// message
+type BlockRequest struct {
+	Size uint64
+}
+
+// MarshalBinary encodes the request in the protobuf wire format
+func (r BlockRequest) MarshalBinary() ([]byte, error) {
+	return appendVarintField(nil, fieldRequestSize, r.Size), nil
+}
+
+// UnmarshalBinary decodes a request in the protobuf wire format
+func (r *BlockRequest) UnmarshalBinary(data []byte) error {
+	var req BlockRequest
+	err := walkFields(data, func(field uint64, value []byte, n uint64) error {
+		if field == fieldRequestSize {
+			req.Size = n
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	*r = req
+	return nil
+}
+
+// appendVarintField appends a varint field, omitting zero as proto3 does
+func appendVarintField(data []byte, field, value uint64) []byte {
+	if value == 0 {
+		return data
+	}
+	data = binary.AppendUvarint(data, field<<3|wireVarint)
+	return binary.AppendUvarint(data, value)
+}
+
+// BlockSource hands out blocks of sequence numbers, each starting above
+// every block handed out before. A Sequencer is one; followers wrap a
+// client of the idforge.v1.Sequencer gRPC service as another.
+type BlockSource interface {
+	AllocateBlock(ctx context.Context, size uint64) (Block, error)
+}
+
+// SequencerOption configures a Sequencer
+type SequencerOption func(*Sequencer)
+
+// WithSequencerLease sets how long the leader lock is held between
+// renewals; the lock is renewed once half of it has passed
+func WithSequencerLease(ttl time.Duration) SequencerOption {
+	return func(s *Sequencer) {
+		if ttl > 0 {
+			s.lease = ttl
+		}
+	}
+}
+
+// WithSequencerChunk sets how many numbers the leader reserves in the
+// store at a time. Larger chunks mean fewer store writes but a larger gap
+// in the sequence when leadership moves.
+func WithSequencerChunk(n uint64) SequencerOption {
+	return func(s *Sequencer) {
+		if n > 0 {
+			s.chunk = n
+		}
+	}
+}
+
+// WithSequencerClock times lease renewals by clock instead of the system
+// clock
+func WithSequencerClock(clock Clock) SequencerOption {
+	return func(s *Sequencer) {
+		if clock != nil {
+			s.clock = clock
+		}
+	}
+}
+
+// Sequencer allocates blocks of cluster-wide increasing sequence numbers.
+// Every instance runs one, but only the instance holding the LeaderLock
+// allocates; the others fail with ErrNotLeader, so their gRPC handlers can
+// send followers elsewhere. The leader reserves chunks in the
+// SequenceStore and splits them into blocks locally.
+type Sequencer struct {
+	owner string
+	lock  LeaderLock
+	store SequenceStore
+	lease time.Duration
+	chunk uint64
+	clock Clock
+
+	mu         sync.Mutex
+	heldUntil  time.Time // When the lock lapses unless renewed
+	renewAfter time.Time
+	next, end  uint64 // Unallocated part of the reserved chunk
+}
+
+// NewSequencer creates a sequencer for the instance named owner, which
+// must be unique in the cluster
+func NewSequencer(owner string, lock LeaderLock, store SequenceStore, opts ...SequencerOption) *Sequencer {
+	s := &Sequencer{
+		owner: owner,
+		lock:  lock,
+		store: store,
+		lease: DefaultSequencerLease,
+		chunk: DefaultSequencerChunk,
+		clock: SystemClock,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// AllocateBlock hands out the next size numbers if this instance leads
+func (s *Sequencer) AllocateBlock(ctx context.Context, size uint64) (Block, error) {
+	if size == 0 {
+		return Block{}, errInvalidBlockSize
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureLeader(ctx); err != nil {
+		return Block{}, err
+	}
+	if s.end-s.next < size {
+		start, err := s.store.Reserve(ctx, max(s.chunk, size))
+		if err != nil {
+			return Block{}, fmt.Errorf("sequence store: %w", err)
+		}
+		// The rest of the old chunk is skipped, never handed out below start
+		s.next, s.end = start, start+max(s.chunk, size)
+	}
+	block := Block{Start: s.next, Size: size}
+	s.next += size
+	return block, nil
+}
+
+// ensureLeader takes or renews the lock as needed; the caller must hold
+// s.mu
+func (s *Sequencer) ensureLeader(ctx context.Context) error {
+	now := s.clock.Now()
+	if now.Before(s.renewAfter) {
+		return nil
+	}
+	held, err := s.lock.Acquire(ctx, s.owner, s.lease)
+	if err != nil {
+		return fmt.Errorf("leader lock: %w", err)
+	}
+	if !held || !now.Before(s.heldUntil) {
+		// Another instance may have led in between and handed out numbers
+		// above the cached chunk, so it must not be used again
+		s.next, s.end = 0, 0
+	}
+	if !held {
+		s.heldUntil, s.renewAfter = time.Time{}, time.Time{}
+		return ErrNotLeader
+	}
+	s.heldUntil, s.renewAfter = now.Add(s.lease), now.Add(s.lease/2)
+	return nil
+}
+
+// Leader reports whether this instance held the lock when last checked
+func (s *Sequencer) Leader() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.clock.Now().Before(s.heldUntil)
+}
+
+// Resign gives up leadership, so another instance can take over without
+// waiting for the lease to lapse
+func (s *Sequencer) Resign(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heldUntil, s.renewAfter = time.Time{}, time.Time{}
+	s.next, s.end = 0, 0
+	return s.lock.Release(ctx, s.owner)
+}
+
+// SequenceGenerator issues strictly increasing sequence numbers from
+// blocks fetched from a BlockSource. Numbers are served from memory; once
+// half of a block is used, the next one is fetched in the background, so
+// Next only waits on the source when it falls behind.
+type SequenceGenerator struct {
+	source    BlockSource
+	blockSize uint64
+
+	mu       sync.Mutex
+	current  Block
+	pos      uint64        // Next number in current
+	spare    *Block        // Prefetched block
+	fetching chan struct{} // Closed when the fetch in flight finishes
+	fetchErr error
+}
+
+// NewSequenceGenerator creates a generator fetching blocks of blockSize
+// numbers from source
+func NewSequenceGenerator(source BlockSource, blockSize uint64) (*SequenceGenerator, error) {
+	if blockSize == 0 {
+		return nil, errInvalidBlockSize
+	}
+	return &SequenceGenerator{source: source, blockSize: blockSize}, nil
+}
+
+// Next returns the next sequence number
+func (g *SequenceGenerator) Next(ctx context.Context) (uint64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for {
+		if g.pos < g.current.End() {
+			n := g.pos
+			g.pos++
+			if g.current.End()-g.pos <= g.blockSize/2 && g.spare == nil && g.fetching == nil && g.fetchErr == nil {
+				g.fetch(ctx)
+			}
+			return n, nil
+		}
+
+		if g.spare != nil {
+			if err := g.install(*g.spare); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if err := g.fetchErr; err != nil {
+			g.fetchErr = nil
+			return 0, err
+		}
+		if g.fetching == nil {
+			g.fetch(ctx)
+		}
+
+		done := g.fetching
+		g.mu.Unlock()
+		select {
+		case <-done:
+			g.mu.Lock()
+		case <-ctx.Done():
+			g.mu.Lock()
+			return 0, ctx.Err()
+		}
+	}
+}
+
+// fetch requests the next block in the background; the caller must hold
+// g.mu
+func (g *SequenceGenerator) fetch(ctx context.Context) {
+	done := make(chan struct{})
+	g.fetching = done
+	go func() {
+		block, err := g.source.AllocateBlock(context.WithoutCancel(ctx), g.blockSize)
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if err != nil {
+			g.fetchErr = err
+		} else {
+			g.spare = &block
+		}
+		g.fetching = nil
+		close(done)
+	}()
+}
+
+// install makes block current, refusing one that would repeat numbers;
+// the caller must hold g.mu
+func (g *SequenceGenerator) install(block Block) error {
+	g.spare = nil
+	if block.Size == 0 || block.Start < g.current.End() {
+		return fmt.Errorf("%w: got [%d, %d) after [%d, %d)", ErrSequenceRegressed,
+			block.Start, block.End(), g.current.Start, g.current.End())
+	}
+	g.current, g.pos = block, block.Start
+	return nil
+}
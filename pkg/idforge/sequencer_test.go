@@ -0,0 +1,225 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSequencerLeaderElection(t *testing.T) {
+	ctx := context.Background()
+	lock := NewMemoryLeaderLock()
+	store := NewMemorySequenceStore()
+	a := NewSequencer("a", lock, store, WithSequencerChunk(100))
+	b := NewSequencer("b", lock, store, WithSequencerChunk(100))
+
+	first, err := a.AllocateBlock(ctx, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first != (Block{Start: 1, Size: 10}) {
+		t.Errorf("Expected [1, 11), got %+v", first)
+	}
+	if _, err := b.AllocateBlock(ctx, 10); !errors.Is(err, ErrNotLeader) {
+		t.Errorf("Expected ErrNotLeader, got %v", err)
+	}
+	if !a.Leader() || b.Leader() {
+		t.Error("Expected a to lead")
+	}
+
+	second, _ := a.AllocateBlock(ctx, 10)
+	if second.Start != first.End() {
+		t.Errorf("Expected the leader to continue its chunk at %d, got %d", first.End(), second.Start)
+	}
+
+	// After a hands over, b continues above a's whole chunk
+	if err := a.Resign(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	third, err := b.AllocateBlock(ctx, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if third.Start != 101 {
+		t.Errorf("Expected b to start a new chunk at 101, got %d", third.Start)
+	}
+
+	// a must not go back to its old chunk when it leads again
+	b.Resign(ctx)
+	fourth, _ := a.AllocateBlock(ctx, 10)
+	if fourth.Start <= third.Start {
+		t.Errorf("Expected %d to follow %d", fourth.Start, third.Start)
+	}
+
+	large, _ := a.AllocateBlock(ctx, 500)
+	if large.Size != 500 || large.Start <= fourth.Start {
+		t.Errorf("Expected an oversized block above %d, got %+v", fourth.Start, large)
+	}
+}
+
+func TestSequencerLeaseExpiry(t *testing.T) {
+	ctx := context.Background()
+	lock := NewMemoryLeaderLock()
+	store := NewMemorySequenceStore()
+	a := NewSequencer("a", lock, store, WithSequencerLease(20*time.Millisecond), WithSequencerChunk(100))
+	b := NewSequencer("b", lock, store, WithSequencerLease(20*time.Millisecond), WithSequencerChunk(100))
+
+	a.AllocateBlock(ctx, 10)
+	time.Sleep(30 * time.Millisecond)
+	taken, err := b.AllocateBlock(ctx, 10)
+	if err != nil {
+		t.Fatalf("Expected b to take over the lapsed lease, got %v", err)
+	}
+	if _, err := a.AllocateBlock(ctx, 10); !errors.Is(err, ErrNotLeader) {
+		t.Errorf("Expected ErrNotLeader for the old leader, got %v", err)
+	}
+	if a.Leader() {
+		t.Error("Expected a to know it lost the lock")
+	}
+	if taken.Start != 101 {
+		t.Errorf("Expected b to start at 101, got %d", taken.Start)
+	}
+}
+
+func TestSequencerErrors(t *testing.T) {
+	ctx := context.Background()
+	s := NewSequencer("a", NewMemoryLeaderLock(), NewMemorySequenceStore())
+	if _, err := s.AllocateBlock(ctx, 0); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig, got %v", err)
+	}
+	if _, err := NewSequenceGenerator(s, 0); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig, got %v", err)
+	}
+
+	failing := errors.New("store down")
+	s = NewSequencer("a", NewMemoryLeaderLock(), failingStore{failing})
+	if _, err := s.AllocateBlock(ctx, 1); !errors.Is(err, failing) {
+		t.Errorf("Expected the store error, got %v", err)
+	}
+	if ErrorCode(ErrNotLeader) != CodeNotLeader {
+		t.Errorf("Expected %s, got %s", CodeNotLeader, ErrorCode(ErrNotLeader))
+	}
+}
+
+type failingStore struct{ err error }
+
+func (s failingStore) Reserve(ctx context.Context, n uint64) (uint64, error) {
+	return 0, s.err
+}
+
+func TestSequenceGenerator(t *testing.T) {
+	ctx := context.Background()
+	leader := NewSequencer("a", NewMemoryLeaderLock(), NewMemorySequenceStore(), WithSequencerChunk(64))
+
+	// Two followers share the leader and never issue the same number
+	var (
+		mu   sync.Mutex
+		seen = map[uint64]bool{}
+		wg   sync.WaitGroup
+	)
+	for f := 0; f < 2; f++ {
+		gen, err := NewSequenceGenerator(leader, 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var last uint64
+				for i := 0; i < 500; i++ {
+					n, err := gen.Next(ctx)
+					if err != nil {
+						t.Errorf("Unexpected error: %v", err)
+						return
+					}
+					if n <= last {
+						t.Errorf("Expected %d to follow %d", n, last)
+					}
+					last = n
+					mu.Lock()
+					if seen[n] {
+						t.Errorf("Expected unique numbers, got %d twice", n)
+					}
+					seen[n] = true
+					mu.Unlock()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	if len(seen) != 4000 {
+		t.Errorf("Expected 4000 numbers, got %d", len(seen))
+	}
+}
+
+// scriptedSource returns prepared blocks and errors in order
+type scriptedSource struct {
+	mu     sync.Mutex
+	blocks []Block
+	errs   []error
+}
+
+func (s *scriptedSource) AllocateBlock(ctx context.Context, size uint64) (Block, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.blocks) == 0 {
+		return Block{}, ErrNotLeader
+	}
+	block, err := s.blocks[0], s.errs[0]
+	s.blocks, s.errs = s.blocks[1:], s.errs[1:]
+	return block, err
+}
+
+func TestSequenceGeneratorSourceErrors(t *testing.T) {
+	ctx := context.Background()
+	source := &scriptedSource{
+		blocks: []Block{{Start: 10, Size: 2}, {}, {Start: 20, Size: 2}, {Start: 5, Size: 2}},
+		errs:   []error{nil, ErrNotLeader, nil, nil},
+	}
+	gen, _ := NewSequenceGenerator(source, 2)
+
+	for _, want := range []uint64{10, 11} {
+		if n, err := gen.Next(ctx); err != nil || n != want {
+			t.Fatalf("Expected %d, got %d (%v)", want, n, err)
+		}
+	}
+	// The prefetch failed; the error surfaces once the block runs out
+	if _, err := gen.Next(ctx); !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("Expected ErrNotLeader, got %v", err)
+	}
+	for _, want := range []uint64{20, 21} {
+		if n, err := gen.Next(ctx); err != nil || n != want {
+			t.Fatalf("Expected %d after the retry, got %d (%v)", want, n, err)
+		}
+	}
+	if _, err := gen.Next(ctx); !errors.Is(err, ErrSequenceRegressed) {
+		t.Errorf("Expected ErrSequenceRegressed for a lower block, got %v", err)
+	}
+}
+
+func TestBlockMessages(t *testing.T) {
+	block := Block{Start: 1 << 40, Size: 1000}
+	data, _ := block.MarshalBinary()
+	// start = 1, varint 2^40; size = 2, varint 1000
+	want := []byte{0x08, 0x80, 0x80, 0x80, 0x80, 0x80, 0x20, 0x10, 0xe8, 0x07}
+	if string(data) != string(want) {
+		t.Errorf("Expected % x, got % x", want, data)
+	}
+	var decoded Block
+	if err := decoded.UnmarshalBinary(data); err != nil || decoded != block {
+		t.Errorf("Expected %+v, got %+v (%v)", block, decoded, err)
+	}
+
+	req := BlockRequest{Size: 300}
+	data, _ = req.MarshalBinary()
+	var decodedReq BlockRequest
+	if err := decodedReq.UnmarshalBinary(data); err != nil || decodedReq != req {
+		t.Errorf("Expected %+v, got %+v (%v)", req, decodedReq, err)
+	}
+	if err := decoded.UnmarshalBinary([]byte{0x08}); !errors.Is(err, ErrInvalidMessage) {
+		t.Errorf("Expected ErrInvalidMessage for a truncated message, got %v", err)
+	}
+}
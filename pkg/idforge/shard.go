@@ -0,0 +1,115 @@
+package idforge
+
+import (
+	"errors"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+var ErrInvalidShard = errors.New("invalid shard")
+
+// ShardedGenerator creates IDs whose leading characters encode a shard
+// number in the generator's alphabet, so reads can be routed from the ID
+// alone. The remaining characters are random.
+type ShardedGenerator struct {
+	gen       *Generator
+	shards    int
+	width     int
+	shardFunc func() int
+	counter   atomic.Uint64
+}
+
+// NewShardedGenerator creates a generator for shards shards. shardFunc picks
+// the shard of each generated ID; when nil, shards are assigned round-robin
+// from a counter. opts configure the alphabet and the total ID size, which
+// includes the shard characters.
+func NewShardedGenerator(shards int, shardFunc func() int, opts ...Option) (*ShardedGenerator, error) {
+	if shards < 1 {
+		return nil, ErrInvalidShard
+	}
+
+	gen := New(opts...)
+	width := 1
+	n := alphabetLen(gen.alphabet)
+	for capacity := n; capacity < shards; capacity *= n {
+		width++
+	}
+	if gen.size <= width {
+		return nil, ErrInvalidSize
+	}
+	gen.size -= width
+
+	return &ShardedGenerator{
+		gen:       gen,
+		shards:    shards,
+		width:     width,
+		shardFunc: shardFunc,
+	}, nil
+}
+
+// Generate creates an ID for the shard chosen by the shard function
+func (s *ShardedGenerator) Generate() (string, error) {
+	var shard int
+	if s.shardFunc != nil {
+		shard = s.shardFunc()
+	} else {
+		shard = int((s.counter.Add(1) - 1) % uint64(s.shards))
+	}
+	return s.GenerateForShard(shard)
+}
+
+// GenerateForShard creates an ID embedding the given shard
+func (s *ShardedGenerator) GenerateForShard(shard int) (string, error) {
+	if shard < 0 || shard >= s.shards {
+		return "", ErrInvalidShard
+	}
+
+	random, err := s.gen.Generate()
+	if err != nil {
+		return "", err
+	}
+
+	alphabet := newSymbols(s.gen.alphabet)
+	digits := make([]int, s.width)
+	for i := s.width - 1; i >= 0; i-- {
+		digits[i] = shard % alphabet.len()
+		shard /= alphabet.len()
+	}
+
+	prefix := make([]byte, 0, s.width*alphabet.maxBytes()+len(random))
+	for _, digit := range digits {
+		prefix = alphabet.append(prefix, digit)
+	}
+	return string(prefix) + random, nil
+}
+
+// ExtractShard returns the shard embedded in id
+func (s *ShardedGenerator) ExtractShard(id string) (int, error) {
+	head, _, ok := splitChars(id, s.width)
+	if !ok || utf8.RuneCountInString(id) != s.width+s.gen.size {
+		return 0, ErrInvalidShard
+	}
+
+	alphabet := newSymbols(s.gen.alphabet)
+	shard := 0
+	for _, char := range head {
+		digit := alphabet.index(char)
+		if digit < 0 {
+			return 0, ErrInvalidShard
+		}
+		shard = shard*alphabet.len() + digit
+	}
+	if shard >= s.shards {
+		return 0, ErrInvalidShard
+	}
+	return shard, nil
+}
+
+// Validate checks if id was produced by this generator
+func (s *ShardedGenerator) Validate(id string) bool {
+	if _, err := s.ExtractShard(id); err != nil {
+		return false
+	}
+	_, random, _ := splitChars(id, s.width)
+	return s.gen.Validate(random)
+}
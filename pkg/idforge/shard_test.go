@@ -0,0 +1,91 @@
+package idforge
+
+import "testing"
+
+func TestShardedGeneratorRoundRobin(t *testing.T) {
+	gen, err := NewShardedGenerator(4, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i := 0; i < 8; i++ {
+		id, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(id) != DefaultSize {
+			t.Errorf("Expected length %d, got %d", DefaultSize, len(id))
+		}
+
+		shard, err := gen.ExtractShard(id)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if shard != i%4 {
+			t.Errorf("Expected shard %d, got %d", i%4, shard)
+		}
+		if !gen.Validate(id) {
+			t.Errorf("Expected %s to validate", id)
+		}
+	}
+}
+
+func TestShardedGeneratorShardFunc(t *testing.T) {
+	// 100 shards need two characters of a ten-character alphabet
+	gen, err := NewShardedGenerator(100, func() int { return 57 }, WithAlphabet("0123456789"), WithSize(10))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	id, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id[:2] != "57" || len(id) != 10 {
+		t.Errorf("Expected ID of length 10 starting with 57, got %s", id)
+	}
+	if shard, _ := gen.ExtractShard(id); shard != 57 {
+		t.Errorf("Expected shard 57, got %d", shard)
+	}
+}
+
+func TestShardedGeneratorInvalid(t *testing.T) {
+	if _, err := NewShardedGenerator(0, nil); err != ErrInvalidShard {
+		t.Errorf("Expected ErrInvalidShard, got %v", err)
+	}
+	if _, err := NewShardedGenerator(1000, nil, WithAlphabet("01"), WithSize(10)); err != ErrInvalidSize {
+		t.Errorf("Expected ErrInvalidSize, got %v", err)
+	}
+
+	gen, _ := NewShardedGenerator(50, nil, WithAlphabet("0123456789"), WithSize(8))
+	if _, err := gen.GenerateForShard(50); err != ErrInvalidShard {
+		t.Errorf("Expected ErrInvalidShard for out of range shard, got %v", err)
+	}
+	for _, id := range []string{"", "99123456", "x0123456", "0012345"} {
+		if _, err := gen.ExtractShard(id); err != ErrInvalidShard {
+			t.Errorf("Expected ErrInvalidShard for %q, got %v", id, err)
+		}
+	}
+}
+
+func TestShardedGeneratorRuneAlphabet(t *testing.T) {
+	gen, err := NewShardedGenerator(20, nil, WithAlphabet(emojiAlphabet), WithSize(12))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	id, err := gen.GenerateForShard(17)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	shard, err := gen.ExtractShard(id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if shard != 17 {
+		t.Errorf("Expected shard 17, got %d", shard)
+	}
+	if !gen.Validate(id) {
+		t.Errorf("Expected %s to validate", id)
+	}
+}
@@ -0,0 +1,94 @@
+package idforge
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// staticKeys is a KeyProvider over a fixed key set
+type staticKeys struct {
+	set keySet
+}
+
+func newStaticKeys(current string, secrets map[string]string) *staticKeys {
+	keys := make(map[string][]byte)
+	for id, secret := range secrets {
+		keys[id] = []byte(secret)
+	}
+	return &staticKeys{set: keySet{current: current, keys: keys}}
+}
+
+func (s *staticKeys) CurrentKey(ctx context.Context) (Key, error) {
+	return s.set.key(s.set.current)
+}
+
+func (s *staticKeys) Key(ctx context.Context, id string) (Key, error) {
+	return s.set.key(id)
+}
+
+func TestSignerRoundTrip(t *testing.T) {
+	signer := NewSigner(newStaticKeys("k1", map[string]string{"k1": "secret"}))
+	ctx := context.Background()
+
+	// IDs may contain dots themselves
+	for _, id := range []string{New().MustGenerate(), "a.b.c"} {
+		signed, err := signer.Sign(ctx, id)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(signed, "k1."+id+".") {
+			t.Errorf("Expected key ID prefix and original ID, got %s", signed)
+		}
+
+		got, err := signer.Verify(ctx, signed)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got != id {
+			t.Errorf("Expected %s, got %s", id, got)
+		}
+	}
+}
+
+func TestSignerRotation(t *testing.T) {
+	keys := newStaticKeys("k1", map[string]string{"k1": "old", "k2": "new"})
+	signer := NewSigner(keys)
+	ctx := context.Background()
+
+	old, _ := signer.Sign(ctx, "abc")
+	keys.set.current = "k2"
+	fresh, _ := signer.Sign(ctx, "abc")
+
+	if !strings.HasPrefix(fresh, "k2.") {
+		t.Errorf("Expected new IDs to use k2, got %s", fresh)
+	}
+	if _, err := signer.Verify(ctx, old); err != nil {
+		t.Errorf("Expected IDs signed before rotation to verify, got %v", err)
+	}
+
+	// Retiring the old key invalidates its IDs
+	delete(keys.set.keys, "k1")
+	if _, err := signer.Verify(ctx, old); err != ErrInvalidSignature {
+		t.Errorf("Expected ErrInvalidSignature for a retired key, got %v", err)
+	}
+}
+
+func TestSignerRejectsTampering(t *testing.T) {
+	signer := NewSigner(newStaticKeys("k1", map[string]string{"k1": "secret", "k2": "other"}))
+	ctx := context.Background()
+	signed, _ := signer.Sign(ctx, "abc")
+
+	tampered := []string{
+		"",
+		"abc",
+		strings.Replace(signed, "abc", "abd", 1),
+		"k2" + strings.TrimPrefix(signed, "k1"),
+		signed[:len(signed)-1],
+	}
+	for _, s := range tampered {
+		if _, err := signer.Verify(ctx, s); err != ErrInvalidSignature {
+			t.Errorf("Expected ErrInvalidSignature for %q, got %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package idforge
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+func TestSimulateCollisionsSmallKeyspace(t *testing.T) {
+	cfg := GeneratorConfig{
+		Alphabet: "01",
+		Size:     10,
+		Entropy:  []entropy.EntropyProvider{&entropy.TimestampEntropy{}},
+	}
+
+	result := SimulateCollisions(cfg, 2000)
+
+	if result.Generated != 2000 {
+		t.Errorf("Expected 2000 generated IDs, got %d", result.Generated)
+	}
+
+	// 2000 draws from 1024 values should repeat roughly 1121 times
+	if math.Abs(float64(result.Duplicates)-result.ExpectedDuplicates) > 0.1*result.ExpectedDuplicates {
+		t.Errorf("Observed %d duplicates, expected about %.0f",
+			result.Duplicates, result.ExpectedDuplicates)
+	}
+
+	if result.CharacterCounts['0']+result.CharacterCounts['1'] != 20000 {
+		t.Errorf("Character counts do not cover every generated character: %v", result.CharacterCounts)
+	}
+
+	// One degree of freedom: values above ~10.8 are significant at 0.1%
+	if result.ChiSquare > 10.8 {
+		t.Errorf("Character distribution looks non-uniform, chi-square %.2f", result.ChiSquare)
+	}
+}
+
+func TestSimulateCollisionsLargeKeyspace(t *testing.T) {
+	cfg := GeneratorConfig{Alphabet: DefaultAlphabet, Size: DefaultSize}
+
+	result := SimulateCollisions(cfg, 1000)
+
+	if result.Duplicates != 0 {
+		t.Errorf("Expected no duplicates for default configuration, got %d", result.Duplicates)
+	}
+	if result.ExpectedDuplicates <= 0 || result.ExpectedDuplicates > 1e-20 {
+		t.Errorf("Unexpected birthday estimate %g", result.ExpectedDuplicates)
+	}
+	if result.Duration <= 0 || result.Duration > time.Minute {
+		t.Errorf("Unexpected simulation duration %v", result.Duration)
+	}
+}
+
+func TestSimulateCollisionsRandomSource(t *testing.T) {
+	for _, opt := range []func(*GeneratorConfig){WithDRBG(time.Hour), WithFIPSMode()} {
+		cfg := GeneratorConfig{Alphabet: "01", Size: 10}
+		opt(&cfg)
+
+		result := SimulateCollisions(cfg, 2000)
+		if result.EntropyErrors != 0 {
+			t.Errorf("Expected no entropy errors, got %d", result.EntropyErrors)
+		}
+		if math.Abs(float64(result.Duplicates)-result.ExpectedDuplicates) > 0.1*result.ExpectedDuplicates {
+			t.Errorf("Observed %d duplicates, expected about %.0f", result.Duplicates, result.ExpectedDuplicates)
+		}
+	}
+}
+
+func TestSimulateCollisionsInvalidConfig(t *testing.T) {
+	result := SimulateCollisions(GeneratorConfig{Alphabet: "a", Size: 5}, 10)
+
+	if result.Generated != 0 {
+		t.Errorf("Expected invalid configuration to generate nothing, got %d", result.Generated)
+	}
+}
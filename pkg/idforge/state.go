@@ -0,0 +1,62 @@
+package idforge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// stateVersion identifies the layout written by ExportState
+const stateVersion = 1
+
+var ErrInvalidState = errors.New("invalid generator state")
+
+// generatorState is the serialized form of the issued ID set
+type generatorState struct {
+	Version int          `json:"version"`
+	Issued  []issuedItem `json:"issued"`
+}
+
+type issuedItem struct {
+	ID       string    `json:"id"`
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+// ExportState writes the IDs currently remembered for duplicate detection,
+// oldest first, so a restarted process can continue to avoid them
+func (g *ExtendedGenerator) ExportState(w io.Writer) error {
+	g.mu.Lock()
+	state := generatorState{Version: stateVersion}
+	g.issued.expire(g.config.now())
+	for e := g.issued.order.Front(); e != nil; e = e.Next() {
+		entry := e.Value.(issuedEntry)
+		state.Issued = append(state.Issued, issuedItem{ID: entry.id, IssuedAt: entry.issuedAt})
+	}
+	g.mu.Unlock()
+
+	return json.NewEncoder(w).Encode(state)
+}
+
+// ImportState merges IDs written by ExportState into the generator. The
+// current MaxUniqueIDs and UniqueIDRetention apply, so only the most recent
+// IDs within the retention window are kept.
+func (g *ExtendedGenerator) ImportState(r io.Reader) error {
+	var state generatorState
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidState, err)
+	}
+	if state.Version != stateVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidState, state.Version)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, item := range state.Issued {
+		g.issued.addAt(item.ID, item.IssuedAt)
+	}
+	g.issued.expire(g.config.now())
+	return nil
+}
@@ -0,0 +1,78 @@
+package idforge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportImportState(t *testing.T) {
+	ctx := context.Background()
+	original := NewExtendedGenerator()
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		id, err := original.Generate(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	var buf bytes.Buffer
+	if err := original.ExportState(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	restored := NewExtendedGenerator()
+	if err := restored.ImportState(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, id := range ids {
+		if !restored.issued.contains(id, time.Now()) {
+			t.Errorf("Expected %s to be remembered after import", id)
+		}
+	}
+}
+
+func TestImportStateAppliesLimits(t *testing.T) {
+	source := NewExtendedGenerator()
+	start := time.Now()
+	source.issued.add("expired", start.Add(-2*time.Hour))
+	source.issued.add("a", start.Add(-3*time.Minute))
+	source.issued.add("b", start.Add(-2*time.Minute))
+	source.issued.add("c", start.Add(-1*time.Minute))
+
+	var buf bytes.Buffer
+	source.ExportState(&buf)
+
+	target := NewExtendedGenerator(
+		WithUniqueIDRetention(time.Hour),
+		func(cfg *GeneratorConfig) { cfg.MaxUniqueIDs = 3 },
+	)
+	target.issued.add("local", start)
+	if err := target.ImportState(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Imported IDs are older than the local one, so "a" is evicted first
+	now := time.Now()
+	for id, want := range map[string]bool{"expired": false, "a": false, "b": true, "c": true, "local": true} {
+		if got := target.issued.contains(id, now); got != want {
+			t.Errorf("%s: expected contains=%v, got %v", id, want, got)
+		}
+	}
+}
+
+func TestImportStateInvalid(t *testing.T) {
+	gen := NewExtendedGenerator()
+
+	for _, input := range []string{"not json", `{"version": 99, "issued": []}`} {
+		if err := gen.ImportState(strings.NewReader(input)); !errors.Is(err, ErrInvalidState) {
+			t.Errorf("Expected ErrInvalidState for %q, got %v", input, err)
+		}
+	}
+}
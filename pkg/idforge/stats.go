@@ -0,0 +1,120 @@
+package idforge
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+// GeneratorStats reports runtime information about a generator
+type GeneratorStats struct {
+	Generated      uint64          // IDs successfully handed out
+	Failures       uint64          // Generate calls that returned an error
+	Retries        uint64          // Extra attempts made after a collision
+	Collisions     uint64          // Candidates rejected as already issued
+	AverageLatency time.Duration   // Mean duration of successful Generate calls
+	Providers      []ProviderStats // Health of each configured entropy provider
+	Config         GeneratorConfig // Configuration in effect when Stats was called
+}
+
+// ProviderStats reports the health of a single entropy provider
+type ProviderStats struct {
+	Provider            string
+	State               BreakerState
+	ConsecutiveFailures int
+	Failures            uint64
+}
+
+// statsCounters accumulates generation statistics without locking
+type statsCounters struct {
+	generated    atomic.Uint64
+	failures     atomic.Uint64
+	retries      atomic.Uint64
+	collisions   atomic.Uint64
+	totalLatency atomic.Int64
+}
+
+// record accounts for one finished Generate call
+func (c *statsCounters) record(start time.Time, err error) {
+	if err != nil {
+		c.failures.Add(1)
+		return
+	}
+	c.generated.Add(1)
+	c.totalLatency.Add(int64(time.Since(start)))
+}
+
+// recordBulk accounts for count IDs generated together in elapsed time
+func (c *statsCounters) recordBulk(count int, elapsed time.Duration) {
+	c.generated.Add(uint64(count))
+	c.totalLatency.Add(int64(elapsed))
+}
+
+// fill copies the counters into stats
+func (c *statsCounters) fill(stats *GeneratorStats) {
+	stats.Generated = c.generated.Load()
+	stats.Failures = c.failures.Load()
+	stats.Retries = c.retries.Load()
+	stats.Collisions = c.collisions.Load()
+	if stats.Generated > 0 {
+		stats.AverageLatency = time.Duration(c.totalLatency.Load() / int64(stats.Generated))
+	}
+}
+
+// providerName identifies a provider in statistics
+func providerName(provider entropy.EntropyProvider) string {
+	return fmt.Sprintf("%T", provider)
+}
+
+// Stats reports generation counters, provider errors and the current configuration
+func (g *Generator) Stats() GeneratorStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stats := GeneratorStats{
+		Providers: make([]ProviderStats, len(g.entropy)),
+		Config: GeneratorConfig{
+			Alphabet: g.alphabet,
+			Size:     g.size,
+			Entropy:  append([]entropy.EntropyProvider(nil), g.entropy...),
+		},
+	}
+	g.stats.fill(&stats)
+
+	for i, provider := range g.entropy {
+		stats.Providers[i].Provider = providerName(provider)
+		if i < len(g.providerErrors) {
+			stats.Providers[i].Failures = g.providerErrors[i]
+		}
+	}
+	return stats
+}
+
+// Stats reports generation counters, the health of the entropy providers
+// and the current configuration
+func (g *ExtendedGenerator) Stats() GeneratorStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.ensureBreakers()
+	stats := GeneratorStats{
+		Providers: make([]ProviderStats, len(g.config.Entropy)),
+		Config:    g.config,
+	}
+	stats.Config.Entropy = append([]entropy.EntropyProvider(nil), g.config.Entropy...)
+	g.stats.fill(&stats)
+
+	now := g.config.now()
+	for i, provider := range g.config.Entropy {
+		b := g.breakers[i]
+		stats.Providers[i] = ProviderStats{
+			Provider:            providerName(provider),
+			State:               b.currentState(now, g.config.BreakerCooldown),
+			ConsecutiveFailures: b.consecutiveFailures,
+			Failures:            b.failures,
+		}
+	}
+	return stats
+}
@@ -0,0 +1,89 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mrityunjay-vashisth/go-idforge/internal/entropy"
+)
+
+// failingEntropy always returns an error
+type failingEntropy struct{}
+
+func (failingEntropy) Provide(ctx context.Context) (string, error) {
+	return "", errors.New("provider unavailable")
+}
+
+func TestGeneratorStats(t *testing.T) {
+	gen := New(WithAlphabet("ABC"), WithSize(8))
+	for i := 0; i < 5; i++ {
+		gen.MustGenerate()
+	}
+
+	stats := gen.Stats()
+	if stats.Generated != 5 {
+		t.Errorf("Expected 5 generated IDs, got %d", stats.Generated)
+	}
+	if stats.AverageLatency <= 0 {
+		t.Errorf("Expected a positive average latency, got %v", stats.AverageLatency)
+	}
+	if stats.Config.Alphabet != "ABC" || stats.Config.Size != 8 {
+		t.Errorf("Config snapshot does not match generator: %+v", stats.Config)
+	}
+	if len(stats.Providers) != len(entropy.DefaultEntropyProviders()) {
+		t.Errorf("Expected one entry per provider, got %d", len(stats.Providers))
+	}
+}
+
+func TestGeneratorStatsProviderErrors(t *testing.T) {
+	gen := New()
+	gen.entropy = []entropy.EntropyProvider{&entropy.TimestampEntropy{}, failingEntropy{}}
+
+	if _, err := gen.Generate(); err == nil {
+		t.Fatal("Expected generation to fail")
+	}
+
+	stats := gen.Stats()
+	if stats.Failures != 1 {
+		t.Errorf("Expected 1 failed generation, got %d", stats.Failures)
+	}
+	if stats.Providers[0].Failures != 0 || stats.Providers[1].Failures != 1 {
+		t.Errorf("Expected only the failing provider to record an error, got %+v", stats.Providers)
+	}
+}
+
+func TestExtendedGeneratorStatsCollisions(t *testing.T) {
+	gen := NewExtendedGenerator(
+		WithCustomAlphabet("01"),
+		func(cfg *GeneratorConfig) {
+			cfg.Size = 2
+		},
+	)
+
+	// Exhaust the keyspace so every candidate collides
+	for _, id := range []string{"00", "01", "10", "11"} {
+		gen.issued.add(id, time.Now())
+	}
+
+	if _, err := gen.Generate(context.Background()); !errors.Is(err, ErrGenerationTimeout) {
+		t.Fatalf("Expected ErrGenerationTimeout, got %v", err)
+	}
+
+	// Four combinations at 0.99 uniqueness pressure allow three attempts
+	stats := gen.Stats()
+	if stats.Collisions != 3 {
+		t.Errorf("Expected 3 collisions, got %d", stats.Collisions)
+	}
+	if stats.Retries != 2 {
+		t.Errorf("Expected 2 retries, got %d", stats.Retries)
+	}
+	if stats.Failures != 1 || stats.Generated != 0 {
+		t.Errorf("Expected 1 failure and no generated IDs, got %d and %d",
+			stats.Failures, stats.Generated)
+	}
+	if stats.Config.Size != 2 {
+		t.Errorf("Expected config snapshot size 2, got %d", stats.Config.Size)
+	}
+}
@@ -0,0 +1,145 @@
+package idforge
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// UniquenessStore reserves IDs across generator instances, so instances
+// sharing a store never return the same ID while its reservation lasts
+type UniquenessStore interface {
+	// Reserve atomically claims id for ttl and reports false when the
+	// ID is already claimed
+	Reserve(ctx context.Context, id string, ttl time.Duration) (bool, error)
+}
+
+// WithUniquenessStore reserves every candidate in store for ttl before it is
+// returned. A candidate that is already reserved counts as a collision.
+// Reservation happens while the generator is locked, so store latency adds
+// to every Generate call.
+func WithUniquenessStore(store UniquenessStore, ttl time.Duration) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.UniquenessStore = store
+		c.UniquenessTTL = ttl
+	}
+}
+
+// MemoryUniquenessStore reserves IDs in process memory, for tests and for
+// sharing one store between generators in the same process
+type MemoryUniquenessStore struct {
+	mu        sync.Mutex
+	reserved  map[string]time.Time // Expiry per ID, zero for no expiry
+	nextSweep time.Time
+	clock     Clock
+}
+
+// NewMemoryUniquenessStore creates an empty in-memory store
+func NewMemoryUniquenessStore(opts ...MemoryOption) *MemoryUniquenessStore {
+	return &MemoryUniquenessStore{reserved: make(map[string]time.Time), clock: memoryClock(opts)}
+}
+
+func (s *MemoryUniquenessStore) Reserve(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	expiry, ok := s.reserved[id]
+	if ok && (expiry.IsZero() || now.Before(expiry)) {
+		return false, nil
+	}
+	if !ok {
+		s.sweep(now)
+	}
+
+	expiry = time.Time{}
+	if ttl > 0 {
+		expiry = now.Add(ttl)
+	}
+	s.reserved[id] = expiry
+	return true, nil
+}
+
+// sweep drops expired reservations, at most once a minute, so IDs reserved
+// with a ttl do not accumulate; the caller must hold s.mu
+func (s *MemoryUniquenessStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	for id, expiry := range s.reserved {
+		if !expiry.IsZero() && !now.Before(expiry) {
+			delete(s.reserved, id)
+		}
+	}
+	s.nextSweep = now.Add(time.Minute)
+}
+
+func (s *MemoryUniquenessStore) Extend(ctx context.Context, id string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	expiry, ok := s.reserved[id]
+	if !ok || (!expiry.IsZero() && !now.Before(expiry)) {
+		return ErrLeaseExpired
+	}
+
+	expiry = time.Time{}
+	if ttl > 0 {
+		expiry = now.Add(ttl)
+	}
+	s.reserved[id] = expiry
+	return nil
+}
+
+func (s *MemoryUniquenessStore) Release(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.reserved, id)
+	return nil
+}
+
+// RedisClient is the subset of Redis commands the Redis store needs. A zero
+// ttl means no expiry. With go-redis each method is a one-line wrapper, e.g.
+//
+//	func (c client) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+//		return c.rdb.SetNX(ctx, key, value, ttl).Result()
+//	}
+type RedisClient interface {
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) // SET NX
+	SetXX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) // SET XX
+	Del(ctx context.Context, key string) error
+}
+
+// RedisUniquenessStore reserves IDs as Redis keys, keeping the Redis client
+// out of this module
+type RedisUniquenessStore struct {
+	client RedisClient
+	prefix string
+}
+
+// NewRedisUniquenessStore creates a store that reserves each ID as the key
+// prefix+id
+func NewRedisUniquenessStore(client RedisClient, prefix string) *RedisUniquenessStore {
+	return &RedisUniquenessStore{client: client, prefix: prefix}
+}
+
+func (s *RedisUniquenessStore) Reserve(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, s.prefix+id, "1", ttl)
+}
+
+func (s *RedisUniquenessStore) Extend(ctx context.Context, id string, ttl time.Duration) error {
+	ok, err := s.client.SetXX(ctx, s.prefix+id, "1", ttl)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrLeaseExpired
+	}
+	return nil
+}
+
+func (s *RedisUniquenessStore) Release(ctx context.Context, id string) error {
+	return s.client.Del(ctx, s.prefix+id)
+}
@@ -0,0 +1,113 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryUniquenessStore(t *testing.T) {
+	store := NewMemoryUniquenessStore()
+	ctx := context.Background()
+
+	if ok, _ := store.Reserve(ctx, "a", 20*time.Millisecond); !ok {
+		t.Fatal("Expected first reservation to succeed")
+	}
+	if ok, _ := store.Reserve(ctx, "a", time.Minute); ok {
+		t.Error("Expected a held reservation to be refused")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if ok, _ := store.Reserve(ctx, "a", 0); !ok {
+		t.Error("Expected an expired reservation to be claimable")
+	}
+	if ok, _ := store.Reserve(ctx, "a", time.Minute); ok {
+		t.Error("Expected a reservation without TTL to be held")
+	}
+}
+
+func TestSharedUniquenessStore(t *testing.T) {
+	store := NewMemoryUniquenessStore()
+	ctx := context.Background()
+	opts := []func(*GeneratorConfig){
+		WithCustomAlphabet("0123"),
+		func(cfg *GeneratorConfig) { cfg.Size = 2 },
+		WithUniquenessStore(store, time.Minute),
+	}
+	a := NewExtendedGenerator(opts...)
+	b := NewExtendedGenerator(opts...)
+
+	// Two instances share a 16-ID keyspace without ever repeating
+	seen := make(map[string]bool)
+	for i := 0; i < 8; i++ {
+		for _, gen := range []*ExtendedGenerator{a, b} {
+			id, err := gen.Generate(ctx)
+			if errors.Is(err, ErrCollision) {
+				continue
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if seen[id] {
+				t.Fatalf("ID %s returned by two instances", id)
+			}
+			seen[id] = true
+		}
+	}
+}
+
+// fakeRedis implements RedisClient over a map of key TTLs
+type fakeRedis struct {
+	keys map[string]time.Duration
+	err  error
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{keys: make(map[string]time.Duration)}
+}
+
+func (r *fakeRedis) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	if _, ok := r.keys[key]; ok || r.err != nil {
+		return false, r.err
+	}
+	r.keys[key] = ttl
+	return true, nil
+}
+
+func (r *fakeRedis) SetXX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	if _, ok := r.keys[key]; !ok || r.err != nil {
+		return false, r.err
+	}
+	r.keys[key] = ttl
+	return true, nil
+}
+
+func (r *fakeRedis) Del(ctx context.Context, key string) error {
+	delete(r.keys, key)
+	return r.err
+}
+
+func TestRedisUniquenessStore(t *testing.T) {
+	redis := newFakeRedis()
+
+	gen := NewExtendedGenerator(WithUniquenessStore(NewRedisUniquenessStore(redis, "ids:"), time.Hour))
+	id, err := gen.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ttl, ok := redis.keys["ids:"+id]; !ok || ttl != time.Hour {
+		t.Errorf("Expected ids:%s to be reserved for an hour, got %v", id, redis.keys)
+	}
+}
+
+func TestUniquenessStoreError(t *testing.T) {
+	storeErr := errors.New("connection refused")
+	failing := newFakeRedis()
+	failing.err = storeErr
+
+	gen := NewExtendedGenerator(WithUniquenessStore(NewRedisUniquenessStore(failing, ""), time.Minute))
+	if _, err := gen.Generate(context.Background()); !errors.Is(err, storeErr) {
+		t.Errorf("Expected store error, got %v", err)
+	}
+}
@@ -3,7 +3,6 @@ package idforge
 import (
 	"crypto/rand"
 	"encoding/base32"
-	"strings"
 )
 
 // GenerateSecureToken creates a cryptographically secure random token
@@ -27,15 +26,5 @@ func MustGenerateSecureToken(length int) string {
 
 // IsValidID checks if the ID follows standard generation rules
 func IsValidID(id string, alphabet string, size int) bool {
-	if len(id) != size {
-		return false
-	}
-
-	for _, char := range id {
-		if !strings.ContainsRune(alphabet, char) {
-			return false
-		}
-	}
-
-	return true
+	return CheckID(id, alphabet, size) == nil
 }
@@ -0,0 +1,161 @@
+//go:build !idforge_lite
+
+package idforge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// vaultMissRefreshInterval limits refreshes caused by unknown key IDs
+const vaultMissRefreshInterval = time.Second
+
+// VaultKeyProvider reads keys from a HashiCorp Vault KV version 2 secret.
+// The secret holds a "current" field naming the current key and one
+// base64-encoded field per key ID. Keys are cached for CacheTTL, and an
+// unknown key ID triggers a refresh so rotated keys are picked up promptly.
+type VaultKeyProvider struct {
+	Address  string        // Vault address, e.g. https://vault:8200
+	Token    string        // Vault token sent as X-Vault-Token
+	Path     string        // API path of the secret, e.g. secret/data/idforge
+	CacheTTL time.Duration // How long fetched keys are reused
+	Client   *http.Client
+
+	mu        sync.Mutex
+	set       keySet
+	fetchedAt time.Time
+	fetch     *vaultFetch // Request in flight, if any
+}
+
+// NewVaultKeyProvider creates a provider for the secret at path
+func NewVaultKeyProvider(address, token, path string) *VaultKeyProvider {
+	return &VaultKeyProvider{
+		Address:  strings.TrimRight(address, "/"),
+		Token:    token,
+		Path:     strings.Trim(path, "/"),
+		CacheTTL: 5 * time.Minute,
+		Client:   http.DefaultClient,
+	}
+}
+
+func (p *VaultKeyProvider) CurrentKey(ctx context.Context) (Key, error) {
+	set, err := p.keys(ctx, false)
+	if err != nil {
+		return Key{}, err
+	}
+	return set.key(set.current)
+}
+
+func (p *VaultKeyProvider) Key(ctx context.Context, id string) (Key, error) {
+	set, err := p.keys(ctx, false)
+	if err != nil {
+		return Key{}, err
+	}
+	key, err := set.key(id)
+	if err == ErrKeyNotFound {
+		if set, err = p.keys(ctx, true); err != nil {
+			return Key{}, err
+		}
+		key, err = set.key(id)
+	}
+	return key, err
+}
+
+// vaultFetch is a request for the secret that concurrent callers share
+type vaultFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// keys returns the cached key set, fetching the secret when the cache
+// expired or, with miss set, when it is older than vaultMissRefreshInterval.
+// The request is made without holding p.mu, so cached lookups never wait
+// for Vault, and callers arriving meanwhile wait for the same request.
+func (p *VaultKeyProvider) keys(ctx context.Context, miss bool) (keySet, error) {
+	p.mu.Lock()
+	maxAge := p.CacheTTL
+	if miss {
+		maxAge = vaultMissRefreshInterval
+	}
+	if p.set.keys != nil && time.Since(p.fetchedAt) < maxAge {
+		set := p.set
+		p.mu.Unlock()
+		return set, nil
+	}
+
+	f := p.fetch
+	if f != nil {
+		p.mu.Unlock()
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return keySet{}, ctx.Err()
+		}
+		if f.err != nil {
+			return keySet{}, f.err
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.set, nil
+	}
+
+	f = &vaultFetch{done: make(chan struct{})}
+	p.fetch = f
+	p.mu.Unlock()
+
+	set, err := p.fetchKeys(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err == nil {
+		p.set = set
+		p.fetchedAt = time.Now()
+	}
+	p.fetch = nil
+	f.err = err
+	close(f.done)
+	return set, err
+}
+
+// fetchKeys reads the key set from Vault
+func (p *VaultKeyProvider) fetchKeys(ctx context.Context) (keySet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Address+"/v1/"+p.Path, nil)
+	if err != nil {
+		return keySet{}, err
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return keySet{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return keySet{}, fmt.Errorf("vault: unexpected status %s", resp.Status)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return keySet{}, fmt.Errorf("vault: %w", err)
+	}
+
+	fields := body.Data.Data
+	current := fields["current"]
+	delete(fields, "current")
+
+	set, err := parseKeySet(current, fields)
+	if err != nil {
+		return keySet{}, fmt.Errorf("vault: %w", err)
+	}
+	return set, nil
+}
@@ -0,0 +1,151 @@
+//go:build !idforge_lite
+
+package idforge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVaultKeyProvider(t *testing.T) {
+	var requests atomic.Int32
+	current := atomic.Value{}
+	current.Store("k1")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("X-Vault-Token") != "token" {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/idforge" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"data": {"data": {"current": %q, "k1": %q, "k2": %q}}}`,
+			current.Load(), encodeKey("first"), encodeKey("second"))
+	}))
+	defer server.Close()
+
+	provider := NewVaultKeyProvider(server.URL, "token", "/secret/data/idforge/")
+	ctx := context.Background()
+
+	key, err := provider.CurrentKey(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if key.ID != "k1" || string(key.Secret) != "first" {
+		t.Errorf("Expected k1, got %s=%q", key.ID, key.Secret)
+	}
+
+	// Cached keys are served without another request
+	provider.Key(ctx, "k2")
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected 1 request while cached, got %d", n)
+	}
+
+	// Rotation is picked up once the cache expires
+	current.Store("k2")
+	provider.CacheTTL = 0
+	if key, _ := provider.CurrentKey(ctx); key.ID != "k2" {
+		t.Errorf("Expected rotated key k2, got %s", key.ID)
+	}
+}
+
+func TestVaultKeyProviderErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "permission denied", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	provider := NewVaultKeyProvider(server.URL, "wrong", "secret/data/idforge")
+	if _, err := provider.CurrentKey(context.Background()); err == nil {
+		t.Error("Expected an error for a rejected token")
+	}
+}
+
+func TestVaultKeyProviderRefreshesUnknownKeys(t *testing.T) {
+	var keys atomic.Value
+	keys.Store(fmt.Sprintf(`{"current": "k1", "k1": %q}`, encodeKey("first")))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data": {"data": %s}}`, keys.Load())
+	}))
+	defer server.Close()
+
+	provider := NewVaultKeyProvider(server.URL, "token", "secret/data/idforge")
+	ctx := context.Background()
+	if _, err := provider.CurrentKey(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Another instance rotated in k2; an ID signed with it forces a refresh
+	keys.Store(fmt.Sprintf(`{"current": "k2", "k1": %q, "k2": %q}`, encodeKey("first"), encodeKey("second")))
+	provider.fetchedAt = time.Now().Add(-2 * vaultMissRefreshInterval)
+
+	if _, err := provider.Key(ctx, "k2"); err != nil {
+		t.Errorf("Expected unknown key to trigger a refresh, got %v", err)
+	}
+}
+
+func TestVaultKeyProviderFetchesOutsideLock(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 1 {
+			<-release
+		}
+		fmt.Fprintf(w, `{"data": {"data": {"current": "k1", "k1": %q}}}`, encodeKey("first"))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	provider := NewVaultKeyProvider(server.URL, "token", "secret/data/idforge")
+	ctx := context.Background()
+	if _, err := provider.CurrentKey(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	provider.mu.Lock()
+	provider.fetchedAt = time.Now().Add(-2 * vaultMissRefreshInterval)
+	provider.mu.Unlock()
+
+	// Misses share one slow request
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			provider.Key(ctx, "missing")
+		}()
+	}
+	for requests.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Cached keys do not wait for it
+	done := make(chan error)
+	go func() {
+		_, err := provider.Key(ctx, "k1")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a cached key while a refresh is in flight")
+	}
+
+	release <- struct{}{}
+	wg.Wait()
+	if n := requests.Load(); n != 2 {
+		t.Errorf("Expected concurrent misses to share one request, got %d requests", n)
+	}
+}
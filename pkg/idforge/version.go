@@ -0,0 +1,73 @@
+package idforge
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidFormatVersion = fmt.Errorf("%w: format version must be a valid, non-zero character", ErrInvalidConfig)
+	ErrUnknownFormat        = errors.New("ID has no known format version")
+)
+
+// WithFormatVersion prepends marker to every generated ID, so IDs from
+// different schemes can be told apart for as long as they are stored.
+// IDs are one character longer than the configured size.
+func WithFormatVersion(marker rune) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.FormatVersion = marker
+	}
+}
+
+// validateFormatVersion checks that a configured marker can be written
+func (c GeneratorConfig) validateFormatVersion() error {
+	if c.FormatVersion != 0 && !utf8.ValidRune(c.FormatVersion) {
+		return ErrInvalidFormatVersion
+	}
+	return nil
+}
+
+// VersionedFormat is one generation of an ID scheme, identified by the
+// marker in front of its IDs
+type VersionedFormat struct {
+	Marker rune
+
+	// Check validates the rest of the ID, after the marker
+	Check func(body string) error
+}
+
+// FormatOf returns the format of IDs generated with cfg, checking their
+// alphabet and size
+func FormatOf(cfg GeneratorConfig) VersionedFormat {
+	alphabet := newCharset(cfg.Alphabet)
+	return VersionedFormat{
+		Marker: cfg.FormatVersion,
+		Check: func(body string) error {
+			return alphabet.check(body, cfg.Size)
+		},
+	}
+}
+
+// DetectFormat finds the format whose marker starts id and validates the
+// rest of the ID with it. It returns ErrUnknownFormat if no format has a
+// matching marker, and the format's Check error if the ID is malformed.
+func DetectFormat(id string, formats ...VersionedFormat) (VersionedFormat, error) {
+	marker, n := utf8.DecodeRuneInString(id)
+	if n == 0 || marker == utf8.RuneError && n == 1 {
+		return VersionedFormat{}, ErrUnknownFormat
+	}
+
+	for _, f := range formats {
+		if f.Marker != marker {
+			continue
+		}
+		if f.Check != nil {
+			if err := f.Check(id[n:]); err != nil {
+				return f, err
+			}
+		}
+		return f, nil
+	}
+	return VersionedFormat{}, fmt.Errorf("%w: %q", ErrUnknownFormat, marker)
+}
@@ -0,0 +1,68 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWithFormatVersion(t *testing.T) {
+	gen := NewExtendedGenerator(WithFormatVersion('2'))
+	id, err := gen.Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(id, "2") {
+		t.Errorf("Expected ID to start with the marker, got %s", id)
+	}
+	if n := utf8.RuneCountInString(id); n != DefaultSize+1 {
+		t.Errorf("Expected %d characters, got %d", DefaultSize+1, n)
+	}
+}
+
+func TestWithFormatVersionInvalid(t *testing.T) {
+	gen := NewExtendedGenerator(WithFormatVersion(utf8.MaxRune + 1))
+	if _, err := gen.Generate(context.Background()); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig, got %v", err)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	v1 := NewExtendedGenerator(WithFormatVersion('1'), func(c *GeneratorConfig) { c.Size = 10 })
+	v2 := NewExtendedGenerator(WithFormatVersion('2'), func(c *GeneratorConfig) { c.Alphabet = "0123456789" })
+	formats := []VersionedFormat{FormatOf(v1.Config()), FormatOf(v2.Config())}
+
+	for marker, gen := range map[rune]*ExtendedGenerator{'1': v1, '2': v2} {
+		id, err := gen.Generate(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		f, err := DetectFormat(id, formats...)
+		if err != nil {
+			t.Fatalf("Unexpected error detecting %s: %v", id, err)
+		}
+		if f.Marker != marker {
+			t.Errorf("Expected format %c for %s, got %c", marker, id, f.Marker)
+		}
+	}
+}
+
+func TestDetectFormatRejects(t *testing.T) {
+	formats := []VersionedFormat{FormatOf(GeneratorConfig{FormatVersion: '1', Alphabet: "abc", Size: 4})}
+
+	if _, err := DetectFormat("9abca", formats...); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat for unknown marker, got %v", err)
+	}
+	if _, err := DetectFormat("", formats...); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat for empty ID, got %v", err)
+	}
+	f, err := DetectFormat("1abcz", formats...)
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for malformed body, got %v", err)
+	}
+	if f.Marker != '1' {
+		t.Errorf("Expected the matched format with the error, got %c", f.Marker)
+	}
+}
@@ -0,0 +1,261 @@
+package idforge
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	// VoucherAlphabet leaves out 0, 1, I and O, which are easily misread
+	// on printed cards and receipts
+	VoucherAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+	// DefaultVoucherDistance is the minimum number of positions in which
+	// any two vouchers differ by default
+	DefaultVoucherDistance = 3
+
+	// voucherAttempts bounds how many candidates are drawn per voucher
+	voucherAttempts = 1000
+)
+
+// VoucherOption configures a VoucherGenerator
+type VoucherOption func(*VoucherGenerator)
+
+// WithVoucherAlphabet draws vouchers from alphabet, which must be ASCII
+// without '-' or spaces
+func WithVoucherAlphabet(alphabet string) VoucherOption {
+	return func(g *VoucherGenerator) {
+		g.alphabet = alphabet
+	}
+}
+
+// WithMinHammingDistance makes every two vouchers differ in at least
+// distance positions, check character included, so a guess or typo that
+// changes fewer characters of a valid code never hits another one
+func WithMinHammingDistance(distance int) VoucherOption {
+	return func(g *VoucherGenerator) {
+		if distance > 0 {
+			g.distance = distance
+		}
+	}
+}
+
+// WithVoucherBlocklist redraws vouchers containing a word from list
+func WithVoucherBlocklist(list *Wordlist) VoucherOption {
+	return func(g *VoucherGenerator) {
+		g.blocklist = list
+	}
+}
+
+// WithVoucherGroups writes vouchers in dash-separated groups of size
+// characters, such as ABCD-EFGH-JKLM, for reading aloud and typing
+func WithVoucherGroups(size int) VoucherOption {
+	return func(g *VoucherGenerator) {
+		if size > 0 {
+			g.group = size
+		}
+	}
+}
+
+// VoucherGenerator issues gift card and voucher codes that resist guessing:
+// codes end in a Luhn mod N check character, are screened against a
+// blocklist and keep a minimum Hamming distance from every code issued by
+// the generator, so near misses of a valid code are never valid. It is
+// safe for concurrent use.
+type VoucherGenerator struct {
+	alphabet  string
+	size      int // Random characters, check character excluded
+	distance  int
+	blocklist *Wordlist
+	group     int
+
+	mu    sync.Mutex
+	index hammingIndex
+}
+
+// NewVoucherGenerator creates a generator of codes with size random
+// characters plus a check character
+func NewVoucherGenerator(size int, opts ...VoucherOption) (*VoucherGenerator, error) {
+	g := &VoucherGenerator{alphabet: VoucherAlphabet, size: size, distance: DefaultVoucherDistance}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if size < 1 {
+		return nil, ErrInvalidSize
+	}
+	if !validAlphabet(g.alphabet) || len(g.alphabet) != alphabetLen(g.alphabet) || strings.ContainsAny(g.alphabet, "- ") {
+		return nil, ErrInvalidAlphabet
+	}
+	if g.distance > size+1 {
+		return nil, fmt.Errorf("%w: minimum distance %d exceeds the code length %d", ErrInvalidConfig, g.distance, size+1)
+	}
+	g.index = newHammingIndex(size+1, g.distance)
+	return g, nil
+}
+
+// GenerateBatch issues n vouchers, each at the minimum distance from the
+// others and from every voucher issued or excluded before. Denser batches
+// take longer; when the code space is too crowded to place a voucher it
+// fails with ErrGenerationTimeout. Vouchers placed before a failure stay
+// recorded as issued.
+func (g *VoucherGenerator) GenerateBatch(ctx context.Context, n int) ([]string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	alphabet := newSymbols(g.alphabet)
+	codes := make([]string, 0, n)
+	for len(codes) < n {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		code, err := g.draw(alphabet)
+		if err != nil {
+			return nil, err
+		}
+		g.index.add(code)
+		codes = append(codes, g.format(code))
+	}
+	return codes, nil
+}
+
+// draw finds a candidate passing the blocklist and the distance check; the
+// caller must hold g.mu
+func (g *VoucherGenerator) draw(alphabet symbols) (string, error) {
+	for attempt := 0; attempt < voucherAttempts; attempt++ {
+		body, err := buildID(rand.Reader, alphabet, g.size, nil, nil)
+		if err != nil {
+			return "", err
+		}
+		code := body + string(g.alphabet[luhnModN(body, g.alphabet)])
+		if g.blocklist != nil && g.blocklist.Contains(code) {
+			continue
+		}
+		if !g.index.near(code) {
+			return code, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no voucher at distance %d after %d attempts", ErrGenerationTimeout, g.distance, voucherAttempts)
+}
+
+// Exclude records vouchers issued elsewhere, such as in earlier runs, so
+// new ones keep their distance from them. Codes that do not validate are
+// ignored.
+func (g *VoucherGenerator) Exclude(codes ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, code := range codes {
+		if normalized := g.Normalize(code); g.valid(normalized) {
+			g.index.add(normalized)
+		}
+	}
+}
+
+// Validate checks the length, characters, check character and blocklist of
+// code, ignoring dashes, spaces and, for alphabets without lowercase
+// letters, case. It does not tell whether the voucher was issued.
+func (g *VoucherGenerator) Validate(code string) bool {
+	return g.valid(g.Normalize(code))
+}
+
+// valid checks a normalized code
+func (g *VoucherGenerator) valid(code string) bool {
+	if len(code) != g.size+1 {
+		return false
+	}
+	body := code[:g.size]
+	for i := 0; i < len(body); i++ {
+		if strings.IndexByte(g.alphabet, body[i]) < 0 {
+			return false
+		}
+	}
+	if code[g.size] != g.alphabet[luhnModN(body, g.alphabet)] {
+		return false
+	}
+	return g.blocklist == nil || !g.blocklist.Contains(code)
+}
+
+// Normalize strips the grouping and, for alphabets without lowercase
+// letters, uppercases code, giving the form to store and look up
+func (g *VoucherGenerator) Normalize(code string) string {
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	if g.alphabet == strings.ToUpper(g.alphabet) {
+		code = strings.ToUpper(code)
+	}
+	return code
+}
+
+// format writes code in groups
+func (g *VoucherGenerator) format(code string) string {
+	if g.group == 0 || len(code) <= g.group {
+		return code
+	}
+	var b strings.Builder
+	for i := 0; i < len(code); i += g.group {
+		if i > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(code[i:min(i+g.group, len(code))])
+	}
+	return b.String()
+}
+
+// Hamming returns the number of positions at which a and b differ, plus
+// the difference in length
+func Hamming(a, b string) int {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	d := len(b) - len(a)
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			d++
+		}
+	}
+	return d
+}
+
+// hammingIndex finds codes of one length within distance-1 substitutions.
+// Codes are split into distance blocks: two codes differing in fewer than
+// distance positions must agree on at least one whole block, so only codes
+// sharing a block with the query are compared.
+type hammingIndex struct {
+	distance int
+	bounds   []int // Block boundaries, len(bounds) == distance+1
+	blocks   []map[string][]string
+}
+
+func newHammingIndex(length, distance int) hammingIndex {
+	idx := hammingIndex{distance: distance}
+	for i := 0; i <= distance; i++ {
+		idx.bounds = append(idx.bounds, i*length/distance)
+	}
+	idx.blocks = make([]map[string][]string, distance)
+	for i := range idx.blocks {
+		idx.blocks[i] = make(map[string][]string)
+	}
+	return idx
+}
+
+// near reports whether an indexed code is closer to code than the distance
+func (idx hammingIndex) near(code string) bool {
+	for i, block := range idx.blocks {
+		for _, other := range block[code[idx.bounds[i]:idx.bounds[i+1]]] {
+			if Hamming(code, other) < idx.distance {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (idx hammingIndex) add(code string) {
+	for i, block := range idx.blocks {
+		key := code[idx.bounds[i]:idx.bounds[i+1]]
+		block[key] = append(block[key], code)
+	}
+}
@@ -0,0 +1,139 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVoucherGenerateBatch(t *testing.T) {
+	gen, err := NewVoucherGenerator(11, WithVoucherGroups(4))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	codes, err := gen.GenerateBatch(context.Background(), 2000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(codes) != 2000 {
+		t.Fatalf("Expected 2000 codes, got %d", len(codes))
+	}
+
+	normalized := make([]string, len(codes))
+	for i, code := range codes {
+		if len(code) != 14 || code[4] != '-' || code[9] != '-' {
+			t.Fatalf("Expected XXXX-XXXX-XXXX, got %s", code)
+		}
+		if !gen.Validate(code) {
+			t.Errorf("Expected %s to validate", code)
+		}
+		normalized[i] = gen.Normalize(code)
+	}
+	for i := range normalized {
+		for j := i + 1; j < len(normalized); j++ {
+			if d := Hamming(normalized[i], normalized[j]); d < DefaultVoucherDistance {
+				t.Fatalf("Expected distance %d or more, got %d between %s and %s", DefaultVoucherDistance, d, codes[i], codes[j])
+			}
+		}
+	}
+}
+
+func TestVoucherValidate(t *testing.T) {
+	gen, _ := NewVoucherGenerator(8)
+	codes, _ := gen.GenerateBatch(context.Background(), 1)
+	code := codes[0]
+
+	if !gen.Validate(strings.ToLower(code[:4]) + " " + code[4:]) {
+		t.Error("Expected case and spacing to be ignored")
+	}
+	for i := 0; i < len(code); i++ {
+		for _, c := range VoucherAlphabet {
+			if byte(c) == code[i] {
+				continue
+			}
+			typo := code[:i] + string(c) + code[i+1:]
+			if gen.Validate(typo) {
+				t.Fatalf("Expected single-character typo %s of %s to fail the check character", typo, code)
+			}
+		}
+	}
+	if gen.Validate(code[:8]) || gen.Validate(code+"2") || gen.Validate("0"+code[1:]) {
+		t.Error("Expected wrong lengths and characters to fail")
+	}
+}
+
+func TestVoucherBlocklist(t *testing.T) {
+	list := NewWordlist("A", "B", "C", "D")
+	gen, _ := NewVoucherGenerator(4, WithVoucherBlocklist(list), WithMinHammingDistance(1))
+	codes, err := gen.GenerateBatch(context.Background(), 50)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, code := range codes {
+		if strings.ContainsAny(code, "ABCD") {
+			t.Errorf("Expected blocked letters to be screened, got %s", code)
+		}
+	}
+}
+
+func TestVoucherExclude(t *testing.T) {
+	gen, _ := NewVoucherGenerator(2, WithVoucherAlphabet("0123456789"), WithMinHammingDistance(2))
+	codes, _ := gen.GenerateBatch(context.Background(), 1)
+
+	// Another generator learns about the first code and keeps its distance
+	other, _ := NewVoucherGenerator(2, WithVoucherAlphabet("0123456789"), WithMinHammingDistance(2))
+	other.Exclude(codes[0], "garbage")
+	more, err := other.GenerateBatch(context.Background(), 20)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, code := range more {
+		if Hamming(code, codes[0]) < 2 {
+			t.Errorf("Expected %s to keep its distance from excluded %s", code, codes[0])
+		}
+	}
+}
+
+func TestVoucherExhausted(t *testing.T) {
+	// 10 possible bodies, all of them differing in their single character
+	gen, _ := NewVoucherGenerator(1, WithVoucherAlphabet("0123456789"), WithMinHammingDistance(2))
+	if _, err := gen.GenerateBatch(context.Background(), 11); !errors.Is(err, ErrGenerationTimeout) {
+		t.Errorf("Expected ErrGenerationTimeout for an impossible batch, got %v", err)
+	}
+}
+
+func TestNewVoucherGeneratorErrors(t *testing.T) {
+	if _, err := NewVoucherGenerator(0); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("Expected ErrInvalidSize, got %v", err)
+	}
+	if _, err := NewVoucherGenerator(8, WithVoucherAlphabet("AB-C")); !errors.Is(err, ErrInvalidAlphabet) {
+		t.Errorf("Expected ErrInvalidAlphabet for a dash, got %v", err)
+	}
+	if _, err := NewVoucherGenerator(8, WithVoucherAlphabet("ÄÖÜ")); !errors.Is(err, ErrInvalidAlphabet) {
+		t.Errorf("Expected ErrInvalidAlphabet for non-ASCII, got %v", err)
+	}
+	if _, err := NewVoucherGenerator(3, WithMinHammingDistance(5)); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for an unreachable distance, got %v", err)
+	}
+}
+
+func TestHamming(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     string
+		expected int
+	}{{"ABCD", "ABCD", 0}, {"ABCD", "ABCE", 1}, {"ABCD", "DCBA", 4}, {"AB", "ABCD", 2}, {"", "", 0}} {
+		if got := Hamming(tc.a, tc.b); got != tc.expected {
+			t.Errorf("Expected Hamming(%q, %q) = %d, got %d", tc.a, tc.b, tc.expected, got)
+		}
+	}
+}
+
+func BenchmarkVoucherGenerateBatch(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		gen, _ := NewVoucherGenerator(11)
+		if _, err := gen.GenerateBatch(context.Background(), 100000); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package idforge
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+var ErrInvalidWeights = fmt.Errorf("%w: alphabet weights must be positive and name alphabet characters", ErrInvalidConfig)
+
+// WithAlphabetWeights biases character selection. Each weight is relative
+// to the characters not listed, which keep a weight of 1, so
+// {'0': 3, ..., '9': 3} makes every digit three times as likely as any
+// letter while letters stay possible.
+//
+// Weighting costs entropy: an ID carries Size * BitsPerCharacter bits
+// instead of Size * log2(len(Alphabet)), and collisions grow more likely
+// accordingly. Increase Size to compensate.
+func WithAlphabetWeights(weights map[rune]float64) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.AlphabetWeights = make(map[rune]float64, len(weights))
+		for char, weight := range weights {
+			c.AlphabetWeights[char] = weight
+		}
+	}
+}
+
+// BitsPerCharacter returns the Shannon entropy of a single generated
+// character, log2(len(Alphabet)) unless AlphabetWeights skews the
+// distribution
+func (c GeneratorConfig) BitsPerCharacter() float64 {
+	bits := 0.0
+	for _, p := range c.characterProbabilities() {
+		bits -= p * math.Log2(p)
+	}
+	return bits
+}
+
+// effectiveAlphabetSize returns the size of a uniform alphabet with the same
+// chance of two characters matching, which governs collisions
+func (c GeneratorConfig) effectiveAlphabetSize() float64 {
+	if c.AlphabetWeights == nil {
+		return float64(alphabetLen(c.Alphabet))
+	}
+	match := 0.0
+	for _, p := range c.characterProbabilities() {
+		match += p * p
+	}
+	return 1 / match
+}
+
+// characterProbabilities returns the chance of drawing each alphabet
+// character, in alphabet order
+func (c GeneratorConfig) characterProbabilities() []float64 {
+	probs := make([]float64, 0, alphabetLen(c.Alphabet))
+	total := 0.0
+	for _, char := range c.Alphabet {
+		weight, ok := c.AlphabetWeights[char]
+		if !ok {
+			weight = 1
+		}
+		probs = append(probs, weight)
+		total += weight
+	}
+	for i := range probs {
+		probs[i] /= total
+	}
+	return probs
+}
+
+// validateWeights rejects weights that are not positive and finite or that
+// name characters outside the alphabet
+func (c GeneratorConfig) validateWeights() error {
+	for char, weight := range c.AlphabetWeights {
+		if !strings.ContainsRune(c.Alphabet, char) || !(weight > 0) || math.IsInf(weight, 1) {
+			return ErrInvalidWeights
+		}
+	}
+	return nil
+}
+
+// aliasTable samples a discrete distribution in constant time per draw
+// using Vose's alias method: a uniformly chosen column is kept with its
+// threshold probability and replaced by its alias otherwise
+type aliasTable struct {
+	threshold []uint64 // Keep probability scaled to 1<<32
+	alias     []int
+}
+
+// newAliasTable builds a table for probs, which must sum to 1
+func newAliasTable(probs []float64) *aliasTable {
+	n := len(probs)
+	t := &aliasTable{
+		threshold: make([]uint64, n),
+		alias:     make([]int, n),
+	}
+
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, p := range probs {
+		scaled[i] = p * float64(n)
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+
+		t.threshold[s] = uint64(scaled[s] * (1 << 32))
+		t.alias[s] = l
+		scaled[l] += scaled[s] - 1
+		if scaled[l] < 1 {
+			small = append(small, l)
+		} else {
+			large = append(large, l)
+		}
+	}
+
+	// Whatever remains has probability 1 up to rounding
+	for _, i := range append(small, large...) {
+		t.threshold[i] = 1 << 32
+		t.alias[i] = i
+	}
+	return t
+}
+
+// resolve replaces each uniformly drawn column in indices with its sampled
+// character, reading four bytes from r per index
+func (t *aliasTable) resolve(r io.Reader, indices []int) error {
+	var buf [4 * sampleChunk]byte
+	coins := buf[:4*len(indices)]
+	if _, err := io.ReadFull(r, coins); err != nil {
+		return err
+	}
+	for i, column := range indices {
+		coin := uint64(binary.LittleEndian.Uint32(coins[4*i:]))
+		if coin >= t.threshold[column] {
+			indices[i] = t.alias[column]
+		}
+	}
+	return nil
+}
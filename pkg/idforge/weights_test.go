@@ -0,0 +1,117 @@
+package idforge
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAliasTableMatchesProbabilities(t *testing.T) {
+	probs := []float64{0.5, 0.25, 0.125, 0.125}
+	table := newAliasTable(probs)
+
+	counts := make([]int, len(probs))
+	columns := make([]int, sampleChunk)
+	for round := 0; round < 300; round++ {
+		if err := sampleIndices(rand.Reader, len(probs), columns); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := table.resolve(rand.Reader, columns); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, index := range columns {
+			counts[index]++
+		}
+	}
+
+	total := float64(300 * sampleChunk)
+	for i, p := range probs {
+		if got := float64(counts[i]) / total; math.Abs(got-p) > 0.01 {
+			t.Errorf("Character %d: expected frequency %.3f, got %.3f", i, p, got)
+		}
+	}
+}
+
+func TestWeightedAlphabetBiasesDigits(t *testing.T) {
+	weights := map[rune]float64{}
+	for _, digit := range "0123456789" {
+		weights[digit] = 10
+	}
+	gen := NewExtendedGenerator(
+		WithCustomAlphabet("0123456789ab"),
+		WithAlphabetWeights(weights),
+		func(c *GeneratorConfig) { c.Size = 64 },
+	)
+
+	digits, letters := 0, 0
+	for i := 0; i < 50; i++ {
+		id, err := gen.Generate(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, char := range id {
+			if char == 'a' || char == 'b' {
+				letters++
+			} else {
+				digits++
+			}
+		}
+	}
+
+	// Each letter has probability 1/102, so about 63 of 3200 characters
+	if letters == 0 || letters > 130 {
+		t.Errorf("Expected about 63 letters among %d characters, got %d", digits+letters, letters)
+	}
+}
+
+func TestBitsPerCharacter(t *testing.T) {
+	uniform := GeneratorConfig{Alphabet: "0123456789abcdef", Size: 8}
+	if bits := uniform.BitsPerCharacter(); math.Abs(bits-4) > 1e-9 {
+		t.Errorf("Expected 4 bits per character, got %f", bits)
+	}
+
+	weighted := uniform
+	weighted.AlphabetWeights = map[rune]float64{'0': 100}
+	if bits := weighted.BitsPerCharacter(); bits >= 4 {
+		t.Errorf("Expected weighting to cost entropy, got %f bits", bits)
+	}
+	if size := weighted.effectiveAlphabetSize(); size >= 16 {
+		t.Errorf("Expected effective alphabet size below 16, got %f", size)
+	}
+}
+
+func TestInvalidAlphabetWeights(t *testing.T) {
+	for _, weights := range []map[rune]float64{
+		{'x': 1},
+		{'a': 0},
+		{'a': -1},
+		{'a': math.NaN()},
+		{'a': math.Inf(1)},
+	} {
+		gen := NewExtendedGenerator(WithCustomAlphabet("abc"), WithAlphabetWeights(weights))
+		if _, err := gen.Generate(context.Background()); !errors.Is(err, ErrInvalidWeights) {
+			t.Errorf("Weights %v: expected ErrInvalidWeights, got %v", weights, err)
+		}
+	}
+}
+
+func TestSimulateWeightedAlphabet(t *testing.T) {
+	cfg := GeneratorConfig{
+		Alphabet:        "01",
+		Size:            10,
+		AlphabetWeights: map[rune]float64{'1': 3},
+	}
+
+	result := SimulateCollisions(cfg, 2000)
+
+	ones := float64(result.CharacterCounts['1']) / 20000
+	if math.Abs(ones-0.75) > 0.02 {
+		t.Errorf("Expected 75%% ones, got %.1f%%", 100*ones)
+	}
+	// One degree of freedom: values above ~10.8 are significant at 0.1%
+	if result.ChiSquare > 10.8 {
+		t.Errorf("Character distribution deviates from the weights, chi-square %.2f", result.ChiSquare)
+	}
+}
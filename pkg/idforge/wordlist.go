@@ -0,0 +1,202 @@
+package idforge
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"unicode"
+)
+
+var ErrForbiddenWord = errors.New("ID contains a forbidden word")
+
+// RuleForbidden is reported in validation violations for IDs containing a
+// word from a Wordlist
+const RuleForbidden = "forbidden"
+
+// maxFilterAttempts bounds how often the basic Generator redraws IDs that
+// contain a forbidden word
+const maxFilterAttempts = 100
+
+// WithForbiddenWords makes Generate redraw IDs containing a word from list
+// and Validate reject them
+func WithForbiddenWords(list *Wordlist) Option {
+	return func(g *Generator) {
+		g.forbidden = list
+	}
+}
+
+// WithWordFilter makes Generate redraw IDs containing a word from list.
+// Rejected candidates count as attempts, like collisions.
+func WithWordFilter(list *Wordlist) func(*GeneratorConfig) {
+	return func(c *GeneratorConfig) {
+		c.WordFilter = list
+	}
+}
+
+// Wordlist matches forbidden substrings, such as profanity or reserved
+// slugs, with an Aho-Corasick automaton, so checking an ID takes one pass
+// however many words the list holds. Matching ignores case. A Wordlist is
+// immutable and safe for concurrent use.
+type Wordlist struct {
+	nodes []wordNode
+	words []string
+}
+
+// wordNode is an automaton state. word is the index of the longest listed
+// word ending here, following failure links, or -1.
+type wordNode struct {
+	next map[rune]int32
+	fail int32
+	word int32
+}
+
+// NewWordlist builds a matcher for words; empty words are ignored
+func NewWordlist(words ...string) *Wordlist {
+	w := &Wordlist{nodes: []wordNode{{word: -1}}}
+	for _, word := range words {
+		w.add(word)
+	}
+	w.link()
+	return w
+}
+
+// ReadWordlist builds a matcher from one word per line. Blank lines and
+// lines starting with '#' are skipped, and surrounding space is trimmed.
+func ReadWordlist(r io.Reader) (*Wordlist, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewWordlist(words...), nil
+}
+
+// LoadWordlist reads and merges the named files from fsys, such as an
+// embed.FS or os.DirFS, in the format accepted by ReadWordlist
+func LoadWordlist(fsys fs.FS, names ...string) (*Wordlist, error) {
+	var words []string
+	for _, name := range names {
+		f, err := fsys.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		list, err := ReadWordlist(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		words = append(words, list.words...)
+	}
+	return NewWordlist(words...), nil
+}
+
+// Len returns the number of words in the list
+func (w *Wordlist) Len() int {
+	return len(w.words)
+}
+
+// Contains reports whether s contains any listed word
+func (w *Wordlist) Contains(s string) bool {
+	_, ok := w.Find(s)
+	return ok
+}
+
+// Find returns the listed word that ends first in s, preferring the
+// longest at that position, spelled as in the list
+func (w *Wordlist) Find(s string) (string, bool) {
+	state := int32(0)
+	for _, char := range s {
+		state = w.step(state, unicode.ToLower(char))
+		if word := w.nodes[state].word; word >= 0 {
+			return w.words[word], true
+		}
+	}
+	return "", false
+}
+
+// Check returns a *ValidationError if id contains a listed word
+func (w *Wordlist) Check(id string) error {
+	word, ok := w.Find(id)
+	if !ok {
+		return nil
+	}
+	return &ValidationError{ID: id, violations: []Violation{w.violation(word)}}
+}
+
+// violation describes a match of word
+func (w *Wordlist) violation(word string) Violation {
+	return Violation{
+		Rule:    RuleForbidden,
+		Message: fmt.Sprintf("contains forbidden word %q", word),
+		Args:    []any{word},
+	}
+}
+
+// step follows the transition for char, falling back along failure links
+func (w *Wordlist) step(state int32, char rune) int32 {
+	for {
+		if next, ok := w.nodes[state].next[char]; ok {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = w.nodes[state].fail
+	}
+}
+
+// add inserts word into the trie
+func (w *Wordlist) add(word string) {
+	if word == "" {
+		return
+	}
+	state := int32(0)
+	for _, char := range word {
+		char = unicode.ToLower(char)
+		next, ok := w.nodes[state].next[char]
+		if !ok {
+			next = int32(len(w.nodes))
+			w.nodes = append(w.nodes, wordNode{word: -1})
+			if w.nodes[state].next == nil {
+				w.nodes[state].next = make(map[rune]int32)
+			}
+			w.nodes[state].next[char] = next
+		}
+		state = next
+	}
+	if w.nodes[state].word < 0 {
+		w.nodes[state].word = int32(len(w.words))
+		w.words = append(w.words, word)
+	}
+}
+
+// link computes failure links breadth first, so each node inherits the
+// match of its longest proper suffix that is also in the trie
+func (w *Wordlist) link() {
+	queue := make([]int32, 0, len(w.nodes))
+	for _, child := range w.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for char, child := range w.nodes[state].next {
+			fail := w.step(w.nodes[state].fail, char)
+			w.nodes[child].fail = fail
+			if w.nodes[child].word < 0 {
+				w.nodes[child].word = w.nodes[fail].word
+			}
+			queue = append(queue, child)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package idforge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWordlistFind(t *testing.T) {
+	list := NewWordlist("he", "she", "his", "hers", "abcd", "bc", "")
+
+	tests := []struct {
+		s    string
+		word string
+		ok   bool
+	}{
+		{"ushers", "she", true},
+		{"ahishers", "his", true},
+		{"USHERS", "she", true},
+		{"abce", "bc", true},
+		{"xyz", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		word, ok := list.Find(tt.s)
+		if word != tt.word || ok != tt.ok {
+			t.Errorf("Expected Find(%q) = %q, %v; got %q, %v", tt.s, tt.word, tt.ok, word, ok)
+		}
+	}
+	if list.Len() != 6 {
+		t.Errorf("Expected 6 words, got %d", list.Len())
+	}
+}
+
+func TestReadWordlist(t *testing.T) {
+	list, err := ReadWordlist(strings.NewReader("# reserved slugs\nadmin\n\n  Login  \nadmin\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if list.Len() != 2 {
+		t.Errorf("Expected 2 words, got %d", list.Len())
+	}
+	if !list.Contains("xxloginxx") || list.Contains("# reserved") {
+		t.Error("Expected words to be trimmed and comments skipped")
+	}
+}
+
+func TestLoadWordlist(t *testing.T) {
+	fsys := fstest.MapFS{
+		"profanity.txt": {Data: []byte("darn\nheck\n")},
+		"reserved.txt":  {Data: []byte("admin\n")},
+	}
+
+	list, err := LoadWordlist(fsys, "profanity.txt", "reserved.txt")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if list.Len() != 3 || !list.Contains("xADMINx") || !list.Contains("oheck") {
+		t.Errorf("Expected words from both files, got %d words", list.Len())
+	}
+
+	if _, err := LoadWordlist(fsys, "missing.txt"); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestWordlistCheck(t *testing.T) {
+	list := NewWordlist("bad")
+	if err := list.Check("good"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	err := list.Check("xBADx")
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Violations()[0].Rule != RuleForbidden {
+		t.Fatalf("Expected a forbidden violation, got %v", err)
+	}
+	if code := ErrorCode(err); code != CodeForbiddenWord {
+		t.Errorf("Expected %s, got %s", CodeForbiddenWord, code)
+	}
+}
+
+func TestGeneratorWithForbiddenWords(t *testing.T) {
+	gen := New(WithAlphabet("ab"), WithSize(2), WithForbiddenWords(NewWordlist("a")))
+	for i := 0; i < 20; i++ {
+		if id := gen.MustGenerate(); id != "bb" {
+			t.Fatalf("Expected only bb to pass the filter, got %q", id)
+		}
+	}
+
+	var verr *ValidationError
+	if err := gen.Check("ax"); !errors.As(err, &verr) || len(verr.Violations()) != 2 {
+		t.Errorf("Expected alphabet and forbidden violations, got %v", err)
+	}
+
+	gen = New(WithAlphabet("ab"), WithForbiddenWords(NewWordlist("a", "b")))
+	if _, err := gen.Generate(); !errors.Is(err, ErrForbiddenWord) {
+		t.Errorf("Expected ErrForbiddenWord, got %v", err)
+	}
+}
+
+func TestExtendedGeneratorWithWordFilter(t *testing.T) {
+	gen := NewExtendedGenerator(WithCustomAlphabet("abcdefghij"), WithWordFilter(NewWordlist("a")), func(c *GeneratorConfig) {
+		c.Size = 2
+	})
+	for i := 0; i < 5; i++ {
+		id, err := gen.Generate(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.ContainsRune(id, 'a') {
+			t.Errorf("Expected the filter to reject %q", id)
+		}
+	}
+
+	gen = NewExtendedGenerator(WithCustomAlphabet("ab"), WithWordFilter(NewWordlist("a", "b")))
+	_, err := gen.Generate(context.Background())
+	if !errors.Is(err, ErrForbiddenWord) || !errors.Is(err, ErrGenerationTimeout) {
+		t.Errorf("Expected ErrForbiddenWord wrapped in ErrGenerationTimeout, got %v", err)
+	}
+	if code := ErrorCode(err); code != CodeFilterExhausted {
+		t.Errorf("Expected %s, got %s", CodeFilterExhausted, code)
+	}
+}
+
+func BenchmarkWordlistContains(b *testing.B) {
+	words := make([]string, 10000)
+	for i := range words {
+		words[i] = fmt.Sprintf("w%04dx", i)
+	}
+	list := NewWordlist(words...)
+	id := New().MustGenerate()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		list.Contains(id)
+	}
+}